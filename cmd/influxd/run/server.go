@@ -339,7 +339,15 @@ func (s *Server) appendContinuousQueryService(c continuous_querier.Config) {
 	srv.MetaClient = s.MetaClient
 	srv.QueryExecutor = s.QueryExecutor
 	srv.Monitor = s.Monitor
+	srv.PointsWriter = s.PointsWriter
+	srv.StatusPath = filepath.Join(s.config.Meta.Dir, "cq_status.json")
+	s.PointsWriter.AddWriteHook(srv.PointsWritten)
 	s.Services = append(s.Services, srv)
+
+	// Backfills are executed by the continuous query service.
+	if e, ok := s.QueryExecutor.StatementExecutor.(*coordinator.StatementExecutor); ok {
+		e.ContinuousQuerier = srv
+	}
 }
 
 // Err returns an error channel that multiplexes all out of band errors received from all services.
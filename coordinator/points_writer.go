@@ -60,7 +60,8 @@ type PointsWriter struct {
 		WriteToShard(shardID uint64, points []models.Point) error
 	}
 
-	subPoints []chan<- *WritePointsRequest
+	subPoints  []chan<- *WritePointsRequest
+	writeHooks []WriteHook
 
 	stats *WriteStatistics
 }
@@ -72,6 +73,11 @@ type WritePointsRequest struct {
 	Points          []models.Point
 }
 
+// WriteHook is called with the points of a write to a shard once they have
+// been stored. Partial is set when the shard dropped some of the points. Hooks
+// are called before the write returns, so they must be fast.
+type WriteHook func(database, retentionPolicy string, points []models.Point, partial bool)
+
 // AddPoint adds a point to the WritePointRequest with field key 'value'
 func (w *WritePointsRequest) AddPoint(name string, value interface{}, timestamp time.Time, tags map[string]string) {
 	pt, err := models.NewPoint(
@@ -147,6 +153,11 @@ func (w *PointsWriter) AddWriteSubscriber(c chan<- *WritePointsRequest) {
 	w.subPoints = append(w.subPoints, c)
 }
 
+// AddWriteHook adds a hook that is called after each successful write to a shard.
+func (w *PointsWriter) AddWriteHook(fn WriteHook) {
+	w.writeHooks = append(w.writeHooks, fn)
+}
+
 // WithLogger sets the Logger on w.
 func (w *PointsWriter) WithLogger(log zap.Logger) {
 	w.Logger = log.With(zap.String("service", "write"))
@@ -366,12 +377,14 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPo
 	err := w.TSDBStore.WriteToShard(shard.ID, points)
 	if err == nil {
 		atomic.AddInt64(&w.stats.WriteOK, 1)
+		w.callWriteHooks(database, retentionPolicy, points, false)
 		return nil
 	}
 
 	// If this is a partial write error, that is also ok.
 	if _, ok := err.(tsdb.PartialWriteError); ok {
 		atomic.AddInt64(&w.stats.WriteErr, 1)
+		w.callWriteHooks(database, retentionPolicy, points, true)
 		return err
 	}
 
@@ -390,9 +403,22 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, retentionPo
 	if err != nil {
 		w.Logger.Info(fmt.Sprintf("write failed for shard %d: %v", shard.ID, err))
 		atomic.AddInt64(&w.stats.WriteErr, 1)
+		if _, ok := err.(tsdb.PartialWriteError); ok {
+			w.callWriteHooks(database, retentionPolicy, points, true)
+		}
 		return err
 	}
 
 	atomic.AddInt64(&w.stats.WriteOK, 1)
+	w.callWriteHooks(database, retentionPolicy, points, false)
 	return nil
 }
+
+// callWriteHooks calls the write hooks with the points written to a shard.
+func (w *PointsWriter) callWriteHooks(database, retentionPolicy string, points []models.Point, partial bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	for _, fn := range w.writeHooks {
+		fn(database, retentionPolicy, points, partial)
+	}
+}
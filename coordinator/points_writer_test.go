@@ -1,6 +1,7 @@
 package coordinator_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -428,6 +429,59 @@ func TestPointsWriter_WritePoints_Dropped(t *testing.T) {
 	}
 }
 
+// Ensure the write hooks are only called with the points stored by a shard.
+func TestPointsWriter_WritePoints_WriteHook(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.NodeIDFn = func() uint64 { return 1 }
+
+	// The points map to two shards. The write to the second shard fails.
+	pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+	pr.AddPoint("cpu", 1.0, time.Now(), nil)
+	pr.AddPoint("cpu", 2.0, time.Now().Add(time.Hour), nil)
+
+	// The failed write waits for the hook so the write does not return
+	// before the hook is called.
+	hookCalled := make(chan struct{})
+	store := &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error {
+			if points[0] == pr.Points[1] {
+				<-hookCalled
+				return errors.New("write failed")
+			}
+			return nil
+		},
+	}
+
+	var mu sync.Mutex
+	var hooked []models.Point
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.TSDBStore = store
+	c.Node = &influxdb.Node{ID: 1}
+	c.AddWriteHook(func(database, retentionPolicy string, points []models.Point, partial bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if database != "mydb" || retentionPolicy != "myrp" || partial {
+			t.Errorf("unexpected hook call: %s.%s partial=%t", database, retentionPolicy, partial)
+		}
+		hooked = append(hooked, points...)
+		close(hookCalled)
+	})
+
+	c.Open()
+	defer c.Close()
+
+	if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); err == nil {
+		t.Fatal("expected error")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(hooked, pr.Points[:1]) {
+		t.Errorf("unexpected points: got %v, exp %v", hooked, pr.Points[:1])
+	}
+}
+
 type fakePointsWriter struct {
 	WritePointsIntoFn func(*coordinator.IntoWriteRequest) error
 }
@@ -76,6 +76,14 @@ func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources influxql.Sour
 				}
 				a.ShardMap[source] = e.TSDBStore.ShardGroup(shardIDs)
 			}
+		case *influxql.Join:
+			sources := make(influxql.Sources, len(s.Sources))
+			for i, js := range s.Sources {
+				sources[i] = js.Measurement
+			}
+			if err := e.mapShards(a, sources, opt); err != nil {
+				return err
+			}
 		case *influxql.SubQuery:
 			if err := e.mapShards(a, s.Statement.Sources, opt); err != nil {
 				return err
@@ -184,6 +192,35 @@ func (a *LocalShardMapping) CreateIterator(m *influxql.Measurement, opt influxql
 	return sg.CreateIterator(m.Name, opt)
 }
 
+// IteratorCost returns the estimated cost of creating an iterator for the measurement.
+func (a *LocalShardMapping) IteratorCost(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.IteratorCost, error) {
+	source := Source{
+		Database:        m.Database,
+		RetentionPolicy: m.RetentionPolicy,
+	}
+
+	sg := a.ShardMap[source]
+	if sg == nil {
+		return influxql.IteratorCost{}, nil
+	}
+
+	if m.Regex != nil {
+		var costs influxql.IteratorCost
+		for _, measurement := range sg.MeasurementsByRegex(m.Regex.Val) {
+			cost, err := sg.IteratorCost(measurement, opt)
+			if err != nil {
+				return influxql.IteratorCost{}, err
+			}
+			costs = costs.Combine(cost)
+
+			// Every measurement is read from the same shards.
+			costs.NumShards = cost.NumShards
+		}
+		return costs, nil
+	}
+	return sg.IteratorCost(m.Name, opt)
+}
+
 // Close does nothing for a LocalShardMapping.
 func (a *LocalShardMapping) Close() error {
 	return nil
@@ -32,6 +32,10 @@ type StatementExecutor struct {
 	// TaskManager holds the StatementExecutor that handles task-related commands.
 	TaskManager influxql.StatementExecutor
 
+	// ContinuousQuerier holds the StatementExecutor that backfills continuous queries.
+	// If it is nil, continuous queries cannot be backfilled.
+	ContinuousQuerier influxql.StatementExecutor
+
 	// TSDB storage for local node.
 	TSDBStore TSDBStore
 
@@ -61,11 +65,22 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influx
 	var messages []*influxql.Message
 	var err error
 	switch stmt := stmt.(type) {
+	case *influxql.AlterMeasurementStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterMeasurementStatement(stmt, ctx.Database)
 	case *influxql.AlterRetentionPolicyStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeAlterRetentionPolicyStatement(stmt)
+	case *influxql.BackfillContinuousQueryStatement, *influxql.ShowContinuousQueryStatusStatement:
+		// Send backfills and status requests to the continuous query service.
+		if e.ContinuousQuerier == nil {
+			return errors.New("continuous queries are disabled")
+		}
+		return e.ContinuousQuerier.ExecuteStatement(stmt, ctx)
 	case *influxql.CreateContinuousQueryStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
@@ -103,6 +118,11 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influx
 			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropDatabaseStatement(stmt)
+	case *influxql.DropFieldStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropFieldStatement(stmt, ctx.Database)
 	case *influxql.DropMeasurementStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
@@ -133,6 +153,8 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influx
 			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropUserStatement(stmt)
+	case *influxql.ExplainStatement:
+		rows, err = e.executeExplainStatement(stmt, &ctx)
 	case *influxql.GrantStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
@@ -159,12 +181,18 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influx
 		rows, err = e.executeShowDatabasesStatement(stmt, &ctx)
 	case *influxql.ShowDiagnosticsStatement:
 		rows, err = e.executeShowDiagnosticsStatement(stmt)
+	case *influxql.ShowFieldKeyCardinalityStatement:
+		rows, err = e.executeShowFieldKeyCardinalityStatement(stmt)
 	case *influxql.ShowGrantsForUserStatement:
 		rows, err = e.executeShowGrantsForUserStatement(stmt)
+	case *influxql.ShowMeasurementCardinalityStatement:
+		rows, err = e.executeShowMeasurementCardinalityStatement(stmt)
 	case *influxql.ShowMeasurementsStatement:
 		return e.executeShowMeasurementsStatement(stmt, &ctx)
 	case *influxql.ShowRetentionPoliciesStatement:
 		rows, err = e.executeShowRetentionPoliciesStatement(stmt)
+	case *influxql.ShowSeriesCardinalityStatement:
+		rows, err = e.executeShowSeriesCardinalityStatement(stmt)
 	case *influxql.ShowShardsStatement:
 		rows, err = e.executeShowShardsStatement(stmt)
 	case *influxql.ShowShardGroupsStatement:
@@ -175,6 +203,8 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influx
 		rows, err = e.executeShowSubscriptionsStatement(stmt)
 	case *influxql.ShowTagValuesStatement:
 		return e.executeShowTagValues(stmt, &ctx)
+	case *influxql.ShowTagValuesCardinalityStatement:
+		rows, err = e.executeShowTagValuesCardinalityStatement(stmt)
 	case *influxql.ShowUsersStatement:
 		rows, err = e.executeShowUsersStatement(stmt)
 	case *influxql.SetPasswordUserStatement:
@@ -200,6 +230,22 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx influx
 	})
 }
 
+func (e *StatementExecutor) executeAlterMeasurementStatement(stmt *influxql.AlterMeasurementStatement, database string) error {
+	if dbi := e.MetaClient.Database(database); dbi == nil {
+		return influxql.ErrDatabaseNotFound(database)
+	}
+
+	// Locally rename the measurement, tag key or tag value.
+	switch {
+	case stmt.TagValue != "":
+		return e.TSDBStore.RenameTagValue(database, stmt.Name, stmt.TagKey, stmt.TagValue, stmt.NewName)
+	case stmt.TagKey != "":
+		return e.TSDBStore.RenameTagKey(database, stmt.Name, stmt.TagKey, stmt.NewName)
+	default:
+		return e.TSDBStore.RenameMeasurement(database, stmt.Name, stmt.NewName)
+	}
+}
+
 func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *influxql.AlterRetentionPolicyStatement) error {
 	rpu := &meta.RetentionPolicyUpdate{
 		Duration:           stmt.Duration,
@@ -313,6 +359,11 @@ func (e *StatementExecutor) executeDeleteSeriesStatement(stmt *influxql.DeleteSe
 	// Convert "now()" to current time.
 	stmt.Condition = influxql.Reduce(stmt.Condition, &influxql.NowValuer{Now: time.Now().UTC()})
 
+	// Locally delete the values of a single field.
+	if stmt.Field != "" {
+		return e.TSDBStore.DeleteField(database, stmt.Sources, stmt.Field, stmt.Condition)
+	}
+
 	// Locally delete the series.
 	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
 }
@@ -347,6 +398,15 @@ func (e *StatementExecutor) executeDropMeasurementStatement(stmt *influxql.DropM
 	return e.TSDBStore.DeleteMeasurement(database, stmt.Name)
 }
 
+func (e *StatementExecutor) executeDropFieldStatement(stmt *influxql.DropFieldStatement, database string) error {
+	if dbi := e.MetaClient.Database(database); dbi == nil {
+		return influxql.ErrDatabaseNotFound(database)
+	}
+
+	// Locally drop the field.
+	return e.TSDBStore.DeleteField(database, stmt.Sources, stmt.Name, nil)
+}
+
 func (e *StatementExecutor) executeDropSeriesStatement(stmt *influxql.DropSeriesStatement, database string) error {
 	if dbi := e.MetaClient.Database(database); dbi == nil {
 		return influxql.ErrDatabaseNotFound(database)
@@ -397,6 +457,103 @@ func (e *StatementExecutor) executeDropUserStatement(q *influxql.DropUserStateme
 	return e.MetaClient.DropUser(q.Name)
 }
 
+func (e *StatementExecutor) executeExplainStatement(q *influxql.ExplainStatement, ctx *influxql.ExecutionContext) (models.Rows, error) {
+	plan := &influxql.QueryPlan{Analyze: q.Analyze}
+
+	start := time.Now()
+	stmt, ic, opt, err := e.prepareSelectStatement(q.Statement, ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer ic.Close()
+	plan.Statement = stmt
+	plan.AddStage("PLANNING", time.Since(start))
+
+	plan.Shards, err = e.shardIDsByTimeRange(stmt.Sources, opt.MinTime, opt.MaxTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only create the iterators against the underlying shards if the
+	// statement is analyzed. Otherwise, the iterators are only planned.
+	eic := influxql.NewExplainIteratorCreator(ic, q.Analyze)
+	start = time.Now()
+	itrs, err := influxql.Select(stmt, eic, &opt)
+	if err != nil {
+		return nil, err
+	}
+	plan.AddStage("CREATE ITERATORS", time.Since(start))
+
+	if q.Analyze {
+		if e.MaxSelectPointN > 0 {
+			monitor := influxql.PointLimitMonitor(itrs, influxql.DefaultStatsInterval, e.MaxSelectPointN)
+			ctx.Query.Monitor(monitor)
+		}
+
+		// Read every row from the iterators and discard the results.
+		em := influxql.NewEmitter(itrs, stmt.TimeAscending(), ctx.ChunkSize)
+		em.Columns = stmt.ColumnNames()
+		if stmt.Location != nil {
+			em.Location = stmt.Location
+		}
+		em.OmitTime = stmt.OmitTime
+		defer em.Close()
+
+		start = time.Now()
+		for {
+			row, _, err := em.Emit()
+			if err != nil {
+				return nil, err
+			} else if row == nil {
+				break
+			}
+
+			select {
+			case <-ctx.InterruptCh:
+				return nil, influxql.ErrQueryInterrupted
+			default:
+			}
+		}
+		plan.AddStage("EXECUTION", time.Since(start))
+	} else {
+		defer influxql.Iterators(itrs).Close()
+	}
+	plan.Iterators = eic.Iterators()
+
+	lines := plan.Lines()
+	row := &models.Row{
+		Columns: []string{"QUERY PLAN"},
+		Values:  make([][]interface{}, len(lines)),
+	}
+	for i, line := range lines {
+		row.Values[i] = []interface{}{line}
+	}
+	return models.Rows{row}, nil
+}
+
+// shardIDsByTimeRange returns the IDs of the shards that are read by the sources within the time range.
+func (e *StatementExecutor) shardIDsByTimeRange(sources influxql.Sources, min, max time.Time) ([]uint64, error) {
+	set := make(map[uint64]struct{})
+	for _, m := range sources.Measurements() {
+		groups, err := e.MetaClient.ShardGroupsByTimeRange(m.Database, m.RetentionPolicy, min, max)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			for _, sh := range g.Shards {
+				set[sh.ID] = struct{}{}
+			}
+		}
+	}
+
+	ids := make([]uint64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
 func (e *StatementExecutor) executeGrantStatement(stmt *influxql.GrantStatement) error {
 	return e.MetaClient.SetPrivilege(stmt.User, stmt.On, stmt.Privilege)
 }
@@ -524,12 +681,36 @@ func (e *StatementExecutor) executeSelectStatement(stmt *influxql.SelectStatemen
 }
 
 func (e *StatementExecutor) createIterators(stmt *influxql.SelectStatement, ctx *influxql.ExecutionContext) ([]influxql.Iterator, *influxql.SelectStatement, error) {
+	stmt, ic, opt, err := e.prepareSelectStatement(stmt, ctx)
+	if err != nil {
+		return nil, stmt, err
+	}
+	defer ic.Close()
+
+	// Create a set of iterators from a selection.
+	itrs, err := influxql.Select(stmt, ic, &opt)
+	if err != nil {
+		return nil, stmt, err
+	}
+
+	if e.MaxSelectPointN > 0 {
+		monitor := influxql.PointLimitMonitor(itrs, influxql.DefaultStatsInterval, e.MaxSelectPointN)
+		ctx.Query.Monitor(monitor)
+	}
+	return itrs, stmt, nil
+}
+
+// prepareSelectStatement rewrites the statement for execution and maps its
+// sources to the shards that will be read. The returned IteratorCreator must
+// be closed by the caller.
+func (e *StatementExecutor) prepareSelectStatement(stmt *influxql.SelectStatement, ctx *influxql.ExecutionContext) (*influxql.SelectStatement, IteratorCreator, influxql.SelectOptions, error) {
 	// It is important to "stamp" this time so that everywhere we evaluate `now()` in the statement is EXACTLY the same `now`
 	now := time.Now().UTC()
 	opt := influxql.SelectOptions{
 		InterruptCh: ctx.InterruptCh,
 		NodeID:      ctx.ExecutionOptions.NodeID,
 		MaxSeriesN:  e.MaxSelectSeriesN,
+		MaxPointN:   e.MaxSelectPointN,
 		Authorizer:  ctx.Authorizer,
 	}
 
@@ -540,7 +721,7 @@ func (e *StatementExecutor) createIterators(stmt *influxql.SelectStatement, ctx
 	var err error
 	opt.MinTime, opt.MaxTime, err = influxql.TimeRange(stmt.Condition, stmt.Location)
 	if err != nil {
-		return nil, stmt, err
+		return stmt, nil, opt, err
 	}
 
 	if opt.MaxTime.IsZero() {
@@ -558,7 +739,7 @@ func (e *StatementExecutor) createIterators(stmt *influxql.SelectStatement, ctx
 
 	// Rewrite time condition.
 	if err := stmt.RewriteTimeCondition(now); err != nil {
-		return nil, stmt, err
+		return stmt, nil, opt, err
 	}
 
 	// Rewrite any regex conditions that could make use of the index.
@@ -567,21 +748,27 @@ func (e *StatementExecutor) createIterators(stmt *influxql.SelectStatement, ctx
 	// Create an iterator creator based on the shards in the cluster.
 	ic, err := e.ShardMapper.MapShards(stmt.Sources, &opt)
 	if err != nil {
-		return nil, stmt, err
+		return stmt, nil, opt, err
 	}
-	defer ic.Close()
 
 	// Rewrite wildcards, if any exist.
 	tmp, err := stmt.RewriteFields(ic)
 	if err != nil {
-		return nil, stmt, err
+		ic.Close()
+		return stmt, nil, opt, err
 	}
 	stmt = tmp
 
 	if e.MaxSelectBucketsN > 0 && !stmt.IsRawQuery {
 		interval, err := stmt.GroupByInterval()
 		if err != nil {
-			return nil, stmt, err
+			ic.Close()
+			return stmt, nil, opt, err
+		}
+
+		// Overlapping windows start every step.
+		if step := stmt.GroupByStep(); step > 0 {
+			interval = step
 		}
 
 		if interval > 0 {
@@ -592,22 +779,13 @@ func (e *StatementExecutor) createIterators(stmt *influxql.SelectStatement, ctx
 			// Determine the number of buckets by finding the time span and dividing by the interval.
 			buckets := int64(max.Sub(min)) / int64(interval)
 			if int(buckets) > e.MaxSelectBucketsN {
-				return nil, stmt, fmt.Errorf("max-select-buckets limit exceeded: (%d/%d)", buckets, e.MaxSelectBucketsN)
+				ic.Close()
+				return stmt, nil, opt, fmt.Errorf("max-select-buckets limit exceeded: (%d/%d)", buckets, e.MaxSelectBucketsN)
 			}
 		}
 	}
 
-	// Create a set of iterators from a selection.
-	itrs, err := influxql.Select(stmt, ic, &opt)
-	if err != nil {
-		return nil, stmt, err
-	}
-
-	if e.MaxSelectPointN > 0 {
-		monitor := influxql.PointLimitMonitor(itrs, influxql.DefaultStatsInterval, e.MaxSelectPointN)
-		ctx.Query.Monitor(monitor)
-	}
-	return itrs, stmt, nil
+	return stmt, ic, opt, nil
 }
 
 func (e *StatementExecutor) executeShowContinuousQueriesStatement(stmt *influxql.ShowContinuousQueriesStatement) (models.Rows, error) {
@@ -666,6 +844,19 @@ func (e *StatementExecutor) executeShowDiagnosticsStatement(stmt *influxql.ShowD
 	return rows, nil
 }
 
+func (e *StatementExecutor) executeShowFieldKeyCardinalityStatement(stmt *influxql.ShowFieldKeyCardinalityStatement) (models.Rows, error) {
+	if stmt.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	// Field keys are not tracked by a sketch so they are always counted exactly.
+	counts, err := e.TSDBStore.FieldKeyCardinalityByMeasurement(stmt.Database, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+	return measurementCardinalityRows(counts), nil
+}
+
 func (e *StatementExecutor) executeShowGrantsForUserStatement(q *influxql.ShowGrantsForUserStatement) (models.Rows, error) {
 	priv, err := e.MetaClient.UserPrivileges(q.Name)
 	if err != nil {
@@ -679,6 +870,34 @@ func (e *StatementExecutor) executeShowGrantsForUserStatement(q *influxql.ShowGr
 	return []*models.Row{row}, nil
 }
 
+func (e *StatementExecutor) executeShowMeasurementCardinalityStatement(stmt *influxql.ShowMeasurementCardinalityStatement) (models.Rows, error) {
+	if stmt.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	// The sketches cover every measurement in the database so a filtered
+	// statement is always counted exactly.
+	if !stmt.Exact && stmt.Condition == nil {
+		n, err := e.TSDBStore.MeasurementsCardinality(stmt.Database)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.Row{{
+			Columns: []string{"cardinality estimation"},
+			Values:  [][]interface{}{{n}},
+		}}, nil
+	}
+
+	names, err := e.TSDBStore.MeasurementNames(stmt.Database, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+	return []*models.Row{{
+		Columns: []string{"count"},
+		Values:  [][]interface{}{{int64(len(names))}},
+	}}, nil
+}
+
 func (e *StatementExecutor) executeShowMeasurementsStatement(q *influxql.ShowMeasurementsStatement, ctx *influxql.ExecutionContext) error {
 	if q.Database == "" {
 		return ErrDatabaseNameRequired
@@ -744,6 +963,31 @@ func (e *StatementExecutor) executeShowRetentionPoliciesStatement(q *influxql.Sh
 	return []*models.Row{row}, nil
 }
 
+func (e *StatementExecutor) executeShowSeriesCardinalityStatement(stmt *influxql.ShowSeriesCardinalityStatement) (models.Rows, error) {
+	if stmt.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	// The sketches cover every series in the database so a filtered
+	// statement is always counted exactly.
+	if !stmt.Exact && stmt.Condition == nil {
+		n, err := e.TSDBStore.SeriesCardinality(stmt.Database)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.Row{{
+			Columns: []string{"cardinality estimation"},
+			Values:  [][]interface{}{{n}},
+		}}, nil
+	}
+
+	counts, err := e.TSDBStore.SeriesCardinalityByMeasurement(stmt.Database, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+	return measurementCardinalityRows(counts), nil
+}
+
 func (e *StatementExecutor) executeShowShardsStatement(stmt *influxql.ShowShardsStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
 
@@ -914,6 +1158,27 @@ func (e *StatementExecutor) executeShowTagValues(q *influxql.ShowTagValuesStatem
 	return nil
 }
 
+func (e *StatementExecutor) executeShowTagValuesCardinalityStatement(stmt *influxql.ShowTagValuesCardinalityStatement) (models.Rows, error) {
+	if stmt.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	// Tag values are not tracked by a sketch so they are always counted exactly.
+	tagValues, err := e.TSDBStore.TagValues(stmt.Database, stmt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]tsdb.MeasurementCardinality, 0, len(tagValues))
+	for _, m := range tagValues {
+		counts = append(counts, tsdb.MeasurementCardinality{
+			Measurement: m.Measurement,
+			Cardinality: int64(len(m.Values)),
+		})
+	}
+	return measurementCardinalityRows(counts), nil
+}
+
 func (e *StatementExecutor) executeShowUsersStatement(q *influxql.ShowUsersStatement) (models.Rows, error) {
 	row := &models.Row{Columns: []string{"user", "admin"}}
 	for _, ui := range e.MetaClient.Users() {
@@ -922,6 +1187,19 @@ func (e *StatementExecutor) executeShowUsersStatement(q *influxql.ShowUsersState
 	return []*models.Row{row}, nil
 }
 
+// measurementCardinalityRows returns a row with the count for each measurement.
+func measurementCardinalityRows(counts []tsdb.MeasurementCardinality) models.Rows {
+	rows := make(models.Rows, 0, len(counts))
+	for _, c := range counts {
+		rows = append(rows, &models.Row{
+			Name:    c.Measurement,
+			Columns: []string{"count"},
+			Values:  [][]interface{}{{c.Cardinality}},
+		})
+	}
+	return rows
+}
+
 // BufferedPointsWriter adds buffering to a pointsWriter so that SELECT INTO queries
 // write their points to the destination in batches.
 type BufferedPointsWriter struct {
@@ -1094,9 +1372,14 @@ func (e *StatementExecutor) NormalizeStatement(stmt influxql.Statement, defaultD
 			if node.Database == "" {
 				node.Database = defaultDatabase
 			}
+		case *influxql.ShowSeriesCardinalityStatement,
+			*influxql.ShowMeasurementCardinalityStatement,
+			*influxql.ShowTagValuesCardinalityStatement,
+			*influxql.ShowFieldKeyCardinalityStatement:
+			err = e.normalizeCardinalityStatement(node, defaultDatabase)
 		case *influxql.Measurement:
 			switch stmt.(type) {
-			case *influxql.DropSeriesStatement, *influxql.DeleteSeriesStatement:
+			case *influxql.DropSeriesStatement, *influxql.DeleteSeriesStatement, *influxql.DropFieldStatement:
 			// DB and RP not supported by these statements so don't rewrite into invalid
 			// statements
 			default:
@@ -1107,6 +1390,35 @@ func (e *StatementExecutor) NormalizeStatement(stmt influxql.Statement, defaultD
 	return
 }
 
+// normalizeCardinalityStatement adds the default database to a SHOW ... CARDINALITY
+// statement and ensures that the database exists.
+func (e *StatementExecutor) normalizeCardinalityStatement(stmt influxql.Node, defaultDatabase string) error {
+	var database *string
+	switch stmt := stmt.(type) {
+	case *influxql.ShowSeriesCardinalityStatement:
+		database = &stmt.Database
+	case *influxql.ShowMeasurementCardinalityStatement:
+		database = &stmt.Database
+	case *influxql.ShowTagValuesCardinalityStatement:
+		database = &stmt.Database
+	case *influxql.ShowFieldKeyCardinalityStatement:
+		database = &stmt.Database
+	default:
+		return fmt.Errorf("unsupported cardinality statement: %T", stmt)
+	}
+
+	if *database == "" {
+		*database = defaultDatabase
+	}
+	if *database == "" {
+		return ErrDatabaseNameRequired
+	}
+	if e.MetaClient.Database(*database) == nil {
+		return influxdb.ErrDatabaseNotFound(*database)
+	}
+	return nil
+}
+
 func (e *StatementExecutor) normalizeMeasurement(m *influxql.Measurement, defaultDatabase string) error {
 	// Targets (measurements in an INTO clause) can have blank names, which means it will be
 	// the same as the measurement name it came from in the FROM clause.
@@ -1160,10 +1472,20 @@ type TSDBStore interface {
 	DeleteMeasurement(database, name string) error
 	DeleteRetentionPolicy(database, name string) error
 	DeleteSeries(database string, sources []influxql.Source, condition influxql.Expr) error
+	DeleteField(database string, sources []influxql.Source, field string, condition influxql.Expr) error
+
+	RenameMeasurement(database, name, newName string) error
+	RenameTagKey(database, name, key, newKey string) error
+	RenameTagValue(database, name, key, value, newValue string) error
 	DeleteShard(id uint64) error
 
 	MeasurementNames(database string, cond influxql.Expr) ([][]byte, error)
 	TagValues(database string, cond influxql.Expr) ([]tsdb.TagValues, error)
+
+	SeriesCardinality(database string) (int64, error)
+	MeasurementsCardinality(database string) (int64, error)
+	SeriesCardinalityByMeasurement(database string, cond influxql.Expr) ([]tsdb.MeasurementCardinality, error)
+	FieldKeyCardinalityByMeasurement(database string, cond influxql.Expr) ([]tsdb.MeasurementCardinality, error)
 }
 
 var _ TSDBStore = LocalTSDBStore{}
@@ -3,10 +3,12 @@ package coordinator_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -127,6 +129,134 @@ func TestQueryExecutor_ExecuteQuery_MaxSelectBucketsN(t *testing.T) {
 	}
 }
 
+// Ensure query executor can explain a SELECT statement without reading any data.
+func TestQueryExecutor_ExecuteQuery_Explain(t *testing.T) {
+	e := DefaultQueryExecutor()
+
+	e.MetaClient.ShardGroupsByTimeRangeFn = func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
+		return []meta.ShardGroupInfo{
+			{ID: 1, Shards: []meta.ShardInfo{
+				{ID: 100, Owners: []meta.ShardOwner{{NodeID: 0}}},
+			}},
+		}, nil
+	}
+
+	e.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		var sh MockShard
+		sh.CreateIteratorFn = func(m string, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+			t.Fatal("unexpected iterator creation")
+			return nil, nil
+		}
+		sh.IteratorCostFn = func(m string, opt influxql.IteratorOptions) (influxql.IteratorCost, error) {
+			if m != "cpu" {
+				t.Fatalf("unexpected measurement: %s", m)
+			}
+			return influxql.IteratorCost{
+				NumShards:    1,
+				NumTagSets:   2,
+				NumSeries:    4,
+				CachedValues: 10,
+				NumFiles:     3,
+				BlocksRead:   8,
+				BlockSize:    1024,
+			}, nil
+		}
+		sh.FieldDimensionsFn = func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+			return map[string]influxql.DataType{"value": influxql.Float}, map[string]struct{}{"host": struct{}{}}, nil
+		}
+		return &sh
+	}
+
+	a := ReadAllResults(e.ExecuteQuery(`EXPLAIN SELECT mean(value) FROM cpu GROUP BY host`, "db0", 0))
+	if len(a) != 1 {
+		t.Fatalf("unexpected results: %s", spew.Sdump(a))
+	} else if a[0].Err != nil {
+		t.Fatalf("unexpected error: %s", a[0].Err)
+	}
+
+	lines := MustPlanLines(t, a[0])
+	for _, exp := range []string{
+		"EXPLAIN",
+		"├── SHARDS: 100",
+		"    └── CREATE ITERATOR: db0.rp0.cpu",
+		"        ├── EXPRESSION: mean(value::float)",
+		"        ├── DIMENSIONS: host",
+		"        ├── NUMBER OF SHARDS: 1",
+		"        ├── NUMBER OF TAG SETS: 2",
+		"        ├── NUMBER OF SERIES: 4",
+		"        ├── CACHED VALUES: 10",
+		"        ├── NUMBER OF FILES: 3",
+		"        ├── NUMBER OF BLOCKS: 8",
+		"        └── SIZE OF BLOCKS: 1024",
+	} {
+		if !containsString(lines, exp) {
+			t.Errorf("missing line %q in plan:\n%s", exp, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// Ensure query executor can execute a SELECT statement and report its execution statistics.
+func TestQueryExecutor_ExecuteQuery_ExplainAnalyze(t *testing.T) {
+	e := DefaultQueryExecutor()
+
+	e.MetaClient.ShardGroupsByTimeRangeFn = func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
+		return []meta.ShardGroupInfo{
+			{ID: 1, Shards: []meta.ShardInfo{
+				{ID: 100, Owners: []meta.ShardOwner{{NodeID: 0}}},
+			}},
+		}, nil
+	}
+
+	e.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		var sh MockShard
+		sh.CreateIteratorFn = func(m string, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+			return &FloatIterator{
+				Points: []influxql.FloatPoint{
+					{Name: "cpu", Time: int64(0 * time.Second), Aux: []interface{}{float64(100)}},
+					{Name: "cpu", Time: int64(1 * time.Second), Aux: []interface{}{float64(200)}},
+				},
+				stats: influxql.IteratorStats{SeriesN: 1, PointN: 2, BlockN: 1, CachePointN: 1, TSMPointN: 1},
+			}, nil
+		}
+		sh.IteratorCostFn = func(m string, opt influxql.IteratorOptions) (influxql.IteratorCost, error) {
+			return influxql.IteratorCost{NumShards: 1, NumSeries: 1}, nil
+		}
+		sh.FieldDimensionsFn = func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+			return map[string]influxql.DataType{"value": influxql.Float}, nil, nil
+		}
+		return &sh
+	}
+
+	a := ReadAllResults(e.ExecuteQuery(`EXPLAIN ANALYZE SELECT value FROM cpu`, "db0", 0))
+	if len(a) != 1 {
+		t.Fatalf("unexpected results: %s", spew.Sdump(a))
+	} else if a[0].Err != nil {
+		t.Fatalf("unexpected error: %s", a[0].Err)
+	}
+
+	lines := MustPlanLines(t, a[0])
+	for _, exp := range []string{
+		"EXPLAIN ANALYZE",
+		"├── SHARDS: 100",
+		"        ├── AUXILIARY FIELDS: value::float",
+		"        ├── SERIES READ: 1",
+		"        ├── POINTS READ: 2",
+		"        ├── BLOCKS DECODED: 1",
+		"        ├── CACHE HITS: 1",
+		"        └── TSM HITS: 1",
+	} {
+		if !containsString(lines, exp) {
+			t.Errorf("missing line %q in plan:\n%s", exp, strings.Join(lines, "\n"))
+		}
+	}
+
+	for _, stage := range []string{"PLANNING", "CREATE ITERATORS", "EXECUTION"} {
+		if !containsPrefix(lines, "│   ├── "+stage+": ") && !containsPrefix(lines, "│   └── "+stage+": ") {
+			t.Errorf("missing stage %q in plan:\n%s", stage, strings.Join(lines, "\n"))
+		}
+	}
+}
+
 func TestStatementExecutor_NormalizeDropSeries(t *testing.T) {
 	q, err := influxql.ParseQuery("DROP SERIES FROM cpu")
 	if err != nil {
@@ -256,6 +386,151 @@ func TestQueryExecutor_ExecuteQuery_ShowDatabases(t *testing.T) {
 	}
 }
 
+// Ensure ALTER MEASUREMENT renames the measurement, tag key or tag value.
+func TestQueryExecutor_ExecuteQuery_AlterMeasurement(t *testing.T) {
+	var renamed []string
+	qe := influxql.NewQueryExecutor()
+	qe.StatementExecutor = &coordinator.StatementExecutor{
+		MetaClient: &internal.MetaClientMock{
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{Name: name}
+			},
+		},
+		TSDBStore: &TSDBStore{
+			RenameMeasurementFn: func(database, name, newName string) error {
+				renamed = append(renamed, fmt.Sprintf("%s.%s -> %s", database, name, newName))
+				return nil
+			},
+			RenameTagKeyFn: func(database, name, key, newKey string) error {
+				renamed = append(renamed, fmt.Sprintf("%s.%s.%s -> %s", database, name, key, newKey))
+				return nil
+			},
+			RenameTagValueFn: func(database, name, key, value, newValue string) error {
+				renamed = append(renamed, fmt.Sprintf("%s.%s.%s=%s -> %s", database, name, key, value, newValue))
+				return nil
+			},
+		},
+	}
+
+	q, err := influxql.ParseQuery(`ALTER MEASUREMENT cpu RENAME TO cpu_v2; ALTER MEASUREMENT cpu RENAME TAG host TO hostname; ALTER MEASUREMENT cpu RENAME TAG host VALUE 'a' TO 'b'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range ReadAllResults(qe.ExecuteQuery(q, influxql.ExecutionOptions{Database: "db0"}, make(chan struct{}))) {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	}
+	if exp := []string{
+		"db0.cpu -> cpu_v2",
+		"db0.cpu.host -> hostname",
+		"db0.cpu.host=a -> b",
+	}; !reflect.DeepEqual(renamed, exp) {
+		t.Fatalf("unexpected renames: exp %v, got %v", exp, renamed)
+	}
+}
+
+// Ensure SHOW SERIES CARDINALITY is estimated from the sketches unless the
+// exact cardinality is requested or the series are filtered.
+func TestQueryExecutor_ExecuteQuery_ShowSeriesCardinality(t *testing.T) {
+	e := DefaultQueryExecutor()
+	e.TSDBStore.SeriesCardinalityFn = func(database string) (int64, error) {
+		if database != "db0" {
+			t.Fatalf("unexpected database: %s", database)
+		}
+		return 12, nil
+	}
+	e.TSDBStore.SeriesCardinalityByMeasurementFn = func(database string, cond influxql.Expr) ([]tsdb.MeasurementCardinality, error) {
+		if database != "db0" {
+			t.Fatalf("unexpected database: %s", database)
+		} else if cond != nil && cond.String() != `host = 'serverA'` {
+			t.Fatalf("unexpected condition: %s", cond)
+		}
+		return []tsdb.MeasurementCardinality{
+			{Measurement: "cpu", Cardinality: 4},
+			{Measurement: "mem", Cardinality: 2},
+		}, nil
+	}
+
+	for _, tt := range []struct {
+		s   string
+		exp []*models.Row
+	}{
+		{
+			s: `SHOW SERIES CARDINALITY`,
+			exp: []*models.Row{
+				{Columns: []string{"cardinality estimation"}, Values: [][]interface{}{{int64(12)}}},
+			},
+		},
+		{
+			s: `SHOW EXACT SERIES CARDINALITY`,
+			exp: []*models.Row{
+				{Name: "cpu", Columns: []string{"count"}, Values: [][]interface{}{{int64(4)}}},
+				{Name: "mem", Columns: []string{"count"}, Values: [][]interface{}{{int64(2)}}},
+			},
+		},
+		{
+			s: `SHOW SERIES CARDINALITY WHERE host = 'serverA'`,
+			exp: []*models.Row{
+				{Name: "cpu", Columns: []string{"count"}, Values: [][]interface{}{{int64(4)}}},
+				{Name: "mem", Columns: []string{"count"}, Values: [][]interface{}{{int64(2)}}},
+			},
+		},
+	} {
+		a := ReadAllResults(e.ExecuteQuery(tt.s, "db0", 0))
+		if len(a) != 1 {
+			t.Fatalf("%s: unexpected results: %s", tt.s, spew.Sdump(a))
+		} else if a[0].Err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.s, a[0].Err)
+		} else if !reflect.DeepEqual(a[0].Series, models.Rows(tt.exp)) {
+			t.Fatalf("%s: unexpected rows: exp %s, got %s", tt.s, spew.Sdump(tt.exp), spew.Sdump(a[0].Series))
+		}
+	}
+
+	e.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		if name != "db0" {
+			return nil
+		}
+		return DefaultMetaClientDatabaseFn(name)
+	}
+	a := ReadAllResults(e.ExecuteQuery(`SHOW SERIES CARDINALITY ON nodb`, "db0", 0))
+	if len(a) != 1 {
+		t.Fatalf("unexpected results: %s", spew.Sdump(a))
+	} else if a[0].Err == nil || a[0].Err.Error() != `database not found: nodb` {
+		t.Fatalf("unexpected error: %v", a[0].Err)
+	}
+}
+
+// Ensure SHOW TAG VALUES CARDINALITY counts the tag values of each measurement.
+func TestQueryExecutor_ExecuteQuery_ShowTagValuesCardinality(t *testing.T) {
+	e := DefaultQueryExecutor()
+	e.TSDBStore.TagValuesFn = func(database string, cond influxql.Expr) ([]tsdb.TagValues, error) {
+		if database != "db0" {
+			t.Fatalf("unexpected database: %s", database)
+		} else if got, exp := cond.String(), `_tagKey = 'host'`; got != exp {
+			t.Fatalf("unexpected condition: exp %s, got %s", exp, got)
+		}
+		return []tsdb.TagValues{
+			{Measurement: "cpu", Values: []tsdb.KeyValue{{Key: "host", Value: "serverA"}, {Key: "host", Value: "serverB"}}},
+			{Measurement: "mem", Values: []tsdb.KeyValue{{Key: "host", Value: "serverA"}}},
+		}, nil
+	}
+
+	a := ReadAllResults(e.ExecuteQuery(`SHOW TAG VALUES CARDINALITY WITH KEY = host`, "db0", 0))
+	exp := models.Rows{
+		{Name: "cpu", Columns: []string{"count"}, Values: [][]interface{}{{int64(2)}}},
+		{Name: "mem", Columns: []string{"count"}, Values: [][]interface{}{{int64(1)}}},
+	}
+	if len(a) != 1 {
+		t.Fatalf("unexpected results: %s", spew.Sdump(a))
+	} else if a[0].Err != nil {
+		t.Fatalf("unexpected error: %s", a[0].Err)
+	} else if !reflect.DeepEqual(a[0].Series, exp) {
+		t.Fatalf("unexpected rows: exp %s, got %s", spew.Sdump(exp), spew.Sdump(a[0].Series))
+	}
+}
+
 // QueryExecutor is a test wrapper for coordinator.QueryExecutor.
 type QueryExecutor struct {
 	*influxql.QueryExecutor
@@ -322,7 +597,17 @@ type TSDBStore struct {
 	DeleteRetentionPolicyFn func(database, name string) error
 	DeleteShardFn           func(id uint64) error
 	DeleteSeriesFn          func(database string, sources []influxql.Source, condition influxql.Expr) error
+	DeleteFieldFn           func(database string, sources []influxql.Source, field string, condition influxql.Expr) error
+	RenameMeasurementFn     func(database, name, newName string) error
+	RenameTagKeyFn          func(database, name, key, newKey string) error
+	RenameTagValueFn        func(database, name, key, value, newValue string) error
 	ShardGroupFn            func(ids []uint64) tsdb.ShardGroup
+
+	TagValuesFn                        func(database string, cond influxql.Expr) ([]tsdb.TagValues, error)
+	SeriesCardinalityFn                func(database string) (int64, error)
+	MeasurementsCardinalityFn          func(database string) (int64, error)
+	SeriesCardinalityByMeasurementFn   func(database string, cond influxql.Expr) ([]tsdb.MeasurementCardinality, error)
+	FieldKeyCardinalityByMeasurementFn func(database string, cond influxql.Expr) ([]tsdb.MeasurementCardinality, error)
 }
 
 func (s *TSDBStore) CreateShard(database, policy string, shardID uint64, enabled bool) error {
@@ -364,6 +649,22 @@ func (s *TSDBStore) DeleteSeries(database string, sources []influxql.Source, con
 	return s.DeleteSeriesFn(database, sources, condition)
 }
 
+func (s *TSDBStore) DeleteField(database string, sources []influxql.Source, field string, condition influxql.Expr) error {
+	return s.DeleteFieldFn(database, sources, field, condition)
+}
+
+func (s *TSDBStore) RenameMeasurement(database, name, newName string) error {
+	return s.RenameMeasurementFn(database, name, newName)
+}
+
+func (s *TSDBStore) RenameTagKey(database, name, key, newKey string) error {
+	return s.RenameTagKeyFn(database, name, key, newKey)
+}
+
+func (s *TSDBStore) RenameTagValue(database, name, key, value, newValue string) error {
+	return s.RenameTagValueFn(database, name, key, value, newValue)
+}
+
 func (s *TSDBStore) ShardGroup(ids []uint64) tsdb.ShardGroup {
 	return s.ShardGroupFn(ids)
 }
@@ -377,13 +678,33 @@ func (s *TSDBStore) MeasurementNames(database string, cond influxql.Expr) ([][]b
 }
 
 func (s *TSDBStore) TagValues(database string, cond influxql.Expr) ([]tsdb.TagValues, error) {
-	return nil, nil
+	if s.TagValuesFn == nil {
+		return nil, nil
+	}
+	return s.TagValuesFn(database, cond)
+}
+
+func (s *TSDBStore) SeriesCardinality(database string) (int64, error) {
+	return s.SeriesCardinalityFn(database)
+}
+
+func (s *TSDBStore) MeasurementsCardinality(database string) (int64, error) {
+	return s.MeasurementsCardinalityFn(database)
+}
+
+func (s *TSDBStore) SeriesCardinalityByMeasurement(database string, cond influxql.Expr) ([]tsdb.MeasurementCardinality, error) {
+	return s.SeriesCardinalityByMeasurementFn(database, cond)
+}
+
+func (s *TSDBStore) FieldKeyCardinalityByMeasurement(database string, cond influxql.Expr) ([]tsdb.MeasurementCardinality, error) {
+	return s.FieldKeyCardinalityByMeasurementFn(database, cond)
 }
 
 type MockShard struct {
 	Measurements      []string
 	FieldDimensionsFn func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error)
 	CreateIteratorFn  func(m string, opt influxql.IteratorOptions) (influxql.Iterator, error)
+	IteratorCostFn    func(m string, opt influxql.IteratorOptions) (influxql.IteratorCost, error)
 	ExpandSourcesFn   func(sources influxql.Sources) (influxql.Sources, error)
 }
 
@@ -419,6 +740,10 @@ func (sh *MockShard) CreateIterator(measurement string, opt influxql.IteratorOpt
 	return sh.CreateIteratorFn(measurement, opt)
 }
 
+func (sh *MockShard) IteratorCost(measurement string, opt influxql.IteratorOptions) (influxql.IteratorCost, error) {
+	return sh.IteratorCostFn(measurement, opt)
+}
+
 func (sh *MockShard) ExpandSources(sources influxql.Sources) (influxql.Sources, error) {
 	return sh.ExpandSourcesFn(sources)
 }
@@ -432,6 +757,39 @@ func MustParseQuery(s string) *influxql.Query {
 	return q
 }
 
+// MustPlanLines returns the lines of the plan returned by an EXPLAIN statement.
+func MustPlanLines(tb testing.TB, result *influxql.Result) []string {
+	if len(result.Series) != 1 {
+		tb.Fatalf("unexpected series count: %d", len(result.Series))
+	} else if row := result.Series[0]; !reflect.DeepEqual(row.Columns, []string{"QUERY PLAN"}) {
+		tb.Fatalf("unexpected columns: %v", row.Columns)
+	}
+
+	lines := make([]string, len(result.Series[0].Values))
+	for i, values := range result.Series[0].Values {
+		lines[i] = values[0].(string)
+	}
+	return lines
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPrefix(a []string, prefix string) bool {
+	for _, v := range a {
+		if strings.HasPrefix(v, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // ReadAllResults reads all results from c and returns as a slice.
 func ReadAllResults(c <-chan *influxql.Result) []*influxql.Result {
 	var a []*influxql.Result
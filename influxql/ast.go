@@ -121,72 +121,85 @@ type Node interface {
 func (*Query) node()     {}
 func (Statements) node() {}
 
-func (*AlterRetentionPolicyStatement) node()  {}
-func (*CreateContinuousQueryStatement) node() {}
-func (*CreateDatabaseStatement) node()        {}
-func (*CreateRetentionPolicyStatement) node() {}
-func (*CreateSubscriptionStatement) node()    {}
-func (*CreateUserStatement) node()            {}
-func (*Distinct) node()                       {}
-func (*DeleteSeriesStatement) node()          {}
-func (*DeleteStatement) node()                {}
-func (*DropContinuousQueryStatement) node()   {}
-func (*DropDatabaseStatement) node()          {}
-func (*DropMeasurementStatement) node()       {}
-func (*DropRetentionPolicyStatement) node()   {}
-func (*DropSeriesStatement) node()            {}
-func (*DropShardStatement) node()             {}
-func (*DropSubscriptionStatement) node()      {}
-func (*DropUserStatement) node()              {}
-func (*GrantStatement) node()                 {}
-func (*GrantAdminStatement) node()            {}
-func (*KillQueryStatement) node()             {}
-func (*RevokeStatement) node()                {}
-func (*RevokeAdminStatement) node()           {}
-func (*SelectStatement) node()                {}
-func (*SetPasswordUserStatement) node()       {}
-func (*ShowContinuousQueriesStatement) node() {}
-func (*ShowGrantsForUserStatement) node()     {}
-func (*ShowDatabasesStatement) node()         {}
-func (*ShowFieldKeysStatement) node()         {}
-func (*ShowRetentionPoliciesStatement) node() {}
-func (*ShowMeasurementsStatement) node()      {}
-func (*ShowQueriesStatement) node()           {}
-func (*ShowSeriesStatement) node()            {}
-func (*ShowShardGroupsStatement) node()       {}
-func (*ShowShardsStatement) node()            {}
-func (*ShowStatsStatement) node()             {}
-func (*ShowSubscriptionsStatement) node()     {}
-func (*ShowDiagnosticsStatement) node()       {}
-func (*ShowTagKeysStatement) node()           {}
-func (*ShowTagValuesStatement) node()         {}
-func (*ShowUsersStatement) node()             {}
-
-func (*BinaryExpr) node()      {}
-func (*BooleanLiteral) node()  {}
-func (*Call) node()            {}
-func (*Dimension) node()       {}
-func (Dimensions) node()       {}
-func (*DurationLiteral) node() {}
-func (*IntegerLiteral) node()  {}
-func (*Field) node()           {}
-func (Fields) node()           {}
-func (*Measurement) node()     {}
-func (Measurements) node()     {}
-func (*nilLiteral) node()      {}
-func (*NumberLiteral) node()   {}
-func (*ParenExpr) node()       {}
-func (*RegexLiteral) node()    {}
-func (*ListLiteral) node()     {}
-func (*SortField) node()       {}
-func (SortFields) node()       {}
-func (Sources) node()          {}
-func (*StringLiteral) node()   {}
-func (*SubQuery) node()        {}
-func (*Target) node()          {}
-func (*TimeLiteral) node()     {}
-func (*VarRef) node()          {}
-func (*Wildcard) node()        {}
+func (*AlterMeasurementStatement) node()           {}
+func (*AlterRetentionPolicyStatement) node()       {}
+func (*BackfillContinuousQueryStatement) node()    {}
+func (*CreateContinuousQueryStatement) node()      {}
+func (*CreateDatabaseStatement) node()             {}
+func (*CreateRetentionPolicyStatement) node()      {}
+func (*CreateSubscriptionStatement) node()         {}
+func (*CreateUserStatement) node()                 {}
+func (*Distinct) node()                            {}
+func (*DeleteSeriesStatement) node()               {}
+func (*DeleteStatement) node()                     {}
+func (*DropContinuousQueryStatement) node()        {}
+func (*DropDatabaseStatement) node()               {}
+func (*DropFieldStatement) node()                  {}
+func (*DropMeasurementStatement) node()            {}
+func (*DropRetentionPolicyStatement) node()        {}
+func (*DropSeriesStatement) node()                 {}
+func (*DropShardStatement) node()                  {}
+func (*DropSubscriptionStatement) node()           {}
+func (*DropUserStatement) node()                   {}
+func (*ExplainStatement) node()                    {}
+func (*GrantStatement) node()                      {}
+func (*GrantAdminStatement) node()                 {}
+func (*KillQueryStatement) node()                  {}
+func (*RevokeStatement) node()                     {}
+func (*RevokeAdminStatement) node()                {}
+func (*SelectStatement) node()                     {}
+func (*SetPasswordUserStatement) node()            {}
+func (*ShowContinuousQueriesStatement) node()      {}
+func (*ShowContinuousQueryStatusStatement) node()  {}
+func (*ShowGrantsForUserStatement) node()          {}
+func (*ShowDatabasesStatement) node()              {}
+func (*ShowFieldKeyCardinalityStatement) node()    {}
+func (*ShowFieldKeysStatement) node()              {}
+func (*ShowRetentionPoliciesStatement) node()      {}
+func (*ShowMeasurementCardinalityStatement) node() {}
+func (*ShowMeasurementsStatement) node()           {}
+func (*ShowQueriesStatement) node()                {}
+func (*ShowSeriesStatement) node()                 {}
+func (*ShowSeriesCardinalityStatement) node()      {}
+func (*ShowShardGroupsStatement) node()            {}
+func (*ShowShardsStatement) node()                 {}
+func (*ShowStatsStatement) node()                  {}
+func (*ShowSubscriptionsStatement) node()          {}
+func (*ShowDiagnosticsStatement) node()            {}
+func (*ShowTagKeysStatement) node()                {}
+func (*ShowTagValuesStatement) node()              {}
+func (*ShowTagValuesCardinalityStatement) node()   {}
+func (*ShowUsersStatement) node()                  {}
+
+func (*BinaryExpr) node()              {}
+func (*BooleanLiteral) node()          {}
+func (*Call) node()                    {}
+func (*CalendarDurationLiteral) node() {}
+func (*CaseExpr) node()                {}
+func (*Dimension) node()               {}
+func (Dimensions) node()               {}
+func (*DurationLiteral) node()         {}
+func (*IntegerLiteral) node()          {}
+func (*Field) node()                   {}
+func (*Join) node()                    {}
+func (Fields) node()                   {}
+func (*Measurement) node()             {}
+func (Measurements) node()             {}
+func (*nilLiteral) node()              {}
+func (*NumberLiteral) node()           {}
+func (*ParenExpr) node()               {}
+func (*RegexLiteral) node()            {}
+func (*ListLiteral) node()             {}
+func (*SortField) node()               {}
+func (SortFields) node()               {}
+func (Sources) node()                  {}
+func (*StringLiteral) node()           {}
+func (*SubQuery) node()                {}
+func (*Target) node()                  {}
+func (*TimeLiteral) node()             {}
+func (*VarRef) node()                  {}
+func (*WhenClause) node()              {}
+func (*Wildcard) node()                {}
 
 // Query represents a collection of ordered statements.
 type Query struct {
@@ -242,45 +255,54 @@ type ExecutionPrivilege struct {
 // ExecutionPrivileges is a list of privileges required to execute a statement.
 type ExecutionPrivileges []ExecutionPrivilege
 
-func (*AlterRetentionPolicyStatement) stmt()  {}
-func (*CreateContinuousQueryStatement) stmt() {}
-func (*CreateDatabaseStatement) stmt()        {}
-func (*CreateRetentionPolicyStatement) stmt() {}
-func (*CreateSubscriptionStatement) stmt()    {}
-func (*CreateUserStatement) stmt()            {}
-func (*DeleteSeriesStatement) stmt()          {}
-func (*DeleteStatement) stmt()                {}
-func (*DropContinuousQueryStatement) stmt()   {}
-func (*DropDatabaseStatement) stmt()          {}
-func (*DropMeasurementStatement) stmt()       {}
-func (*DropRetentionPolicyStatement) stmt()   {}
-func (*DropSeriesStatement) stmt()            {}
-func (*DropSubscriptionStatement) stmt()      {}
-func (*DropUserStatement) stmt()              {}
-func (*GrantStatement) stmt()                 {}
-func (*GrantAdminStatement) stmt()            {}
-func (*KillQueryStatement) stmt()             {}
-func (*ShowContinuousQueriesStatement) stmt() {}
-func (*ShowGrantsForUserStatement) stmt()     {}
-func (*ShowDatabasesStatement) stmt()         {}
-func (*ShowFieldKeysStatement) stmt()         {}
-func (*ShowMeasurementsStatement) stmt()      {}
-func (*ShowQueriesStatement) stmt()           {}
-func (*ShowRetentionPoliciesStatement) stmt() {}
-func (*ShowSeriesStatement) stmt()            {}
-func (*ShowShardGroupsStatement) stmt()       {}
-func (*ShowShardsStatement) stmt()            {}
-func (*ShowStatsStatement) stmt()             {}
-func (*DropShardStatement) stmt()             {}
-func (*ShowSubscriptionsStatement) stmt()     {}
-func (*ShowDiagnosticsStatement) stmt()       {}
-func (*ShowTagKeysStatement) stmt()           {}
-func (*ShowTagValuesStatement) stmt()         {}
-func (*ShowUsersStatement) stmt()             {}
-func (*RevokeStatement) stmt()                {}
-func (*RevokeAdminStatement) stmt()           {}
-func (*SelectStatement) stmt()                {}
-func (*SetPasswordUserStatement) stmt()       {}
+func (*AlterMeasurementStatement) stmt()           {}
+func (*AlterRetentionPolicyStatement) stmt()       {}
+func (*BackfillContinuousQueryStatement) stmt()    {}
+func (*CreateContinuousQueryStatement) stmt()      {}
+func (*CreateDatabaseStatement) stmt()             {}
+func (*CreateRetentionPolicyStatement) stmt()      {}
+func (*CreateSubscriptionStatement) stmt()         {}
+func (*CreateUserStatement) stmt()                 {}
+func (*DeleteSeriesStatement) stmt()               {}
+func (*DeleteStatement) stmt()                     {}
+func (*DropContinuousQueryStatement) stmt()        {}
+func (*DropDatabaseStatement) stmt()               {}
+func (*DropFieldStatement) stmt()                  {}
+func (*DropMeasurementStatement) stmt()            {}
+func (*DropRetentionPolicyStatement) stmt()        {}
+func (*DropSeriesStatement) stmt()                 {}
+func (*DropSubscriptionStatement) stmt()           {}
+func (*DropUserStatement) stmt()                   {}
+func (*ExplainStatement) stmt()                    {}
+func (*GrantStatement) stmt()                      {}
+func (*GrantAdminStatement) stmt()                 {}
+func (*KillQueryStatement) stmt()                  {}
+func (*ShowContinuousQueriesStatement) stmt()      {}
+func (*ShowContinuousQueryStatusStatement) stmt()  {}
+func (*ShowGrantsForUserStatement) stmt()          {}
+func (*ShowDatabasesStatement) stmt()              {}
+func (*ShowFieldKeyCardinalityStatement) stmt()    {}
+func (*ShowFieldKeysStatement) stmt()              {}
+func (*ShowMeasurementCardinalityStatement) stmt() {}
+func (*ShowMeasurementsStatement) stmt()           {}
+func (*ShowQueriesStatement) stmt()                {}
+func (*ShowRetentionPoliciesStatement) stmt()      {}
+func (*ShowSeriesStatement) stmt()                 {}
+func (*ShowSeriesCardinalityStatement) stmt()      {}
+func (*ShowShardGroupsStatement) stmt()            {}
+func (*ShowShardsStatement) stmt()                 {}
+func (*ShowStatsStatement) stmt()                  {}
+func (*DropShardStatement) stmt()                  {}
+func (*ShowSubscriptionsStatement) stmt()          {}
+func (*ShowDiagnosticsStatement) stmt()            {}
+func (*ShowTagKeysStatement) stmt()                {}
+func (*ShowTagValuesStatement) stmt()              {}
+func (*ShowTagValuesCardinalityStatement) stmt()   {}
+func (*ShowUsersStatement) stmt()                  {}
+func (*RevokeStatement) stmt()                     {}
+func (*RevokeAdminStatement) stmt()                {}
+func (*SelectStatement) stmt()                     {}
+func (*SetPasswordUserStatement) stmt()            {}
 
 // Expr represents an expression that can be evaluated to a value.
 type Expr interface {
@@ -290,21 +312,23 @@ type Expr interface {
 	expr()
 }
 
-func (*BinaryExpr) expr()      {}
-func (*BooleanLiteral) expr()  {}
-func (*Call) expr()            {}
-func (*Distinct) expr()        {}
-func (*DurationLiteral) expr() {}
-func (*IntegerLiteral) expr()  {}
-func (*nilLiteral) expr()      {}
-func (*NumberLiteral) expr()   {}
-func (*ParenExpr) expr()       {}
-func (*RegexLiteral) expr()    {}
-func (*ListLiteral) expr()     {}
-func (*StringLiteral) expr()   {}
-func (*TimeLiteral) expr()     {}
-func (*VarRef) expr()          {}
-func (*Wildcard) expr()        {}
+func (*BinaryExpr) expr()              {}
+func (*BooleanLiteral) expr()          {}
+func (*Call) expr()                    {}
+func (*CalendarDurationLiteral) expr() {}
+func (*CaseExpr) expr()                {}
+func (*Distinct) expr()                {}
+func (*DurationLiteral) expr()         {}
+func (*IntegerLiteral) expr()          {}
+func (*nilLiteral) expr()              {}
+func (*NumberLiteral) expr()           {}
+func (*ParenExpr) expr()               {}
+func (*RegexLiteral) expr()            {}
+func (*ListLiteral) expr()             {}
+func (*StringLiteral) expr()           {}
+func (*TimeLiteral) expr()             {}
+func (*VarRef) expr()                  {}
+func (*Wildcard) expr()                {}
 
 // Literal represents a static literal.
 type Literal interface {
@@ -314,15 +338,16 @@ type Literal interface {
 	literal()
 }
 
-func (*BooleanLiteral) literal()  {}
-func (*DurationLiteral) literal() {}
-func (*IntegerLiteral) literal()  {}
-func (*nilLiteral) literal()      {}
-func (*NumberLiteral) literal()   {}
-func (*RegexLiteral) literal()    {}
-func (*ListLiteral) literal()     {}
-func (*StringLiteral) literal()   {}
-func (*TimeLiteral) literal()     {}
+func (*BooleanLiteral) literal()          {}
+func (*CalendarDurationLiteral) literal() {}
+func (*DurationLiteral) literal()         {}
+func (*IntegerLiteral) literal()          {}
+func (*nilLiteral) literal()              {}
+func (*NumberLiteral) literal()           {}
+func (*RegexLiteral) literal()            {}
+func (*ListLiteral) literal()             {}
+func (*StringLiteral) literal()           {}
+func (*TimeLiteral) literal()             {}
 
 // Source represents a source of data for a statement.
 type Source interface {
@@ -332,6 +357,7 @@ type Source interface {
 	source()
 }
 
+func (*Join) source()        {}
 func (*Measurement) source() {}
 func (*SubQuery) source()    {}
 
@@ -359,6 +385,12 @@ func (a Sources) Filter(database, retentionPolicy string) []Source {
 			if s.Database == database && s.RetentionPolicy == retentionPolicy {
 				sources = append(sources, s)
 			}
+		case *Join:
+			for _, js := range s.Sources {
+				if js.Measurement.Database == database && js.Measurement.RetentionPolicy == retentionPolicy {
+					sources = append(sources, js.Measurement)
+				}
+			}
 		case *SubQuery:
 			filteredSources := s.Statement.Sources.Filter(database, retentionPolicy)
 			sources = append(sources, filteredSources...)
@@ -375,6 +407,22 @@ func (a Sources) HasSystemSource() bool {
 			if IsSystemName(s.Name) {
 				return true
 			}
+		case *Join:
+			for _, js := range s.Sources {
+				if IsSystemName(js.Measurement.Name) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// HasJoin returns true if any of the sources are joins.
+func (a Sources) HasJoin() bool {
+	for _, s := range a {
+		if _, ok := s.(*Join); ok {
+			return true
 		}
 	}
 	return false
@@ -415,6 +463,10 @@ func (a Sources) Measurements() []*Measurement {
 		switch src := src.(type) {
 		case *Measurement:
 			mms = append(mms, src)
+		case *Join:
+			for _, js := range src.Sources {
+				mms = append(mms, js.Measurement)
+			}
 		case *SubQuery:
 			mms = append(mms, src.Statement.Sources.Measurements()...)
 		}
@@ -476,7 +528,7 @@ type SortField struct {
 func (field *SortField) String() string {
 	var buf bytes.Buffer
 	if field.Name != "" {
-		_, _ = buf.WriteString(field.Name)
+		_, _ = buf.WriteString(QuoteIdent(field.Name))
 		_, _ = buf.WriteString(" ")
 	}
 	if field.Ascending {
@@ -890,6 +942,52 @@ func (s *CreateRetentionPolicyStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// AlterMeasurementStatement represents a command to rename a measurement or
+// a tag key or tag value of a measurement.
+type AlterMeasurementStatement struct {
+	// Name of the measurement to alter.
+	Name string
+
+	// Tag key that is renamed or whose value is renamed (optional).
+	TagKey string
+
+	// Tag value that is renamed (optional).
+	TagValue string
+
+	// New name of the measurement, tag key or tag value.
+	NewName string
+}
+
+// String returns a string representation of the alter measurement statement.
+func (s *AlterMeasurementStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("ALTER MEASUREMENT ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" RENAME")
+
+	if s.TagKey != "" {
+		_, _ = buf.WriteString(" TAG ")
+		_, _ = buf.WriteString(QuoteIdent(s.TagKey))
+	}
+
+	if s.TagValue != "" {
+		_, _ = buf.WriteString(" VALUE ")
+		_, _ = buf.WriteString(QuoteString(s.TagValue))
+		_, _ = buf.WriteString(" TO ")
+		_, _ = buf.WriteString(QuoteString(s.NewName))
+	} else {
+		_, _ = buf.WriteString(" TO ")
+		_, _ = buf.WriteString(QuoteIdent(s.NewName))
+	}
+
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an AlterMeasurementStatement.
+func (s *AlterMeasurementStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // AlterRetentionPolicyStatement represents a command to alter an existing retention policy.
 type AlterRetentionPolicyStatement struct {
 	// Name of policy to alter.
@@ -984,6 +1082,9 @@ type SelectStatement struct {
 	// An expression evaluated on data point.
 	Condition Expr
 
+	// An expression evaluated on each row after aggregation.
+	Having Expr
+
 	// Fields to sort results by.
 	SortFields SortFields
 
@@ -1011,6 +1112,10 @@ type SelectStatement struct {
 	// The value to fill empty aggregate buckets with, if any.
 	FillValue interface{}
 
+	// Whether fill(previous) and fill(linear) use the last point before the
+	// time range of the query to fill the first buckets.
+	FillLookback bool
+
 	// The timezone for the query, if any.
 	Location *time.Location
 
@@ -1066,7 +1171,29 @@ func (s *SelectStatement) HasSelector() bool {
 
 // TimeAscending returns true if the time field is sorted in chronological order.
 func (s *SelectStatement) TimeAscending() bool {
-	return len(s.SortFields) == 0 || s.SortFields[0].Ascending
+	for _, field := range s.SortFields {
+		if s.isTimeSortField(field) {
+			return field.Ascending
+		}
+	}
+	return true
+}
+
+// SortsAcrossSeries returns true if the results are sorted by a field or a
+// tag. The rows of every series are then sorted together instead of each
+// series being sorted by time.
+func (s *SelectStatement) SortsAcrossSeries() bool {
+	for _, field := range s.SortFields {
+		if !s.isTimeSortField(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTimeSortField returns true if the sort field refers to the time.
+func (s *SelectStatement) isTimeSortField(field *SortField) bool {
+	return field.Name == "" || field.Name == "time" || field.Name == s.TimeFieldName()
 }
 
 // TimeFieldName returns the name of the time field.
@@ -1085,6 +1212,7 @@ func (s *SelectStatement) Clone() *SelectStatement {
 	clone.Sources = cloneSources(s.Sources)
 	clone.SortFields = make(SortFields, 0, len(s.SortFields))
 	clone.Condition = CloneExpr(s.Condition)
+	clone.Having = CloneExpr(s.Having)
 
 	if s.Target != nil {
 		clone.Target = &Target{
@@ -1100,7 +1228,7 @@ func (s *SelectStatement) Clone() *SelectStatement {
 		clone.Fields = append(clone.Fields, &Field{Expr: CloneExpr(f.Expr), Alias: f.Alias})
 	}
 	for _, d := range s.Dimensions {
-		clone.Dimensions = append(clone.Dimensions, &Dimension{Expr: CloneExpr(d.Expr)})
+		clone.Dimensions = append(clone.Dimensions, &Dimension{Expr: CloneExpr(d.Expr), Alias: d.Alias})
 	}
 	for _, f := range s.SortFields {
 		clone.SortFields = append(clone.SortFields, &SortField{Name: f.Name, Ascending: f.Ascending})
@@ -1128,6 +1256,19 @@ func cloneSource(s Source) Source {
 			m.Regex = &RegexLiteral{Val: regexp.MustCompile(s.Regex.Val.String())}
 		}
 		return m
+	case *Join:
+		other := &Join{
+			Sources: make([]*JoinSource, len(s.Sources)),
+			On:      make([]string, len(s.On)),
+		}
+		for i, js := range s.Sources {
+			other.Sources[i] = &JoinSource{
+				Measurement: cloneSource(js.Measurement).(*Measurement),
+				Alias:       js.Alias,
+			}
+		}
+		copy(other.On, s.On)
+		return other
 	case *SubQuery:
 		return &SubQuery{Statement: s.Statement.Clone()}
 	default:
@@ -1176,6 +1317,10 @@ func (s *SelectStatement) RewriteFields(m FieldMapper) (*SelectStatement, error)
 	hasFieldWildcard := other.HasFieldWildcard()
 	hasDimensionWildcard := other.HasDimensionWildcard()
 	if !hasFieldWildcard && !hasDimensionWildcard {
+		if err := other.rewriteHistograms(); err != nil {
+			return nil, err
+		}
+		other.rewriteLinearRegressions()
 		return other, nil
 	}
 
@@ -1188,11 +1333,8 @@ func (s *SelectStatement) RewriteFields(m FieldMapper) (*SelectStatement, error)
 	if !hasDimensionWildcard {
 		// Remove the dimensions present in the group by so they don't get added as fields.
 		for _, d := range other.Dimensions {
-			switch expr := d.Expr.(type) {
-			case *VarRef:
-				if _, ok := dimensionSet[expr.Val]; ok {
-					delete(dimensionSet, expr.Val)
-				}
+			if key, ok := d.TagKey(); ok {
+				delete(dimensionSet, key)
 			}
 		}
 	}
@@ -1278,7 +1420,7 @@ func (s *SelectStatement) RewriteFields(m FieldMapper) (*SelectStatement, error)
 
 				// Add additional types for certain functions.
 				switch call.Name {
-				case "count", "first", "last", "distinct", "elapsed", "mode", "sample":
+				case "count", "count_distinct_approx", "first", "last", "distinct", "elapsed", "mode", "sample":
 					supportedTypes[String] = struct{}{}
 					fallthrough
 				case "min", "max":
@@ -1353,9 +1495,118 @@ func (s *SelectStatement) RewriteFields(m FieldMapper) (*SelectStatement, error)
 		other.Dimensions = rwDimensions
 	}
 
+	if err := other.rewriteHistograms(); err != nil {
+		return nil, err
+	}
+	other.rewriteLinearRegressions()
 	return other, nil
 }
 
+// rewriteHistograms expands every histogram() field into one field per
+// bucket. Each field counts the values within a single bucket and is named
+// after the alias or the field of the histogram and the lower bound of the
+// bucket.
+func (s *SelectStatement) rewriteHistograms() error {
+	var rwFields Fields
+	for i, f := range s.Fields {
+		call, ok := f.Expr.(*Call)
+		if !ok || call.Name != "histogram" || len(call.Args) != 2 {
+			if rwFields != nil {
+				rwFields = append(rwFields, f)
+			}
+			continue
+		}
+
+		spec, ok := call.Args[1].(*Call)
+		if !ok {
+			return errors.New("expected bucket argument in histogram()")
+		}
+		bounds, err := histogramBounds(spec)
+		if err != nil {
+			return err
+		}
+
+		if rwFields == nil {
+			rwFields = make(Fields, i, len(s.Fields)+len(bounds)-2)
+			copy(rwFields, s.Fields[:i])
+		}
+		name := f.Name()
+		if ref, ok := call.Args[0].(*VarRef); ok && f.Alias == "" {
+			name = fmt.Sprintf("%s_%s", name, ref.Val)
+		}
+		for j := 0; j < len(bounds)-1; j++ {
+			rwFields = append(rwFields, &Field{
+				Expr: &Call{
+					Name: "histogram",
+					Args: []Expr{
+						CloneExpr(call.Args[0]),
+						&NumberLiteral{Val: bounds[j]},
+						&NumberLiteral{Val: bounds[j+1]},
+					},
+				},
+				Alias: fmt.Sprintf("%s_%s", name, strconv.FormatFloat(bounds[j], 'f', -1, 64)),
+			})
+		}
+	}
+
+	if rwFields != nil {
+		s.Fields = rwFields
+	}
+	return nil
+}
+
+// linearRegressionStats are the statistics computed by a linear_regression()
+// call in the order the fields are expanded.
+var linearRegressionStats = []string{"slope", "intercept", "r2"}
+
+// isLinearRegressionStat returns true if name is a statistic of a linear regression.
+func isLinearRegressionStat(name string) bool {
+	for _, stat := range linearRegressionStats {
+		if name == stat {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteLinearRegressions expands every linear_regression() field into one
+// field per statistic of the regression. Each field is named after the
+// statistic it computes.
+func (s *SelectStatement) rewriteLinearRegressions() {
+	var rwFields Fields
+	for i, f := range s.Fields {
+		call, ok := f.Expr.(*Call)
+		if !ok || call.Name != "linear_regression" || len(call.Args) != 1 {
+			if rwFields != nil {
+				rwFields = append(rwFields, f)
+			}
+			continue
+		}
+
+		if rwFields == nil {
+			rwFields = make(Fields, i, len(s.Fields)+len(linearRegressionStats)-1)
+			copy(rwFields, s.Fields[:i])
+		}
+		name := f.Name()
+		for _, stat := range linearRegressionStats {
+			rwFields = append(rwFields, &Field{
+				Expr: &Call{
+					Name: "linear_regression",
+					Args: []Expr{
+						CloneExpr(call.Args[0]),
+						&StringLiteral{Val: stat},
+					},
+				},
+				Alias: fmt.Sprintf("%s_%s", name, stat),
+			})
+		}
+	}
+
+	if rwFields != nil {
+		s.Fields = rwFields
+	}
+}
+
 // RewriteRegexConditions rewrites regex conditions to make better use of the
 // database index.
 //
@@ -1659,9 +1910,21 @@ func (s *SelectStatement) String() string {
 	case NumberFill:
 		_, _ = buf.WriteString(fmt.Sprintf(" fill(%v)", s.FillValue))
 	case LinearFill:
-		_, _ = buf.WriteString(" fill(linear)")
+		if s.FillLookback {
+			_, _ = buf.WriteString(" fill(linear, lookback)")
+		} else {
+			_, _ = buf.WriteString(" fill(linear)")
+		}
 	case PreviousFill:
-		_, _ = buf.WriteString(" fill(previous)")
+		if s.FillLookback {
+			_, _ = buf.WriteString(" fill(previous, lookback)")
+		} else {
+			_, _ = buf.WriteString(" fill(previous)")
+		}
+	}
+	if s.Having != nil {
+		_, _ = buf.WriteString(" HAVING ")
+		_, _ = buf.WriteString(s.Having.String())
 	}
 	if len(s.SortFields) > 0 {
 		_, _ = buf.WriteString(" ORDER BY ")
@@ -1700,6 +1963,13 @@ func (s *SelectStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 				Name:      source.Database,
 				Privilege: ReadPrivilege,
 			})
+		case *Join:
+			for _, js := range source.Sources {
+				ep = append(ep, ExecutionPrivilege{
+					Name:      js.Measurement.Database,
+					Privilege: ReadPrivilege,
+				})
+			}
 		case *SubQuery:
 			privs, err := source.Statement.RequiredPrivileges()
 			if err != nil {
@@ -1759,6 +2029,10 @@ func (s *SelectStatement) validate(tr targetRequirement) error {
 		return err
 	}
 
+	if err := s.validateJoin(); err != nil {
+		return err
+	}
+
 	if err := s.validateDistinct(); err != nil {
 		return err
 	}
@@ -1775,9 +2049,50 @@ func (s *SelectStatement) validate(tr targetRequirement) error {
 		return err
 	}
 
+	if err := s.validateHaving(); err != nil {
+		return err
+	}
+
+	if err := s.validateSortFields(tr); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// validateSortFields ensures that a subquery is only sorted by time because
+// the outer query reads its rows in the order of their series and time. The
+// names of the sort fields are resolved when the statement is executed in the
+// same way as the HAVING clause.
+func (s *SelectStatement) validateSortFields(tr targetRequirement) error {
+	if tr == targetSubquery && s.SortsAcrossSeries() {
+		return errors.New("cannot ORDER BY a field or tag in a subquery")
+	}
+	return nil
+}
+
+// validateHaving ensures that the HAVING clause is only used with aggregates.
+// The names referenced by the clause are resolved when the statement is
+// executed because they depend on the rewritten fields and dimensions.
+func (s *SelectStatement) validateHaving() error {
+	if s.Having == nil {
+		return nil
+	} else if s.IsRawQuery {
+		return errors.New("HAVING requires at least one aggregate function")
+	}
+
+	var err error
+	WalkFunc(s.Having, func(n Node) {
+		if err != nil {
+			return
+		}
+		if ref, ok := n.(*VarRef); ok && strings.ToLower(ref.Val) == "time" {
+			err = errors.New("cannot use time in HAVING clause")
+		}
+	})
+	return err
+}
+
 func (s *SelectStatement) validateFields() error {
 	ns := s.NamesInSelect()
 	if len(ns) == 1 && ns[0] == "time" {
@@ -1791,29 +2106,231 @@ func (s *SelectStatement) validateFields() error {
 				return err
 			}
 		}
+
+		var err error
+		WalkFunc(f.Expr, func(n Node) {
+			if err != nil {
+				return
+			}
+			switch n := n.(type) {
+			case *Call:
+				if isMathFunction(n) {
+					err = validateMathFunction(n)
+				}
+			case *CaseExpr:
+				err = validateCaseExpr(n)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if err := validateMathArgs(f.Expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCaseExpr ensures that a CASE expression can be evaluated on each
+// point. Aggregate functions are not allowed within the expression.
+func validateCaseExpr(expr *CaseExpr) error {
+	var err error
+	WalkFunc(expr, func(n Node) {
+		if err != nil {
+			return
+		}
+		switch n := n.(type) {
+		case *Call:
+			if !isMathFunction(n) {
+				err = fmt.Errorf("%s() cannot be used within a CASE expression", n.Name)
+			}
+		case *Wildcard, *Distinct:
+			err = fmt.Errorf("invalid expression within a CASE expression: %s", n)
+		}
+	})
+	return err
+}
+
+// isStateFunction returns true if the call is to state_duration() or
+// state_count(). The first argument of these calls is a condition that is
+// evaluated on each point.
+func isStateFunction(call *Call) bool {
+	return call.Name == "state_duration" || call.Name == "state_count"
+}
+
+// validateStateCondition ensures that the first argument of a state function
+// is a condition that can be evaluated on each point.
+func validateStateCondition(expr *Call) error {
+	cond, ok := expr.Args[0].(*BinaryExpr)
+	if !ok || cond.Op.Precedence() > EQ.Precedence() {
+		return fmt.Errorf("expected condition argument in %s()", expr.Name)
+	}
+
+	var err error
+	WalkFunc(cond, func(n Node) {
+		if err != nil {
+			return
+		}
+		switch n := n.(type) {
+		case *Call:
+			if !isMathFunction(n) {
+				err = fmt.Errorf("%s() cannot be used within the condition of %s()", n.Name, expr.Name)
+			}
+		case *Wildcard, *Distinct:
+			err = fmt.Errorf("invalid expression within the condition of %s(): %s", expr.Name, n)
+		}
+	})
+	return err
+}
+
+// isDerivedDimension returns true if the call is a dimension whose value is
+// computed from the value of a tag.
+func isDerivedDimension(call *Call) bool {
+	return call.Name == "regex_extract" || call.Name == "map_values"
+}
+
+// validateDerivedDimension ensures that the arguments of a derived dimension
+// are valid.
+func validateDerivedDimension(expr *Call) error {
+	if len(expr.Args) > 0 {
+		if _, ok := expr.Args[0].(*VarRef); !ok {
+			return fmt.Errorf("expected tag argument in %s()", expr.Name)
+		}
+	}
+
+	switch expr.Name {
+	case "regex_extract":
+		if min, max, got := 2, 3, len(expr.Args); got > max || got < min {
+			return fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, min, max, got)
+		}
+		re, ok := expr.Args[1].(*RegexLiteral)
+		if !ok {
+			return fmt.Errorf("second argument to %s must be a regular expression, got %T", expr.Name, expr.Args[1])
+		}
+		if len(expr.Args) == 3 {
+			group, ok := expr.Args[2].(*IntegerLiteral)
+			if !ok {
+				return fmt.Errorf("third argument to %s must be an integer, got %T", expr.Name, expr.Args[2])
+			} else if n := re.Val.NumSubexp(); group.Val < 0 || group.Val > int64(n) {
+				return fmt.Errorf("capture group for %s must be between 0 and %d, got %d", expr.Name, n, group.Val)
+			}
+		}
+	case "map_values":
+		if min, got := 3, len(expr.Args); got < min {
+			return fmt.Errorf("invalid number of arguments for %s, expected at least %d, got %d", expr.Name, min, got)
+		}
+
+		// The arguments after the tag are pairs of a match and the value
+		// it is mapped to, optionally followed by a default value.
+		args := expr.Args[1:]
+		for ; len(args) >= 2; args = args[2:] {
+			switch args[0].(type) {
+			case *StringLiteral, *RegexLiteral:
+			default:
+				return fmt.Errorf("expected string or regular expression to match in %s(), got %s", expr.Name, args[0])
+			}
+			if _, ok := args[1].(*StringLiteral); !ok {
+				return fmt.Errorf("expected string value in %s(), got %s", expr.Name, args[1])
+			}
+		}
+		if len(args) == 1 {
+			if _, ok := args[0].(*StringLiteral); !ok {
+				return fmt.Errorf("expected string default value in %s(), got %s", expr.Name, args[0])
+			}
+		}
+	}
+	return nil
+}
+
+// validateJoin ensures that a join is the only source of the statement and
+// that the fields and condition reference the join sources correctly.
+func (s *SelectStatement) validateJoin() error {
+	if !s.Sources.HasJoin() {
+		return nil
+	} else if len(s.Sources) > 1 {
+		return errors.New("JOIN cannot be combined with other sources")
+	} else if s.HasFieldWildcard() {
+		return errors.New("wildcards are not supported with JOIN")
+	}
+	join := s.Sources[0].(*Join)
+
+	var err error
+	WalkFunc(s.Fields, func(n Node) {
+		if ref, ok := n.(*VarRef); ok && err == nil && ref.Val != "time" {
+			if js, _ := join.Resolve(ref.Val); js == nil {
+				err = fmt.Errorf("field %s must be qualified with a join alias", ref.Val)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// Rows are only aligned before the condition is evaluated in raw queries.
+	if cond := join.CrossCondition(s.Condition); cond != nil && !s.IsRawQuery {
+		return fmt.Errorf("condition must not reference more than one join source: %s", splitConjunction(cond)[0])
 	}
 	return nil
 }
 
 func (s *SelectStatement) validateDimensions() error {
 	var dur time.Duration
+	keys := make(map[string]bool, len(s.Dimensions))
 	for _, dim := range s.Dimensions {
+		// Only a derived dimension names its own tag key so a derived
+		// dimension must not group by the same key as another dimension.
+		call, derived := dim.Expr.(*Call)
+		derived = derived && isDerivedDimension(call)
+		if dim.Alias != "" && !derived {
+			return errors.New("only regex_extract() and map_values() dimensions can have an alias")
+		} else if key, ok := dim.TagKey(); ok {
+			if prev, ok := keys[key]; ok && (prev || derived) {
+				return fmt.Errorf("duplicate dimension: %s", key)
+			}
+			keys[key] = derived
+		}
+
 		switch expr := dim.Expr.(type) {
 		case *Call:
-			// Ensure the call is time() and it has one or two duration arguments.
+			if derived {
+				if err := validateDerivedDimension(expr); err != nil {
+					return err
+				}
+				break
+			}
+
+			// Ensure the call is time() and it has a duration, an optional
+			// offset and an optional step.
 			// If we already have a duration
 			if expr.Name != "time" {
 				return errors.New("only time() calls allowed in dimensions")
-			} else if got := len(expr.Args); got < 1 || got > 2 {
-				return errors.New("time dimension expected 1 or 2 arguments")
-			} else if lit, ok := expr.Args[0].(*DurationLiteral); !ok {
-				return errors.New("time dimension must have duration argument")
+			} else if got := len(expr.Args); got < 1 || got > 3 {
+				return errors.New("time dimension expected 1 to 3 arguments")
 			} else if dur != 0 {
 				return errors.New("multiple time dimensions not allowed")
 			} else {
-				dur = lit.Val
-				if len(expr.Args) == 2 {
-					switch lit := expr.Args[1].(type) {
+				switch lit := expr.Args[0].(type) {
+				case *DurationLiteral:
+					dur = lit.Val
+				case *CalendarDurationLiteral:
+					if lit.Months <= 0 {
+						return errors.New("time dimension must have a positive calendar duration")
+					}
+					dur = lit.Duration()
+				default:
+					return errors.New("time dimension must have duration argument")
+				}
+				args := expr.Args[1:]
+				if step := timeDimensionStep(expr); step != nil {
+					if err := validateTimeDimensionStep(step, expr.Args[0]); err != nil {
+						return err
+					}
+					args = args[:len(args)-1]
+				} else if len(args) == 2 {
+					return errors.New("time dimension third argument must be step()")
+				}
+				if len(args) == 1 {
+					switch lit := args[0].(type) {
 					case *DurationLiteral:
 						// noop
 					case *Call:
@@ -1840,6 +2357,41 @@ func (s *SelectStatement) validateDimensions() error {
 	return nil
 }
 
+// timeDimensionStep returns the step() call of a time dimension. Returns nil
+// if the windows of the time dimension do not overlap.
+func timeDimensionStep(call *Call) *Call {
+	if n := len(call.Args); n > 1 {
+		if step, ok := call.Args[n-1].(*Call); ok && step.Name == "step" {
+			return step
+		}
+	}
+	return nil
+}
+
+// validateTimeDimensionStep ensures the step between overlapping windows is a
+// positive duration that evenly divides the interval.
+func validateTimeDimensionStep(step *Call, interval Expr) error {
+	if len(step.Args) != 1 {
+		return errors.New("step() requires a duration argument")
+	}
+	lit, ok := step.Args[0].(*DurationLiteral)
+	if !ok {
+		return errors.New("step() requires a duration argument")
+	} else if lit.Val <= 0 {
+		return errors.New("step() duration must be greater than zero")
+	}
+
+	switch interval := interval.(type) {
+	case *CalendarDurationLiteral:
+		return errors.New("step() cannot be used with a calendar interval")
+	case *DurationLiteral:
+		if interval.Val%lit.Val != 0 {
+			return errors.New("time dimension interval must be a multiple of the step() duration")
+		}
+	}
+	return nil
+}
+
 // validSelectWithAggregate determines if a SELECT statement has the correct
 // combination of aggregate functions combined with selected fields and tags
 // Currently we don't have support for all aggregates, but aggregates that
@@ -1862,7 +2414,7 @@ func (s *SelectStatement) validSelectWithAggregate() error {
 	onlySelectors := true
 	for k := range calls {
 		switch k {
-		case "top", "bottom", "max", "min", "first", "last", "percentile", "sample":
+		case "top", "bottom", "max", "min", "first", "last", "percentile", "sample", "mad_outliers", "lttb":
 		default:
 			onlySelectors = false
 			break
@@ -1922,19 +2474,29 @@ func (s *SelectStatement) validPercentileAggr(expr *Call) error {
 	case *VarRef, *RegexLiteral, *Wildcard:
 		// do nothing
 	default:
-		return fmt.Errorf("expected field argument in percentile()")
+		return fmt.Errorf("expected field argument in %s()", expr.Name)
 	}
 
-	switch expr.Args[1].(type) {
-	case *IntegerLiteral, *NumberLiteral:
-		return nil
+	var percentile float64
+	switch arg := expr.Args[1].(type) {
+	case *IntegerLiteral:
+		percentile = float64(arg.Val)
+	case *NumberLiteral:
+		percentile = arg.Val
 	default:
-		return fmt.Errorf("expected float argument in percentile()")
+		return fmt.Errorf("expected float argument in %s()", expr.Name)
 	}
+
+	// A percentile outside of the range does not select a point with
+	// percentile() but it cannot be estimated by percentile_approx().
+	if expr.Name == "percentile_approx" && (percentile < 0 || percentile > 100) {
+		return fmt.Errorf("percentile must be between 0 and 100 in %s(), got %v", expr.Name, percentile)
+	}
+	return nil
 }
 
-// validPercentileAggr determines if the call to SAMPLE has valid arguments.
-func (s *SelectStatement) validSampleAggr(expr *Call) error {
+// validHistogramAggr determines if the call to HISTOGRAM has valid arguments.
+func (s *SelectStatement) validHistogramAggr(expr *Call) error {
 	if err := s.validSelectWithAggregate(); err != nil {
 		return err
 	}
@@ -1946,22 +2508,135 @@ func (s *SelectStatement) validSampleAggr(expr *Call) error {
 	case *VarRef, *RegexLiteral, *Wildcard:
 		// do nothing
 	default:
-		return fmt.Errorf("expected field argument in sample()")
+		return fmt.Errorf("expected field argument in %s()", expr.Name)
 	}
 
-	switch expr.Args[1].(type) {
+	spec, ok := expr.Args[1].(*Call)
+	if !ok {
+		return fmt.Errorf("expected bucket argument in %s()", expr.Name)
+	}
+	_, err := histogramBounds(spec)
+	return err
+}
+
+// validOutlierThreshold determines if the threshold of an outlier function is
+// a positive number.
+func validOutlierThreshold(expr *Call, arg Expr) error {
+	var threshold float64
+	switch arg := arg.(type) {
+	case *NumberLiteral:
+		threshold = arg.Val
 	case *IntegerLiteral:
-		return nil
+		threshold = float64(arg.Val)
 	default:
-		return fmt.Errorf("expected integer argument in sample()")
+		return fmt.Errorf("expected float argument in %s()", expr.Name)
 	}
+	if threshold <= 0 {
+		return fmt.Errorf("threshold for %s must be greater than 0, got %v", expr.Name, threshold)
+	}
+	return nil
 }
 
-func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
-	for _, f := range s.Fields {
-		for _, expr := range walkFunctionCalls(f.Expr) {
-			switch expr.Name {
-			case "derivative", "non_negative_derivative", "difference", "non_negative_difference", "moving_average", "cumulative_sum", "elapsed":
+// maxHistogramBuckets is the maximum number of buckets in a histogram.
+const maxHistogramBuckets = 1000
+
+// histogramBounds returns the boundaries of the buckets described by a
+// linear(), exponential() or fixed() call. A histogram with n buckets has
+// n+1 boundaries and each bucket includes its lower boundary.
+func histogramBounds(spec *Call) ([]float64, error) {
+	args := make([]float64, len(spec.Args))
+	for i, arg := range spec.Args {
+		switch arg := arg.(type) {
+		case *NumberLiteral:
+			args[i] = arg.Val
+		case *IntegerLiteral:
+			args[i] = float64(arg.Val)
+		default:
+			return nil, fmt.Errorf("expected number argument in %s()", spec.Name)
+		}
+	}
+
+	var bounds []float64
+	switch spec.Name {
+	case "linear", "exponential":
+		if exp, got := 3, len(args); got != exp {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", spec.Name, exp, got)
+		}
+		n, ok := spec.Args[2].(*IntegerLiteral)
+		if !ok || n.Val <= 0 {
+			return nil, fmt.Errorf("third argument to %s must be a positive integer", spec.Name)
+		} else if n.Val > maxHistogramBuckets {
+			return nil, fmt.Errorf("histogram cannot have more than %d buckets", maxHistogramBuckets)
+		}
+
+		start := args[0]
+		bounds = make([]float64, n.Val+1)
+		if spec.Name == "linear" {
+			width := args[1]
+			if width <= 0 {
+				return nil, fmt.Errorf("second argument to %s must be greater than 0", spec.Name)
+			}
+			for i := range bounds {
+				bounds[i] = start + float64(i)*width
+			}
+		} else {
+			factor := args[1]
+			if start <= 0 {
+				return nil, fmt.Errorf("first argument to %s must be greater than 0", spec.Name)
+			} else if factor <= 1 {
+				return nil, fmt.Errorf("second argument to %s must be greater than 1", spec.Name)
+			}
+			for i := range bounds {
+				bounds[i] = start * math.Pow(factor, float64(i))
+			}
+		}
+	case "fixed":
+		if len(args) < 2 {
+			return nil, fmt.Errorf("invalid number of arguments for %s, expected at least 2, got %d", spec.Name, len(args))
+		} else if len(args)-1 > maxHistogramBuckets {
+			return nil, fmt.Errorf("histogram cannot have more than %d buckets", maxHistogramBuckets)
+		}
+		for i := 1; i < len(args); i++ {
+			if args[i] <= args[i-1] {
+				return nil, fmt.Errorf("arguments to %s must be in increasing order", spec.Name)
+			}
+		}
+		bounds = args
+	default:
+		return nil, fmt.Errorf("expected linear(), exponential() or fixed() bucket argument in histogram(), got %s()", spec.Name)
+	}
+	return bounds, nil
+}
+
+// validPercentileAggr determines if the call to SAMPLE has valid arguments.
+func (s *SelectStatement) validSampleAggr(expr *Call) error {
+	if err := s.validSelectWithAggregate(); err != nil {
+		return err
+	}
+	if exp, got := 2, len(expr.Args); got != exp {
+		return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+	}
+
+	switch expr.Args[0].(type) {
+	case *VarRef, *RegexLiteral, *Wildcard:
+		// do nothing
+	default:
+		return fmt.Errorf("expected field argument in sample()")
+	}
+
+	switch expr.Args[1].(type) {
+	case *IntegerLiteral:
+		return nil
+	default:
+		return fmt.Errorf("expected integer argument in sample()")
+	}
+}
+
+func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
+	for _, f := range s.Fields {
+		for _, expr := range walkFunctionCalls(f.Expr) {
+			switch expr.Name {
+			case "derivative", "non_negative_derivative", "difference", "non_negative_difference", "moving_average", "zscore", "cumulative_sum", "elapsed":
 				if err := s.validSelectWithAggregate(); err != nil {
 					return err
 				}
@@ -1993,6 +2668,18 @@ func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
 					} else if int64(int(lit.Val)) != lit.Val {
 						return fmt.Errorf("moving_average window too large, got %d", lit.Val)
 					}
+				case "zscore":
+					if got := len(expr.Args); got != 2 {
+						return fmt.Errorf("invalid number of arguments for zscore, expected 2, got %d", got)
+					}
+
+					if lit, ok := expr.Args[1].(*IntegerLiteral); !ok {
+						return fmt.Errorf("second argument for zscore must be an integer, got %T", expr.Args[1])
+					} else if lit.Val <= 2 {
+						return fmt.Errorf("zscore window must be greater than 2, got %d", lit.Val)
+					} else if int64(int(lit.Val)) != lit.Val {
+						return fmt.Errorf("zscore window too large, got %d", lit.Val)
+					}
 				}
 				// Validate that if they have grouping by time, they need a sub-call like min/max, etc.
 				groupByInterval, err := s.GroupByInterval()
@@ -2010,7 +2697,7 @@ func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
 						if err := s.validTopBottomAggr(c); err != nil {
 							return err
 						}
-					case "percentile":
+					case "percentile", "percentile_approx":
 						if err := s.validPercentileAggr(c); err != nil {
 							return err
 						}
@@ -2020,7 +2707,7 @@ func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
 						}
 
 						switch fc := c.Args[0].(type) {
-						case *VarRef, *Wildcard, *RegexLiteral:
+						case *VarRef, *Wildcard, *RegexLiteral, *CaseExpr:
 							// do nothing
 						case *Call:
 							if fc.Name != "distinct" || expr.Name != "count" {
@@ -2043,7 +2730,7 @@ func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
 				if err := s.validTopBottomAggr(expr); err != nil {
 					return err
 				}
-			case "percentile":
+			case "percentile", "percentile_approx":
 				if err := s.validPercentileAggr(expr); err != nil {
 					return err
 				}
@@ -2051,6 +2738,69 @@ func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
 				if err := s.validSampleAggr(expr); err != nil {
 					return err
 				}
+			case "lttb":
+				if err := s.validSelectWithAggregate(); err != nil {
+					return err
+				}
+				if exp, got := 2, len(expr.Args); got != exp {
+					return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+				}
+				switch expr.Args[0].(type) {
+				case *VarRef, *RegexLiteral, *Wildcard:
+					// do nothing
+				default:
+					return fmt.Errorf("expected field argument in %s()", expr.Name)
+				}
+				if n, ok := expr.Args[1].(*IntegerLiteral); !ok {
+					return fmt.Errorf("expected integer argument in %s()", expr.Name)
+				} else if n.Val < 3 {
+					return fmt.Errorf("%s must select at least 3 points, got %d", expr.Name, n.Val)
+				} else if int64(int(n.Val)) != n.Val {
+					return fmt.Errorf("%s number of points too large, got %d", expr.Name, n.Val)
+				}
+			case "mad_outliers":
+				if err := s.validSelectWithAggregate(); err != nil {
+					return err
+				}
+				if exp, got := 2, len(expr.Args); got != exp {
+					return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+				}
+				switch expr.Args[0].(type) {
+				case *VarRef, *RegexLiteral, *Wildcard:
+					// do nothing
+				default:
+					return fmt.Errorf("expected field argument in %s()", expr.Name)
+				}
+				if err := validOutlierThreshold(expr, expr.Args[1]); err != nil {
+					return err
+				}
+			case "outliers":
+				if err := s.validSelectWithAggregate(); err != nil {
+					return err
+				}
+				if min, max, got := 2, 3, len(expr.Args); got > max || got < min {
+					return fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, min, max, got)
+				}
+				if c, ok := expr.Args[0].(*Call); !ok || c.Name == expr.Name || len(c.Args) == 0 {
+					return fmt.Errorf("expected aggregate argument in %s()", expr.Name)
+				} else if _, ok := c.Args[0].(*VarRef); !ok {
+					return fmt.Errorf("expected field argument in %s()", c.Name)
+				}
+				if err := validOutlierThreshold(expr, expr.Args[1]); err != nil {
+					return err
+				}
+				if len(expr.Args) == 3 {
+					if method, ok := expr.Args[2].(*StringLiteral); !ok || (method.Val != "mad" && method.Val != "iqr") {
+						return fmt.Errorf("third argument to %s must be 'mad' or 'iqr'", expr.Name)
+					}
+				}
+			case "histogram":
+				if f.Expr != Expr(expr) {
+					return errors.New("histogram() cannot be used within an expression")
+				}
+				if err := s.validHistogramAggr(expr); err != nil {
+					return err
+				}
 			case "integral":
 				if err := s.validSelectWithAggregate(); err != nil {
 					return err
@@ -2065,6 +2815,105 @@ func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
 						return errors.New("second argument must be a duration")
 					}
 				}
+			case "time_weighted_avg":
+				if err := s.validSelectWithAggregate(); err != nil {
+					return err
+				}
+				if exp, got := 2, len(expr.Args); got != exp {
+					return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+				} else if _, ok := expr.Args[0].(*VarRef); !ok {
+					return fmt.Errorf("expected field argument in %s()", expr.Name)
+				} else if s.GroupByStep() > 0 {
+					return fmt.Errorf("%s does not support overlapping windows", expr.Name)
+				}
+				if method, ok := expr.Args[1].(*StringLiteral); !ok || (method.Val != "step" && method.Val != "linear") {
+					return fmt.Errorf("second argument to %s must be 'step' or 'linear'", expr.Name)
+				}
+			case "state_duration", "state_count":
+				if err := s.validSelectWithAggregate(); err != nil {
+					return err
+				}
+				max := 1
+				if expr.Name == "state_duration" {
+					max = 2
+				}
+				if min, got := 1, len(expr.Args); got > max || got < min {
+					return fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, min, max, got)
+				}
+				if err := validateStateCondition(expr); err != nil {
+					return err
+				}
+				if len(expr.Args) == 2 {
+					if unit, ok := expr.Args[1].(*DurationLiteral); !ok {
+						return fmt.Errorf("second argument to %s must be a duration, got %T", expr.Name, expr.Args[1])
+					} else if unit.Val <= 0 {
+						return fmt.Errorf("unit for %s must be greater than 0, got %s", expr.Name, FormatDuration(unit.Val))
+					}
+				}
+			case "rate", "increase":
+				if err := s.validSelectWithAggregate(); err != nil {
+					return err
+				}
+				max := 2
+				if expr.Name == "rate" {
+					max = 3
+				}
+				if min, got := 1, len(expr.Args); got > max || got < min {
+					return fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, min, max, got)
+				} else if _, ok := expr.Args[0].(*VarRef); !ok {
+					return fmt.Errorf("expected field argument in %s()", expr.Name)
+				} else if s.GroupByStep() > 0 {
+					return fmt.Errorf("%s does not support overlapping windows", expr.Name)
+				}
+				args := expr.Args[1:]
+				if expr.Name == "rate" && len(args) > 0 {
+					if unit, ok := args[0].(*DurationLiteral); !ok {
+						return fmt.Errorf("second argument to %s must be a duration, got %T", expr.Name, args[0])
+					} else if unit.Val <= 0 {
+						return fmt.Errorf("unit for %s must be greater than 0, got %s", expr.Name, FormatDuration(unit.Val))
+					}
+					args = args[1:]
+				}
+				if len(args) > 0 {
+					var max float64
+					switch arg := args[0].(type) {
+					case *NumberLiteral:
+						max = arg.Val
+					case *IntegerLiteral:
+						max = float64(arg.Val)
+					default:
+						return fmt.Errorf("expected counter maximum argument in %s()", expr.Name)
+					}
+					if max <= 0 {
+						return fmt.Errorf("counter maximum for %s must be greater than 0, got %v", expr.Name, max)
+					}
+				}
+			case "corr", "covar":
+				if err := s.validSelectWithAggregate(); err != nil {
+					return err
+				}
+				if exp, got := 2, len(expr.Args); got != exp {
+					return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
+				}
+				for _, arg := range expr.Args {
+					if _, ok := arg.(*VarRef); !ok {
+						return fmt.Errorf("expected field arguments in %s()", expr.Name)
+					}
+				}
+			case "linear_regression":
+				if err := s.validSelectWithAggregate(); err != nil {
+					return err
+				}
+				if min, max, got := 1, 2, len(expr.Args); got > max || got < min {
+					return fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, min, max, got)
+				} else if _, ok := expr.Args[0].(*VarRef); !ok {
+					return fmt.Errorf("expected field argument in %s()", expr.Name)
+				}
+				if len(expr.Args) == 2 {
+					if stat, ok := expr.Args[1].(*StringLiteral); !ok || !isLinearRegressionStat(stat.Val) {
+						return fmt.Errorf("second argument to %s must be 'slope', 'intercept' or 'r2'", expr.Name)
+					}
+				}
 			case "holt_winters", "holt_winters_with_fit":
 				if exp, got := 3, len(expr.Args); got != exp {
 					return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
@@ -2079,6 +2928,8 @@ func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
 					return fmt.Errorf("%s aggregate requires a GROUP BY interval", expr.Name)
 				} else if !ok {
 					return fmt.Errorf("must use aggregate function with %s", expr.Name)
+				} else if s.GroupByMonths() > 0 {
+					return fmt.Errorf("%s aggregate does not support calendar intervals", expr.Name)
 				}
 				if arg, ok := expr.Args[1].(*IntegerLiteral); !ok {
 					return fmt.Errorf("expected integer argument as second arg in %s", expr.Name)
@@ -2102,7 +2953,7 @@ func (s *SelectStatement) validateAggregates(tr targetRequirement) error {
 					return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, exp, got)
 				}
 				switch fc := expr.Args[0].(type) {
-				case *VarRef, *Wildcard, *RegexLiteral:
+				case *VarRef, *Wildcard, *RegexLiteral, *CaseExpr:
 					// do nothing
 				case *Call:
 					if fc.Name != "distinct" || expr.Name != "count" {
@@ -2211,7 +3062,7 @@ func (s *SelectStatement) validateGroupByInterval() error {
 		switch expr := f.Expr.(type) {
 		case *Call:
 			switch expr.Name {
-			case "derivative", "non_negative_derivative", "difference", "non_negative_difference", "moving_average", "cumulative_sum", "elapsed", "holt_winters", "holt_winters_with_fit":
+			case "derivative", "non_negative_derivative", "difference", "non_negative_difference", "moving_average", "zscore", "cumulative_sum", "elapsed", "holt_winters", "holt_winters_with_fit":
 				// If the first argument is a call, we needed a group by interval and we don't have one.
 				if _, ok := expr.Args[0].(*Call); ok {
 					return fmt.Errorf("%s aggregate requires a GROUP BY interval", expr.Name)
@@ -2297,22 +3148,56 @@ func (s *SelectStatement) GroupByInterval() (time.Duration, error) {
 	for _, d := range s.Dimensions {
 		if call, ok := d.Expr.(*Call); ok && call.Name == "time" {
 			// Make sure there is exactly one argument.
-			if got := len(call.Args); got < 1 || got > 2 {
-				return 0, errors.New("time dimension expected 1 or 2 arguments")
+			if got := len(call.Args); got < 1 || got > 3 {
+				return 0, errors.New("time dimension expected 1 to 3 arguments")
 			}
 
-			// Ensure the argument is a duration.
-			lit, ok := call.Args[0].(*DurationLiteral)
-			if !ok {
+			// Ensure the argument is a duration. Calendar durations use
+			// the longest possible length of the interval.
+			switch lit := call.Args[0].(type) {
+			case *DurationLiteral:
+				s.groupByInterval = lit.Val
+			case *CalendarDurationLiteral:
+				s.groupByInterval = lit.Duration()
+			default:
 				return 0, errors.New("time dimension must have duration argument")
 			}
-			s.groupByInterval = lit.Val
-			return lit.Val, nil
+			return s.groupByInterval, nil
 		}
 	}
 	return 0, nil
 }
 
+// GroupByMonths returns the number of months in the time interval if the
+// statement is grouped by a calendar duration. Returns 0 otherwise.
+func (s *SelectStatement) GroupByMonths() int {
+	for _, d := range s.Dimensions {
+		if call, ok := d.Expr.(*Call); ok && call.Name == "time" && len(call.Args) > 0 {
+			if lit, ok := call.Args[0].(*CalendarDurationLiteral); ok {
+				return lit.Months
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// GroupByStep returns the step between overlapping windows if the time
+// dimension has a step() argument. Returns 0 otherwise.
+func (s *SelectStatement) GroupByStep() time.Duration {
+	for _, d := range s.Dimensions {
+		if call, ok := d.Expr.(*Call); ok && call.Name == "time" {
+			if step := timeDimensionStep(call); step != nil && len(step.Args) == 1 {
+				if lit, ok := step.Args[0].(*DurationLiteral); ok {
+					return lit.Val
+				}
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
 // GroupByOffset extracts the time interval offset, if specified.
 func (s *SelectStatement) GroupByOffset() (time.Duration, error) {
 	interval, err := s.GroupByInterval()
@@ -2327,11 +3212,18 @@ func (s *SelectStatement) GroupByOffset() (time.Duration, error) {
 
 	for _, d := range s.Dimensions {
 		if call, ok := d.Expr.(*Call); ok && call.Name == "time" {
-			if len(call.Args) == 2 {
-				switch expr := call.Args[1].(type) {
+			args := call.Args[1:]
+			if timeDimensionStep(call) != nil {
+				args = args[:len(args)-1]
+			}
+			if len(args) == 1 {
+				switch expr := args[0].(type) {
 				case *DurationLiteral:
 					return expr.Val % interval, nil
 				case *TimeLiteral:
+					if s.GroupByMonths() > 0 {
+						return 0, errors.New("time dimension offset must be a duration with a calendar interval")
+					}
 					return expr.Val.Sub(expr.Val.Truncate(interval)), nil
 				default:
 					return 0, fmt.Errorf("invalid time dimension offset: %s", expr)
@@ -2439,9 +3331,11 @@ func walkNames(exp Expr) []string {
 		return []string{expr.Val}
 	case *Call:
 		var a []string
-		for _, expr := range expr.Args {
-			if ref, ok := expr.(*VarRef); ok {
+		for _, arg := range expr.Args {
+			if ref, ok := arg.(*VarRef); ok {
 				a = append(a, ref.Val)
+			} else if _, ok := arg.(*CaseExpr); ok || isMathFunction(expr) || isStateFunction(expr) {
+				a = append(a, walkNames(arg)...)
 			}
 		}
 		return a
@@ -2452,6 +3346,14 @@ func walkNames(exp Expr) []string {
 		return ret
 	case *ParenExpr:
 		return walkNames(expr.Expr)
+	case *CaseExpr:
+		var ret []string
+		for _, w := range expr.WhenClauses {
+			ret = append(ret, walkNames(w.Condition)...)
+			ret = append(ret, walkNames(w.Result)...)
+		}
+		ret = append(ret, walkNames(expr.Else)...)
+		return ret
 	}
 
 	return nil
@@ -2464,9 +3366,11 @@ func walkRefs(exp Expr) []VarRef {
 		return []VarRef{*expr}
 	case *Call:
 		a := make([]VarRef, 0, len(expr.Args))
-		for _, expr := range expr.Args {
-			if ref, ok := expr.(*VarRef); ok {
+		for _, arg := range expr.Args {
+			if ref, ok := arg.(*VarRef); ok {
 				a = append(a, *ref)
+			} else if _, ok := arg.(*CaseExpr); ok || isMathFunction(expr) || isStateFunction(expr) {
+				a = append(a, walkRefs(arg)...)
 			}
 		}
 		return a
@@ -2479,6 +3383,14 @@ func walkRefs(exp Expr) []VarRef {
 		return ret
 	case *ParenExpr:
 		return walkRefs(expr.Expr)
+	case *CaseExpr:
+		var ret []VarRef
+		for _, w := range expr.WhenClauses {
+			ret = append(ret, walkRefs(w.Condition)...)
+			ret = append(ret, walkRefs(w.Result)...)
+		}
+		ret = append(ret, walkRefs(expr.Else)...)
+		return ret
 	}
 
 	return nil
@@ -2527,6 +3439,13 @@ func walkFunctionCalls(exp Expr) []*Call {
 	case *VarRef:
 		return nil
 	case *Call:
+		if isMathFunction(expr) {
+			var ret []*Call
+			for _, arg := range expr.Args {
+				ret = append(ret, walkFunctionCalls(arg)...)
+			}
+			return ret
+		}
 		return []*Call{expr}
 	case *BinaryExpr:
 		var ret []*Call
@@ -2610,6 +3529,33 @@ func (s *DeleteStatement) DefaultDatabase() string {
 	return ""
 }
 
+// ExplainStatement represents a command for explaining how a SELECT statement
+// will be executed.
+type ExplainStatement struct {
+	// The statement to explain.
+	Statement *SelectStatement
+
+	// Executes the statement and reports statistics about its execution
+	// instead of only reporting the plan.
+	Analyze bool
+}
+
+// String returns a string representation of the explain statement.
+func (s *ExplainStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("EXPLAIN ")
+	if s.Analyze {
+		_, _ = buf.WriteString("ANALYZE ")
+	}
+	_, _ = buf.WriteString(s.Statement.String())
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute an ExplainStatement.
+func (s *ExplainStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return s.Statement.RequiredPrivileges()
+}
+
 // ShowSeriesStatement represents a command for listing series in the database.
 type ShowSeriesStatement struct {
 	// Database to query. If blank, use the default database.
@@ -2676,6 +3622,57 @@ func (s *ShowSeriesStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// ShowSeriesCardinalityStatement represents a command for counting the series
+// in a database.
+type ShowSeriesCardinalityStatement struct {
+	// Database to query. If blank, use the default database.
+	Database string
+
+	// Count the series by walking the index instead of estimating the
+	// cardinality from the sketches.
+	Exact bool
+
+	// Measurement(s) the series are counted for.
+	Sources Sources
+
+	// An expression evaluated on a series name or tag.
+	Condition Expr
+}
+
+// String returns a string representation of the statement.
+func (s *ShowSeriesCardinalityStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("SHOW ")
+	if s.Exact {
+		_, _ = buf.WriteString("EXACT ")
+	}
+	_, _ = buf.WriteString("SERIES CARDINALITY")
+
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	if s.Sources != nil {
+		_, _ = buf.WriteString(" FROM ")
+		_, _ = buf.WriteString(s.Sources.String())
+	}
+	if s.Condition != nil {
+		_, _ = buf.WriteString(" WHERE ")
+		_, _ = buf.WriteString(s.Condition.String())
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowSeriesCardinalityStatement.
+func (s *ShowSeriesCardinalityStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *ShowSeriesCardinalityStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // DropSeriesStatement represents a command for removing a series from the database.
 type DropSeriesStatement struct {
 	// Data source that fields are extracted from (optional)
@@ -2707,8 +3704,35 @@ func (s DropSeriesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: WritePrivilege}}, nil
 }
 
+// DropFieldStatement represents a command for removing a field from measurements.
+type DropFieldStatement struct {
+	// Name of the field to be dropped.
+	Name string
+
+	// Measurements the field is dropped from.
+	Sources Sources
+}
+
+// String returns a string representation of the drop field statement.
+func (s *DropFieldStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("DROP FIELD ")
+	buf.WriteString(QuoteIdent(s.Name))
+	buf.WriteString(" FROM ")
+	buf.WriteString(s.Sources.String())
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a DropFieldStatement.
+func (s *DropFieldStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: WritePrivilege}}, nil
+}
+
 // DeleteSeriesStatement represents a command for deleting all or part of a series from a database.
 type DeleteSeriesStatement struct {
+	// Field whose values are deleted (optional). All fields are deleted if empty.
+	Field string
+
 	// Data source that fields are extracted from (optional)
 	Sources Sources
 
@@ -2721,6 +3745,10 @@ func (s *DeleteSeriesStatement) String() string {
 	var buf bytes.Buffer
 	buf.WriteString("DELETE")
 
+	if s.Field != "" {
+		buf.WriteString(" FIELD ")
+		buf.WriteString(QuoteIdent(s.Field))
+	}
 	if s.Sources != nil {
 		buf.WriteString(" FROM ")
 		buf.WriteString(s.Sources.String())
@@ -2770,6 +3798,18 @@ func (s *ShowContinuousQueriesStatement) RequiredPrivileges() (ExecutionPrivileg
 	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
 }
 
+// ShowContinuousQueryStatusStatement represents a command for listing the
+// outcome of the most recent runs of each continuous query.
+type ShowContinuousQueryStatusStatement struct{}
+
+// String returns a string representation of the show continuous query status statement.
+func (s *ShowContinuousQueryStatusStatement) String() string { return "SHOW CONTINUOUS QUERY STATUS" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowContinuousQueryStatusStatement.
+func (s *ShowContinuousQueryStatusStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+}
+
 // ShowGrantsForUserStatement represents a command for listing user privileges.
 type ShowGrantsForUserStatement struct {
 	// Name of the user to display privileges.
@@ -2804,6 +3844,62 @@ func (s *ShowDatabasesStatement) RequiredPrivileges() (ExecutionPrivileges, erro
 	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: NoPrivileges}}, nil
 }
 
+// BackfillContinuousQueryStatement represents a command for running an
+// existing continuous query over a past time range.
+type BackfillContinuousQueryStatement struct {
+	// Name of the continuous query to backfill.
+	Name string
+
+	// Database the continuous query belongs to.
+	Database string
+
+	// Time range to backfill. The start time is inclusive and the end time
+	// is exclusive. Each time is either a time literal or an expression of
+	// now() that is evaluated when the statement is executed.
+	StartTime Expr
+	EndTime   Expr
+}
+
+// String returns a string representation of the statement.
+func (s *BackfillContinuousQueryStatement) String() string {
+	return fmt.Sprintf("BACKFILL CONTINUOUS QUERY %s ON %s FROM %s TO %s",
+		QuoteIdent(s.Name), QuoteIdent(s.Database), s.StartTime, s.EndTime)
+}
+
+// TimeRange returns the time range to backfill with now() replaced by the
+// given time.
+func (s *BackfillContinuousQueryStatement) TimeRange(now time.Time) (startTime, endTime time.Time, err error) {
+	valuer := &NowValuer{Now: now}
+	for _, t := range []struct {
+		expr Expr
+		val  *time.Time
+	}{
+		{expr: s.StartTime, val: &startTime},
+		{expr: s.EndTime, val: &endTime},
+	} {
+		lit, ok := Reduce(t.expr, valuer).(*TimeLiteral)
+		if !ok {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid backfill time: %s", t.expr)
+		}
+		*t.val = lit.Val.UTC()
+	}
+
+	if !endTime.After(startTime) {
+		return time.Time{}, time.Time{}, errors.New("backfill end time must be after the start time")
+	}
+	return startTime, endTime, nil
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a BackfillContinuousQueryStatement.
+func (s *BackfillContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *BackfillContinuousQueryStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // CreateContinuousQueryStatement represents a command for creating a continuous query.
 type CreateContinuousQueryStatement struct {
 	// Name of the continuous query to be created.
@@ -2820,6 +3916,10 @@ type CreateContinuousQueryStatement struct {
 
 	// Maximum duration to resample previous queries.
 	ResampleFor time.Duration
+
+	// Update the results from the points as they are written instead of
+	// querying each interval on a timer.
+	Incremental bool
 }
 
 // String returns a string representation of the statement.
@@ -2836,6 +3936,9 @@ func (s *CreateContinuousQueryStatement) String() string {
 			fmt.Fprintf(&buf, "FOR %s ", FormatDuration(s.ResampleFor))
 		}
 	}
+	if s.Incremental {
+		buf.WriteString("INCREMENTAL ")
+	}
 	fmt.Fprintf(&buf, "BEGIN %s END", s.Source.String())
 	return buf.String()
 }
@@ -2880,6 +3983,68 @@ func (s *CreateContinuousQueryStatement) validate() error {
 			return fmt.Errorf("FOR duration must be >= GROUP BY time duration: must be a minimum of %s, got %s", FormatDuration(interval), FormatDuration(s.ResampleFor))
 		}
 	}
+
+	if s.Incremental {
+		return s.validateIncremental()
+	}
+	return nil
+}
+
+// validateIncremental returns an error if the source of the continuous query
+// cannot be computed from the points as they are written.
+func (s *CreateContinuousQueryStatement) validateIncremental() error {
+	source := s.Source
+	if len(source.Sources) != 1 {
+		return errors.New("incremental continuous queries must select from a single measurement")
+	} else if m, ok := source.Sources[0].(*Measurement); !ok || m.Regex != nil {
+		return errors.New("incremental continuous queries must select from a single measurement")
+	}
+
+	for _, f := range source.Fields {
+		call, ok := f.Expr.(*Call)
+		if !ok {
+			return errors.New("incremental continuous queries only support count(), sum(), min(), max(), mean() and last()")
+		}
+		switch call.Name {
+		case "count", "sum", "min", "max", "mean", "last":
+		default:
+			return errors.New("incremental continuous queries only support count(), sum(), min(), max(), mean() and last()")
+		}
+		if len(call.Args) != 1 {
+			return fmt.Errorf("invalid number of arguments for %s, expected 1, got %d", call.Name, len(call.Args))
+		} else if _, ok := call.Args[0].(*VarRef); !ok {
+			return fmt.Errorf("incremental continuous queries only support fields as the argument to %s()", call.Name)
+		}
+	}
+
+	for _, d := range source.Dimensions {
+		switch expr := d.Expr.(type) {
+		case *Call:
+			if expr.Name != "time" {
+				return fmt.Errorf("incremental continuous queries do not support %s() dimensions", expr.Name)
+			}
+		case *VarRef:
+		default:
+			return errors.New("incremental continuous queries only support grouping by time() and tags")
+		}
+	}
+	if source.GroupByMonths() > 0 {
+		return errors.New("incremental continuous queries do not support calendar intervals")
+	}
+
+	var err error
+	WalkFunc(source.Condition, func(n Node) {
+		if ref, ok := n.(*VarRef); ok && strings.ToLower(ref.Val) != "time" && err == nil {
+			err = errors.New("incremental continuous queries only support conditions on time")
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if source.Limit != 0 || source.Offset != 0 || source.SLimit != 0 || source.SOffset != 0 {
+		return errors.New("incremental continuous queries do not support LIMIT, OFFSET, SLIMIT or SOFFSET")
+	}
 	return nil
 }
 
@@ -2904,6 +4069,57 @@ func (s *DropContinuousQueryStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// ShowMeasurementCardinalityStatement represents a command for counting the
+// measurements in a database.
+type ShowMeasurementCardinalityStatement struct {
+	// Database to query. If blank, use the default database.
+	Database string
+
+	// Count the measurements by walking the index instead of estimating the
+	// cardinality from the sketches.
+	Exact bool
+
+	// Measurement(s) that are counted.
+	Sources Sources
+
+	// An expression evaluated on a measurement name or tag.
+	Condition Expr
+}
+
+// String returns a string representation of the statement.
+func (s *ShowMeasurementCardinalityStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("SHOW ")
+	if s.Exact {
+		_, _ = buf.WriteString("EXACT ")
+	}
+	_, _ = buf.WriteString("MEASUREMENT CARDINALITY")
+
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	if s.Sources != nil {
+		_, _ = buf.WriteString(" FROM ")
+		_, _ = buf.WriteString(s.Sources.String())
+	}
+	if s.Condition != nil {
+		_, _ = buf.WriteString(" WHERE ")
+		_, _ = buf.WriteString(s.Condition.String())
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowMeasurementCardinalityStatement.
+func (s *ShowMeasurementCardinalityStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *ShowMeasurementCardinalityStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // ShowMeasurementsStatement represents a command for listing measurements.
 type ShowMeasurementsStatement struct {
 	// Database to query. If blank, use the default database.
@@ -3242,23 +4458,106 @@ func (s *ShowTagKeysStatement) String() string {
 	return buf.String()
 }
 
-// RequiredPrivileges returns the privilege(s) required to execute a ShowTagKeysStatement.
-func (s *ShowTagKeysStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+// RequiredPrivileges returns the privilege(s) required to execute a ShowTagKeysStatement.
+func (s *ShowTagKeysStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *ShowTagKeysStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// ShowTagValuesStatement represents a command for listing tag values.
+type ShowTagValuesStatement struct {
+	// Database to query. If blank, use the default database.
+	// The database can also be specified per source in the Sources.
+	Database string
+
+	// Data source that fields are extracted from.
+	Sources Sources
+
+	// Operation to use when selecting tag key(s).
+	Op Token
+
+	// Literal to compare the tag key(s) with.
+	TagKeyExpr Literal
+
+	// An expression evaluated on data point.
+	Condition Expr
+
+	// Fields to sort results by.
+	SortFields SortFields
+
+	// Maximum number of rows to be returned.
+	// Unlimited if zero.
+	Limit int
+
+	// Returns rows starting at an offset from the first row.
+	Offset int
+}
+
+// String returns a string representation of the statement.
+func (s *ShowTagValuesStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("SHOW TAG VALUES")
+
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	if s.Sources != nil {
+		_, _ = buf.WriteString(" FROM ")
+		_, _ = buf.WriteString(s.Sources.String())
+	}
+	_, _ = buf.WriteString(" WITH KEY ")
+	_, _ = buf.WriteString(s.Op.String())
+	_, _ = buf.WriteString(" ")
+	if lit, ok := s.TagKeyExpr.(*StringLiteral); ok {
+		_, _ = buf.WriteString(QuoteIdent(lit.Val))
+	} else {
+		_, _ = buf.WriteString(s.TagKeyExpr.String())
+	}
+	if s.Condition != nil {
+		_, _ = buf.WriteString(" WHERE ")
+		_, _ = buf.WriteString(s.Condition.String())
+	}
+	if len(s.SortFields) > 0 {
+		_, _ = buf.WriteString(" ORDER BY ")
+		_, _ = buf.WriteString(s.SortFields.String())
+	}
+	if s.Limit > 0 {
+		_, _ = buf.WriteString(" LIMIT ")
+		_, _ = buf.WriteString(strconv.Itoa(s.Limit))
+	}
+	if s.Offset > 0 {
+		_, _ = buf.WriteString(" OFFSET ")
+		_, _ = buf.WriteString(strconv.Itoa(s.Offset))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowTagValuesStatement.
+func (s *ShowTagValuesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
 }
 
 // DefaultDatabase returns the default database from the statement.
-func (s *ShowTagKeysStatement) DefaultDatabase() string {
+func (s *ShowTagValuesStatement) DefaultDatabase() string {
 	return s.Database
 }
 
-// ShowTagValuesStatement represents a command for listing tag values.
-type ShowTagValuesStatement struct {
+// ShowTagValuesCardinalityStatement represents a command for counting the
+// values of tag keys.
+type ShowTagValuesCardinalityStatement struct {
 	// Database to query. If blank, use the default database.
-	// The database can also be specified per source in the Sources.
 	Database string
 
-	// Data source that fields are extracted from.
+	// Accepted for symmetry with the other cardinality statements. There is
+	// no sketch for tag values so they are always counted exactly.
+	Exact bool
+
+	// Data source that tag values are counted for.
 	Sources Sources
 
 	// Operation to use when selecting tag key(s).
@@ -3269,22 +4568,16 @@ type ShowTagValuesStatement struct {
 
 	// An expression evaluated on data point.
 	Condition Expr
-
-	// Fields to sort results by.
-	SortFields SortFields
-
-	// Maximum number of rows to be returned.
-	// Unlimited if zero.
-	Limit int
-
-	// Returns rows starting at an offset from the first row.
-	Offset int
 }
 
 // String returns a string representation of the statement.
-func (s *ShowTagValuesStatement) String() string {
+func (s *ShowTagValuesCardinalityStatement) String() string {
 	var buf bytes.Buffer
-	_, _ = buf.WriteString("SHOW TAG VALUES")
+	_, _ = buf.WriteString("SHOW ")
+	if s.Exact {
+		_, _ = buf.WriteString("EXACT ")
+	}
+	_, _ = buf.WriteString("TAG VALUES CARDINALITY")
 
 	if s.Database != "" {
 		_, _ = buf.WriteString(" ON ")
@@ -3306,28 +4599,16 @@ func (s *ShowTagValuesStatement) String() string {
 		_, _ = buf.WriteString(" WHERE ")
 		_, _ = buf.WriteString(s.Condition.String())
 	}
-	if len(s.SortFields) > 0 {
-		_, _ = buf.WriteString(" ORDER BY ")
-		_, _ = buf.WriteString(s.SortFields.String())
-	}
-	if s.Limit > 0 {
-		_, _ = buf.WriteString(" LIMIT ")
-		_, _ = buf.WriteString(strconv.Itoa(s.Limit))
-	}
-	if s.Offset > 0 {
-		_, _ = buf.WriteString(" OFFSET ")
-		_, _ = buf.WriteString(strconv.Itoa(s.Offset))
-	}
 	return buf.String()
 }
 
-// RequiredPrivileges returns the privilege(s) required to execute a ShowTagValuesStatement.
-func (s *ShowTagValuesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+// RequiredPrivileges returns the privilege(s) required to execute a ShowTagValuesCardinalityStatement.
+func (s *ShowTagValuesCardinalityStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
 }
 
 // DefaultDatabase returns the default database from the statement.
-func (s *ShowTagValuesStatement) DefaultDatabase() string {
+func (s *ShowTagValuesCardinalityStatement) DefaultDatabase() string {
 	return s.Database
 }
 
@@ -3402,6 +4683,57 @@ func (s *ShowFieldKeysStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// ShowFieldKeyCardinalityStatement represents a command for counting the field
+// keys of measurements.
+type ShowFieldKeyCardinalityStatement struct {
+	// Database to query. If blank, use the default database.
+	Database string
+
+	// Accepted for symmetry with the other cardinality statements. There is
+	// no sketch for field keys so they are always counted exactly.
+	Exact bool
+
+	// Data sources that field keys are counted for.
+	Sources Sources
+
+	// An expression evaluated on a measurement name or tag.
+	Condition Expr
+}
+
+// String returns a string representation of the statement.
+func (s *ShowFieldKeyCardinalityStatement) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("SHOW ")
+	if s.Exact {
+		_, _ = buf.WriteString("EXACT ")
+	}
+	_, _ = buf.WriteString("FIELD KEY CARDINALITY")
+
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	if s.Sources != nil {
+		_, _ = buf.WriteString(" FROM ")
+		_, _ = buf.WriteString(s.Sources.String())
+	}
+	if s.Condition != nil {
+		_, _ = buf.WriteString(" WHERE ")
+		_, _ = buf.WriteString(s.Condition.String())
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowFieldKeyCardinalityStatement.
+func (s *ShowFieldKeyCardinalityStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *ShowFieldKeyCardinalityStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // Fields represents a list of fields.
 type Fields []*Field
 
@@ -3426,7 +4758,7 @@ func (a Fields) Names() []string {
 			names = append(names, expr.Val)
 		case *BinaryExpr:
 			names = append(names, walkNames(expr)...)
-		case *ParenExpr:
+		case *ParenExpr, *CaseExpr:
 			names = append(names, walkNames(expr)...)
 		}
 	}
@@ -3465,6 +4797,8 @@ func (f *Field) Name() string {
 	case *ParenExpr:
 		f := Field{Expr: expr.Expr}
 		return f.Name()
+	case *CaseExpr:
+		return "case"
 	case *VarRef:
 		return expr.Val
 	}
@@ -3513,10 +4847,15 @@ func (a Dimensions) Normalize() (time.Duration, []string) {
 	for _, dim := range a {
 		switch expr := dim.Expr.(type) {
 		case *Call:
-			lit, _ := expr.Args[0].(*DurationLiteral)
-			dur = lit.Val
-		case *VarRef:
-			tags = append(tags, expr.Val)
+			switch lit := expr.Args[0].(type) {
+			case *DurationLiteral:
+				dur = lit.Val
+			case *CalendarDurationLiteral:
+				dur = lit.Duration()
+			}
+		}
+		if key, ok := dim.TagKey(); ok {
+			tags = append(tags, key)
 		}
 	}
 
@@ -3525,11 +4864,36 @@ func (a Dimensions) Normalize() (time.Duration, []string) {
 
 // Dimension represents an expression that a select statement is grouped by.
 type Dimension struct {
-	Expr Expr
+	Expr  Expr
+	Alias string
 }
 
 // String returns a string representation of the dimension.
-func (d *Dimension) String() string { return d.Expr.String() }
+func (d *Dimension) String() string {
+	if d.Alias != "" {
+		return fmt.Sprintf("%s AS %s", d.Expr.String(), QuoteIdent(d.Alias))
+	}
+	return d.Expr.String()
+}
+
+// TagKey returns the tag key that the dimension groups series by. A derived
+// dimension groups series by its alias or, if it has none, by the tag it is
+// derived from. False is returned if the dimension is not a tag.
+func (d *Dimension) TagKey() (string, bool) {
+	switch expr := d.Expr.(type) {
+	case *VarRef:
+		return expr.Val, true
+	case *Call:
+		if !isDerivedDimension(expr) {
+			return "", false
+		} else if d.Alias != "" {
+			return d.Alias, true
+		} else if ref, ok := expr.Args[0].(*VarRef); ok {
+			return ref.Val, true
+		}
+	}
+	return "", false
+}
 
 // Measurements represents a list of measurements.
 type Measurements []*Measurement
@@ -3619,6 +4983,175 @@ func (s *SubQuery) String() string {
 	return fmt.Sprintf("(%s)", s.Statement.String())
 }
 
+// Join is a source that joins the series of several measurements on a set
+// of tag keys. Fields of each measurement are referenced by prefixing them
+// with the measurement's alias, such as "a.value".
+type Join struct {
+	Sources []*JoinSource
+	On      []string
+}
+
+// JoinSource is a measurement that is part of a join.
+type JoinSource struct {
+	Measurement *Measurement
+
+	// The alias used to reference fields of this measurement.
+	// If it is empty, the name of the measurement is used.
+	Alias string
+}
+
+// Name returns the name used to reference fields of the join source.
+func (s *JoinSource) Name() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Measurement.Name
+}
+
+// String returns a string representation of the join source.
+func (s *JoinSource) String() string {
+	if s.Alias == "" {
+		return s.Measurement.String()
+	}
+	return fmt.Sprintf("%s AS %s", s.Measurement.String(), QuoteIdent(s.Alias))
+}
+
+// String returns a string representation of the join.
+func (j *Join) String() string {
+	var buf bytes.Buffer
+	for i, js := range j.Sources {
+		if i > 0 {
+			_, _ = buf.WriteString(" JOIN ")
+		}
+		_, _ = buf.WriteString(js.String())
+	}
+	_, _ = buf.WriteString(" ON ")
+	for i, key := range j.On {
+		if i > 0 {
+			_, _ = buf.WriteString(", ")
+		}
+		_, _ = buf.WriteString(QuoteIdent(key))
+	}
+	return buf.String()
+}
+
+// Name returns the measurement name given to the joined series.
+func (j *Join) Name() string {
+	names := make([]string, len(j.Sources))
+	for i, js := range j.Sources {
+		names[i] = js.Measurement.Name
+	}
+	return strings.Join(names, "_")
+}
+
+// Source returns the join source with the given alias.
+func (j *Join) Source(alias string) *JoinSource {
+	for _, js := range j.Sources {
+		if js.Name() == alias {
+			return js
+		}
+	}
+	return nil
+}
+
+// Resolve returns the join source referenced by a variable name along with the
+// name of the variable within that source. It returns nil if the name is not
+// prefixed with the alias of one of the join sources.
+func (j *Join) Resolve(name string) (*JoinSource, string) {
+	if i := strings.Index(name, "."); i > 0 {
+		if js := j.Source(name[:i]); js != nil {
+			return js, name[i+1:]
+		}
+	}
+	return nil, name
+}
+
+// Condition returns the parts of cond that apply to the join source.
+// Conjunctions of cond that do not reference any join source apply to every
+// source. Conjunctions that reference another join source are left out and
+// are returned by CrossCondition instead.
+func (j *Join) Condition(js *JoinSource, cond Expr) Expr {
+	var other Expr
+	for _, expr := range splitConjunction(cond) {
+		if srcs := j.sources(expr); len(srcs) > 1 || (len(srcs) == 1 && srcs[0] != js) {
+			continue
+		}
+
+		expr = j.Unqualify(expr)
+		if other == nil {
+			other = expr
+		} else {
+			other = &BinaryExpr{Op: AND, LHS: other, RHS: expr}
+		}
+	}
+	return other
+}
+
+// CrossCondition returns the conjunctions of cond that reference more than
+// one join source. They can only be evaluated once the points of the join
+// sources have been aligned.
+func (j *Join) CrossCondition(cond Expr) Expr {
+	var other Expr
+	for _, expr := range splitConjunction(cond) {
+		if len(j.sources(expr)) < 2 {
+			continue
+		}
+
+		if other == nil {
+			other = expr
+		} else {
+			other = &BinaryExpr{Op: AND, LHS: other, RHS: expr}
+		}
+	}
+	return other
+}
+
+// sources returns the join sources referenced by expr.
+func (j *Join) sources(expr Expr) []*JoinSource {
+	var a []*JoinSource
+	WalkFunc(expr, func(n Node) {
+		if ref, ok := n.(*VarRef); ok {
+			if js, _ := j.Resolve(ref.Val); js != nil {
+				for _, other := range a {
+					if other == js {
+						return
+					}
+				}
+				a = append(a, js)
+			}
+		}
+	})
+	return a
+}
+
+// Unqualify returns a copy of expr with the join aliases removed from
+// the variable references.
+func (j *Join) Unqualify(expr Expr) Expr {
+	return RewriteExpr(CloneExpr(expr), func(e Expr) Expr {
+		if ref, ok := e.(*VarRef); ok {
+			if js, name := j.Resolve(ref.Val); js != nil {
+				return &VarRef{Val: name, Type: ref.Type}
+			}
+		}
+		return e
+	})
+}
+
+// splitConjunction returns the expressions that are combined with AND in expr.
+func splitConjunction(expr Expr) []Expr {
+	switch e := expr.(type) {
+	case nil:
+		return nil
+	case *ParenExpr:
+		return splitConjunction(e.Expr)
+	case *BinaryExpr:
+		if e.Op == AND {
+			return append(splitConjunction(e.LHS), splitConjunction(e.RHS)...)
+		}
+	}
+	return []Expr{expr}
+}
+
 // VarRef represents a reference to a variable.
 type VarRef struct {
 	Val  string
@@ -3821,6 +5354,21 @@ type DurationLiteral struct {
 // String returns a string representation of the literal.
 func (l *DurationLiteral) String() string { return FormatDuration(l.Val) }
 
+// CalendarDurationLiteral represents a duration of calendar months, such as
+// 1mo, 1q or 1y. The length of a month depends on the calendar, so these can
+// only be used as the interval of a GROUP BY time() dimension.
+type CalendarDurationLiteral struct {
+	Months int
+}
+
+// String returns a string representation of the literal.
+func (l *CalendarDurationLiteral) String() string { return FormatCalendarDuration(l.Months) }
+
+// Duration returns the longest possible length of the calendar duration.
+func (l *CalendarDurationLiteral) Duration() time.Duration {
+	return time.Duration(l.Months) * MaxMonthDuration
+}
+
 // nilLiteral represents a nil literal.
 // This is not available to the query language itself. It's only used internally.
 type nilLiteral struct{}
@@ -3905,6 +5453,9 @@ func (v *binaryExprNameVisitor) Visit(n Node) Visitor {
 	case *Call:
 		v.names = append(v.names, n.Name)
 		return nil
+	case *CaseExpr:
+		v.names = append(v.names, "case")
+		return nil
 	}
 	return v
 }
@@ -3917,6 +5468,41 @@ type ParenExpr struct {
 // String returns a string representation of the parenthesized expression.
 func (e *ParenExpr) String() string { return fmt.Sprintf("(%s)", e.Expr.String()) }
 
+// CaseExpr represents a conditional expression. The result of the first WHEN
+// clause with a true condition is returned. Otherwise the result of the ELSE
+// clause is returned or nil if there is no ELSE clause.
+type CaseExpr struct {
+	WhenClauses []*WhenClause
+	Else        Expr
+}
+
+// String returns a string representation of the conditional expression.
+func (e *CaseExpr) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("CASE")
+	for _, w := range e.WhenClauses {
+		_, _ = buf.WriteString(" ")
+		_, _ = buf.WriteString(w.String())
+	}
+	if e.Else != nil {
+		_, _ = buf.WriteString(" ELSE ")
+		_, _ = buf.WriteString(e.Else.String())
+	}
+	_, _ = buf.WriteString(" END")
+	return buf.String()
+}
+
+// WhenClause represents a condition and its result within a CASE expression.
+type WhenClause struct {
+	Condition Expr
+	Result    Expr
+}
+
+// String returns a string representation of the WHEN clause.
+func (w *WhenClause) String() string {
+	return fmt.Sprintf("WHEN %s THEN %s", w.Condition.String(), w.Result.String())
+}
+
 // RegexLiteral represents a regular expression.
 type RegexLiteral struct {
 	Val *regexp.Regexp
@@ -3977,10 +5563,18 @@ func CloneExpr(expr Expr) Expr {
 			args[i] = CloneExpr(arg)
 		}
 		return &Call{Name: expr.Name, Args: args}
+	case *CaseExpr:
+		whens := make([]*WhenClause, len(expr.WhenClauses))
+		for i, w := range expr.WhenClauses {
+			whens[i] = &WhenClause{Condition: CloneExpr(w.Condition), Result: CloneExpr(w.Result)}
+		}
+		return &CaseExpr{WhenClauses: whens, Else: CloneExpr(expr.Else)}
 	case *Distinct:
 		return &Distinct{Val: expr.Val}
 	case *DurationLiteral:
 		return &DurationLiteral{Val: expr.Val}
+	case *CalendarDurationLiteral:
+		return &CalendarDurationLiteral{Months: expr.Months}
 	case *IntegerLiteral:
 		return &IntegerLiteral{Val: expr.Val}
 	case *NumberLiteral:
@@ -4191,6 +5785,12 @@ func Walk(v Visitor, node Node) {
 			Walk(v, expr)
 		}
 
+	case *CaseExpr:
+		for _, w := range n.WhenClauses {
+			Walk(v, w)
+		}
+		Walk(v, n.Else)
+
 	case *CreateContinuousQueryStatement:
 		Walk(v, n.Source)
 
@@ -4210,6 +5810,12 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Sources)
 		Walk(v, n.Condition)
 
+	case *DropFieldStatement:
+		Walk(v, n.Sources)
+
+	case *ExplainStatement:
+		Walk(v, n.Statement)
+
 	case *Field:
 		Walk(v, n.Expr)
 
@@ -4218,6 +5824,11 @@ func Walk(v Visitor, node Node) {
 			Walk(v, c)
 		}
 
+	case *Join:
+		for _, js := range n.Sources {
+			Walk(v, js.Measurement)
+		}
+
 	case *ParenExpr:
 		Walk(v, n.Expr)
 
@@ -4230,12 +5841,21 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Dimensions)
 		Walk(v, n.Sources)
 		Walk(v, n.Condition)
+		Walk(v, n.Having)
 		Walk(v, n.SortFields)
 
 	case *ShowSeriesStatement:
 		Walk(v, n.Sources)
 		Walk(v, n.Condition)
 
+	case *ShowSeriesCardinalityStatement:
+		Walk(v, n.Sources)
+		Walk(v, n.Condition)
+
+	case *ShowMeasurementCardinalityStatement:
+		Walk(v, n.Sources)
+		Walk(v, n.Condition)
+
 	case *ShowTagKeysStatement:
 		Walk(v, n.Sources)
 		Walk(v, n.Condition)
@@ -4246,10 +5866,18 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Condition)
 		Walk(v, n.SortFields)
 
+	case *ShowTagValuesCardinalityStatement:
+		Walk(v, n.Sources)
+		Walk(v, n.Condition)
+
 	case *ShowFieldKeysStatement:
 		Walk(v, n.Sources)
 		Walk(v, n.SortFields)
 
+	case *ShowFieldKeyCardinalityStatement:
+		Walk(v, n.Sources)
+		Walk(v, n.Condition)
+
 	case SortFields:
 		for _, sf := range n {
 			Walk(v, sf)
@@ -4272,6 +5900,10 @@ func Walk(v Visitor, node Node) {
 		if n != nil {
 			Walk(v, n.Measurement)
 		}
+
+	case *WhenClause:
+		Walk(v, n.Condition)
+		Walk(v, n.Result)
 	}
 }
 
@@ -4315,6 +5947,11 @@ func Rewrite(r Rewriter, node Node) Node {
 		} else {
 			n.Condition = nil
 		}
+		if having := Rewrite(r, n.Having); having != nil {
+			n.Having = having.(Expr)
+		} else {
+			n.Having = nil
+		}
 
 	case *SubQuery:
 		n.Statement = Rewrite(r, n.Statement).(*SelectStatement)
@@ -4346,6 +5983,18 @@ func Rewrite(r Rewriter, node Node) Node {
 		for i, expr := range n.Args {
 			n.Args[i] = Rewrite(r, expr).(Expr)
 		}
+
+	case *CaseExpr:
+		for i, w := range n.WhenClauses {
+			n.WhenClauses[i] = Rewrite(r, w).(*WhenClause)
+		}
+		if n.Else != nil {
+			n.Else = Rewrite(r, n.Else).(Expr)
+		}
+
+	case *WhenClause:
+		n.Condition = Rewrite(r, n.Condition).(Expr)
+		n.Result = Rewrite(r, n.Result).(Expr)
 	}
 
 	return r.Rewrite(node)
@@ -4385,6 +6034,13 @@ func RewriteExpr(expr Expr, fn func(Expr) Expr) Expr {
 		for i, expr := range e.Args {
 			e.Args[i] = RewriteExpr(expr, fn)
 		}
+
+	case *CaseExpr:
+		for _, w := range e.WhenClauses {
+			w.Condition = RewriteExpr(w.Condition, fn)
+			w.Result = RewriteExpr(w.Result, fn)
+		}
+		e.Else = RewriteExpr(e.Else, fn)
 	}
 
 	return fn(expr)
@@ -4413,6 +6069,18 @@ func Eval(expr Expr, m map[string]interface{}) interface{} {
 		return expr.Val
 	case *VarRef:
 		return m[expr.Val]
+	case *Call:
+		if isMathFunction(expr) {
+			return evalMathCall(expr, m)
+		}
+		return nil
+	case *CaseExpr:
+		for _, w := range expr.WhenClauses {
+			if EvalBool(w.Condition, m) {
+				return Eval(w.Result, m)
+			}
+		}
+		return Eval(expr.Else, m)
 	default:
 		return nil
 	}
@@ -4667,6 +6335,18 @@ func EvalType(expr Expr, sources Sources, typmap TypeMapper) DataType {
 				if t := typmap.MapType(src, expr.Val); typ.LessThan(t) {
 					typ = t
 				}
+			case *Join:
+				// Map a qualified reference to its own source. Other
+				// references, such as tags, may come from any source.
+				js, name := src.Resolve(expr.Val)
+				for _, other := range src.Sources {
+					if js != nil && other != js {
+						continue
+					}
+					if t := typmap.MapType(other.Measurement, name); typ.LessThan(t) {
+						typ = t
+					}
+				}
 			case *SubQuery:
 				_, e := src.Statement.FieldExprByName(expr.Val)
 				if e != nil {
@@ -4677,7 +6357,7 @@ func EvalType(expr Expr, sources Sources, typmap TypeMapper) DataType {
 
 				if typ == Unknown {
 					for _, d := range src.Statement.Dimensions {
-						if d, ok := d.Expr.(*VarRef); ok && expr.Val == d.Val {
+						if key, ok := d.TagKey(); ok && expr.Val == key {
 							typ = Tag
 						}
 					}
@@ -4686,16 +6366,39 @@ func EvalType(expr Expr, sources Sources, typmap TypeMapper) DataType {
 		}
 		return typ
 	case *Call:
+		if isMathFunction(expr) {
+			if len(expr.Args) == 0 {
+				return Float
+			}
+			return mathFunctionType(expr.Name, EvalType(expr.Args[0], sources, typmap))
+		}
+
 		switch expr.Name {
-		case "mean", "median", "integral":
+		case "mean", "median", "integral", "percentile_approx", "time_weighted_avg",
+			"corr", "covar", "linear_regression", "zscore", "rate", "increase":
 			return Float
-		case "count":
+		case "count", "count_distinct_approx", "histogram", "state_duration", "state_count":
 			return Integer
 		default:
 			return EvalType(expr.Args[0], sources, typmap)
 		}
 	case *ParenExpr:
 		return EvalType(expr.Expr, sources, typmap)
+	case *CaseExpr:
+		// The result types are combined the same way as a binary expression.
+		var typ DataType
+		for _, w := range expr.WhenClauses {
+			if t := EvalType(w.Result, sources, typmap); typ.LessThan(t) {
+				typ = t
+			}
+		}
+		if t := EvalType(expr.Else, sources, typmap); typ.LessThan(t) {
+			typ = t
+		}
+		if typ == Tag {
+			return String
+		}
+		return typ
 	case *NumberLiteral:
 		return Float
 	case *IntegerLiteral:
@@ -4742,6 +6445,22 @@ func FieldDimensions(sources Sources, m FieldMapper) (fields map[string]DataType
 			for k := range d {
 				dimensions[k] = struct{}{}
 			}
+		case *Join:
+			for _, js := range src.Sources {
+				f, d, err := m.FieldDimensions(js.Measurement)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				for k, typ := range f {
+					if typ != Unknown {
+						fields[js.Name()+"."+k] = typ
+					}
+				}
+				for k := range d {
+					dimensions[k] = struct{}{}
+				}
+			}
 		case *SubQuery:
 			for _, f := range src.Statement.Fields {
 				k := f.Name()
@@ -4753,8 +6472,8 @@ func FieldDimensions(sources Sources, m FieldMapper) (fields map[string]DataType
 			}
 
 			for _, d := range src.Statement.Dimensions {
-				if expr, ok := d.Expr.(*VarRef); ok {
-					dimensions[expr.Val] = struct{}{}
+				if key, ok := d.TagKey(); ok {
+					dimensions[key] = struct{}{}
 				}
 			}
 		}
@@ -4786,6 +6505,8 @@ func reduce(expr Expr, valuer Valuer) Expr {
 		return reduceCall(expr, valuer)
 	case *ParenExpr:
 		return reduceParenExpr(expr, valuer)
+	case *CaseExpr:
+		return reduceCaseExpr(expr, valuer)
 	case *VarRef:
 		return reduceVarRef(expr, valuer)
 	case *nilLiteral:
@@ -5265,6 +6986,35 @@ func reduceCall(expr *Call, valuer Valuer) Expr {
 	return &Call{Name: expr.Name, Args: args}
 }
 
+func reduceCaseExpr(expr *CaseExpr, valuer Valuer) Expr {
+	other := &CaseExpr{Else: reduce(expr.Else, valuer)}
+	for _, w := range expr.WhenClauses {
+		cond := reduce(w.Condition, valuer)
+		result := reduce(w.Result, valuer)
+
+		// Conditions that are always false can be removed and the result of
+		// a condition that is always true is used if no earlier condition
+		// may have been true.
+		if isFalseLiteral(cond) {
+			continue
+		} else if isTrueLiteral(cond) {
+			other.Else = result
+			break
+		}
+		other.WhenClauses = append(other.WhenClauses, &WhenClause{Condition: cond, Result: result})
+	}
+
+	// Without a valuer the expression is reduced before it is evaluated on
+	// each point so a constant result remains a CASE expression.
+	if len(other.WhenClauses) == 0 && valuer != nil {
+		if other.Else == nil {
+			return &nilLiteral{}
+		}
+		return other.Else
+	}
+	return other
+}
+
 func reduceParenExpr(expr *ParenExpr, valuer Valuer) Expr {
 	subexpr := reduce(expr.Expr, valuer)
 	if subexpr, ok := subexpr.(*BinaryExpr); ok {
@@ -5349,8 +7099,11 @@ type containsVarRefVisitor struct {
 }
 
 func (v *containsVarRefVisitor) Visit(n Node) Visitor {
-	switch n.(type) {
+	switch n := n.(type) {
 	case *Call:
+		if isMathFunction(n) {
+			return v
+		}
 		return nil
 	case *VarRef:
 		v.contains = true
@@ -5361,7 +7114,7 @@ func (v *containsVarRefVisitor) Visit(n Node) Visitor {
 func IsSelector(expr Expr) bool {
 	if call, ok := expr.(*Call); ok {
 		switch call.Name {
-		case "first", "last", "min", "max", "percentile", "sample", "top", "bottom":
+		case "first", "last", "min", "max", "percentile", "sample", "top", "bottom", "mad_outliers", "lttb":
 			return true
 		}
 	}
@@ -3,6 +3,7 @@ package influxql_test
 import (
 	"fmt"
 	"go/importer"
+	"math"
 	"reflect"
 	"strings"
 	"testing"
@@ -493,6 +494,27 @@ func TestSelectStatement_RewriteFields(t *testing.T) {
 			stmt:    `SELECT value1 + value2, /value/ FROM cpu`,
 			rewrite: `SELECT value1::float + value2::integer, value1::float, value2::integer FROM cpu`,
 		},
+
+		// Rewrite histograms into a field for each bucket.
+		{
+			stmt:    `SELECT histogram(value1, linear(0, 10, 3)) FROM cpu`,
+			rewrite: `SELECT histogram(value1::float, 0.000, 10.000) AS histogram_value1_0, histogram(value1::float, 10.000, 20.000) AS histogram_value1_10, histogram(value1::float, 20.000, 30.000) AS histogram_value1_20 FROM cpu`,
+		},
+
+		{
+			stmt:    `SELECT histogram(value1, fixed(0, 1)), histogram(value2, fixed(0, 1)) FROM cpu`,
+			rewrite: `SELECT histogram(value1::float, 0.000, 1.000) AS histogram_value1_0, histogram(value2::integer, 0.000, 1.000) AS histogram_value2_0 FROM cpu`,
+		},
+
+		{
+			stmt:    `SELECT mean(value1), histogram(value2, exponential(1, 2, 2)) AS latency FROM cpu`,
+			rewrite: `SELECT mean(value1::float), histogram(value2::integer, 1.000, 2.000) AS latency_1, histogram(value2::integer, 2.000, 4.000) AS latency_2 FROM cpu`,
+		},
+
+		{
+			stmt:    `SELECT histogram(*, fixed(0, 0.5, 1)) FROM cpu`,
+			rewrite: `SELECT histogram(value1::float, 0.000, 0.500) AS histogram_value1_0, histogram(value1::float, 0.500, 1.000) AS "histogram_value1_0.5", histogram(value2::integer, 0.000, 0.500) AS histogram_value2_0, histogram(value2::integer, 0.500, 1.000) AS "histogram_value2_0.5" FROM cpu`,
+		},
 	}
 
 	for i, tt := range tests {
@@ -698,6 +720,14 @@ func TestSelectStatement_IsRawQuerySet(t *testing.T) {
 			stmt:  "select mean(value) from foo group by *",
 			isRaw: false,
 		},
+		{
+			stmt:  "select abs(value) from foo",
+			isRaw: true,
+		},
+		{
+			stmt:  "select round(mean(value)) from foo group by *",
+			isRaw: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1172,6 +1202,30 @@ func TestEval(t *testing.T) {
 		{in: `foo !~ /b.*/`, out: false, data: map[string]interface{}{"foo": "bar"}},
 		{in: `foo > 2 OR bar > 3`, out: true, data: map[string]interface{}{"foo": float64(4)}},
 		{in: `foo > 2 OR bar > 3`, out: true, data: map[string]interface{}{"bar": float64(4)}},
+
+		// Math functions.
+		{in: `abs(foo)`, out: float64(2.5), data: map[string]interface{}{"foo": float64(-2.5)}},
+		{in: `abs(foo)`, out: int64(3), data: map[string]interface{}{"foo": int64(-3)}},
+		{in: `round(foo)`, out: float64(-3), data: map[string]interface{}{"foo": float64(-2.5)}},
+		{in: `floor(foo)`, out: float64(2), data: map[string]interface{}{"foo": float64(2.7)}},
+		{in: `ceil(foo)`, out: float64(3), data: map[string]interface{}{"foo": float64(2.2)}},
+		{in: `sqrt(foo * 4)`, out: float64(4), data: map[string]interface{}{"foo": int64(4)}},
+		{in: `pow(foo, 2)`, out: float64(9), data: map[string]interface{}{"foo": float64(3)}},
+		{in: `log(foo, 10)`, out: float64(2), data: map[string]interface{}{"foo": float64(100)}},
+		{in: `ln(exp(foo))`, out: float64(1), data: map[string]interface{}{"foo": float64(1)}},
+		{in: `abs(foo)`, out: nil, data: map[string]interface{}{"foo": "bar"}},
+		{in: `abs(foo)`, out: nil, data: map[string]interface{}{"foo": int64(math.MinInt64)}},
+		{in: `sqrt(foo)`, out: nil, data: map[string]interface{}{"foo": float64(-3)}},
+		{in: `log(foo, 10)`, out: nil, data: map[string]interface{}{"foo": int64(0)}},
+		{in: `abs(foo)`, out: nil},
+		{in: `mean(foo)`, out: nil, data: map[string]interface{}{"foo": float64(1)}},
+
+		// Conditional expressions.
+		{in: `CASE WHEN foo >= 500 THEN 1 ELSE 0 END`, out: int64(1), data: map[string]interface{}{"foo": int64(503)}},
+		{in: `CASE WHEN foo >= 500 THEN 1 ELSE 0 END`, out: int64(0), data: map[string]interface{}{"foo": int64(200)}},
+		{in: `CASE WHEN foo >= 500 THEN 1 ELSE 0 END`, out: int64(0)},
+		{in: `CASE WHEN host = 'a' THEN foo * 2 WHEN host = 'b' THEN foo END`, out: float64(3), data: map[string]interface{}{"host": "b", "foo": float64(3)}},
+		{in: `CASE WHEN host = 'a' THEN foo * 2 WHEN host = 'b' THEN foo END`, out: nil, data: map[string]interface{}{"host": "c", "foo": float64(3)}},
 	} {
 		// Evaluate expression.
 		out := influxql.Eval(MustParseExpr(tt.in), tt.data)
@@ -1254,6 +1308,36 @@ func TestEvalType(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: `abs() with an integer`,
+			in:   `abs(value)`,
+			typ:  influxql.Integer,
+			data: EvalFixture{
+				"cpu": map[string]influxql.DataType{
+					"value": influxql.Integer,
+				},
+			},
+		},
+		{
+			name: `sqrt() with an integer`,
+			in:   `sqrt(value)`,
+			typ:  influxql.Float,
+			data: EvalFixture{
+				"cpu": map[string]influxql.DataType{
+					"value": influxql.Integer,
+				},
+			},
+		},
+		{
+			name: `round() of an aggregate`,
+			in:   `round(mean(value))`,
+			typ:  influxql.Float,
+			data: EvalFixture{
+				"cpu": map[string]influxql.DataType{
+					"value": influxql.Integer,
+				},
+			},
+		},
 	} {
 		sources := make([]influxql.Source, 0, len(tt.data))
 		for src := range tt.data {
@@ -1373,6 +1457,12 @@ func TestReduce(t *testing.T) {
 		{in: `foo = 'bar'`, out: `true`, data: map[string]interface{}{"foo": "bar"}},
 		{in: `foo = 'bar'`, out: `false`, data: map[string]interface{}{"foo": nil}},
 		{in: `foo <> 'bar'`, out: `false`, data: map[string]interface{}{"foo": nil}},
+
+		// Conditional expressions.
+		{in: `CASE WHEN 1 > 2 THEN 'a' WHEN foo > 1 THEN 'b' ELSE 'c' END`, out: `CASE WHEN foo > 1 THEN 'b' ELSE 'c' END`},
+		{in: `CASE WHEN foo > 1 THEN 'a' WHEN 1 < 2 THEN 'b' WHEN bar > 1 THEN 'c' END`, out: `CASE WHEN foo > 1 THEN 'a' ELSE 'b' END`},
+		{in: `CASE WHEN foo = 'bar' THEN 1 + 2 ELSE 0 END`, out: `3`, data: map[string]interface{}{"foo": "bar"}},
+		{in: `CASE WHEN foo = 'bar' THEN 1 END`, out: `nil`, data: map[string]interface{}{"foo": "baz"}},
 	} {
 		// Fold expression.
 		expr := influxql.Reduce(MustParseExpr(tt.in), tt.data)
@@ -1511,6 +1601,10 @@ func TestSelect_ColumnNames(t *testing.T) {
 			},
 			columns: []string{"timestamp", "value"},
 		},
+		{
+			stmt:    MustParseSelectStatement(`SELECT CASE WHEN status >= 500 THEN 1 END + 1, CASE WHEN host = 'a' THEN value END FROM cpu`),
+			columns: []string{"time", "case", "case_1"},
+		},
 	} {
 		columns := tt.stmt.ColumnNames()
 		if !reflect.DeepEqual(columns, tt.columns) {
@@ -1731,10 +1825,12 @@ func Test_EnforceHasDefaultDatabase(t *testing.T) {
 
 	// this is a list of statements that do not have a database context
 	exemptStatements := []string{
+		"AlterMeasurementStatement",
 		"CreateDatabaseStatement",
 		"CreateUserStatement",
 		"DeleteSeriesStatement",
 		"DropDatabaseStatement",
+		"DropFieldStatement",
 		"DropMeasurementStatement",
 		"DropSeriesStatement",
 		"DropShardStatement",
@@ -1745,6 +1841,7 @@ func Test_EnforceHasDefaultDatabase(t *testing.T) {
 		"SelectStatement",
 		"SetPasswordUserStatement",
 		"ShowContinuousQueriesStatement",
+		"ShowContinuousQueryStatusStatement",
 		"ShowDatabasesStatement",
 		"ShowDiagnosticsStatement",
 		"ShowGrantsForUserStatement",
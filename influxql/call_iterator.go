@@ -1,6 +1,7 @@
 package influxql
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -50,6 +51,20 @@ func NewCallIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
 		return newLastIterator(input, opt)
 	case "mean":
 		return newMeanIterator(input, opt)
+	case "percentile_approx":
+		return newTDigestIterator(input, opt)
+	case "count_distinct_approx":
+		return newHLLIterator(input, opt)
+	case "merge_hll":
+		return newHLLMergeIterator(input, opt)
+	case "histogram":
+		return newHistogramIterator(input, opt)
+	case "merge_histogram":
+		return newHistogramMergeIterator(input, opt)
+	case "corr", "covar":
+		return newCovarianceIterator(input, opt, false)
+	case "linear_regression":
+		return newCovarianceIterator(input, opt, true)
 	default:
 		return nil, fmt.Errorf("unsupported function call: %s", name)
 	}
@@ -849,6 +864,217 @@ func newPercentileIterator(input Iterator, opt IteratorOptions, percentile float
 	}
 }
 
+// newTDigestIterator returns an iterator that summarizes the points of each
+// window into a t-digest for a percentile_approx() call. The t-digests are
+// encoded in string points so they can be merged by another iterator.
+func newTDigestIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, StringPointEmitter) {
+			fn := NewFloatTDigestReducer()
+			return fn, fn
+		}
+		return newFloatReduceStringIterator(input, opt, createFn), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, StringPointEmitter) {
+			fn := NewIntegerTDigestReducer()
+			return fn, fn
+		}
+		return newIntegerReduceStringIterator(input, opt, createFn), nil
+	case StringIterator:
+		createFn := func() (StringPointAggregator, StringPointEmitter) {
+			fn := NewStringTDigestReducer()
+			return fn, fn
+		}
+		return newStringReduceStringIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported percentile_approx iterator type: %T", input)
+	}
+}
+
+// newPercentileApproxIterator returns an iterator that estimates a percentile
+// from the t-digests created by a percentile_approx() call iterator.
+func newPercentileApproxIterator(input Iterator, opt IteratorOptions, percentile float64) (Iterator, error) {
+	switch input := input.(type) {
+	case StringIterator:
+		createFn := func() (StringPointAggregator, FloatPointEmitter) {
+			fn := NewStringPercentileApproxReducer(percentile)
+			return fn, fn
+		}
+		return newStringReduceFloatIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported percentile_approx iterator type: %T", input)
+	}
+}
+
+// newCovarianceIterator returns an iterator that computes the co-moments of
+// the pairs in each window for a corr(), covar() or linear_regression() call.
+// The pairs are the values of the points and their first auxiliary field or,
+// when byTime is set, the times and values of the points. The co-moments are
+// encoded in string points so they can be merged by another iterator.
+func newCovarianceIterator(input Iterator, opt IteratorOptions, byTime bool) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, StringPointEmitter) {
+			fn := NewFloatCovarianceReducer(byTime)
+			return fn, fn
+		}
+		return newFloatReduceStringIterator(input, opt, createFn), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, StringPointEmitter) {
+			fn := NewIntegerCovarianceReducer(byTime)
+			return fn, fn
+		}
+		return newIntegerReduceStringIterator(input, opt, createFn), nil
+	case StringIterator:
+		createFn := func() (StringPointAggregator, StringPointEmitter) {
+			fn := NewStringCovarianceReducer()
+			return fn, fn
+		}
+		return newStringReduceStringIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported %s iterator type: %T", opt.Expr.(*Call).Name, input)
+	}
+}
+
+// newCovarianceStatIterator returns an iterator that computes a statistic
+// from the co-moments created by a covariance iterator.
+func newCovarianceStatIterator(input Iterator, opt IteratorOptions, stat string) (Iterator, error) {
+	switch input := input.(type) {
+	case StringIterator:
+		createFn := func() (StringPointAggregator, FloatPointEmitter) {
+			fn := NewStringCovarianceStatReducer(stat)
+			return fn, fn
+		}
+		return newStringReduceFloatIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported %s iterator type: %T", opt.Expr.(*Call).Name, input)
+	}
+}
+
+// newHLLIterator returns an iterator that adds the values of each window to
+// a HyperLogLog sketch for a count_distinct_approx() call. The sketches are
+// encoded in string points so they can be merged by another iterator.
+func newHLLIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, StringPointEmitter) {
+			fn := NewFloatHLLReducer()
+			return fn, fn
+		}
+		return newFloatReduceStringIterator(input, opt, createFn), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, StringPointEmitter) {
+			fn := NewIntegerHLLReducer()
+			return fn, fn
+		}
+		return newIntegerReduceStringIterator(input, opt, createFn), nil
+	case StringIterator:
+		createFn := func() (StringPointAggregator, StringPointEmitter) {
+			fn := NewStringHLLReducer()
+			return fn, fn
+		}
+		return newStringReduceStringIterator(input, opt, createFn), nil
+	case BooleanIterator:
+		createFn := func() (BooleanPointAggregator, StringPointEmitter) {
+			fn := NewBooleanHLLReducer()
+			return fn, fn
+		}
+		return newBooleanReduceStringIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported count_distinct_approx iterator type: %T", input)
+	}
+}
+
+// newHLLMergeIterator returns an iterator that merges the HyperLogLog
+// sketches created by a count_distinct_approx() call iterator.
+func newHLLMergeIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
+	switch input := input.(type) {
+	case StringIterator:
+		createFn := func() (StringPointAggregator, StringPointEmitter) {
+			fn := NewStringHLLMergeReducer()
+			return fn, fn
+		}
+		return newStringReduceStringIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported count_distinct_approx iterator type: %T", input)
+	}
+}
+
+// newCountDistinctApproxIterator returns an iterator that estimates the number
+// of distinct values from the sketches created by a count_distinct_approx()
+// call iterator.
+func newCountDistinctApproxIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
+	switch input := input.(type) {
+	case StringIterator:
+		createFn := func() (StringPointAggregator, IntegerPointEmitter) {
+			fn := NewStringCountDistinctApproxReducer()
+			return fn, fn
+		}
+		return newStringReduceIntegerIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported count_distinct_approx iterator type: %T", input)
+	}
+}
+
+// newHistogramIterator returns an iterator for operating on a histogram() call
+// that has been rewritten to count the points within the buckets between each
+// pair of adjacent bounds. The count of each bucket is an auxiliary field of
+// the points and the value is the count of every bucket.
+func newHistogramIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
+	call := opt.Expr.(*Call)
+	if got := len(call.Args); got < 3 {
+		return nil, fmt.Errorf("invalid number of arguments for histogram, expected at least 3, got %d", got)
+	}
+
+	bounds := make([]float64, len(call.Args)-1)
+	for i, arg := range call.Args[1:] {
+		switch arg := arg.(type) {
+		case *NumberLiteral:
+			bounds[i] = arg.Val
+		case *IntegerLiteral:
+			bounds[i] = float64(arg.Val)
+		default:
+			return nil, fmt.Errorf("expected number argument in histogram(), got %s", arg)
+		}
+		if i > 0 && bounds[i] <= bounds[i-1] {
+			return nil, errors.New("histogram bounds must be in increasing order")
+		}
+	}
+
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, IntegerPointEmitter) {
+			fn := NewFloatHistogramReducer(bounds)
+			return fn, fn
+		}
+		return newFloatReduceIntegerIterator(input, opt, createFn), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, IntegerPointEmitter) {
+			fn := NewIntegerHistogramReducer(bounds)
+			return fn, fn
+		}
+		return newIntegerReduceIntegerIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported histogram iterator type: %T", input)
+	}
+}
+
+// newHistogramMergeIterator returns an iterator that adds up the bucket counts
+// of the points created by a histogram() call iterator.
+func newHistogramMergeIterator(input Iterator, opt IteratorOptions) (Iterator, error) {
+	switch input := input.(type) {
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, IntegerPointEmitter) {
+			fn := NewIntegerHistogramMergeReducer()
+			return fn, fn
+		}
+		return newIntegerReduceIntegerIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported histogram iterator type: %T", input)
+	}
+}
+
 // NewFloatPercentileReduceSliceFunc returns the percentile value within a window.
 func NewFloatPercentileReduceSliceFunc(percentile float64) FloatReduceSliceFunc {
 	return func(a []FloatPoint) []FloatPoint {
@@ -1067,3 +1293,290 @@ func newIntegralIterator(input Iterator, opt IteratorOptions, interval Interval)
 		return nil, fmt.Errorf("unsupported integral iterator type: %T", input)
 	}
 }
+
+// newTimeWeightedAverageIterator returns an iterator for operating on a time_weighted_avg() call.
+func newTimeWeightedAverageIterator(input Iterator, opt IteratorOptions, method string) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, FloatPointEmitter) {
+			fn := NewFloatTimeWeightedAverageReducer(method, opt)
+			return fn, fn
+		}
+		return newFloatStreamFloatIterator(input, createFn, opt), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, FloatPointEmitter) {
+			fn := NewIntegerTimeWeightedAverageReducer(method, opt)
+			return fn, fn
+		}
+		return newIntegerStreamFloatIterator(input, createFn, opt), nil
+	default:
+		return nil, fmt.Errorf("unsupported time_weighted_avg iterator type: %T", input)
+	}
+}
+
+// newZScoreIterator returns an iterator for operating on a zscore() call.
+func newZScoreIterator(input Iterator, n int, opt IteratorOptions) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, FloatPointEmitter) {
+			fn := NewFloatZScoreReducer(n)
+			return fn, fn
+		}
+		return newFloatStreamFloatIterator(input, createFn, opt), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, FloatPointEmitter) {
+			fn := NewIntegerZScoreReducer(n)
+			return fn, fn
+		}
+		return newIntegerStreamFloatIterator(input, createFn, opt), nil
+	default:
+		return nil, fmt.Errorf("unsupported zscore iterator type: %T", input)
+	}
+}
+
+// newMADOutliersIterator returns an iterator for operating on a mad_outliers() call.
+func newMADOutliersIterator(input Iterator, opt IteratorOptions, threshold float64) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, FloatPointEmitter) {
+			fn := NewFloatMADOutliersReducer(threshold)
+			return fn, fn
+		}
+		return newFloatReduceFloatIterator(input, opt, createFn), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, IntegerPointEmitter) {
+			fn := NewIntegerMADOutliersReducer(threshold)
+			return fn, fn
+		}
+		return newIntegerReduceIntegerIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported mad_outliers iterator type: %T", input)
+	}
+}
+
+// newOutliersIterator returns an iterator for operating on an outliers()
+// call. The values of every series with the same name are compared at each
+// time and only the points of the series that deviate from the others are
+// returned. All of the points are read from the input before the first point
+// is returned.
+func newOutliersIterator(input Iterator, method string, threshold float64) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		return &floatOutliersIterator{input: input, method: method, threshold: threshold}, nil
+	case IntegerIterator:
+		return &integerOutliersIterator{input: input, method: method, threshold: threshold}, nil
+	default:
+		return nil, fmt.Errorf("unsupported outliers iterator type: %T", input)
+	}
+}
+
+// floatOutliersIterator returns the float points that are outliers among the
+// points with the same name and time.
+type floatOutliersIterator struct {
+	input     FloatIterator
+	method    string
+	threshold float64
+
+	points []FloatPoint
+	init   bool
+}
+
+// Stats returns stats from the input iterator.
+func (itr *floatOutliersIterator) Stats() IteratorStats { return itr.input.Stats() }
+
+// Close closes the iterator and all child iterators.
+func (itr *floatOutliersIterator) Close() error { return itr.input.Close() }
+
+// Next returns the next outlier.
+func (itr *floatOutliersIterator) Next() (*FloatPoint, error) {
+	if !itr.init {
+		itr.init = true
+
+		var values []float64
+		for {
+			p, err := itr.input.Next()
+			if err != nil {
+				return nil, err
+			} else if p == nil {
+				break
+			} else if p.Nil {
+				continue
+			}
+			itr.points = append(itr.points, *p.Clone())
+			values = append(values, p.Value)
+		}
+
+		keys := make([]outlierKey, len(itr.points))
+		for i, p := range itr.points {
+			keys[i] = outlierKey{name: p.Name, time: p.Time}
+		}
+		outliers := findOutliers(keys, values, itr.method, itr.threshold)
+
+		points := itr.points[:0]
+		for i, p := range itr.points {
+			if outliers[i] {
+				points = append(points, p)
+			}
+		}
+		itr.points = points
+	}
+
+	if len(itr.points) == 0 {
+		return nil, nil
+	}
+	p := &itr.points[0]
+	itr.points = itr.points[1:]
+	return p, nil
+}
+
+// integerOutliersIterator returns the integer points that are outliers among
+// the points with the same name and time.
+type integerOutliersIterator struct {
+	input     IntegerIterator
+	method    string
+	threshold float64
+
+	points []IntegerPoint
+	init   bool
+}
+
+// Stats returns stats from the input iterator.
+func (itr *integerOutliersIterator) Stats() IteratorStats { return itr.input.Stats() }
+
+// Close closes the iterator and all child iterators.
+func (itr *integerOutliersIterator) Close() error { return itr.input.Close() }
+
+// Next returns the next outlier.
+func (itr *integerOutliersIterator) Next() (*IntegerPoint, error) {
+	if !itr.init {
+		itr.init = true
+
+		var values []float64
+		for {
+			p, err := itr.input.Next()
+			if err != nil {
+				return nil, err
+			} else if p == nil {
+				break
+			} else if p.Nil {
+				continue
+			}
+			itr.points = append(itr.points, *p.Clone())
+			values = append(values, float64(p.Value))
+		}
+
+		keys := make([]outlierKey, len(itr.points))
+		for i, p := range itr.points {
+			keys[i] = outlierKey{name: p.Name, time: p.Time}
+		}
+		outliers := findOutliers(keys, values, itr.method, itr.threshold)
+
+		points := itr.points[:0]
+		for i, p := range itr.points {
+			if outliers[i] {
+				points = append(points, p)
+			}
+		}
+		itr.points = points
+	}
+
+	if len(itr.points) == 0 {
+		return nil, nil
+	}
+	p := &itr.points[0]
+	itr.points = itr.points[1:]
+	return p, nil
+}
+
+// outlierKey identifies the group of values that a value is compared with.
+type outlierKey struct {
+	name string
+	time int64
+}
+
+// findOutliers returns which of the values are outliers among the values with
+// the same key. The method is either "mad", where the threshold is the maximum
+// modified z-score, or "iqr", where the threshold is the multiple of the
+// interquartile range used for the fences.
+func findOutliers(keys []outlierKey, values []float64, method string, threshold float64) []bool {
+	groups := make(map[outlierKey][]int)
+	for i, key := range keys {
+		groups[key] = append(groups[key], i)
+	}
+
+	outliers := make([]bool, len(values))
+	for _, indexes := range groups {
+		group := make([]float64, len(indexes))
+		for i, index := range indexes {
+			group[i] = values[index]
+		}
+
+		switch method {
+		case "iqr":
+			for i, outlier := range iqrOutliers(group, threshold) {
+				outliers[indexes[i]] = outlier
+			}
+		default:
+			for i, score := range madScores(group) {
+				outliers[indexes[i]] = math.Abs(score) > threshold
+			}
+		}
+	}
+	return outliers
+}
+
+// newCounterIterator returns an iterator for operating on a rate() or
+// increase() call. The increase is calculated if unit is zero.
+func newCounterIterator(input Iterator, opt IteratorOptions, unit time.Duration, max float64) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, FloatPointEmitter) {
+			fn := NewFloatSeriesCounterReducer(unit, max, opt)
+			return fn, fn
+		}
+		return newFloatStreamFloatIterator(input, createFn, opt), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, FloatPointEmitter) {
+			fn := NewIntegerSeriesCounterReducer(unit, max, opt)
+			return fn, fn
+		}
+		return newIntegerStreamFloatIterator(input, createFn, opt), nil
+	default:
+		return nil, fmt.Errorf("unsupported %s iterator type: %T", opt.Expr.(*Call).Name, input)
+	}
+}
+
+// newLTTBIterator returns an iterator for operating on a lttb() call.
+func newLTTBIterator(input Iterator, opt IteratorOptions, n int) (Iterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		createFn := func() (FloatPointAggregator, FloatPointEmitter) {
+			fn := NewFloatLTTBReducer(n)
+			return fn, fn
+		}
+		return newFloatReduceFloatIterator(input, opt, createFn), nil
+	case IntegerIterator:
+		createFn := func() (IntegerPointAggregator, IntegerPointEmitter) {
+			fn := NewIntegerLTTBReducer(n)
+			return fn, fn
+		}
+		return newIntegerReduceIntegerIterator(input, opt, createFn), nil
+	default:
+		return nil, fmt.Errorf("unsupported lttb iterator type: %T", input)
+	}
+}
+
+// newStateIterator returns an iterator for operating on a state_duration() or
+// state_count() call. The points are counted if unit is zero.
+func newStateIterator(input Iterator, opt IteratorOptions, unit time.Duration) (Iterator, error) {
+	switch input := input.(type) {
+	case BooleanIterator:
+		createFn := func() (BooleanPointAggregator, IntegerPointEmitter) {
+			fn := NewBooleanStateReducer(unit)
+			return fn, fn
+		}
+		return newBooleanStreamIntegerIterator(input, createFn, opt), nil
+	default:
+		return nil, fmt.Errorf("unsupported %s iterator type: %T", opt.Expr.(*Call).Name, input)
+	}
+}
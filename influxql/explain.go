@@ -0,0 +1,254 @@
+package influxql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// QueryPlan describes how a SELECT statement is executed. It is produced by
+// the EXPLAIN statement.
+type QueryPlan struct {
+	// The statement after it has been rewritten for execution.
+	Statement *SelectStatement
+
+	// Set if the statement was executed while it was being explained.
+	Analyze bool
+
+	// The shards that are accessed by the statement.
+	Shards []uint64
+
+	// The time spent in each stage of the query.
+	Stages []QueryStage
+
+	// The iterators created against the underlying data.
+	Iterators []*IteratorPlan
+}
+
+// QueryStage records the time spent in a single stage of a query.
+type QueryStage struct {
+	Name     string
+	Duration time.Duration
+}
+
+// AddStage records the time spent in a stage of the query.
+func (p *QueryPlan) AddStage(name string, d time.Duration) {
+	p.Stages = append(p.Stages, QueryStage{Name: name, Duration: d})
+}
+
+// Lines returns the plan formatted as a tree with one line per element.
+func (p *QueryPlan) Lines() []string {
+	root := &planNode{label: "EXPLAIN"}
+	if p.Analyze {
+		root.label = "EXPLAIN ANALYZE"
+	}
+
+	if p.Statement != nil {
+		root.add("QUERY: " + p.Statement.String())
+	}
+
+	shards := make([]string, len(p.Shards))
+	for i, id := range p.Shards {
+		shards[i] = strconv.FormatUint(id, 10)
+	}
+	root.addf("SHARDS: %s", strings.Join(shards, ", "))
+
+	if len(p.Stages) > 0 {
+		stages := root.add("STAGES")
+		for _, stage := range p.Stages {
+			stages.addf("%s: %s", stage.Name, stage.Duration)
+		}
+	}
+
+	if p.Analyze {
+		var stats IteratorStats
+		for _, itr := range p.Iterators {
+			stats.Add(itr.Stats)
+		}
+		total := root.add("TOTAL")
+		addIteratorStats(total, stats)
+	}
+
+	iterators := root.addf("ITERATORS: %d", len(p.Iterators))
+	for _, itr := range p.Iterators {
+		itr.addTo(iterators, p.Analyze)
+	}
+	return root.lines()
+}
+
+// IteratorPlan describes an iterator that is requested from an
+// IteratorCreator while a statement is being explained.
+type IteratorPlan struct {
+	Source  *Measurement
+	Options IteratorOptions
+
+	// The estimated cost of the iterator. This is only set when the
+	// IteratorCreator implements IteratorCostEstimator.
+	Cost IteratorCost
+
+	// The time spent creating the iterator and the statistics collected
+	// while reading from it. These are only set when the statement is
+	// analyzed.
+	CreateTime time.Duration
+	Stats      IteratorStats
+
+	itr Iterator
+}
+
+// addTo adds a description of the iterator to the node.
+func (p *IteratorPlan) addTo(parent *planNode, analyze bool) {
+	n := parent.add("CREATE ITERATOR: " + p.Source.String())
+	if p.Options.Expr != nil {
+		n.add("EXPRESSION: " + p.Options.Expr.String())
+	}
+	if len(p.Options.Aux) > 0 {
+		aux := make([]string, len(p.Options.Aux))
+		for i, ref := range p.Options.Aux {
+			aux[i] = ref.String()
+		}
+		n.add("AUXILIARY FIELDS: " + strings.Join(aux, ", "))
+	}
+	if len(p.Options.Dimensions) > 0 {
+		n.add("DIMENSIONS: " + strings.Join(p.Options.Dimensions, ", "))
+	}
+	if p.Options.Interval.Length != 0 {
+		n.addf("INTERVAL: %s, STEP: %s", FormatDuration(p.Options.Interval.Length), FormatDuration(p.Options.Interval.Duration))
+	} else if p.Options.Interval.Months != 0 {
+		n.add("INTERVAL: " + FormatCalendarDuration(p.Options.Interval.Months))
+	} else if p.Options.Interval.Duration > 0 {
+		n.add("INTERVAL: " + FormatDuration(p.Options.Interval.Duration))
+	}
+	if p.Options.Condition != nil {
+		n.add("CONDITION: " + p.Options.Condition.String())
+	}
+	if p.Options.StartTime != MinTime || p.Options.EndTime != MaxTime {
+		n.addf("TIME RANGE: %s - %s",
+			time.Unix(0, p.Options.StartTime).UTC().Format(time.RFC3339Nano),
+			time.Unix(0, p.Options.EndTime).UTC().Format(time.RFC3339Nano),
+		)
+	}
+	n.addf("NUMBER OF SHARDS: %d", p.Cost.NumShards)
+	n.addf("NUMBER OF TAG SETS: %d", p.Cost.NumTagSets)
+	n.addf("NUMBER OF SERIES: %d", p.Cost.NumSeries)
+	n.addf("CACHED VALUES: %d", p.Cost.CachedValues)
+	n.addf("NUMBER OF FILES: %d", p.Cost.NumFiles)
+	n.addf("NUMBER OF BLOCKS: %d", p.Cost.BlocksRead)
+	n.addf("SIZE OF BLOCKS: %d", p.Cost.BlockSize)
+
+	if analyze {
+		n.addf("CREATE TIME: %s", p.CreateTime)
+		addIteratorStats(n, p.Stats)
+	}
+}
+
+// addIteratorStats adds the statistics collected from an iterator to the node.
+func addIteratorStats(n *planNode, stats IteratorStats) {
+	n.addf("SERIES READ: %d", stats.SeriesN)
+	n.addf("POINTS READ: %d", stats.PointN)
+	n.addf("BLOCKS DECODED: %d", stats.BlockN)
+	n.addf("CACHE HITS: %d", stats.CachePointN)
+	n.addf("TSM HITS: %d", stats.TSMPointN)
+}
+
+// ExplainIteratorCreator wraps an IteratorCreator and records every iterator
+// that is requested from it. Iterators are only created by the underlying
+// IteratorCreator when Analyze is set. Otherwise, a nil iterator is returned
+// so a statement can be planned without reading any data.
+type ExplainIteratorCreator struct {
+	IteratorCreator IteratorCreator
+	Analyze         bool
+
+	mu        sync.Mutex
+	iterators []*IteratorPlan
+}
+
+// NewExplainIteratorCreator returns a new instance of ExplainIteratorCreator.
+func NewExplainIteratorCreator(ic IteratorCreator, analyze bool) *ExplainIteratorCreator {
+	return &ExplainIteratorCreator{
+		IteratorCreator: ic,
+		Analyze:         analyze,
+	}
+}
+
+// CreateIterator records the iterator request and, if the statement is being
+// analyzed, creates the iterator from the underlying IteratorCreator.
+func (ic *ExplainIteratorCreator) CreateIterator(source *Measurement, opt IteratorOptions) (Iterator, error) {
+	plan := &IteratorPlan{Source: source, Options: opt}
+	if estimator, ok := ic.IteratorCreator.(IteratorCostEstimator); ok {
+		cost, err := estimator.IteratorCost(source, opt)
+		if err != nil {
+			return nil, err
+		}
+		plan.Cost = cost
+	}
+
+	ic.mu.Lock()
+	ic.iterators = append(ic.iterators, plan)
+	ic.mu.Unlock()
+
+	if !ic.Analyze {
+		return nil, nil
+	}
+
+	start := time.Now()
+	itr, err := ic.IteratorCreator.CreateIterator(source, opt)
+	if err != nil {
+		return nil, err
+	}
+	plan.CreateTime = time.Since(start)
+	plan.itr = itr
+	return itr, nil
+}
+
+// Iterators returns the iterators that have been requested. The statistics of
+// each created iterator are read so this should be called after the iterators
+// have been drained and before they are closed.
+func (ic *ExplainIteratorCreator) Iterators() []*IteratorPlan {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	for _, plan := range ic.iterators {
+		if plan.itr != nil {
+			plan.Stats = plan.itr.Stats()
+		}
+	}
+	return ic.iterators
+}
+
+// planNode is a node in the tree printed by EXPLAIN.
+type planNode struct {
+	label    string
+	children []*planNode
+}
+
+// add adds a child node with the label and returns it.
+func (n *planNode) add(label string) *planNode {
+	child := &planNode{label: label}
+	n.children = append(n.children, child)
+	return child
+}
+
+// addf adds a child node with a formatted label and returns it.
+func (n *planNode) addf(format string, a ...interface{}) *planNode {
+	return n.add(fmt.Sprintf(format, a...))
+}
+
+// lines returns the node and its children drawn as a tree.
+func (n *planNode) lines() []string {
+	lines := []string{n.label}
+	return n.appendChildren(lines, "")
+}
+
+func (n *planNode) appendChildren(lines []string, prefix string) []string {
+	for i, child := range n.children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		lines = append(lines, prefix+branch+child.label)
+		lines = child.appendChildren(lines, prefix+indent)
+	}
+	return lines
+}
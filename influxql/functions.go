@@ -1,12 +1,18 @@
 package influxql
 
 import (
+	"bytes"
 	"container/heap"
+	"encoding/binary"
+	"fmt"
 	"math"
 	"sort"
 	"time"
 
 	"github.com/influxdata/influxdb/influxql/neldermead"
+	"github.com/influxdata/influxdb/pkg/estimator"
+	"github.com/influxdata/influxdb/pkg/estimator/hll"
+	"github.com/influxdata/influxdb/pkg/estimator/tdigest"
 )
 
 // FloatMeanReducer calculates the mean of the aggregated points.
@@ -110,11 +116,11 @@ func (r *FloatDerivativeReducer) Emit() []FloatPoint {
 		// Calculate the derivative of successive points by dividing the
 		// difference of each value by the elapsed time normalized to the interval.
 		diff := r.curr.Value - r.prev.Value
-		elapsed := r.curr.Time - r.prev.Time
+		elapsed := derivativeUnits(r.interval, r.prev.Time, r.curr.Time)
 		if !r.ascending {
 			elapsed = -elapsed
 		}
-		value := diff / (float64(elapsed) / float64(r.interval.Duration))
+		value := diff / elapsed
 
 		// Mark this point as read by changing the previous point to nil.
 		r.prev.Nil = true
@@ -128,6 +134,18 @@ func (r *FloatDerivativeReducer) Emit() []FloatPoint {
 	return nil
 }
 
+// derivativeUnits returns the number of intervals that elapsed between two
+// times. Months are not of equal length so an interval of calendar months is
+// counted by the months between the times rather than by its duration.
+func derivativeUnits(interval Interval, start, end int64) float64 {
+	if interval.Months != 0 {
+		s, e := time.Unix(0, start).UTC(), time.Unix(0, end).UTC()
+		months := (e.Year()-s.Year())*12 + int(e.Month()-s.Month())
+		return float64(months) / float64(interval.Months)
+	}
+	return float64(end-start) / float64(interval.Duration)
+}
+
 // IntegerDerivativeReducer calculates the derivative of the aggregated points.
 type IntegerDerivativeReducer struct {
 	interval      Interval
@@ -167,11 +185,11 @@ func (r *IntegerDerivativeReducer) Emit() []FloatPoint {
 		// Calculate the derivative of successive points by dividing the
 		// difference of each value by the elapsed time normalized to the interval.
 		diff := float64(r.curr.Value - r.prev.Value)
-		elapsed := r.curr.Time - r.prev.Time
+		elapsed := derivativeUnits(r.interval, r.prev.Time, r.curr.Time)
 		if !r.ascending {
 			elapsed = -elapsed
 		}
-		value := diff / (float64(elapsed) / float64(r.interval.Duration))
+		value := diff / elapsed
 
 		// Mark this point as read by changing the previous point to nil.
 		r.prev.Nil = true
@@ -282,6 +300,353 @@ func (r *IntegerDifferenceReducer) Emit() []IntegerPoint {
 	return nil
 }
 
+// FloatCounterReducer calculates the increase of a counter within each window
+// or, when a unit is given, the rate of the increase per unit of time. A
+// decrease of the counter is treated as a reset where the counter restarted
+// from zero. If the maximum value of the counter is known, a decrease from the
+// upper half of the range is treated as a wraparound past the maximum instead.
+//
+// The increase is extrapolated from the first and last points of a window to
+// the boundaries of the window so it is not undercounted when the points are
+// not aligned with the windows.
+type FloatCounterReducer struct {
+	unit   time.Duration
+	max    float64
+	opt    IteratorOptions
+	window struct {
+		start int64
+		end   int64
+	}
+
+	// The earliest and latest points within the current window, the last
+	// point that was aggregated and the number of points.
+	first    FloatPoint
+	last     FloatPoint
+	prev     FloatPoint
+	n        int
+	increase float64
+
+	emitted []FloatPoint
+}
+
+// NewFloatCounterReducer creates a new FloatCounterReducer. The increase is
+// calculated if unit is zero and the rate is calculated otherwise. A max of
+// zero means the maximum value of the counter is unknown.
+func NewFloatCounterReducer(unit time.Duration, max float64, opt IteratorOptions) *FloatCounterReducer {
+	return &FloatCounterReducer{
+		unit: unit,
+		max:  max,
+		opt:  opt,
+	}
+}
+
+// AggregateFloat aggregates a point into the reducer. Points are expected to
+// be fed in order.
+func (r *FloatCounterReducer) AggregateFloat(p *FloatPoint) {
+	if r.n > 0 && (p.Time < r.window.start || p.Time >= r.window.end) {
+		r.flush()
+		r.n = 0
+	}
+
+	if r.n == 0 {
+		r.window.start, r.window.end = r.opt.Window(p.Time)
+		r.first, r.last, r.prev = *p, *p, *p
+		r.n, r.increase = 1, 0
+		return
+	} else if p.Time == r.prev.Time {
+		// Points with the same timestamp are skipped.
+		return
+	}
+
+	if r.opt.Ascending {
+		r.increase += r.counterIncrease(r.prev.Value, p.Value)
+		r.last = *p
+	} else {
+		r.increase += r.counterIncrease(p.Value, r.prev.Value)
+		r.first = *p
+	}
+	r.prev = *p
+	r.n++
+}
+
+// counterIncrease returns the increase of the counter between two values.
+func (r *FloatCounterReducer) counterIncrease(from, to float64) float64 {
+	if to >= from {
+		return to - from
+	} else if r.max > 0 && from > r.max/2 {
+		// The counter wrapped around to zero after its maximum value.
+		return r.max - from + to + 1
+	}
+	// The counter was reset so it has increased from zero.
+	return to
+}
+
+// flush extrapolates the increase of the current window and queues the
+// result to be emitted. Nothing is emitted for a window with a single point.
+func (r *FloatCounterReducer) flush() {
+	if r.n < 2 {
+		return
+	}
+
+	// Only extrapolate to the part of the window that is within the query.
+	start, end := r.window.start, r.window.end
+	if start < r.opt.StartTime {
+		start = r.opt.StartTime
+	}
+	if end > r.opt.EndTime+1 {
+		end = r.opt.EndTime + 1
+	}
+
+	// Extrapolate to the boundaries of the window if the first and last
+	// points are close to them. Otherwise, the series likely started or
+	// ended within the window so only extrapolate by half of the average
+	// distance between the points.
+	sampled := float64(r.last.Time - r.first.Time)
+	average := sampled / float64(r.n-1)
+	toStart, toEnd := float64(r.first.Time)-float64(start), float64(end)-float64(r.last.Time)
+
+	// A counter cannot be extrapolated to below zero.
+	if r.increase > 0 && r.first.Value >= 0 {
+		if toZero := sampled * r.first.Value / r.increase; toZero < toStart {
+			toStart = toZero
+		}
+	}
+
+	extrapolated := sampled
+	for _, d := range []float64{toStart, toEnd} {
+		if d < average*1.1 {
+			extrapolated += d
+		} else {
+			extrapolated += average / 2
+		}
+	}
+	value := r.increase * extrapolated / sampled
+
+	if r.unit != 0 {
+		// The rate is over the whole window unless the window is unbounded.
+		elapsed := extrapolated
+		if start != MinTime && end <= MaxTime {
+			elapsed = float64(end - start)
+		}
+		value /= elapsed / float64(r.unit)
+	}
+	r.emitted = append(r.emitted, FloatPoint{
+		Time:       r.window.start,
+		Value:      value,
+		Aggregated: uint32(r.n),
+	})
+}
+
+// Emit emits the values of the windows that have been completed.
+func (r *FloatCounterReducer) Emit() []FloatPoint {
+	points := r.emitted
+	r.emitted = nil
+	return points
+}
+
+// Close calculates the value of the last window so it is emitted.
+func (r *FloatCounterReducer) Close() error {
+	r.flush()
+	r.n = 0
+	return nil
+}
+
+// IntegerCounterReducer calculates the increase of a counter within each
+// window or, when a unit is given, the rate of the increase per unit of time.
+type IntegerCounterReducer struct {
+	fn *FloatCounterReducer
+}
+
+// NewIntegerCounterReducer creates a new IntegerCounterReducer.
+func NewIntegerCounterReducer(unit time.Duration, max float64, opt IteratorOptions) *IntegerCounterReducer {
+	return &IntegerCounterReducer{
+		fn: NewFloatCounterReducer(unit, max, opt),
+	}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerCounterReducer) AggregateInteger(p *IntegerPoint) {
+	r.fn.AggregateFloat(&FloatPoint{Time: p.Time, Value: float64(p.Value)})
+}
+
+// Emit emits the values of the windows that have been completed.
+func (r *IntegerCounterReducer) Emit() []FloatPoint {
+	return r.fn.Emit()
+}
+
+// Close calculates the value of the last window so it is emitted.
+func (r *IntegerCounterReducer) Close() error {
+	return r.fn.Close()
+}
+
+// FloatSeriesCounterReducer calculates the increase or the rate of the counter
+// of each series separately and sums them within each window. Counters of
+// different series cannot be compared with each other so the points of a
+// series are only compared with the points of the same series. The series of
+// a point is identified by its auxiliary fields.
+type FloatSeriesCounterReducer struct {
+	unit   time.Duration
+	max    float64
+	opt    IteratorOptions
+	series map[string]*FloatCounterReducer
+	sums   map[int64]*FloatPoint
+
+	emitted []FloatPoint
+}
+
+// NewFloatSeriesCounterReducer creates a new FloatSeriesCounterReducer. The
+// arguments are the same as the arguments of NewFloatCounterReducer.
+func NewFloatSeriesCounterReducer(unit time.Duration, max float64, opt IteratorOptions) *FloatSeriesCounterReducer {
+	return &FloatSeriesCounterReducer{
+		unit:   unit,
+		max:    max,
+		opt:    opt,
+		series: make(map[string]*FloatCounterReducer),
+		sums:   make(map[int64]*FloatPoint),
+	}
+}
+
+// AggregateFloat aggregates a point into the reducer of its series. Points
+// of each series are expected to be fed in order.
+func (r *FloatSeriesCounterReducer) AggregateFloat(p *FloatPoint) {
+	var buf bytes.Buffer
+	for _, v := range p.Aux {
+		if s, ok := v.(string); ok {
+			buf.WriteString(s)
+		}
+		buf.WriteByte(0)
+	}
+	key := buf.String()
+
+	fn := r.series[key]
+	if fn == nil {
+		fn = NewFloatCounterReducer(r.unit, r.max, r.opt)
+		r.series[key] = fn
+	}
+	fn.AggregateFloat(p)
+	r.add(fn.Emit())
+}
+
+// add adds the values of the windows of a series to the sums of the windows.
+func (r *FloatSeriesCounterReducer) add(points []FloatPoint) {
+	for _, p := range points {
+		sum := r.sums[p.Time]
+		if sum == nil {
+			sum = &FloatPoint{Time: p.Time}
+			r.sums[p.Time] = sum
+		}
+		sum.Value += p.Value
+		sum.Aggregated += p.Aggregated
+	}
+}
+
+// Emit emits the sums of the windows once every series has been read. The
+// points are returned in reverse order since they are read from the end.
+func (r *FloatSeriesCounterReducer) Emit() []FloatPoint {
+	points := r.emitted
+	r.emitted = nil
+	return points
+}
+
+// Close calculates the value of the last window of every series and queues
+// the sums of the windows to be emitted.
+func (r *FloatSeriesCounterReducer) Close() error {
+	for _, fn := range r.series {
+		fn.Close()
+		r.add(fn.Emit())
+	}
+	r.series = nil
+
+	points := make([]FloatPoint, 0, len(r.sums))
+	for _, p := range r.sums {
+		points = append(points, *p)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if r.opt.Ascending {
+			return points[i].Time > points[j].Time
+		}
+		return points[i].Time < points[j].Time
+	})
+	r.emitted = append(r.emitted, points...)
+	r.sums = nil
+	return nil
+}
+
+// IntegerSeriesCounterReducer calculates the increase or the rate of the
+// counter of each series separately and sums them within each window.
+type IntegerSeriesCounterReducer struct {
+	fn *FloatSeriesCounterReducer
+}
+
+// NewIntegerSeriesCounterReducer creates a new IntegerSeriesCounterReducer.
+func NewIntegerSeriesCounterReducer(unit time.Duration, max float64, opt IteratorOptions) *IntegerSeriesCounterReducer {
+	return &IntegerSeriesCounterReducer{
+		fn: NewFloatSeriesCounterReducer(unit, max, opt),
+	}
+}
+
+// AggregateInteger aggregates a point into the reducer of its series.
+func (r *IntegerSeriesCounterReducer) AggregateInteger(p *IntegerPoint) {
+	r.fn.AggregateFloat(&FloatPoint{Time: p.Time, Value: float64(p.Value), Aux: p.Aux})
+}
+
+// Emit emits the sums of the windows once every series has been read.
+func (r *IntegerSeriesCounterReducer) Emit() []FloatPoint {
+	return r.fn.Emit()
+}
+
+// Close queues the sums of the windows to be emitted.
+func (r *IntegerSeriesCounterReducer) Close() error {
+	return r.fn.Close()
+}
+
+// BooleanStateReducer calculates how long a series has been in a state or,
+// when no unit is given, for how many consecutive points. The value of each
+// point is whether the series is in the state at that point.
+type BooleanStateReducer struct {
+	unit  time.Duration
+	start int64
+	count int64
+	curr  BooleanPoint
+}
+
+// NewBooleanStateReducer creates a new BooleanStateReducer. The points are
+// counted if unit is zero.
+func NewBooleanStateReducer(unit time.Duration) *BooleanStateReducer {
+	return &BooleanStateReducer{unit: unit}
+}
+
+// AggregateBoolean aggregates a point into the reducer and updates the current state.
+func (r *BooleanStateReducer) AggregateBoolean(p *BooleanPoint) {
+	r.curr = *p
+	if !p.Value {
+		r.count = 0
+		return
+	} else if r.count == 0 {
+		r.start = p.Time
+	}
+	r.count++
+}
+
+// Emit emits the duration or number of points the series has been in the
+// state. If the series is not in the state at the current point, -1 is
+// emitted.
+func (r *BooleanStateReducer) Emit() []IntegerPoint {
+	value := int64(-1)
+	if r.count > 0 {
+		if r.unit == 0 {
+			value = r.count
+		} else {
+			elapsed := r.curr.Time - r.start
+			if elapsed < 0 {
+				elapsed = -elapsed
+			}
+			value = elapsed / int64(r.unit)
+		}
+	}
+	return []IntegerPoint{{Time: r.curr.Time, Value: value}}
+}
+
 // FloatMovingAverageReducer calculates the moving average of the aggregated points.
 type FloatMovingAverageReducer struct {
 	pos  int
@@ -376,6 +741,88 @@ func (r *IntegerMovingAverageReducer) Emit() []FloatPoint {
 	}
 }
 
+// FloatZScoreReducer calculates the z-score of each point against the mean and
+// standard deviation of a moving window of points.
+type FloatZScoreReducer struct {
+	pos  int
+	curr FloatPoint
+	buf  []float64
+}
+
+// NewFloatZScoreReducer creates a new FloatZScoreReducer.
+func NewFloatZScoreReducer(n int) *FloatZScoreReducer {
+	return &FloatZScoreReducer{
+		buf: make([]float64, 0, n),
+	}
+}
+
+// AggregateFloat aggregates a point into the reducer and updates the current window.
+func (r *FloatZScoreReducer) AggregateFloat(p *FloatPoint) {
+	if len(r.buf) != cap(r.buf) {
+		r.buf = append(r.buf, p.Value)
+	} else {
+		r.buf[r.pos] = p.Value
+	}
+	r.curr.Time, r.curr.Value = p.Time, p.Value
+	r.pos++
+	if r.pos >= cap(r.buf) {
+		r.pos = 0
+	}
+}
+
+// Emit emits the z-score of the last point. Emit should be called after every
+// call to AggregateFloat and it will produce one point if there is enough data
+// to fill a window and the values within it are not all the same, otherwise
+// it will produce zero points.
+func (r *FloatZScoreReducer) Emit() []FloatPoint {
+	if len(r.buf) != cap(r.buf) {
+		return []FloatPoint{}
+	}
+
+	var mean float64
+	for _, v := range r.buf {
+		mean += v
+	}
+	mean /= float64(len(r.buf))
+
+	var variance float64
+	for _, v := range r.buf {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(r.buf)-1))
+	if stddev == 0 {
+		return []FloatPoint{}
+	}
+	return []FloatPoint{
+		{
+			Value:      (r.curr.Value - mean) / stddev,
+			Time:       r.curr.Time,
+			Aggregated: uint32(len(r.buf)),
+		},
+	}
+}
+
+// IntegerZScoreReducer calculates the z-score of each point against the mean
+// and standard deviation of a moving window of points.
+type IntegerZScoreReducer struct {
+	fn *FloatZScoreReducer
+}
+
+// NewIntegerZScoreReducer creates a new IntegerZScoreReducer.
+func NewIntegerZScoreReducer(n int) *IntegerZScoreReducer {
+	return &IntegerZScoreReducer{fn: NewFloatZScoreReducer(n)}
+}
+
+// AggregateInteger aggregates a point into the reducer and updates the current window.
+func (r *IntegerZScoreReducer) AggregateInteger(p *IntegerPoint) {
+	r.fn.AggregateFloat(&FloatPoint{Time: p.Time, Value: float64(p.Value)})
+}
+
+// Emit emits the z-score of the last point.
+func (r *IntegerZScoreReducer) Emit() []FloatPoint {
+	return r.fn.Emit()
+}
+
 // FloatCumulativeSumReducer cumulates the values from each point.
 type FloatCumulativeSumReducer struct {
 	curr FloatPoint
@@ -990,6 +1437,261 @@ func (r *IntegerIntegralReducer) Close() error {
 	return nil
 }
 
+// FloatTimeWeightedAverageReducer calculates the average of the aggregated
+// points weighted by the time each value was held within the window.
+type FloatTimeWeightedAverageReducer struct {
+	linear bool
+	opt    IteratorOptions
+	window struct {
+		start int64
+		end   int64
+	}
+
+	// The points within the current window and the closest points on either
+	// side of it. Points before the start time of the query are only used to
+	// find the value at the start of the first window.
+	points []FloatPoint
+	before *FloatPoint
+	after  *FloatPoint
+
+	emitted []FloatPoint
+}
+
+// NewFloatTimeWeightedAverageReducer creates a new FloatTimeWeightedAverageReducer.
+// The method is either "step", where each value is held until the next point,
+// or "linear", where values are interpolated between points.
+func NewFloatTimeWeightedAverageReducer(method string, opt IteratorOptions) *FloatTimeWeightedAverageReducer {
+	return &FloatTimeWeightedAverageReducer{
+		linear: method == "linear",
+		opt:    opt,
+	}
+}
+
+// AggregateFloat aggregates a point into the reducer. Points are expected to
+// be fed in order.
+func (r *FloatTimeWeightedAverageReducer) AggregateFloat(p *FloatPoint) {
+	if p.Time < r.opt.StartTime {
+		v := *p
+		r.before = &v
+		return
+	} else if len(r.points) == 0 {
+		// The windows between the start of the query and the first point
+		// hold the value of the point before the query. When descending,
+		// the windows after the last point hold the value of that point.
+		start, end := r.opt.Window(p.Time)
+		if r.opt.Ascending && r.before != nil {
+			first, _ := r.opt.Window(r.opt.StartTime)
+			r.fill(r.before, p, first, start)
+		} else if !r.opt.Ascending {
+			r.fill(p, nil, end, r.lastWindowEnd())
+		}
+		r.window.start, r.window.end = start, end
+		r.points = append(r.points, *p)
+		return
+	}
+
+	// Average the current window once a point from another window is seen.
+	// That point is the closest one on the far side of the window. Any
+	// windows between the two points do not have any points of their own.
+	if r.opt.Ascending && p.Time >= r.window.end {
+		r.flush(r.before, p)
+		v := r.points[len(r.points)-1]
+		r.before = &v
+		r.points = r.points[:0]
+		start, end := r.opt.Window(p.Time)
+		r.fill(&v, p, r.window.end, start)
+		r.window.start, r.window.end = start, end
+	} else if !r.opt.Ascending && p.Time < r.window.start {
+		r.flush(p, r.after)
+		v := r.points[len(r.points)-1]
+		r.after = &v
+		r.points = r.points[:0]
+		start, end := r.opt.Window(p.Time)
+		r.fill(p, &v, end, r.window.start)
+		r.window.start, r.window.end = start, end
+	}
+	r.points = append(r.points, *p)
+}
+
+// flush averages the points in the current window and queues the result to
+// be emitted.
+func (r *FloatTimeWeightedAverageReducer) flush(before, after *FloatPoint) {
+	points := r.points
+	if !r.opt.Ascending {
+		points = make([]FloatPoint, len(r.points))
+		for i, p := range r.points {
+			points[len(points)-i-1] = p
+		}
+	}
+
+	// Only average over the part of the window that is within the query.
+	start, end := r.window.start, r.window.end
+	if start < r.opt.StartTime {
+		start = r.opt.StartTime
+	}
+	if end > r.opt.EndTime+1 {
+		end = r.opt.EndTime + 1
+	}
+
+	// Begin at the value the series had at the start of the window if a
+	// point before the window is known. Otherwise, begin at the first point.
+	prev := points[0]
+	if before != nil && start < prev.Time {
+		value := before.Value
+		if r.linear {
+			value = linearFloat(start, before.Time, prev.Time, before.Value, prev.Value)
+		}
+		prev = FloatPoint{Time: start, Value: value}
+	}
+	first := prev.Time
+
+	var sum float64
+	for _, p := range points {
+		sum += r.area(prev, p)
+		prev = p
+	}
+
+	// Continue to the end of the window using the next point if there is one.
+	// Otherwise, the last value is held until the end of the window as long
+	// as the end is bounded.
+	if prev.Time < end {
+		if after != nil {
+			value := prev.Value
+			if r.linear {
+				value = linearFloat(end, prev.Time, after.Time, prev.Value, after.Value)
+			}
+			sum += r.area(prev, FloatPoint{Time: end, Value: value})
+			prev.Time = end
+		} else if end <= MaxTime {
+			sum += r.area(prev, FloatPoint{Time: end, Value: prev.Value})
+			prev.Time = end
+		}
+	}
+
+	value := prev.Value
+	if elapsed := prev.Time - first; elapsed > 0 {
+		value = sum / float64(elapsed)
+	}
+	r.emitted = append(r.emitted, FloatPoint{Time: r.window.start, Value: value})
+}
+
+// fill queues the averages of the windows that start between from and to.
+// These windows do not contain any points so the value of prev is held over
+// each window or, with linear interpolation, interpolated toward next.
+func (r *FloatTimeWeightedAverageReducer) fill(prev, next *FloatPoint, from, to int64) {
+	if r.opt.Interval.IsZero() {
+		return
+	}
+
+	var points []FloatPoint
+	for t := from; t < to; {
+		windowStart, windowEnd := r.opt.Window(t)
+		t = windowEnd
+
+		start, end := windowStart, windowEnd
+		if start < r.opt.StartTime {
+			start = r.opt.StartTime
+		}
+		if end > r.opt.EndTime+1 {
+			end = r.opt.EndTime + 1
+		}
+
+		value := prev.Value
+		if r.linear && next != nil {
+			value = (linearFloat(start, prev.Time, next.Time, prev.Value, next.Value) +
+				linearFloat(end, prev.Time, next.Time, prev.Value, next.Value)) / 2
+		}
+		points = append(points, FloatPoint{Time: windowStart, Value: value})
+	}
+
+	if !r.opt.Ascending {
+		for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+			points[i], points[j] = points[j], points[i]
+		}
+	}
+	r.emitted = append(r.emitted, points...)
+}
+
+// lastWindowEnd returns the end of the last window of the query. Returns
+// MinTime when the query does not have an end time so no windows are filled.
+func (r *FloatTimeWeightedAverageReducer) lastWindowEnd() int64 {
+	if r.opt.EndTime >= MaxTime {
+		return MinTime
+	}
+	_, end := r.opt.Window(r.opt.EndTime)
+	return end
+}
+
+// area returns the area between two points using the interpolation method.
+func (r *FloatTimeWeightedAverageReducer) area(prev, curr FloatPoint) float64 {
+	elapsed := float64(curr.Time - prev.Time)
+	if r.linear {
+		// Use the trapezium rule in the same way as integral().
+		return 0.5 * (prev.Value + curr.Value) * elapsed
+	}
+	return prev.Value * elapsed
+}
+
+// Emit emits the time-weighted averages of the windows that have been completed.
+// The points are returned in reverse order since they are read from the end.
+func (r *FloatTimeWeightedAverageReducer) Emit() []FloatPoint {
+	points := r.emitted
+	for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+	r.emitted = nil
+	return points
+}
+
+// Close averages the last window so it is emitted. The windows up to the end
+// of the query hold the last value when ascending. When descending, the
+// windows from the start of the query hold the value of the point before it.
+func (r *FloatTimeWeightedAverageReducer) Close() error {
+	if len(r.points) > 0 {
+		r.flush(r.before, r.after)
+		v := r.points[len(r.points)-1]
+		if r.opt.Ascending {
+			r.fill(&v, nil, r.window.end, r.lastWindowEnd())
+		} else if r.before != nil {
+			first, _ := r.opt.Window(r.opt.StartTime)
+			r.fill(r.before, &v, first, r.window.start)
+		}
+		r.points = nil
+	} else if r.before != nil {
+		first, _ := r.opt.Window(r.opt.StartTime)
+		r.fill(r.before, nil, first, r.lastWindowEnd())
+	}
+	return nil
+}
+
+// IntegerTimeWeightedAverageReducer calculates the average of the aggregated
+// points weighted by the time each value was held within the window.
+type IntegerTimeWeightedAverageReducer struct {
+	fn *FloatTimeWeightedAverageReducer
+}
+
+// NewIntegerTimeWeightedAverageReducer creates a new IntegerTimeWeightedAverageReducer.
+func NewIntegerTimeWeightedAverageReducer(method string, opt IteratorOptions) *IntegerTimeWeightedAverageReducer {
+	return &IntegerTimeWeightedAverageReducer{
+		fn: NewFloatTimeWeightedAverageReducer(method, opt),
+	}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerTimeWeightedAverageReducer) AggregateInteger(p *IntegerPoint) {
+	r.fn.AggregateFloat(&FloatPoint{Time: p.Time, Value: float64(p.Value)})
+}
+
+// Emit emits the time-weighted averages of the windows that have been completed.
+func (r *IntegerTimeWeightedAverageReducer) Emit() []FloatPoint {
+	return r.fn.Emit()
+}
+
+// Close averages the last window so it is emitted.
+func (r *IntegerTimeWeightedAverageReducer) Close() error {
+	return r.fn.Close()
+}
+
 type FloatTopReducer struct {
 	h *floatPointsByFunc
 }
@@ -1161,3 +1863,921 @@ func (r *IntegerBottomReducer) Emit() []IntegerPoint {
 	sort.Sort(sort.Reverse(&h))
 	return points
 }
+
+// FloatTDigestReducer summarizes the aggregated points into a t-digest.
+type FloatTDigestReducer struct {
+	digest *tdigest.TDigest
+}
+
+// NewFloatTDigestReducer creates a new FloatTDigestReducer.
+func NewFloatTDigestReducer() *FloatTDigestReducer {
+	return &FloatTDigestReducer{digest: tdigest.New()}
+}
+
+// AggregateFloat aggregates a point into the reducer.
+func (r *FloatTDigestReducer) AggregateFloat(p *FloatPoint) {
+	r.digest.Add(p.Value)
+}
+
+// Emit emits the encoded t-digest as a single point.
+func (r *FloatTDigestReducer) Emit() []StringPoint {
+	return emitTDigest(r.digest)
+}
+
+// IntegerTDigestReducer summarizes the aggregated points into a t-digest.
+type IntegerTDigestReducer struct {
+	digest *tdigest.TDigest
+}
+
+// NewIntegerTDigestReducer creates a new IntegerTDigestReducer.
+func NewIntegerTDigestReducer() *IntegerTDigestReducer {
+	return &IntegerTDigestReducer{digest: tdigest.New()}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerTDigestReducer) AggregateInteger(p *IntegerPoint) {
+	r.digest.Add(float64(p.Value))
+}
+
+// Emit emits the encoded t-digest as a single point.
+func (r *IntegerTDigestReducer) Emit() []StringPoint {
+	return emitTDigest(r.digest)
+}
+
+// StringTDigestReducer merges the encoded t-digests of the aggregated points.
+type StringTDigestReducer struct {
+	digest *tdigest.TDigest
+}
+
+// NewStringTDigestReducer creates a new StringTDigestReducer.
+func NewStringTDigestReducer() *StringTDigestReducer {
+	return &StringTDigestReducer{digest: tdigest.New()}
+}
+
+// AggregateString aggregates a point into the reducer. Points that do not
+// contain an encoded t-digest are ignored.
+func (r *StringTDigestReducer) AggregateString(p *StringPoint) {
+	mergeTDigest(r.digest, p)
+}
+
+// Emit emits the merged t-digest as a single point.
+func (r *StringTDigestReducer) Emit() []StringPoint {
+	return emitTDigest(r.digest)
+}
+
+// StringPercentileApproxReducer merges the encoded t-digests of the
+// aggregated points and estimates a percentile from the result.
+type StringPercentileApproxReducer struct {
+	digest     *tdigest.TDigest
+	percentile float64
+}
+
+// NewStringPercentileApproxReducer creates a new StringPercentileApproxReducer.
+func NewStringPercentileApproxReducer(percentile float64) *StringPercentileApproxReducer {
+	return &StringPercentileApproxReducer{
+		digest:     tdigest.New(),
+		percentile: percentile,
+	}
+}
+
+// AggregateString aggregates a point into the reducer. Points that do not
+// contain an encoded t-digest are ignored.
+func (r *StringPercentileApproxReducer) AggregateString(p *StringPoint) {
+	mergeTDigest(r.digest, p)
+}
+
+// Emit emits the estimated percentile as a single point.
+func (r *StringPercentileApproxReducer) Emit() []FloatPoint {
+	if r.digest.Count() == 0 {
+		return nil
+	}
+	return []FloatPoint{{
+		Time:       ZeroTime,
+		Value:      r.digest.Quantile(r.percentile / 100),
+		Aggregated: uint32(r.digest.Count()),
+	}}
+}
+
+// emitTDigest returns a point with the encoded t-digest as its value.
+func emitTDigest(digest *tdigest.TDigest) []StringPoint {
+	if digest.Count() == 0 {
+		return nil
+	}
+
+	buf, err := digest.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return []StringPoint{{
+		Time:  ZeroTime,
+		Value: string(buf),
+	}}
+}
+
+// mergeTDigest decodes the t-digest in the value of a point and merges it.
+func mergeTDigest(digest *tdigest.TDigest, p *StringPoint) {
+	if p.Nil {
+		return
+	}
+
+	var other tdigest.TDigest
+	if err := other.UnmarshalBinary([]byte(p.Value)); err != nil {
+		return
+	}
+	digest.Merge(&other)
+}
+
+// FloatHLLReducer adds the aggregated values to a HyperLogLog sketch.
+type FloatHLLReducer struct {
+	sketch estimator.Sketch
+}
+
+// NewFloatHLLReducer creates a new FloatHLLReducer.
+func NewFloatHLLReducer() *FloatHLLReducer {
+	return &FloatHLLReducer{sketch: hll.NewDefaultPlus()}
+}
+
+// AggregateFloat aggregates a point into the reducer.
+func (r *FloatHLLReducer) AggregateFloat(p *FloatPoint) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], math.Float64bits(p.Value))
+	r.sketch.Add(buf[:])
+}
+
+// Emit emits the encoded sketch as a single point.
+func (r *FloatHLLReducer) Emit() []StringPoint {
+	return emitSketch(r.sketch)
+}
+
+// IntegerHLLReducer adds the aggregated values to a HyperLogLog sketch.
+type IntegerHLLReducer struct {
+	sketch estimator.Sketch
+}
+
+// NewIntegerHLLReducer creates a new IntegerHLLReducer.
+func NewIntegerHLLReducer() *IntegerHLLReducer {
+	return &IntegerHLLReducer{sketch: hll.NewDefaultPlus()}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerHLLReducer) AggregateInteger(p *IntegerPoint) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(p.Value))
+	r.sketch.Add(buf[:])
+}
+
+// Emit emits the encoded sketch as a single point.
+func (r *IntegerHLLReducer) Emit() []StringPoint {
+	return emitSketch(r.sketch)
+}
+
+// StringHLLReducer adds the aggregated values to a HyperLogLog sketch.
+type StringHLLReducer struct {
+	sketch estimator.Sketch
+}
+
+// NewStringHLLReducer creates a new StringHLLReducer.
+func NewStringHLLReducer() *StringHLLReducer {
+	return &StringHLLReducer{sketch: hll.NewDefaultPlus()}
+}
+
+// AggregateString aggregates a point into the reducer.
+func (r *StringHLLReducer) AggregateString(p *StringPoint) {
+	r.sketch.Add([]byte(p.Value))
+}
+
+// Emit emits the encoded sketch as a single point.
+func (r *StringHLLReducer) Emit() []StringPoint {
+	return emitSketch(r.sketch)
+}
+
+// BooleanHLLReducer adds the aggregated values to a HyperLogLog sketch.
+type BooleanHLLReducer struct {
+	sketch estimator.Sketch
+}
+
+// NewBooleanHLLReducer creates a new BooleanHLLReducer.
+func NewBooleanHLLReducer() *BooleanHLLReducer {
+	return &BooleanHLLReducer{sketch: hll.NewDefaultPlus()}
+}
+
+// AggregateBoolean aggregates a point into the reducer.
+func (r *BooleanHLLReducer) AggregateBoolean(p *BooleanPoint) {
+	if p.Value {
+		r.sketch.Add([]byte{1})
+	} else {
+		r.sketch.Add([]byte{0})
+	}
+}
+
+// Emit emits the encoded sketch as a single point.
+func (r *BooleanHLLReducer) Emit() []StringPoint {
+	return emitSketch(r.sketch)
+}
+
+// StringHLLMergeReducer merges the encoded sketches of the aggregated points.
+type StringHLLMergeReducer struct {
+	sketch estimator.Sketch
+}
+
+// NewStringHLLMergeReducer creates a new StringHLLMergeReducer.
+func NewStringHLLMergeReducer() *StringHLLMergeReducer {
+	return &StringHLLMergeReducer{sketch: hll.NewDefaultPlus()}
+}
+
+// AggregateString aggregates a point into the reducer.
+func (r *StringHLLMergeReducer) AggregateString(p *StringPoint) {
+	mergeSketch(r.sketch, p)
+}
+
+// Emit emits the merged sketch as a single point.
+func (r *StringHLLMergeReducer) Emit() []StringPoint {
+	return emitSketch(r.sketch)
+}
+
+// StringCountDistinctApproxReducer merges the encoded sketches of the
+// aggregated points and estimates the number of distinct values from the
+// result.
+type StringCountDistinctApproxReducer struct {
+	sketch estimator.Sketch
+	merged bool
+}
+
+// NewStringCountDistinctApproxReducer creates a new StringCountDistinctApproxReducer.
+func NewStringCountDistinctApproxReducer() *StringCountDistinctApproxReducer {
+	return &StringCountDistinctApproxReducer{sketch: hll.NewDefaultPlus()}
+}
+
+// AggregateString aggregates a point into the reducer.
+func (r *StringCountDistinctApproxReducer) AggregateString(p *StringPoint) {
+	if mergeSketch(r.sketch, p) {
+		r.merged = true
+	}
+}
+
+// Emit emits the estimated number of distinct values as a single point.
+func (r *StringCountDistinctApproxReducer) Emit() []IntegerPoint {
+	if !r.merged {
+		return nil
+	}
+	return []IntegerPoint{{
+		Time:  ZeroTime,
+		Value: int64(r.sketch.Count()),
+	}}
+}
+
+// emitSketch returns a point with the encoded sketch as its value. No point
+// is returned if the sketch is empty.
+func emitSketch(sketch estimator.Sketch) []StringPoint {
+	if sketch.Count() == 0 {
+		return nil
+	}
+
+	buf, err := sketch.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return []StringPoint{{
+		Time:  ZeroTime,
+		Value: string(buf),
+	}}
+}
+
+// mergeSketch decodes the HyperLogLog sketch in the value of a point and
+// merges it. It returns false if the point is nil or could not be merged.
+func mergeSketch(sketch estimator.Sketch, p *StringPoint) bool {
+	if p.Nil {
+		return false
+	}
+
+	var other hll.Plus
+	if err := other.UnmarshalBinary([]byte(p.Value)); err != nil {
+		return false
+	}
+	return sketch.Merge(&other) == nil
+}
+
+// histogramCounts counts the values within the buckets between each pair of
+// adjacent bounds.
+type histogramCounts struct {
+	bounds []float64
+	counts []int64
+}
+
+// add counts v in the bucket that contains it. Values outside of every
+// bucket are not counted.
+func (h *histogramCounts) add(v float64) {
+	i := sort.Search(len(h.bounds), func(i int) bool { return h.bounds[i] > v }) - 1
+	if i >= 0 && i < len(h.counts) {
+		h.counts[i]++
+	}
+}
+
+// emit returns a point with the count of each bucket as an auxiliary field
+// and the count of every bucket as its value.
+func (h *histogramCounts) emit() []IntegerPoint {
+	p := IntegerPoint{Time: ZeroTime, Aux: make([]interface{}, len(h.counts))}
+	for i, n := range h.counts {
+		p.Value += n
+		p.Aux[i] = n
+	}
+	return []IntegerPoint{p}
+}
+
+// FloatHistogramReducer counts the aggregated values within each bucket of a histogram.
+type FloatHistogramReducer struct {
+	histogramCounts
+}
+
+// NewFloatHistogramReducer creates a new FloatHistogramReducer.
+func NewFloatHistogramReducer(bounds []float64) *FloatHistogramReducer {
+	return &FloatHistogramReducer{histogramCounts{bounds: bounds, counts: make([]int64, len(bounds)-1)}}
+}
+
+// AggregateFloat aggregates a point into the reducer.
+func (r *FloatHistogramReducer) AggregateFloat(p *FloatPoint) {
+	r.add(p.Value)
+}
+
+// Emit emits the bucket counts as a single point.
+func (r *FloatHistogramReducer) Emit() []IntegerPoint {
+	return r.emit()
+}
+
+// IntegerHistogramReducer counts the aggregated values within each bucket of a histogram.
+type IntegerHistogramReducer struct {
+	histogramCounts
+}
+
+// NewIntegerHistogramReducer creates a new IntegerHistogramReducer.
+func NewIntegerHistogramReducer(bounds []float64) *IntegerHistogramReducer {
+	return &IntegerHistogramReducer{histogramCounts{bounds: bounds, counts: make([]int64, len(bounds)-1)}}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerHistogramReducer) AggregateInteger(p *IntegerPoint) {
+	r.add(float64(p.Value))
+}
+
+// Emit emits the bucket counts as a single point.
+func (r *IntegerHistogramReducer) Emit() []IntegerPoint {
+	return r.emit()
+}
+
+// IntegerHistogramMergeReducer adds up the bucket counts of the aggregated points.
+type IntegerHistogramMergeReducer struct {
+	histogramCounts
+}
+
+// NewIntegerHistogramMergeReducer creates a new IntegerHistogramMergeReducer.
+func NewIntegerHistogramMergeReducer() *IntegerHistogramMergeReducer {
+	return &IntegerHistogramMergeReducer{}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerHistogramMergeReducer) AggregateInteger(p *IntegerPoint) {
+	for i, v := range p.Aux {
+		if i == len(r.counts) {
+			r.counts = append(r.counts, 0)
+		}
+		if n, ok := v.(int64); ok {
+			r.counts[i] += n
+		}
+	}
+}
+
+// Emit emits the bucket counts as a single point.
+func (r *IntegerHistogramMergeReducer) Emit() []IntegerPoint {
+	return r.emit()
+}
+
+// covariance holds the means and co-moments of pairs of values. It is updated
+// one pair at a time with Welford's algorithm and the co-moments of separate
+// sets of pairs can be merged without a loss of precision.
+type covariance struct {
+	n          float64
+	meanX      float64
+	meanY      float64
+	m2X        float64
+	m2Y        float64
+	coMomentXY float64
+}
+
+// add adds a pair of values.
+func (c *covariance) add(x, y float64) {
+	c.n++
+	dx := x - c.meanX
+	c.meanX += dx / c.n
+	dy := y - c.meanY
+	c.meanY += dy / c.n
+	c.m2X += dx * (x - c.meanX)
+	c.m2Y += dy * (y - c.meanY)
+	c.coMomentXY += dx * (y - c.meanY)
+}
+
+// merge merges the pairs of another set of values.
+func (c *covariance) merge(other *covariance) {
+	if other.n == 0 {
+		return
+	} else if c.n == 0 {
+		*c = *other
+		return
+	}
+
+	n := c.n + other.n
+	dx, dy := other.meanX-c.meanX, other.meanY-c.meanY
+	c.m2X += other.m2X + dx*dx*c.n*other.n/n
+	c.m2Y += other.m2Y + dy*dy*c.n*other.n/n
+	c.coMomentXY += other.coMomentXY + dx*dy*c.n*other.n/n
+	c.meanX += dx * other.n / n
+	c.meanY += dy * other.n / n
+	c.n = n
+}
+
+// stat computes a statistic from the pairs. For a linear regression, x is the
+// time in seconds and the intercept is the value at time t. It returns false
+// if the statistic is undefined for the pairs.
+func (c *covariance) stat(name string, t int64) (float64, bool) {
+	if c.n < 2 {
+		return 0, false
+	}
+
+	switch name {
+	case "covar":
+		return c.coMomentXY / (c.n - 1), true
+	case "corr":
+		if c.m2X == 0 || c.m2Y == 0 {
+			return 0, false
+		}
+		return c.coMomentXY / math.Sqrt(c.m2X*c.m2Y), true
+	case "slope":
+		if c.m2X == 0 {
+			return 0, false
+		}
+		return c.coMomentXY / c.m2X, true
+	case "intercept":
+		if c.m2X == 0 {
+			return 0, false
+		}
+		slope := c.coMomentXY / c.m2X
+		return c.meanY + slope*(float64(t)/float64(time.Second)-c.meanX), true
+	case "r2":
+		if c.m2X == 0 {
+			return 0, false
+		} else if c.m2Y == 0 {
+			// Every value is on the regression line.
+			return 1, true
+		}
+		return c.coMomentXY * c.coMomentXY / (c.m2X * c.m2Y), true
+	}
+	return 0, false
+}
+
+// MarshalBinary encodes the means and co-moments.
+func (c *covariance) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 6*8)
+	for i, v := range []float64{c.n, c.meanX, c.meanY, c.m2X, c.m2Y, c.coMomentXY} {
+		binary.BigEndian.PutUint64(buf[i*8:], math.Float64bits(v))
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the means and co-moments.
+func (c *covariance) UnmarshalBinary(buf []byte) error {
+	if len(buf) != 6*8 {
+		return fmt.Errorf("invalid covariance length: %d", len(buf))
+	}
+	for i, v := range []*float64{&c.n, &c.meanX, &c.meanY, &c.m2X, &c.m2Y, &c.coMomentXY} {
+		*v = math.Float64frombits(binary.BigEndian.Uint64(buf[i*8:]))
+	}
+	return nil
+}
+
+// FloatCovarianceReducer adds the aggregated points to the co-moments of a
+// corr(), covar() or linear_regression() call. The second value of each pair
+// is the first auxiliary field of the point or, for a linear regression, the
+// value of the point paired with its time.
+type FloatCovarianceReducer struct {
+	c      covariance
+	byTime bool
+}
+
+// NewFloatCovarianceReducer creates a new FloatCovarianceReducer.
+func NewFloatCovarianceReducer(byTime bool) *FloatCovarianceReducer {
+	return &FloatCovarianceReducer{byTime: byTime}
+}
+
+// AggregateFloat aggregates a point into the reducer.
+func (r *FloatCovarianceReducer) AggregateFloat(p *FloatPoint) {
+	if r.byTime {
+		r.c.add(float64(p.Time)/float64(time.Second), p.Value)
+	} else if y, ok := covarianceAuxValue(p.Aux); ok {
+		r.c.add(p.Value, y)
+	}
+}
+
+// Emit emits the encoded co-moments as a single point.
+func (r *FloatCovarianceReducer) Emit() []StringPoint {
+	return emitCovariance(&r.c)
+}
+
+// IntegerCovarianceReducer adds the aggregated points to the co-moments of a
+// corr(), covar() or linear_regression() call.
+type IntegerCovarianceReducer struct {
+	c      covariance
+	byTime bool
+}
+
+// NewIntegerCovarianceReducer creates a new IntegerCovarianceReducer.
+func NewIntegerCovarianceReducer(byTime bool) *IntegerCovarianceReducer {
+	return &IntegerCovarianceReducer{byTime: byTime}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerCovarianceReducer) AggregateInteger(p *IntegerPoint) {
+	if r.byTime {
+		r.c.add(float64(p.Time)/float64(time.Second), float64(p.Value))
+	} else if y, ok := covarianceAuxValue(p.Aux); ok {
+		r.c.add(float64(p.Value), y)
+	}
+}
+
+// Emit emits the encoded co-moments as a single point.
+func (r *IntegerCovarianceReducer) Emit() []StringPoint {
+	return emitCovariance(&r.c)
+}
+
+// StringCovarianceReducer merges the encoded co-moments of the aggregated points.
+type StringCovarianceReducer struct {
+	c covariance
+}
+
+// NewStringCovarianceReducer creates a new StringCovarianceReducer.
+func NewStringCovarianceReducer() *StringCovarianceReducer {
+	return &StringCovarianceReducer{}
+}
+
+// AggregateString aggregates a point into the reducer. Points that do not
+// contain encoded co-moments are ignored.
+func (r *StringCovarianceReducer) AggregateString(p *StringPoint) {
+	mergeCovariance(&r.c, p)
+}
+
+// Emit emits the merged co-moments as a single point.
+func (r *StringCovarianceReducer) Emit() []StringPoint {
+	return emitCovariance(&r.c)
+}
+
+// StringCovarianceStatReducer merges the encoded co-moments of the aggregated
+// points and computes a statistic from the result.
+type StringCovarianceStatReducer struct {
+	c    covariance
+	stat string
+	time int64
+}
+
+// NewStringCovarianceStatReducer creates a new StringCovarianceStatReducer.
+func NewStringCovarianceStatReducer(stat string) *StringCovarianceStatReducer {
+	return &StringCovarianceStatReducer{stat: stat}
+}
+
+// AggregateString aggregates a point into the reducer. Points that do not
+// contain encoded co-moments are ignored.
+func (r *StringCovarianceStatReducer) AggregateString(p *StringPoint) {
+	mergeCovariance(&r.c, p)
+
+	// The points have already been reduced so their time is the start of
+	// the window.
+	r.time = p.Time
+	if r.time == MinTime {
+		r.time = 0
+	}
+}
+
+// Emit emits the statistic as a single point.
+func (r *StringCovarianceStatReducer) Emit() []FloatPoint {
+	v, ok := r.c.stat(r.stat, r.time)
+	if !ok {
+		return nil
+	}
+	return []FloatPoint{{
+		Time:       ZeroTime,
+		Value:      v,
+		Aggregated: uint32(r.c.n),
+	}}
+}
+
+// covarianceAuxValue returns the first auxiliary field as a float.
+func covarianceAuxValue(aux []interface{}) (float64, bool) {
+	if len(aux) == 0 {
+		return 0, false
+	}
+	switch v := aux[0].(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// emitCovariance returns a point with the encoded co-moments as its value. No
+// point is returned if no pairs have been added.
+func emitCovariance(c *covariance) []StringPoint {
+	if c.n == 0 {
+		return nil
+	}
+
+	buf, err := c.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return []StringPoint{{
+		Time:  ZeroTime,
+		Value: string(buf),
+	}}
+}
+
+// mergeCovariance decodes the co-moments in the value of a point and merges them.
+func mergeCovariance(c *covariance, p *StringPoint) {
+	if p.Nil {
+		return
+	}
+
+	var other covariance
+	if err := other.UnmarshalBinary([]byte(p.Value)); err != nil {
+		return
+	}
+	c.merge(&other)
+}
+
+// FloatMADOutliersReducer selects the points whose modified z-score, which is
+// based on the median and median absolute deviation of the points, exceeds a
+// threshold.
+type FloatMADOutliersReducer struct {
+	threshold float64
+	points    floatPoints
+}
+
+// NewFloatMADOutliersReducer creates a new FloatMADOutliersReducer.
+func NewFloatMADOutliersReducer(threshold float64) *FloatMADOutliersReducer {
+	return &FloatMADOutliersReducer{threshold: threshold}
+}
+
+// AggregateFloat aggregates a point into the reducer.
+func (r *FloatMADOutliersReducer) AggregateFloat(p *FloatPoint) {
+	r.points = append(r.points, *p.Clone())
+}
+
+// Emit emits the outliers sorted by time.
+func (r *FloatMADOutliersReducer) Emit() []FloatPoint {
+	values := make([]float64, len(r.points))
+	for i, p := range r.points {
+		values[i] = p.Value
+	}
+
+	var outliers floatPoints
+	for i, score := range madScores(values) {
+		if math.Abs(score) > r.threshold {
+			outliers = append(outliers, r.points[i])
+		}
+	}
+	sort.Sort(outliers)
+	return outliers
+}
+
+// IntegerMADOutliersReducer selects the points whose modified z-score, which
+// is based on the median and median absolute deviation of the points, exceeds
+// a threshold.
+type IntegerMADOutliersReducer struct {
+	threshold float64
+	points    integerPoints
+}
+
+// NewIntegerMADOutliersReducer creates a new IntegerMADOutliersReducer.
+func NewIntegerMADOutliersReducer(threshold float64) *IntegerMADOutliersReducer {
+	return &IntegerMADOutliersReducer{threshold: threshold}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerMADOutliersReducer) AggregateInteger(p *IntegerPoint) {
+	r.points = append(r.points, *p.Clone())
+}
+
+// Emit emits the outliers sorted by time.
+func (r *IntegerMADOutliersReducer) Emit() []IntegerPoint {
+	values := make([]float64, len(r.points))
+	for i, p := range r.points {
+		values[i] = float64(p.Value)
+	}
+
+	var outliers integerPoints
+	for i, score := range madScores(values) {
+		if math.Abs(score) > r.threshold {
+			outliers = append(outliers, r.points[i])
+		}
+	}
+	sort.Sort(outliers)
+	return outliers
+}
+
+// madScores returns the modified z-score of each value. The score is the
+// distance of a value from the median in units of the median absolute
+// deviation scaled to be comparable with a standard deviation. If more than
+// half of the values are the same, the mean absolute deviation is used
+// instead. Every score is zero if all of the values are the same.
+func madScores(values []float64) []float64 {
+	scores := make([]float64, len(values))
+	if len(values) == 0 {
+		return scores
+	}
+
+	median := medianOf(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+
+	scale := medianOf(deviations) / 0.6745
+	if scale == 0 {
+		var sum float64
+		for _, d := range deviations {
+			sum += d
+		}
+		scale = 1.253314 * sum / float64(len(deviations))
+	}
+	if scale == 0 {
+		return scores
+	}
+
+	for i, v := range values {
+		scores[i] = (v - median) / scale
+	}
+	return scores
+}
+
+// iqrOutliers returns which of the values are outside of the Tukey fences.
+// The fences are k times the interquartile range below the first quartile and
+// above the third quartile.
+func iqrOutliers(values []float64, k float64) []bool {
+	outliers := make([]bool, len(values))
+	if len(values) == 0 {
+		return outliers
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	q1, q3 := quantileOf(sorted, 0.25), quantileOf(sorted, 0.75)
+	lower, upper := q1-k*(q3-q1), q3+k*(q3-q1)
+	for i, v := range values {
+		outliers[i] = v < lower || v > upper
+	}
+	return outliers
+}
+
+// medianOf returns the median of the values.
+func medianOf(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return quantileOf(sorted, 0.5)
+}
+
+// quantileOf returns the quantile q of sorted values. The quantile is
+// interpolated between the two closest values.
+func quantileOf(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[i]
+	}
+	return sorted[i] + (sorted[i+1]-sorted[i])*(pos-float64(i))
+}
+
+// FloatLTTBReducer downsamples the aggregated points with the
+// largest-triangle-three-buckets algorithm. The selected points keep their
+// original timestamps.
+type FloatLTTBReducer struct {
+	n      int
+	points floatPoints
+}
+
+// NewFloatLTTBReducer creates a new FloatLTTBReducer that selects n points.
+func NewFloatLTTBReducer(n int) *FloatLTTBReducer {
+	return &FloatLTTBReducer{n: n}
+}
+
+// AggregateFloat aggregates a point into the reducer.
+func (r *FloatLTTBReducer) AggregateFloat(p *FloatPoint) {
+	r.points = append(r.points, *p.Clone())
+}
+
+// Emit emits the selected points sorted by time.
+func (r *FloatLTTBReducer) Emit() []FloatPoint {
+	sort.Stable(r.points)
+
+	times := make([]int64, len(r.points))
+	values := make([]float64, len(r.points))
+	for i, p := range r.points {
+		times[i], values[i] = p.Time, p.Value
+	}
+
+	indexes := lttb(times, values, r.n)
+	points := make([]FloatPoint, len(indexes))
+	for i, index := range indexes {
+		points[i] = r.points[index]
+	}
+	return points
+}
+
+// IntegerLTTBReducer downsamples the aggregated points with the
+// largest-triangle-three-buckets algorithm. The selected points keep their
+// original timestamps.
+type IntegerLTTBReducer struct {
+	n      int
+	points integerPoints
+}
+
+// NewIntegerLTTBReducer creates a new IntegerLTTBReducer that selects n points.
+func NewIntegerLTTBReducer(n int) *IntegerLTTBReducer {
+	return &IntegerLTTBReducer{n: n}
+}
+
+// AggregateInteger aggregates a point into the reducer.
+func (r *IntegerLTTBReducer) AggregateInteger(p *IntegerPoint) {
+	r.points = append(r.points, *p.Clone())
+}
+
+// Emit emits the selected points sorted by time.
+func (r *IntegerLTTBReducer) Emit() []IntegerPoint {
+	sort.Stable(r.points)
+
+	times := make([]int64, len(r.points))
+	values := make([]float64, len(r.points))
+	for i, p := range r.points {
+		times[i], values[i] = p.Time, float64(p.Value)
+	}
+
+	indexes := lttb(times, values, r.n)
+	points := make([]IntegerPoint, len(indexes))
+	for i, index := range indexes {
+		points[i] = r.points[index]
+	}
+	return points
+}
+
+// lttb returns the indexes of n points selected with the
+// largest-triangle-three-buckets algorithm described by Sveinn Steinarsson in
+// "Downsampling Time Series for Visual Representation". The first and last
+// points are always selected. The points between them are divided into n-2
+// buckets and the point of each bucket that forms the largest triangle with
+// the previously selected point and the average of the next bucket is
+// selected. Every point is selected if there are no more than n points.
+func lttb(times []int64, values []float64, n int) []int {
+	size := len(times)
+	if n >= size || n < 3 {
+		indexes := make([]int, size)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	}
+
+	indexes := make([]int, 0, n)
+	indexes = append(indexes, 0)
+
+	every := float64(size-2) / float64(n-2)
+	a := 0
+	for i := 0; i < n-2; i++ {
+		// Average the points of the next bucket.
+		avgStart := int(float64(i+1)*every) + 1
+		avgEnd := int(float64(i+2)*every) + 1
+		if avgEnd > size {
+			avgEnd = size
+		}
+		var avgX, avgY float64
+		for j := avgStart; j < avgEnd; j++ {
+			avgX += float64(times[j])
+			avgY += values[j]
+		}
+		avgX /= float64(avgEnd - avgStart)
+		avgY /= float64(avgEnd - avgStart)
+
+		// Select the point of this bucket with the largest triangle.
+		ax, ay := float64(times[a]), values[a]
+		maxArea, next := -1.0, a
+		for j := int(float64(i)*every) + 1; j < avgStart; j++ {
+			area := math.Abs((ax-avgX)*(values[j]-ay)-(ax-float64(times[j]))*(avgY-ay)) / 2
+			if area > maxArea {
+				maxArea, next = area, j
+			}
+		}
+		indexes = append(indexes, next)
+		a = next
+	}
+	return append(indexes, size-1)
+}
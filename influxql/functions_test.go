@@ -496,3 +496,47 @@ func TestSample_SampleSizeGreaterThanNumPoints(t *testing.T) {
 		t.Fatalf("unexpected points: %s", spew.Sdump(points))
 	}
 }
+
+func TestLTTB_SelectsLargestTriangles(t *testing.T) {
+	r := influxql.NewFloatLTTBReducer(4)
+
+	ps := []influxql.FloatPoint{
+		{Time: 1, Value: 0},
+		{Time: 2, Value: 1},
+		{Time: 3, Value: 10},
+		{Time: 4, Value: 1},
+		{Time: 5, Value: 0},
+		{Time: 6, Value: -8},
+		{Time: 7, Value: 0},
+	}
+
+	for _, p := range ps {
+		r.AggregateFloat(&p)
+	}
+
+	points := r.Emit()
+
+	if exp := []influxql.FloatPoint{ps[0], ps[2], ps[5], ps[6]}; !deep.Equal(exp, points) {
+		t.Fatalf("unexpected points: %s", spew.Sdump(points))
+	}
+}
+
+func TestLTTB_SizeGreaterThanNumPoints(t *testing.T) {
+	r := influxql.NewIntegerLTTBReducer(4)
+
+	ps := []influxql.IntegerPoint{
+		{Time: 1, Value: 1},
+		{Time: 2, Value: 2},
+		{Time: 3, Value: 3},
+	}
+
+	for _, p := range ps {
+		r.AggregateInteger(&p)
+	}
+
+	points := r.Emit()
+
+	if !deep.Equal(ps, points) {
+		t.Fatalf("unexpected points: %s", spew.Sdump(points))
+	}
+}
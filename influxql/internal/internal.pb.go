@@ -443,6 +443,8 @@ func (m *Measurement) GetIsTarget() bool {
 type Interval struct {
 	Duration         *int64 `protobuf:"varint,1,opt,name=Duration" json:"Duration,omitempty"`
 	Offset           *int64 `protobuf:"varint,2,opt,name=Offset" json:"Offset,omitempty"`
+	Months           *int64 `protobuf:"varint,3,opt,name=Months" json:"Months,omitempty"`
+	Length           *int64 `protobuf:"varint,4,opt,name=Length" json:"Length,omitempty"`
 	XXX_unrecognized []byte `json:"-"`
 }
 
@@ -465,6 +467,20 @@ func (m *Interval) GetOffset() int64 {
 	return 0
 }
 
+func (m *Interval) GetMonths() int64 {
+	if m != nil && m.Months != nil {
+		return *m.Months
+	}
+	return 0
+}
+
+func (m *Interval) GetLength() int64 {
+	if m != nil && m.Length != nil {
+		return *m.Length
+	}
+	return 0
+}
+
 type IteratorStats struct {
 	SeriesN          *int64 `protobuf:"varint,1,opt,name=SeriesN" json:"SeriesN,omitempty"`
 	PointN           *int64 `protobuf:"varint,2,opt,name=PointN" json:"PointN,omitempty"`
@@ -111,6 +111,160 @@ func (itr *bufFloatIterator) NextInWindow(startTime, endTime int64) (*FloatPoint
 // unread sets v to the buffer. It is read on the next call to Next().
 func (itr *bufFloatIterator) unread(v *FloatPoint) { itr.buf = v }
 
+// floatWindows reads the points of overlapping windows from an input
+// iterator. The input is read one interval at a time and each interval is
+// buffered until every window that contains it has been read.
+type floatWindows struct {
+	input *bufFloatIterator
+	opt   IteratorOptions
+
+	// The series being read and its buffered intervals in the order they
+	// were read.
+	name, tags string
+	active     bool
+	done       bool
+	intervals  []floatWindowInterval
+	last       int64
+
+	// The start of the next window and of the earliest window in the
+	// time range of the query.
+	next, min int64
+}
+
+// floatWindowInterval holds the points of a series within an interval.
+type floatWindowInterval struct {
+	start  int64
+	points []FloatPoint
+}
+
+// newFloatWindows returns a new instance of floatWindows.
+func newFloatWindows(input *bufFloatIterator, opt IteratorOptions) *floatWindows {
+	min, _ := opt.Window(opt.StartTime)
+	return &floatWindows{input: input, opt: opt, min: min}
+}
+
+// Next returns the start time and the points of the next window that contains
+// any points. Returns nil points when there are no more windows.
+func (w *floatWindows) Next() (int64, []FloatPoint, error) {
+	step, length := int64(w.opt.Interval.Duration), int64(w.opt.Interval.Length)
+	for {
+		// Read the first interval of the next series.
+		if !w.active {
+			iv, err := w.read()
+			if err != nil || iv == nil {
+				return 0, nil, err
+			}
+			w.intervals = append(w.intervals[:0], *iv)
+			w.last, w.done = iv.start, false
+			if w.opt.Ascending {
+				w.next = w.first(iv.start)
+			} else {
+				w.next = iv.start
+			}
+			continue
+		}
+
+		// Read intervals until every interval of the next window has been read.
+		if !w.done && ((w.opt.Ascending && w.last-w.next < length) || (!w.opt.Ascending && w.last >= w.next)) {
+			iv, err := w.read()
+			if err != nil {
+				return 0, nil, err
+			} else if iv == nil {
+				w.done = true
+				continue
+			}
+
+			// Skip the windows without any points if the series has a gap.
+			if len(w.intervals) == 0 {
+				if w.opt.Ascending {
+					if first := w.first(iv.start); first > w.next {
+						w.next = first
+					}
+				} else if iv.start < w.next {
+					w.next = iv.start
+				}
+			}
+			w.intervals = append(w.intervals, *iv)
+			w.last = iv.start
+			continue
+		}
+
+		// Move on to the next series once every window has been read.
+		if len(w.intervals) == 0 {
+			w.active = false
+			continue
+		}
+
+		start := w.next
+		var points []FloatPoint
+		for _, iv := range w.intervals {
+			if iv.start >= start && iv.start-start < length {
+				points = append(points, iv.points...)
+			}
+		}
+
+		// Drop the intervals that are not a part of any of the later windows.
+		i := 0
+		if w.opt.Ascending {
+			w.next += step
+			for i < len(w.intervals) && w.intervals[i].start < w.next {
+				i++
+			}
+		} else {
+			w.next -= step
+			for i < len(w.intervals) && w.intervals[i].start-w.next >= length {
+				i++
+			}
+		}
+		w.intervals = w.intervals[i:]
+
+		if len(points) > 0 && start >= w.min {
+			return start, points, nil
+		}
+	}
+}
+
+// first returns the start of the earliest window that contains the interval.
+func (w *floatWindows) first(start int64) int64 {
+	if d := int64(w.opt.Interval.Length - w.opt.Interval.Duration); uint64(start-w.min) > uint64(d) {
+		return start - d
+	}
+	return w.min
+}
+
+// read reads the points of the next interval of the current series. Returns
+// nil if there are no more points in the series.
+func (w *floatWindows) read() (*floatWindowInterval, error) {
+	var iv *floatWindowInterval
+	for {
+		p, err := w.input.Next()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			return iv, nil
+		} else if p.Nil {
+			continue
+		}
+
+		tags := p.Tags.Subset(w.opt.Dimensions).ID()
+		if !w.active {
+			w.name, w.tags, w.active = p.Name, tags, true
+		} else if p.Name != w.name || tags != w.tags {
+			w.input.unread(p)
+			return iv, nil
+		}
+
+		start, _ := w.opt.Window(p.Time)
+		if iv == nil {
+			iv = &floatWindowInterval{start: start}
+		} else if start != iv.start {
+			w.input.unread(p)
+			return iv, nil
+		}
+		iv.points = append(iv.points, *p.Clone())
+	}
+}
+
 // floatMergeIterator represents an iterator that combines multiple float iterators.
 type floatMergeIterator struct {
 	inputs []FloatIterator
@@ -571,6 +725,8 @@ type floatFillIterator struct {
 	auxFields []interface{}
 	init      bool
 	opt       IteratorOptions
+	lookback  map[fillSeriesKey]lookbackPoint
+	seeds     []fillSeriesKey
 
 	window struct {
 		name   string
@@ -580,9 +736,9 @@ type floatFillIterator struct {
 	}
 }
 
-func newFloatFillIterator(input FloatIterator, expr Expr, opt IteratorOptions) *floatFillIterator {
+func newFloatFillIterator(input FloatIterator, expr Expr, opt IteratorOptions, lookback map[fillSeriesKey]lookbackPoint) *floatFillIterator {
 	if opt.Fill == NullFill {
-		if expr, ok := expr.(*Call); ok && expr.Name == "count" {
+		if expr, ok := expr.(*Call); ok && (expr.Name == "count" || expr.Name == "count_distinct_approx" || expr.Name == "histogram") {
 			opt.Fill = NumberFill
 			opt.FillValue = float64(0)
 		}
@@ -609,6 +765,8 @@ func newFloatFillIterator(input FloatIterator, expr Expr, opt IteratorOptions) *
 		endTime:   endTime,
 		auxFields: auxFields,
 		opt:       opt,
+		lookback:  lookback,
+		seeds:     lookbackSeries(lookback, opt.Ascending),
 	}
 }
 
@@ -618,13 +776,10 @@ func (itr *floatFillIterator) Close() error         { return itr.input.Close() }
 func (itr *floatFillIterator) Next() (*FloatPoint, error) {
 	if !itr.init {
 		p, err := itr.input.peek()
-		if p == nil || err != nil {
+		if err != nil {
 			return nil, err
-		}
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		} else if !itr.nextSeries(p) {
+			return nil, nil
 		}
 		itr.init = true
 	}
@@ -652,19 +807,18 @@ func (itr *floatFillIterator) Next() (*FloatPoint, error) {
 			}
 		}
 
-		// We are *not* in a current interval. If there is no next point,
-		// we are at the end of all intervals.
-		if p == nil {
+		// We are *not* in a current interval. If there is no next point and
+		// no series with only a lookback point, we are at the end of all
+		// intervals.
+		if !itr.nextSeries(p) {
 			return nil, nil
 		}
 
-		// Set the new interval.
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		// Fill the series with only a lookback point before the point.
+		if p != nil && (p.Name != itr.window.name || p.Tags.ID() != itr.window.tags.ID()) {
+			itr.input.unread(p)
+			p = nil
 		}
-		itr.prev = FloatPoint{Nil: true}
 		break
 	}
 
@@ -689,6 +843,11 @@ func (itr *floatFillIterator) Next() (*FloatPoint, error) {
 					return nil, err
 				} else if next != nil && next.Name == itr.window.name && next.Tags.ID() == itr.window.tags.ID() {
 					interval := int64(itr.opt.Interval.Duration)
+					if itr.opt.Interval.Months != 0 {
+						// Calendar windows vary in length so the times are
+						// used as they are.
+						interval = 1
+					}
 					start := itr.window.time / interval
 					p.Value = linearFloat(start, itr.prev.Time/interval, next.Time/interval, itr.prev.Value, next.Value)
 				} else {
@@ -717,7 +876,14 @@ func (itr *floatFillIterator) Next() (*FloatPoint, error) {
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows vary in length so look up the adjacent window.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -725,7 +891,7 @@ func (itr *floatFillIterator) Next() (*FloatPoint, error) {
 
 	// Check to see if we have passed over an offset change and adjust the time
 	// to account for this new offset.
-	if itr.opt.Location != nil {
+	if itr.opt.Location != nil && itr.opt.Interval.Months == 0 {
 		if _, offset := itr.opt.Zone(itr.window.time - 1); offset != itr.window.offset {
 			diff := itr.window.offset - offset
 			if abs(diff) < int64(itr.opt.Interval.Duration) {
@@ -737,6 +903,50 @@ func (itr *floatFillIterator) Next() (*FloatPoint, error) {
 	return p, nil
 }
 
+// nextSeries starts the windows of the next series. The series of the point
+// is next unless a series with only a lookback point comes before it. It
+// returns false if there are no more series.
+func (itr *floatFillIterator) nextSeries(p *FloatPoint) bool {
+	var key fillSeriesKey
+	if p != nil {
+		key = fillSeriesKey{name: p.Name, tags: p.Tags.ID()}
+	}
+
+	if len(itr.seeds) > 0 && (p == nil || itr.seeds[0].before(key, itr.opt.Ascending)) {
+		itr.window.name, itr.window.tags = itr.seeds[0].name, itr.lookback[itr.seeds[0]].tags
+		itr.seeds = itr.seeds[1:]
+	} else if p != nil {
+		if len(itr.seeds) > 0 && itr.seeds[0] == key {
+			itr.seeds = itr.seeds[1:]
+		}
+		itr.window.name, itr.window.tags = p.Name, p.Tags
+	} else {
+		return false
+	}
+
+	itr.window.time = itr.startTime
+	if itr.opt.Location != nil {
+		_, itr.window.offset = itr.opt.Zone(itr.window.time)
+	}
+	itr.prev = itr.previous()
+	return true
+}
+
+// previous returns the point that precedes the current series from the
+// lookback points. A nil point is returned if there is none.
+func (itr *floatFillIterator) previous() FloatPoint {
+	p, ok := itr.lookback[fillSeriesKey{name: itr.window.name, tags: itr.window.tags.ID()}]
+	if !ok {
+		return FloatPoint{Nil: true}
+	}
+	return FloatPoint{
+		Name:  itr.window.name,
+		Tags:  itr.window.tags,
+		Time:  p.time,
+		Value: castToFloat(p.value),
+	}
+}
+
 // floatIntervalIterator represents a float implementation of IntervalIterator.
 type floatIntervalIterator struct {
 	input FloatIterator
@@ -764,6 +974,28 @@ func (itr *floatIntervalIterator) Next() (*FloatPoint, error) {
 	return p, nil
 }
 
+// floatRenameIterator represents a float implementation of RenameIterator.
+type floatRenameIterator struct {
+	input FloatIterator
+	name  string
+}
+
+func newFloatRenameIterator(input FloatIterator, name string) *floatRenameIterator {
+	return &floatRenameIterator{input: input, name: name}
+}
+
+func (itr *floatRenameIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *floatRenameIterator) Close() error         { return itr.input.Close() }
+
+func (itr *floatRenameIterator) Next() (*FloatPoint, error) {
+	p, err := itr.input.Next()
+	if p == nil || err != nil {
+		return nil, err
+	}
+	p.Name = itr.name
+	return p, nil
+}
+
 // floatInterruptIterator represents a float implementation of InterruptIterator.
 type floatInterruptIterator struct {
 	input   FloatIterator
@@ -983,17 +1215,17 @@ func (itr *floatChanIterator) Next() (*FloatPoint, error) {
 	itr.cond.L.Lock()
 	defer itr.cond.L.Unlock()
 
+	// Wait until either a value is available in the buffer, the iterator
+	// is closed or an error is set.
+	for !itr.done && !itr.buf.filled && itr.err == nil {
+		itr.cond.Wait()
+	}
+
 	// Check for an error and return one if there.
 	if itr.err != nil {
 		return nil, itr.err
 	}
 
-	// Wait until either a value is available in the buffer or
-	// the iterator is closed.
-	for !itr.done && !itr.buf.filled {
-		itr.cond.Wait()
-	}
-
 	// Return nil once the channel is done and the buffer is empty.
 	if itr.done && !itr.buf.filled {
 		return nil, nil
@@ -1012,6 +1244,7 @@ func (itr *floatChanIterator) Next() (*FloatPoint, error) {
 // floatReduceFloatIterator executes a reducer for every interval and buffers the result.
 type floatReduceFloatIterator struct {
 	input    *bufFloatIterator
+	windows  *floatWindows
 	create   func() (FloatPointAggregator, FloatPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -1020,12 +1253,16 @@ type floatReduceFloatIterator struct {
 }
 
 func newFloatReduceFloatIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, FloatPointEmitter)) *floatReduceFloatIterator {
-	return &floatReduceFloatIterator{
+	itr := &floatReduceFloatIterator{
 		input:  newBufFloatIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newFloatWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -1062,6 +1299,10 @@ type floatReduceFloatPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *floatReduceFloatIterator) reduce() ([]FloatPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -1129,7 +1370,42 @@ func (itr *floatReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		}
 		rp.Aggregator.AggregateFloat(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *floatReduceFloatIterator) reduceWindow() ([]FloatPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*floatReduceFloatPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &floatReduceFloatPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateFloat(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *floatReduceFloatIterator) emit(m map[string]*floatReduceFloatPoint, startTime int64) []FloatPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -1152,7 +1428,9 @@ func (itr *floatReduceFloatIterator) reduce() ([]FloatPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -1166,7 +1444,7 @@ func (itr *floatReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		sort.Stable(sort.Reverse(floatPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // floatStreamFloatIterator streams inputs into the iterator and emits points gradually.
@@ -1176,6 +1454,7 @@ type floatStreamFloatIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*floatReduceFloatPoint
+	id     string
 	points []FloatPoint
 }
 
@@ -1256,6 +1535,32 @@ func (itr *floatStreamFloatIterator) reduce() ([]FloatPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -1427,6 +1732,7 @@ type floatExprFunc func(a, b float64) float64
 // floatReduceIntegerIterator executes a reducer for every interval and buffers the result.
 type floatReduceIntegerIterator struct {
 	input    *bufFloatIterator
+	windows  *floatWindows
 	create   func() (FloatPointAggregator, IntegerPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -1435,12 +1741,16 @@ type floatReduceIntegerIterator struct {
 }
 
 func newFloatReduceIntegerIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, IntegerPointEmitter)) *floatReduceIntegerIterator {
-	return &floatReduceIntegerIterator{
+	itr := &floatReduceIntegerIterator{
 		input:  newBufFloatIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newFloatWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -1477,6 +1787,10 @@ type floatReduceIntegerPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *floatReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -1544,7 +1858,42 @@ func (itr *floatReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		}
 		rp.Aggregator.AggregateFloat(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *floatReduceIntegerIterator) reduceWindow() ([]IntegerPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*floatReduceIntegerPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &floatReduceIntegerPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateFloat(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *floatReduceIntegerIterator) emit(m map[string]*floatReduceIntegerPoint, startTime int64) []IntegerPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -1567,7 +1916,9 @@ func (itr *floatReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -1581,7 +1932,7 @@ func (itr *floatReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		sort.Stable(sort.Reverse(integerPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // floatStreamIntegerIterator streams inputs into the iterator and emits points gradually.
@@ -1591,6 +1942,7 @@ type floatStreamIntegerIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*floatReduceIntegerPoint
+	id     string
 	points []IntegerPoint
 }
 
@@ -1671,6 +2023,32 @@ func (itr *floatStreamIntegerIterator) reduce() ([]IntegerPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -1846,6 +2224,7 @@ type floatIntegerExprFunc func(a, b float64) int64
 // floatReduceUnsignedIterator executes a reducer for every interval and buffers the result.
 type floatReduceUnsignedIterator struct {
 	input    *bufFloatIterator
+	windows  *floatWindows
 	create   func() (FloatPointAggregator, UnsignedPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -1854,12 +2233,16 @@ type floatReduceUnsignedIterator struct {
 }
 
 func newFloatReduceUnsignedIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, UnsignedPointEmitter)) *floatReduceUnsignedIterator {
-	return &floatReduceUnsignedIterator{
+	itr := &floatReduceUnsignedIterator{
 		input:  newBufFloatIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newFloatWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -1896,6 +2279,10 @@ type floatReduceUnsignedPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *floatReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -1963,7 +2350,42 @@ func (itr *floatReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		}
 		rp.Aggregator.AggregateFloat(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *floatReduceUnsignedIterator) reduceWindow() ([]UnsignedPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*floatReduceUnsignedPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &floatReduceUnsignedPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateFloat(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *floatReduceUnsignedIterator) emit(m map[string]*floatReduceUnsignedPoint, startTime int64) []UnsignedPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -1986,7 +2408,9 @@ func (itr *floatReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -2000,7 +2424,7 @@ func (itr *floatReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		sort.Stable(sort.Reverse(unsignedPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // floatStreamUnsignedIterator streams inputs into the iterator and emits points gradually.
@@ -2010,6 +2434,7 @@ type floatStreamUnsignedIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*floatReduceUnsignedPoint
+	id     string
 	points []UnsignedPoint
 }
 
@@ -2090,6 +2515,32 @@ func (itr *floatStreamUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -2265,6 +2716,7 @@ type floatUnsignedExprFunc func(a, b float64) uint64
 // floatReduceStringIterator executes a reducer for every interval and buffers the result.
 type floatReduceStringIterator struct {
 	input    *bufFloatIterator
+	windows  *floatWindows
 	create   func() (FloatPointAggregator, StringPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -2273,12 +2725,16 @@ type floatReduceStringIterator struct {
 }
 
 func newFloatReduceStringIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, StringPointEmitter)) *floatReduceStringIterator {
-	return &floatReduceStringIterator{
+	itr := &floatReduceStringIterator{
 		input:  newBufFloatIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newFloatWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -2315,6 +2771,10 @@ type floatReduceStringPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *floatReduceStringIterator) reduce() ([]StringPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -2382,30 +2842,67 @@ func (itr *floatReduceStringIterator) reduce() ([]StringPoint, error) {
 		}
 		rp.Aggregator.AggregateFloat(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
 
-	// Reverse sort points by name & tag if our output is supposed to be ordered.
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-	if len(keys) > 1 && itr.opt.Ordered {
-		sort.Sort(reverseStringSlice(keys))
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *floatReduceStringIterator) reduceWindow() ([]StringPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
 	}
 
-	// Assume the points are already sorted until proven otherwise.
-	sortedByTime := true
-	// Emit the points for each name & tag combination.
-	a := make([]StringPoint, 0, len(m))
-	for _, k := range keys {
-		rp := m[k]
-		points := rp.Emitter.Emit()
+	// Create points by tags.
+	m := make(map[string]*floatReduceStringPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &floatReduceStringPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateFloat(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
+
+// emit returns the points emitted by the reducers of a window.
+func (itr *floatReduceStringIterator) emit(m map[string]*floatReduceStringPoint, startTime int64) []StringPoint {
+	// Reverse sort points by name & tag if our output is supposed to be ordered.
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	if len(keys) > 1 && itr.opt.Ordered {
+		sort.Sort(reverseStringSlice(keys))
+	}
+
+	// Assume the points are already sorted until proven otherwise.
+	sortedByTime := true
+	// Emit the points for each name & tag combination.
+	a := make([]StringPoint, 0, len(m))
+	for _, k := range keys {
+		rp := m[k]
+		points := rp.Emitter.Emit()
 		for i := len(points) - 1; i >= 0; i-- {
 			points[i].Name = rp.Name
 			if !itr.keepTags {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -2419,7 +2916,7 @@ func (itr *floatReduceStringIterator) reduce() ([]StringPoint, error) {
 		sort.Stable(sort.Reverse(stringPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // floatStreamStringIterator streams inputs into the iterator and emits points gradually.
@@ -2429,6 +2926,7 @@ type floatStreamStringIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*floatReduceStringPoint
+	id     string
 	points []StringPoint
 }
 
@@ -2509,6 +3007,32 @@ func (itr *floatStreamStringIterator) reduce() ([]StringPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -2684,6 +3208,7 @@ type floatStringExprFunc func(a, b float64) string
 // floatReduceBooleanIterator executes a reducer for every interval and buffers the result.
 type floatReduceBooleanIterator struct {
 	input    *bufFloatIterator
+	windows  *floatWindows
 	create   func() (FloatPointAggregator, BooleanPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -2692,12 +3217,16 @@ type floatReduceBooleanIterator struct {
 }
 
 func newFloatReduceBooleanIterator(input FloatIterator, opt IteratorOptions, createFn func() (FloatPointAggregator, BooleanPointEmitter)) *floatReduceBooleanIterator {
-	return &floatReduceBooleanIterator{
+	itr := &floatReduceBooleanIterator{
 		input:  newBufFloatIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newFloatWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -2734,6 +3263,10 @@ type floatReduceBooleanPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *floatReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -2801,7 +3334,42 @@ func (itr *floatReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		}
 		rp.Aggregator.AggregateFloat(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *floatReduceBooleanIterator) reduceWindow() ([]BooleanPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*floatReduceBooleanPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &floatReduceBooleanPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateFloat(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *floatReduceBooleanIterator) emit(m map[string]*floatReduceBooleanPoint, startTime int64) []BooleanPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -2824,7 +3392,9 @@ func (itr *floatReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -2838,7 +3408,7 @@ func (itr *floatReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		sort.Stable(sort.Reverse(booleanPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // floatStreamBooleanIterator streams inputs into the iterator and emits points gradually.
@@ -2848,6 +3418,7 @@ type floatStreamBooleanIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*floatReduceBooleanPoint
+	id     string
 	points []BooleanPoint
 }
 
@@ -2928,6 +3499,32 @@ func (itr *floatStreamBooleanIterator) reduce() ([]BooleanPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -3290,6 +3887,54 @@ func (itr *floatFilterIterator) Next() (*FloatPoint, error) {
 	}
 }
 
+// floatCaseIterator evaluates a CASE expression for each point of the
+// input. The auxiliary fields of the input are the values of refs.
+type floatCaseIterator struct {
+	input FloatIterator
+	expr  *CaseExpr
+	refs  []VarRef
+	m     map[string]interface{}
+}
+
+func newFloatCaseIterator(input FloatIterator, expr *CaseExpr, refs []VarRef) *floatCaseIterator {
+	return &floatCaseIterator{
+		input: input,
+		expr:  expr,
+		refs:  refs,
+		m:     make(map[string]interface{}, len(refs)),
+	}
+}
+
+func (itr *floatCaseIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *floatCaseIterator) Close() error         { return itr.input.Close() }
+
+func (itr *floatCaseIterator) Next() (*FloatPoint, error) {
+	p, err := itr.input.Next()
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	for i, ref := range itr.refs {
+		itr.m[ref.Val] = p.Aux[i]
+	}
+
+	out := &FloatPoint{
+		Name:       p.Name,
+		Tags:       p.Tags,
+		Time:       p.Time,
+		Aggregated: p.Aggregated,
+	}
+	switch v := Eval(itr.expr, itr.m).(type) {
+	case float64:
+		out.Value = v
+	case int64:
+		out.Value = float64(v)
+	default:
+		out.Nil = true
+	}
+	return out, nil
+}
+
 // newFloatDedupeIterator returns a new instance of floatDedupeIterator.
 func newFloatDedupeIterator(input FloatIterator) *floatDedupeIterator {
 	return &floatDedupeIterator{
@@ -3458,6 +4103,160 @@ func (itr *bufIntegerIterator) NextInWindow(startTime, endTime int64) (*IntegerP
 // unread sets v to the buffer. It is read on the next call to Next().
 func (itr *bufIntegerIterator) unread(v *IntegerPoint) { itr.buf = v }
 
+// integerWindows reads the points of overlapping windows from an input
+// iterator. The input is read one interval at a time and each interval is
+// buffered until every window that contains it has been read.
+type integerWindows struct {
+	input *bufIntegerIterator
+	opt   IteratorOptions
+
+	// The series being read and its buffered intervals in the order they
+	// were read.
+	name, tags string
+	active     bool
+	done       bool
+	intervals  []integerWindowInterval
+	last       int64
+
+	// The start of the next window and of the earliest window in the
+	// time range of the query.
+	next, min int64
+}
+
+// integerWindowInterval holds the points of a series within an interval.
+type integerWindowInterval struct {
+	start  int64
+	points []IntegerPoint
+}
+
+// newIntegerWindows returns a new instance of integerWindows.
+func newIntegerWindows(input *bufIntegerIterator, opt IteratorOptions) *integerWindows {
+	min, _ := opt.Window(opt.StartTime)
+	return &integerWindows{input: input, opt: opt, min: min}
+}
+
+// Next returns the start time and the points of the next window that contains
+// any points. Returns nil points when there are no more windows.
+func (w *integerWindows) Next() (int64, []IntegerPoint, error) {
+	step, length := int64(w.opt.Interval.Duration), int64(w.opt.Interval.Length)
+	for {
+		// Read the first interval of the next series.
+		if !w.active {
+			iv, err := w.read()
+			if err != nil || iv == nil {
+				return 0, nil, err
+			}
+			w.intervals = append(w.intervals[:0], *iv)
+			w.last, w.done = iv.start, false
+			if w.opt.Ascending {
+				w.next = w.first(iv.start)
+			} else {
+				w.next = iv.start
+			}
+			continue
+		}
+
+		// Read intervals until every interval of the next window has been read.
+		if !w.done && ((w.opt.Ascending && w.last-w.next < length) || (!w.opt.Ascending && w.last >= w.next)) {
+			iv, err := w.read()
+			if err != nil {
+				return 0, nil, err
+			} else if iv == nil {
+				w.done = true
+				continue
+			}
+
+			// Skip the windows without any points if the series has a gap.
+			if len(w.intervals) == 0 {
+				if w.opt.Ascending {
+					if first := w.first(iv.start); first > w.next {
+						w.next = first
+					}
+				} else if iv.start < w.next {
+					w.next = iv.start
+				}
+			}
+			w.intervals = append(w.intervals, *iv)
+			w.last = iv.start
+			continue
+		}
+
+		// Move on to the next series once every window has been read.
+		if len(w.intervals) == 0 {
+			w.active = false
+			continue
+		}
+
+		start := w.next
+		var points []IntegerPoint
+		for _, iv := range w.intervals {
+			if iv.start >= start && iv.start-start < length {
+				points = append(points, iv.points...)
+			}
+		}
+
+		// Drop the intervals that are not a part of any of the later windows.
+		i := 0
+		if w.opt.Ascending {
+			w.next += step
+			for i < len(w.intervals) && w.intervals[i].start < w.next {
+				i++
+			}
+		} else {
+			w.next -= step
+			for i < len(w.intervals) && w.intervals[i].start-w.next >= length {
+				i++
+			}
+		}
+		w.intervals = w.intervals[i:]
+
+		if len(points) > 0 && start >= w.min {
+			return start, points, nil
+		}
+	}
+}
+
+// first returns the start of the earliest window that contains the interval.
+func (w *integerWindows) first(start int64) int64 {
+	if d := int64(w.opt.Interval.Length - w.opt.Interval.Duration); uint64(start-w.min) > uint64(d) {
+		return start - d
+	}
+	return w.min
+}
+
+// read reads the points of the next interval of the current series. Returns
+// nil if there are no more points in the series.
+func (w *integerWindows) read() (*integerWindowInterval, error) {
+	var iv *integerWindowInterval
+	for {
+		p, err := w.input.Next()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			return iv, nil
+		} else if p.Nil {
+			continue
+		}
+
+		tags := p.Tags.Subset(w.opt.Dimensions).ID()
+		if !w.active {
+			w.name, w.tags, w.active = p.Name, tags, true
+		} else if p.Name != w.name || tags != w.tags {
+			w.input.unread(p)
+			return iv, nil
+		}
+
+		start, _ := w.opt.Window(p.Time)
+		if iv == nil {
+			iv = &integerWindowInterval{start: start}
+		} else if start != iv.start {
+			w.input.unread(p)
+			return iv, nil
+		}
+		iv.points = append(iv.points, *p.Clone())
+	}
+}
+
 // integerMergeIterator represents an iterator that combines multiple integer iterators.
 type integerMergeIterator struct {
 	inputs []IntegerIterator
@@ -3918,6 +4717,8 @@ type integerFillIterator struct {
 	auxFields []interface{}
 	init      bool
 	opt       IteratorOptions
+	lookback  map[fillSeriesKey]lookbackPoint
+	seeds     []fillSeriesKey
 
 	window struct {
 		name   string
@@ -3927,9 +4728,9 @@ type integerFillIterator struct {
 	}
 }
 
-func newIntegerFillIterator(input IntegerIterator, expr Expr, opt IteratorOptions) *integerFillIterator {
+func newIntegerFillIterator(input IntegerIterator, expr Expr, opt IteratorOptions, lookback map[fillSeriesKey]lookbackPoint) *integerFillIterator {
 	if opt.Fill == NullFill {
-		if expr, ok := expr.(*Call); ok && expr.Name == "count" {
+		if expr, ok := expr.(*Call); ok && (expr.Name == "count" || expr.Name == "count_distinct_approx" || expr.Name == "histogram") {
 			opt.Fill = NumberFill
 			opt.FillValue = int64(0)
 		}
@@ -3956,6 +4757,8 @@ func newIntegerFillIterator(input IntegerIterator, expr Expr, opt IteratorOption
 		endTime:   endTime,
 		auxFields: auxFields,
 		opt:       opt,
+		lookback:  lookback,
+		seeds:     lookbackSeries(lookback, opt.Ascending),
 	}
 }
 
@@ -3965,13 +4768,10 @@ func (itr *integerFillIterator) Close() error         { return itr.input.Close()
 func (itr *integerFillIterator) Next() (*IntegerPoint, error) {
 	if !itr.init {
 		p, err := itr.input.peek()
-		if p == nil || err != nil {
+		if err != nil {
 			return nil, err
-		}
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		} else if !itr.nextSeries(p) {
+			return nil, nil
 		}
 		itr.init = true
 	}
@@ -3999,19 +4799,18 @@ func (itr *integerFillIterator) Next() (*IntegerPoint, error) {
 			}
 		}
 
-		// We are *not* in a current interval. If there is no next point,
-		// we are at the end of all intervals.
-		if p == nil {
+		// We are *not* in a current interval. If there is no next point and
+		// no series with only a lookback point, we are at the end of all
+		// intervals.
+		if !itr.nextSeries(p) {
 			return nil, nil
 		}
 
-		// Set the new interval.
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		// Fill the series with only a lookback point before the point.
+		if p != nil && (p.Name != itr.window.name || p.Tags.ID() != itr.window.tags.ID()) {
+			itr.input.unread(p)
+			p = nil
 		}
-		itr.prev = IntegerPoint{Nil: true}
 		break
 	}
 
@@ -4036,6 +4835,11 @@ func (itr *integerFillIterator) Next() (*IntegerPoint, error) {
 					return nil, err
 				} else if next != nil && next.Name == itr.window.name && next.Tags.ID() == itr.window.tags.ID() {
 					interval := int64(itr.opt.Interval.Duration)
+					if itr.opt.Interval.Months != 0 {
+						// Calendar windows vary in length so the times are
+						// used as they are.
+						interval = 1
+					}
 					start := itr.window.time / interval
 					p.Value = linearInteger(start, itr.prev.Time/interval, next.Time/interval, itr.prev.Value, next.Value)
 				} else {
@@ -4064,7 +4868,14 @@ func (itr *integerFillIterator) Next() (*IntegerPoint, error) {
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows vary in length so look up the adjacent window.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -4072,7 +4883,7 @@ func (itr *integerFillIterator) Next() (*IntegerPoint, error) {
 
 	// Check to see if we have passed over an offset change and adjust the time
 	// to account for this new offset.
-	if itr.opt.Location != nil {
+	if itr.opt.Location != nil && itr.opt.Interval.Months == 0 {
 		if _, offset := itr.opt.Zone(itr.window.time - 1); offset != itr.window.offset {
 			diff := itr.window.offset - offset
 			if abs(diff) < int64(itr.opt.Interval.Duration) {
@@ -4084,6 +4895,50 @@ func (itr *integerFillIterator) Next() (*IntegerPoint, error) {
 	return p, nil
 }
 
+// nextSeries starts the windows of the next series. The series of the point
+// is next unless a series with only a lookback point comes before it. It
+// returns false if there are no more series.
+func (itr *integerFillIterator) nextSeries(p *IntegerPoint) bool {
+	var key fillSeriesKey
+	if p != nil {
+		key = fillSeriesKey{name: p.Name, tags: p.Tags.ID()}
+	}
+
+	if len(itr.seeds) > 0 && (p == nil || itr.seeds[0].before(key, itr.opt.Ascending)) {
+		itr.window.name, itr.window.tags = itr.seeds[0].name, itr.lookback[itr.seeds[0]].tags
+		itr.seeds = itr.seeds[1:]
+	} else if p != nil {
+		if len(itr.seeds) > 0 && itr.seeds[0] == key {
+			itr.seeds = itr.seeds[1:]
+		}
+		itr.window.name, itr.window.tags = p.Name, p.Tags
+	} else {
+		return false
+	}
+
+	itr.window.time = itr.startTime
+	if itr.opt.Location != nil {
+		_, itr.window.offset = itr.opt.Zone(itr.window.time)
+	}
+	itr.prev = itr.previous()
+	return true
+}
+
+// previous returns the point that precedes the current series from the
+// lookback points. A nil point is returned if there is none.
+func (itr *integerFillIterator) previous() IntegerPoint {
+	p, ok := itr.lookback[fillSeriesKey{name: itr.window.name, tags: itr.window.tags.ID()}]
+	if !ok {
+		return IntegerPoint{Nil: true}
+	}
+	return IntegerPoint{
+		Name:  itr.window.name,
+		Tags:  itr.window.tags,
+		Time:  p.time,
+		Value: castToInteger(p.value),
+	}
+}
+
 // integerIntervalIterator represents a integer implementation of IntervalIterator.
 type integerIntervalIterator struct {
 	input IntegerIterator
@@ -4111,6 +4966,28 @@ func (itr *integerIntervalIterator) Next() (*IntegerPoint, error) {
 	return p, nil
 }
 
+// integerRenameIterator represents a integer implementation of RenameIterator.
+type integerRenameIterator struct {
+	input IntegerIterator
+	name  string
+}
+
+func newIntegerRenameIterator(input IntegerIterator, name string) *integerRenameIterator {
+	return &integerRenameIterator{input: input, name: name}
+}
+
+func (itr *integerRenameIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *integerRenameIterator) Close() error         { return itr.input.Close() }
+
+func (itr *integerRenameIterator) Next() (*IntegerPoint, error) {
+	p, err := itr.input.Next()
+	if p == nil || err != nil {
+		return nil, err
+	}
+	p.Name = itr.name
+	return p, nil
+}
+
 // integerInterruptIterator represents a integer implementation of InterruptIterator.
 type integerInterruptIterator struct {
 	input   IntegerIterator
@@ -4327,17 +5204,17 @@ func (itr *integerChanIterator) Next() (*IntegerPoint, error) {
 	itr.cond.L.Lock()
 	defer itr.cond.L.Unlock()
 
+	// Wait until either a value is available in the buffer, the iterator
+	// is closed or an error is set.
+	for !itr.done && !itr.buf.filled && itr.err == nil {
+		itr.cond.Wait()
+	}
+
 	// Check for an error and return one if there.
 	if itr.err != nil {
 		return nil, itr.err
 	}
 
-	// Wait until either a value is available in the buffer or
-	// the iterator is closed.
-	for !itr.done && !itr.buf.filled {
-		itr.cond.Wait()
-	}
-
 	// Return nil once the channel is done and the buffer is empty.
 	if itr.done && !itr.buf.filled {
 		return nil, nil
@@ -4356,6 +5233,7 @@ func (itr *integerChanIterator) Next() (*IntegerPoint, error) {
 // integerReduceFloatIterator executes a reducer for every interval and buffers the result.
 type integerReduceFloatIterator struct {
 	input    *bufIntegerIterator
+	windows  *integerWindows
 	create   func() (IntegerPointAggregator, FloatPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -4364,12 +5242,16 @@ type integerReduceFloatIterator struct {
 }
 
 func newIntegerReduceFloatIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, FloatPointEmitter)) *integerReduceFloatIterator {
-	return &integerReduceFloatIterator{
+	itr := &integerReduceFloatIterator{
 		input:  newBufIntegerIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newIntegerWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -4406,6 +5288,10 @@ type integerReduceFloatPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *integerReduceFloatIterator) reduce() ([]FloatPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -4473,7 +5359,42 @@ func (itr *integerReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		}
 		rp.Aggregator.AggregateInteger(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *integerReduceFloatIterator) reduceWindow() ([]FloatPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*integerReduceFloatPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &integerReduceFloatPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateInteger(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *integerReduceFloatIterator) emit(m map[string]*integerReduceFloatPoint, startTime int64) []FloatPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -4496,7 +5417,9 @@ func (itr *integerReduceFloatIterator) reduce() ([]FloatPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -4510,7 +5433,7 @@ func (itr *integerReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		sort.Stable(sort.Reverse(floatPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // integerStreamFloatIterator streams inputs into the iterator and emits points gradually.
@@ -4520,6 +5443,7 @@ type integerStreamFloatIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*integerReduceFloatPoint
+	id     string
 	points []FloatPoint
 }
 
@@ -4600,8 +5524,34 @@ func (itr *integerStreamFloatIterator) reduce() ([]FloatPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
-		// Retrieve the aggregator for this name/tag combination or create one.
-		rp := itr.m[id]
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		rp := itr.m[id]
 		if rp == nil {
 			aggregator, emitter := itr.create()
 			rp = &integerReduceFloatPoint{
@@ -4775,6 +5725,7 @@ type integerFloatExprFunc func(a, b int64) float64
 // integerReduceIntegerIterator executes a reducer for every interval and buffers the result.
 type integerReduceIntegerIterator struct {
 	input    *bufIntegerIterator
+	windows  *integerWindows
 	create   func() (IntegerPointAggregator, IntegerPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -4783,12 +5734,16 @@ type integerReduceIntegerIterator struct {
 }
 
 func newIntegerReduceIntegerIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, IntegerPointEmitter)) *integerReduceIntegerIterator {
-	return &integerReduceIntegerIterator{
+	itr := &integerReduceIntegerIterator{
 		input:  newBufIntegerIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newIntegerWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -4825,6 +5780,10 @@ type integerReduceIntegerPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *integerReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -4892,7 +5851,42 @@ func (itr *integerReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		}
 		rp.Aggregator.AggregateInteger(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *integerReduceIntegerIterator) reduceWindow() ([]IntegerPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*integerReduceIntegerPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &integerReduceIntegerPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateInteger(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *integerReduceIntegerIterator) emit(m map[string]*integerReduceIntegerPoint, startTime int64) []IntegerPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -4915,7 +5909,9 @@ func (itr *integerReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -4929,7 +5925,7 @@ func (itr *integerReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		sort.Stable(sort.Reverse(integerPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // integerStreamIntegerIterator streams inputs into the iterator and emits points gradually.
@@ -4939,6 +5935,7 @@ type integerStreamIntegerIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*integerReduceIntegerPoint
+	id     string
 	points []IntegerPoint
 }
 
@@ -5019,6 +6016,32 @@ func (itr *integerStreamIntegerIterator) reduce() ([]IntegerPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -5190,6 +6213,7 @@ type integerExprFunc func(a, b int64) int64
 // integerReduceUnsignedIterator executes a reducer for every interval and buffers the result.
 type integerReduceUnsignedIterator struct {
 	input    *bufIntegerIterator
+	windows  *integerWindows
 	create   func() (IntegerPointAggregator, UnsignedPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -5198,12 +6222,16 @@ type integerReduceUnsignedIterator struct {
 }
 
 func newIntegerReduceUnsignedIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, UnsignedPointEmitter)) *integerReduceUnsignedIterator {
-	return &integerReduceUnsignedIterator{
+	itr := &integerReduceUnsignedIterator{
 		input:  newBufIntegerIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newIntegerWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -5240,6 +6268,10 @@ type integerReduceUnsignedPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *integerReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -5307,7 +6339,42 @@ func (itr *integerReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		}
 		rp.Aggregator.AggregateInteger(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *integerReduceUnsignedIterator) reduceWindow() ([]UnsignedPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
 
+	// Create points by tags.
+	m := make(map[string]*integerReduceUnsignedPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &integerReduceUnsignedPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateInteger(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
+
+// emit returns the points emitted by the reducers of a window.
+func (itr *integerReduceUnsignedIterator) emit(m map[string]*integerReduceUnsignedPoint, startTime int64) []UnsignedPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -5330,7 +6397,9 @@ func (itr *integerReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -5344,7 +6413,7 @@ func (itr *integerReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		sort.Stable(sort.Reverse(unsignedPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // integerStreamUnsignedIterator streams inputs into the iterator and emits points gradually.
@@ -5354,6 +6423,7 @@ type integerStreamUnsignedIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*integerReduceUnsignedPoint
+	id     string
 	points []UnsignedPoint
 }
 
@@ -5434,6 +6504,32 @@ func (itr *integerStreamUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -5609,6 +6705,7 @@ type integerUnsignedExprFunc func(a, b int64) uint64
 // integerReduceStringIterator executes a reducer for every interval and buffers the result.
 type integerReduceStringIterator struct {
 	input    *bufIntegerIterator
+	windows  *integerWindows
 	create   func() (IntegerPointAggregator, StringPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -5617,12 +6714,16 @@ type integerReduceStringIterator struct {
 }
 
 func newIntegerReduceStringIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, StringPointEmitter)) *integerReduceStringIterator {
-	return &integerReduceStringIterator{
+	itr := &integerReduceStringIterator{
 		input:  newBufIntegerIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newIntegerWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -5659,6 +6760,10 @@ type integerReduceStringPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *integerReduceStringIterator) reduce() ([]StringPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -5726,7 +6831,42 @@ func (itr *integerReduceStringIterator) reduce() ([]StringPoint, error) {
 		}
 		rp.Aggregator.AggregateInteger(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
 
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *integerReduceStringIterator) reduceWindow() ([]StringPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*integerReduceStringPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &integerReduceStringPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateInteger(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
+
+// emit returns the points emitted by the reducers of a window.
+func (itr *integerReduceStringIterator) emit(m map[string]*integerReduceStringPoint, startTime int64) []StringPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -5749,7 +6889,9 @@ func (itr *integerReduceStringIterator) reduce() ([]StringPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -5763,7 +6905,7 @@ func (itr *integerReduceStringIterator) reduce() ([]StringPoint, error) {
 		sort.Stable(sort.Reverse(stringPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // integerStreamStringIterator streams inputs into the iterator and emits points gradually.
@@ -5773,6 +6915,7 @@ type integerStreamStringIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*integerReduceStringPoint
+	id     string
 	points []StringPoint
 }
 
@@ -5853,6 +6996,32 @@ func (itr *integerStreamStringIterator) reduce() ([]StringPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -6028,6 +7197,7 @@ type integerStringExprFunc func(a, b int64) string
 // integerReduceBooleanIterator executes a reducer for every interval and buffers the result.
 type integerReduceBooleanIterator struct {
 	input    *bufIntegerIterator
+	windows  *integerWindows
 	create   func() (IntegerPointAggregator, BooleanPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -6036,12 +7206,16 @@ type integerReduceBooleanIterator struct {
 }
 
 func newIntegerReduceBooleanIterator(input IntegerIterator, opt IteratorOptions, createFn func() (IntegerPointAggregator, BooleanPointEmitter)) *integerReduceBooleanIterator {
-	return &integerReduceBooleanIterator{
+	itr := &integerReduceBooleanIterator{
 		input:  newBufIntegerIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newIntegerWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -6078,6 +7252,10 @@ type integerReduceBooleanPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *integerReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -6145,7 +7323,42 @@ func (itr *integerReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		}
 		rp.Aggregator.AggregateInteger(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *integerReduceBooleanIterator) reduceWindow() ([]BooleanPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*integerReduceBooleanPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &integerReduceBooleanPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateInteger(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *integerReduceBooleanIterator) emit(m map[string]*integerReduceBooleanPoint, startTime int64) []BooleanPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -6168,7 +7381,9 @@ func (itr *integerReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -6182,7 +7397,7 @@ func (itr *integerReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		sort.Stable(sort.Reverse(booleanPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // integerStreamBooleanIterator streams inputs into the iterator and emits points gradually.
@@ -6192,6 +7407,7 @@ type integerStreamBooleanIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*integerReduceBooleanPoint
+	id     string
 	points []BooleanPoint
 }
 
@@ -6272,6 +7488,32 @@ func (itr *integerStreamBooleanIterator) reduce() ([]BooleanPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -6634,6 +7876,52 @@ func (itr *integerFilterIterator) Next() (*IntegerPoint, error) {
 	}
 }
 
+// integerCaseIterator evaluates a CASE expression for each point of the
+// input. The auxiliary fields of the input are the values of refs.
+type integerCaseIterator struct {
+	input FloatIterator
+	expr  *CaseExpr
+	refs  []VarRef
+	m     map[string]interface{}
+}
+
+func newIntegerCaseIterator(input FloatIterator, expr *CaseExpr, refs []VarRef) *integerCaseIterator {
+	return &integerCaseIterator{
+		input: input,
+		expr:  expr,
+		refs:  refs,
+		m:     make(map[string]interface{}, len(refs)),
+	}
+}
+
+func (itr *integerCaseIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *integerCaseIterator) Close() error         { return itr.input.Close() }
+
+func (itr *integerCaseIterator) Next() (*IntegerPoint, error) {
+	p, err := itr.input.Next()
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	for i, ref := range itr.refs {
+		itr.m[ref.Val] = p.Aux[i]
+	}
+
+	out := &IntegerPoint{
+		Name:       p.Name,
+		Tags:       p.Tags,
+		Time:       p.Time,
+		Aggregated: p.Aggregated,
+	}
+	switch v := Eval(itr.expr, itr.m).(type) {
+	case int64:
+		out.Value = v
+	default:
+		out.Nil = true
+	}
+	return out, nil
+}
+
 // newIntegerDedupeIterator returns a new instance of integerDedupeIterator.
 func newIntegerDedupeIterator(input IntegerIterator) *integerDedupeIterator {
 	return &integerDedupeIterator{
@@ -6753,55 +8041,209 @@ func newBufUnsignedIterator(itr UnsignedIterator) *bufUnsignedIterator {
 // Stats returns statistics from the input iterator.
 func (itr *bufUnsignedIterator) Stats() IteratorStats { return itr.itr.Stats() }
 
-// Close closes the underlying iterator.
-func (itr *bufUnsignedIterator) Close() error { return itr.itr.Close() }
+// Close closes the underlying iterator.
+func (itr *bufUnsignedIterator) Close() error { return itr.itr.Close() }
+
+// peek returns the next point without removing it from the iterator.
+func (itr *bufUnsignedIterator) peek() (*UnsignedPoint, error) {
+	p, err := itr.Next()
+	if err != nil {
+		return nil, err
+	}
+	itr.unread(p)
+	return p, nil
+}
+
+// peekTime returns the time of the next point.
+// Returns zero time if no more points available.
+func (itr *bufUnsignedIterator) peekTime() (int64, error) {
+	p, err := itr.peek()
+	if p == nil || err != nil {
+		return ZeroTime, err
+	}
+	return p.Time, nil
+}
+
+// Next returns the current buffer, if exists, or calls the underlying iterator.
+func (itr *bufUnsignedIterator) Next() (*UnsignedPoint, error) {
+	buf := itr.buf
+	if buf != nil {
+		itr.buf = nil
+		return buf, nil
+	}
+	return itr.itr.Next()
+}
+
+// NextInWindow returns the next value if it is between [startTime, endTime).
+// If the next value is outside the range then it is moved to the buffer.
+func (itr *bufUnsignedIterator) NextInWindow(startTime, endTime int64) (*UnsignedPoint, error) {
+	v, err := itr.Next()
+	if v == nil || err != nil {
+		return nil, err
+	} else if t := v.Time; t >= endTime || t < startTime {
+		itr.unread(v)
+		return nil, nil
+	}
+	return v, nil
+}
+
+// unread sets v to the buffer. It is read on the next call to Next().
+func (itr *bufUnsignedIterator) unread(v *UnsignedPoint) { itr.buf = v }
+
+// unsignedWindows reads the points of overlapping windows from an input
+// iterator. The input is read one interval at a time and each interval is
+// buffered until every window that contains it has been read.
+type unsignedWindows struct {
+	input *bufUnsignedIterator
+	opt   IteratorOptions
+
+	// The series being read and its buffered intervals in the order they
+	// were read.
+	name, tags string
+	active     bool
+	done       bool
+	intervals  []unsignedWindowInterval
+	last       int64
+
+	// The start of the next window and of the earliest window in the
+	// time range of the query.
+	next, min int64
+}
+
+// unsignedWindowInterval holds the points of a series within an interval.
+type unsignedWindowInterval struct {
+	start  int64
+	points []UnsignedPoint
+}
+
+// newUnsignedWindows returns a new instance of unsignedWindows.
+func newUnsignedWindows(input *bufUnsignedIterator, opt IteratorOptions) *unsignedWindows {
+	min, _ := opt.Window(opt.StartTime)
+	return &unsignedWindows{input: input, opt: opt, min: min}
+}
+
+// Next returns the start time and the points of the next window that contains
+// any points. Returns nil points when there are no more windows.
+func (w *unsignedWindows) Next() (int64, []UnsignedPoint, error) {
+	step, length := int64(w.opt.Interval.Duration), int64(w.opt.Interval.Length)
+	for {
+		// Read the first interval of the next series.
+		if !w.active {
+			iv, err := w.read()
+			if err != nil || iv == nil {
+				return 0, nil, err
+			}
+			w.intervals = append(w.intervals[:0], *iv)
+			w.last, w.done = iv.start, false
+			if w.opt.Ascending {
+				w.next = w.first(iv.start)
+			} else {
+				w.next = iv.start
+			}
+			continue
+		}
+
+		// Read intervals until every interval of the next window has been read.
+		if !w.done && ((w.opt.Ascending && w.last-w.next < length) || (!w.opt.Ascending && w.last >= w.next)) {
+			iv, err := w.read()
+			if err != nil {
+				return 0, nil, err
+			} else if iv == nil {
+				w.done = true
+				continue
+			}
+
+			// Skip the windows without any points if the series has a gap.
+			if len(w.intervals) == 0 {
+				if w.opt.Ascending {
+					if first := w.first(iv.start); first > w.next {
+						w.next = first
+					}
+				} else if iv.start < w.next {
+					w.next = iv.start
+				}
+			}
+			w.intervals = append(w.intervals, *iv)
+			w.last = iv.start
+			continue
+		}
+
+		// Move on to the next series once every window has been read.
+		if len(w.intervals) == 0 {
+			w.active = false
+			continue
+		}
+
+		start := w.next
+		var points []UnsignedPoint
+		for _, iv := range w.intervals {
+			if iv.start >= start && iv.start-start < length {
+				points = append(points, iv.points...)
+			}
+		}
+
+		// Drop the intervals that are not a part of any of the later windows.
+		i := 0
+		if w.opt.Ascending {
+			w.next += step
+			for i < len(w.intervals) && w.intervals[i].start < w.next {
+				i++
+			}
+		} else {
+			w.next -= step
+			for i < len(w.intervals) && w.intervals[i].start-w.next >= length {
+				i++
+			}
+		}
+		w.intervals = w.intervals[i:]
 
-// peek returns the next point without removing it from the iterator.
-func (itr *bufUnsignedIterator) peek() (*UnsignedPoint, error) {
-	p, err := itr.Next()
-	if err != nil {
-		return nil, err
+		if len(points) > 0 && start >= w.min {
+			return start, points, nil
+		}
 	}
-	itr.unread(p)
-	return p, nil
 }
 
-// peekTime returns the time of the next point.
-// Returns zero time if no more points available.
-func (itr *bufUnsignedIterator) peekTime() (int64, error) {
-	p, err := itr.peek()
-	if p == nil || err != nil {
-		return ZeroTime, err
+// first returns the start of the earliest window that contains the interval.
+func (w *unsignedWindows) first(start int64) int64 {
+	if d := int64(w.opt.Interval.Length - w.opt.Interval.Duration); uint64(start-w.min) > uint64(d) {
+		return start - d
 	}
-	return p.Time, nil
+	return w.min
 }
 
-// Next returns the current buffer, if exists, or calls the underlying iterator.
-func (itr *bufUnsignedIterator) Next() (*UnsignedPoint, error) {
-	buf := itr.buf
-	if buf != nil {
-		itr.buf = nil
-		return buf, nil
-	}
-	return itr.itr.Next()
-}
+// read reads the points of the next interval of the current series. Returns
+// nil if there are no more points in the series.
+func (w *unsignedWindows) read() (*unsignedWindowInterval, error) {
+	var iv *unsignedWindowInterval
+	for {
+		p, err := w.input.Next()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			return iv, nil
+		} else if p.Nil {
+			continue
+		}
 
-// NextInWindow returns the next value if it is between [startTime, endTime).
-// If the next value is outside the range then it is moved to the buffer.
-func (itr *bufUnsignedIterator) NextInWindow(startTime, endTime int64) (*UnsignedPoint, error) {
-	v, err := itr.Next()
-	if v == nil || err != nil {
-		return nil, err
-	} else if t := v.Time; t >= endTime || t < startTime {
-		itr.unread(v)
-		return nil, nil
+		tags := p.Tags.Subset(w.opt.Dimensions).ID()
+		if !w.active {
+			w.name, w.tags, w.active = p.Name, tags, true
+		} else if p.Name != w.name || tags != w.tags {
+			w.input.unread(p)
+			return iv, nil
+		}
+
+		start, _ := w.opt.Window(p.Time)
+		if iv == nil {
+			iv = &unsignedWindowInterval{start: start}
+		} else if start != iv.start {
+			w.input.unread(p)
+			return iv, nil
+		}
+		iv.points = append(iv.points, *p.Clone())
 	}
-	return v, nil
 }
 
-// unread sets v to the buffer. It is read on the next call to Next().
-func (itr *bufUnsignedIterator) unread(v *UnsignedPoint) { itr.buf = v }
-
 // unsignedMergeIterator represents an iterator that combines multiple unsigned iterators.
 type unsignedMergeIterator struct {
 	inputs []UnsignedIterator
@@ -7262,6 +8704,8 @@ type unsignedFillIterator struct {
 	auxFields []interface{}
 	init      bool
 	opt       IteratorOptions
+	lookback  map[fillSeriesKey]lookbackPoint
+	seeds     []fillSeriesKey
 
 	window struct {
 		name   string
@@ -7271,9 +8715,9 @@ type unsignedFillIterator struct {
 	}
 }
 
-func newUnsignedFillIterator(input UnsignedIterator, expr Expr, opt IteratorOptions) *unsignedFillIterator {
+func newUnsignedFillIterator(input UnsignedIterator, expr Expr, opt IteratorOptions, lookback map[fillSeriesKey]lookbackPoint) *unsignedFillIterator {
 	if opt.Fill == NullFill {
-		if expr, ok := expr.(*Call); ok && expr.Name == "count" {
+		if expr, ok := expr.(*Call); ok && (expr.Name == "count" || expr.Name == "count_distinct_approx" || expr.Name == "histogram") {
 			opt.Fill = NumberFill
 			opt.FillValue = uint64(0)
 		}
@@ -7300,6 +8744,8 @@ func newUnsignedFillIterator(input UnsignedIterator, expr Expr, opt IteratorOpti
 		endTime:   endTime,
 		auxFields: auxFields,
 		opt:       opt,
+		lookback:  lookback,
+		seeds:     lookbackSeries(lookback, opt.Ascending),
 	}
 }
 
@@ -7309,13 +8755,10 @@ func (itr *unsignedFillIterator) Close() error         { return itr.input.Close(
 func (itr *unsignedFillIterator) Next() (*UnsignedPoint, error) {
 	if !itr.init {
 		p, err := itr.input.peek()
-		if p == nil || err != nil {
+		if err != nil {
 			return nil, err
-		}
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		} else if !itr.nextSeries(p) {
+			return nil, nil
 		}
 		itr.init = true
 	}
@@ -7343,19 +8786,18 @@ func (itr *unsignedFillIterator) Next() (*UnsignedPoint, error) {
 			}
 		}
 
-		// We are *not* in a current interval. If there is no next point,
-		// we are at the end of all intervals.
-		if p == nil {
+		// We are *not* in a current interval. If there is no next point and
+		// no series with only a lookback point, we are at the end of all
+		// intervals.
+		if !itr.nextSeries(p) {
 			return nil, nil
 		}
 
-		// Set the new interval.
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		// Fill the series with only a lookback point before the point.
+		if p != nil && (p.Name != itr.window.name || p.Tags.ID() != itr.window.tags.ID()) {
+			itr.input.unread(p)
+			p = nil
 		}
-		itr.prev = UnsignedPoint{Nil: true}
 		break
 	}
 
@@ -7394,7 +8836,14 @@ func (itr *unsignedFillIterator) Next() (*UnsignedPoint, error) {
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows vary in length so look up the adjacent window.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -7402,7 +8851,7 @@ func (itr *unsignedFillIterator) Next() (*UnsignedPoint, error) {
 
 	// Check to see if we have passed over an offset change and adjust the time
 	// to account for this new offset.
-	if itr.opt.Location != nil {
+	if itr.opt.Location != nil && itr.opt.Interval.Months == 0 {
 		if _, offset := itr.opt.Zone(itr.window.time - 1); offset != itr.window.offset {
 			diff := itr.window.offset - offset
 			if abs(diff) < int64(itr.opt.Interval.Duration) {
@@ -7414,6 +8863,50 @@ func (itr *unsignedFillIterator) Next() (*UnsignedPoint, error) {
 	return p, nil
 }
 
+// nextSeries starts the windows of the next series. The series of the point
+// is next unless a series with only a lookback point comes before it. It
+// returns false if there are no more series.
+func (itr *unsignedFillIterator) nextSeries(p *UnsignedPoint) bool {
+	var key fillSeriesKey
+	if p != nil {
+		key = fillSeriesKey{name: p.Name, tags: p.Tags.ID()}
+	}
+
+	if len(itr.seeds) > 0 && (p == nil || itr.seeds[0].before(key, itr.opt.Ascending)) {
+		itr.window.name, itr.window.tags = itr.seeds[0].name, itr.lookback[itr.seeds[0]].tags
+		itr.seeds = itr.seeds[1:]
+	} else if p != nil {
+		if len(itr.seeds) > 0 && itr.seeds[0] == key {
+			itr.seeds = itr.seeds[1:]
+		}
+		itr.window.name, itr.window.tags = p.Name, p.Tags
+	} else {
+		return false
+	}
+
+	itr.window.time = itr.startTime
+	if itr.opt.Location != nil {
+		_, itr.window.offset = itr.opt.Zone(itr.window.time)
+	}
+	itr.prev = itr.previous()
+	return true
+}
+
+// previous returns the point that precedes the current series from the
+// lookback points. A nil point is returned if there is none.
+func (itr *unsignedFillIterator) previous() UnsignedPoint {
+	p, ok := itr.lookback[fillSeriesKey{name: itr.window.name, tags: itr.window.tags.ID()}]
+	if !ok {
+		return UnsignedPoint{Nil: true}
+	}
+	return UnsignedPoint{
+		Name:  itr.window.name,
+		Tags:  itr.window.tags,
+		Time:  p.time,
+		Value: castToUnsigned(p.value),
+	}
+}
+
 // unsignedIntervalIterator represents a unsigned implementation of IntervalIterator.
 type unsignedIntervalIterator struct {
 	input UnsignedIterator
@@ -7441,6 +8934,28 @@ func (itr *unsignedIntervalIterator) Next() (*UnsignedPoint, error) {
 	return p, nil
 }
 
+// unsignedRenameIterator represents a unsigned implementation of RenameIterator.
+type unsignedRenameIterator struct {
+	input UnsignedIterator
+	name  string
+}
+
+func newUnsignedRenameIterator(input UnsignedIterator, name string) *unsignedRenameIterator {
+	return &unsignedRenameIterator{input: input, name: name}
+}
+
+func (itr *unsignedRenameIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *unsignedRenameIterator) Close() error         { return itr.input.Close() }
+
+func (itr *unsignedRenameIterator) Next() (*UnsignedPoint, error) {
+	p, err := itr.input.Next()
+	if p == nil || err != nil {
+		return nil, err
+	}
+	p.Name = itr.name
+	return p, nil
+}
+
 // unsignedInterruptIterator represents a unsigned implementation of InterruptIterator.
 type unsignedInterruptIterator struct {
 	input   UnsignedIterator
@@ -7657,17 +9172,17 @@ func (itr *unsignedChanIterator) Next() (*UnsignedPoint, error) {
 	itr.cond.L.Lock()
 	defer itr.cond.L.Unlock()
 
+	// Wait until either a value is available in the buffer, the iterator
+	// is closed or an error is set.
+	for !itr.done && !itr.buf.filled && itr.err == nil {
+		itr.cond.Wait()
+	}
+
 	// Check for an error and return one if there.
 	if itr.err != nil {
 		return nil, itr.err
 	}
 
-	// Wait until either a value is available in the buffer or
-	// the iterator is closed.
-	for !itr.done && !itr.buf.filled {
-		itr.cond.Wait()
-	}
-
 	// Return nil once the channel is done and the buffer is empty.
 	if itr.done && !itr.buf.filled {
 		return nil, nil
@@ -7686,6 +9201,7 @@ func (itr *unsignedChanIterator) Next() (*UnsignedPoint, error) {
 // unsignedReduceFloatIterator executes a reducer for every interval and buffers the result.
 type unsignedReduceFloatIterator struct {
 	input    *bufUnsignedIterator
+	windows  *unsignedWindows
 	create   func() (UnsignedPointAggregator, FloatPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -7694,12 +9210,16 @@ type unsignedReduceFloatIterator struct {
 }
 
 func newUnsignedReduceFloatIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, FloatPointEmitter)) *unsignedReduceFloatIterator {
-	return &unsignedReduceFloatIterator{
+	itr := &unsignedReduceFloatIterator{
 		input:  newBufUnsignedIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newUnsignedWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -7736,6 +9256,10 @@ type unsignedReduceFloatPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *unsignedReduceFloatIterator) reduce() ([]FloatPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -7803,7 +9327,42 @@ func (itr *unsignedReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		}
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *unsignedReduceFloatIterator) reduceWindow() ([]FloatPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*unsignedReduceFloatPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &unsignedReduceFloatPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateUnsigned(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *unsignedReduceFloatIterator) emit(m map[string]*unsignedReduceFloatPoint, startTime int64) []FloatPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -7826,7 +9385,9 @@ func (itr *unsignedReduceFloatIterator) reduce() ([]FloatPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -7840,7 +9401,7 @@ func (itr *unsignedReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		sort.Stable(sort.Reverse(floatPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // unsignedStreamFloatIterator streams inputs into the iterator and emits points gradually.
@@ -7850,6 +9411,7 @@ type unsignedStreamFloatIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*unsignedReduceFloatPoint
+	id     string
 	points []FloatPoint
 }
 
@@ -7930,6 +9492,32 @@ func (itr *unsignedStreamFloatIterator) reduce() ([]FloatPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -8105,6 +9693,7 @@ type unsignedFloatExprFunc func(a, b uint64) float64
 // unsignedReduceIntegerIterator executes a reducer for every interval and buffers the result.
 type unsignedReduceIntegerIterator struct {
 	input    *bufUnsignedIterator
+	windows  *unsignedWindows
 	create   func() (UnsignedPointAggregator, IntegerPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -8113,12 +9702,16 @@ type unsignedReduceIntegerIterator struct {
 }
 
 func newUnsignedReduceIntegerIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, IntegerPointEmitter)) *unsignedReduceIntegerIterator {
-	return &unsignedReduceIntegerIterator{
+	itr := &unsignedReduceIntegerIterator{
 		input:  newBufUnsignedIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newUnsignedWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -8155,6 +9748,10 @@ type unsignedReduceIntegerPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *unsignedReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -8222,7 +9819,42 @@ func (itr *unsignedReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		}
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *unsignedReduceIntegerIterator) reduceWindow() ([]IntegerPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*unsignedReduceIntegerPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &unsignedReduceIntegerPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateUnsigned(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *unsignedReduceIntegerIterator) emit(m map[string]*unsignedReduceIntegerPoint, startTime int64) []IntegerPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -8245,7 +9877,9 @@ func (itr *unsignedReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -8259,7 +9893,7 @@ func (itr *unsignedReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		sort.Stable(sort.Reverse(integerPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // unsignedStreamIntegerIterator streams inputs into the iterator and emits points gradually.
@@ -8269,6 +9903,7 @@ type unsignedStreamIntegerIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*unsignedReduceIntegerPoint
+	id     string
 	points []IntegerPoint
 }
 
@@ -8349,6 +9984,32 @@ func (itr *unsignedStreamIntegerIterator) reduce() ([]IntegerPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -8524,6 +10185,7 @@ type unsignedIntegerExprFunc func(a, b uint64) int64
 // unsignedReduceUnsignedIterator executes a reducer for every interval and buffers the result.
 type unsignedReduceUnsignedIterator struct {
 	input    *bufUnsignedIterator
+	windows  *unsignedWindows
 	create   func() (UnsignedPointAggregator, UnsignedPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -8532,12 +10194,16 @@ type unsignedReduceUnsignedIterator struct {
 }
 
 func newUnsignedReduceUnsignedIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, UnsignedPointEmitter)) *unsignedReduceUnsignedIterator {
-	return &unsignedReduceUnsignedIterator{
+	itr := &unsignedReduceUnsignedIterator{
 		input:  newBufUnsignedIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newUnsignedWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -8574,6 +10240,10 @@ type unsignedReduceUnsignedPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *unsignedReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -8641,7 +10311,42 @@ func (itr *unsignedReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		}
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *unsignedReduceUnsignedIterator) reduceWindow() ([]UnsignedPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*unsignedReduceUnsignedPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &unsignedReduceUnsignedPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateUnsigned(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *unsignedReduceUnsignedIterator) emit(m map[string]*unsignedReduceUnsignedPoint, startTime int64) []UnsignedPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -8664,7 +10369,9 @@ func (itr *unsignedReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -8678,7 +10385,7 @@ func (itr *unsignedReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		sort.Stable(sort.Reverse(unsignedPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // unsignedStreamUnsignedIterator streams inputs into the iterator and emits points gradually.
@@ -8688,6 +10395,7 @@ type unsignedStreamUnsignedIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*unsignedReduceUnsignedPoint
+	id     string
 	points []UnsignedPoint
 }
 
@@ -8768,6 +10476,32 @@ func (itr *unsignedStreamUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -8939,6 +10673,7 @@ type unsignedExprFunc func(a, b uint64) uint64
 // unsignedReduceStringIterator executes a reducer for every interval and buffers the result.
 type unsignedReduceStringIterator struct {
 	input    *bufUnsignedIterator
+	windows  *unsignedWindows
 	create   func() (UnsignedPointAggregator, StringPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -8947,12 +10682,16 @@ type unsignedReduceStringIterator struct {
 }
 
 func newUnsignedReduceStringIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, StringPointEmitter)) *unsignedReduceStringIterator {
-	return &unsignedReduceStringIterator{
+	itr := &unsignedReduceStringIterator{
 		input:  newBufUnsignedIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newUnsignedWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -8989,6 +10728,10 @@ type unsignedReduceStringPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *unsignedReduceStringIterator) reduce() ([]StringPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -9056,7 +10799,42 @@ func (itr *unsignedReduceStringIterator) reduce() ([]StringPoint, error) {
 		}
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *unsignedReduceStringIterator) reduceWindow() ([]StringPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*unsignedReduceStringPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &unsignedReduceStringPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateUnsigned(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *unsignedReduceStringIterator) emit(m map[string]*unsignedReduceStringPoint, startTime int64) []StringPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -9079,7 +10857,9 @@ func (itr *unsignedReduceStringIterator) reduce() ([]StringPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -9093,7 +10873,7 @@ func (itr *unsignedReduceStringIterator) reduce() ([]StringPoint, error) {
 		sort.Stable(sort.Reverse(stringPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // unsignedStreamStringIterator streams inputs into the iterator and emits points gradually.
@@ -9103,6 +10883,7 @@ type unsignedStreamStringIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*unsignedReduceStringPoint
+	id     string
 	points []StringPoint
 }
 
@@ -9183,6 +10964,32 @@ func (itr *unsignedStreamStringIterator) reduce() ([]StringPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -9358,6 +11165,7 @@ type unsignedStringExprFunc func(a, b uint64) string
 // unsignedReduceBooleanIterator executes a reducer for every interval and buffers the result.
 type unsignedReduceBooleanIterator struct {
 	input    *bufUnsignedIterator
+	windows  *unsignedWindows
 	create   func() (UnsignedPointAggregator, BooleanPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -9366,12 +11174,16 @@ type unsignedReduceBooleanIterator struct {
 }
 
 func newUnsignedReduceBooleanIterator(input UnsignedIterator, opt IteratorOptions, createFn func() (UnsignedPointAggregator, BooleanPointEmitter)) *unsignedReduceBooleanIterator {
-	return &unsignedReduceBooleanIterator{
+	itr := &unsignedReduceBooleanIterator{
 		input:  newBufUnsignedIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newUnsignedWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -9408,6 +11220,10 @@ type unsignedReduceBooleanPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *unsignedReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -9475,7 +11291,42 @@ func (itr *unsignedReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		}
 		rp.Aggregator.AggregateUnsigned(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *unsignedReduceBooleanIterator) reduceWindow() ([]BooleanPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*unsignedReduceBooleanPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &unsignedReduceBooleanPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateUnsigned(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *unsignedReduceBooleanIterator) emit(m map[string]*unsignedReduceBooleanPoint, startTime int64) []BooleanPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -9498,7 +11349,9 @@ func (itr *unsignedReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -9512,7 +11365,7 @@ func (itr *unsignedReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		sort.Stable(sort.Reverse(booleanPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // unsignedStreamBooleanIterator streams inputs into the iterator and emits points gradually.
@@ -9522,6 +11375,7 @@ type unsignedStreamBooleanIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*unsignedReduceBooleanPoint
+	id     string
 	points []BooleanPoint
 }
 
@@ -9602,6 +11456,32 @@ func (itr *unsignedStreamBooleanIterator) reduce() ([]BooleanPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -9964,6 +11844,52 @@ func (itr *unsignedFilterIterator) Next() (*UnsignedPoint, error) {
 	}
 }
 
+// unsignedCaseIterator evaluates a CASE expression for each point of the
+// input. The auxiliary fields of the input are the values of refs.
+type unsignedCaseIterator struct {
+	input FloatIterator
+	expr  *CaseExpr
+	refs  []VarRef
+	m     map[string]interface{}
+}
+
+func newUnsignedCaseIterator(input FloatIterator, expr *CaseExpr, refs []VarRef) *unsignedCaseIterator {
+	return &unsignedCaseIterator{
+		input: input,
+		expr:  expr,
+		refs:  refs,
+		m:     make(map[string]interface{}, len(refs)),
+	}
+}
+
+func (itr *unsignedCaseIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *unsignedCaseIterator) Close() error         { return itr.input.Close() }
+
+func (itr *unsignedCaseIterator) Next() (*UnsignedPoint, error) {
+	p, err := itr.input.Next()
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	for i, ref := range itr.refs {
+		itr.m[ref.Val] = p.Aux[i]
+	}
+
+	out := &UnsignedPoint{
+		Name:       p.Name,
+		Tags:       p.Tags,
+		Time:       p.Time,
+		Aggregated: p.Aggregated,
+	}
+	switch v := Eval(itr.expr, itr.m).(type) {
+	case uint64:
+		out.Value = v
+	default:
+		out.Nil = true
+	}
+	return out, nil
+}
+
 // newUnsignedDedupeIterator returns a new instance of unsignedDedupeIterator.
 func newUnsignedDedupeIterator(input UnsignedIterator) *unsignedDedupeIterator {
 	return &unsignedDedupeIterator{
@@ -10132,6 +12058,160 @@ func (itr *bufStringIterator) NextInWindow(startTime, endTime int64) (*StringPoi
 // unread sets v to the buffer. It is read on the next call to Next().
 func (itr *bufStringIterator) unread(v *StringPoint) { itr.buf = v }
 
+// stringWindows reads the points of overlapping windows from an input
+// iterator. The input is read one interval at a time and each interval is
+// buffered until every window that contains it has been read.
+type stringWindows struct {
+	input *bufStringIterator
+	opt   IteratorOptions
+
+	// The series being read and its buffered intervals in the order they
+	// were read.
+	name, tags string
+	active     bool
+	done       bool
+	intervals  []stringWindowInterval
+	last       int64
+
+	// The start of the next window and of the earliest window in the
+	// time range of the query.
+	next, min int64
+}
+
+// stringWindowInterval holds the points of a series within an interval.
+type stringWindowInterval struct {
+	start  int64
+	points []StringPoint
+}
+
+// newStringWindows returns a new instance of stringWindows.
+func newStringWindows(input *bufStringIterator, opt IteratorOptions) *stringWindows {
+	min, _ := opt.Window(opt.StartTime)
+	return &stringWindows{input: input, opt: opt, min: min}
+}
+
+// Next returns the start time and the points of the next window that contains
+// any points. Returns nil points when there are no more windows.
+func (w *stringWindows) Next() (int64, []StringPoint, error) {
+	step, length := int64(w.opt.Interval.Duration), int64(w.opt.Interval.Length)
+	for {
+		// Read the first interval of the next series.
+		if !w.active {
+			iv, err := w.read()
+			if err != nil || iv == nil {
+				return 0, nil, err
+			}
+			w.intervals = append(w.intervals[:0], *iv)
+			w.last, w.done = iv.start, false
+			if w.opt.Ascending {
+				w.next = w.first(iv.start)
+			} else {
+				w.next = iv.start
+			}
+			continue
+		}
+
+		// Read intervals until every interval of the next window has been read.
+		if !w.done && ((w.opt.Ascending && w.last-w.next < length) || (!w.opt.Ascending && w.last >= w.next)) {
+			iv, err := w.read()
+			if err != nil {
+				return 0, nil, err
+			} else if iv == nil {
+				w.done = true
+				continue
+			}
+
+			// Skip the windows without any points if the series has a gap.
+			if len(w.intervals) == 0 {
+				if w.opt.Ascending {
+					if first := w.first(iv.start); first > w.next {
+						w.next = first
+					}
+				} else if iv.start < w.next {
+					w.next = iv.start
+				}
+			}
+			w.intervals = append(w.intervals, *iv)
+			w.last = iv.start
+			continue
+		}
+
+		// Move on to the next series once every window has been read.
+		if len(w.intervals) == 0 {
+			w.active = false
+			continue
+		}
+
+		start := w.next
+		var points []StringPoint
+		for _, iv := range w.intervals {
+			if iv.start >= start && iv.start-start < length {
+				points = append(points, iv.points...)
+			}
+		}
+
+		// Drop the intervals that are not a part of any of the later windows.
+		i := 0
+		if w.opt.Ascending {
+			w.next += step
+			for i < len(w.intervals) && w.intervals[i].start < w.next {
+				i++
+			}
+		} else {
+			w.next -= step
+			for i < len(w.intervals) && w.intervals[i].start-w.next >= length {
+				i++
+			}
+		}
+		w.intervals = w.intervals[i:]
+
+		if len(points) > 0 && start >= w.min {
+			return start, points, nil
+		}
+	}
+}
+
+// first returns the start of the earliest window that contains the interval.
+func (w *stringWindows) first(start int64) int64 {
+	if d := int64(w.opt.Interval.Length - w.opt.Interval.Duration); uint64(start-w.min) > uint64(d) {
+		return start - d
+	}
+	return w.min
+}
+
+// read reads the points of the next interval of the current series. Returns
+// nil if there are no more points in the series.
+func (w *stringWindows) read() (*stringWindowInterval, error) {
+	var iv *stringWindowInterval
+	for {
+		p, err := w.input.Next()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			return iv, nil
+		} else if p.Nil {
+			continue
+		}
+
+		tags := p.Tags.Subset(w.opt.Dimensions).ID()
+		if !w.active {
+			w.name, w.tags, w.active = p.Name, tags, true
+		} else if p.Name != w.name || tags != w.tags {
+			w.input.unread(p)
+			return iv, nil
+		}
+
+		start, _ := w.opt.Window(p.Time)
+		if iv == nil {
+			iv = &stringWindowInterval{start: start}
+		} else if start != iv.start {
+			w.input.unread(p)
+			return iv, nil
+		}
+		iv.points = append(iv.points, *p.Clone())
+	}
+}
+
 // stringMergeIterator represents an iterator that combines multiple string iterators.
 type stringMergeIterator struct {
 	inputs []StringIterator
@@ -10592,6 +12672,8 @@ type stringFillIterator struct {
 	auxFields []interface{}
 	init      bool
 	opt       IteratorOptions
+	lookback  map[fillSeriesKey]lookbackPoint
+	seeds     []fillSeriesKey
 
 	window struct {
 		name   string
@@ -10601,9 +12683,9 @@ type stringFillIterator struct {
 	}
 }
 
-func newStringFillIterator(input StringIterator, expr Expr, opt IteratorOptions) *stringFillIterator {
+func newStringFillIterator(input StringIterator, expr Expr, opt IteratorOptions, lookback map[fillSeriesKey]lookbackPoint) *stringFillIterator {
 	if opt.Fill == NullFill {
-		if expr, ok := expr.(*Call); ok && expr.Name == "count" {
+		if expr, ok := expr.(*Call); ok && (expr.Name == "count" || expr.Name == "count_distinct_approx" || expr.Name == "histogram") {
 			opt.Fill = NumberFill
 			opt.FillValue = ""
 		}
@@ -10630,6 +12712,8 @@ func newStringFillIterator(input StringIterator, expr Expr, opt IteratorOptions)
 		endTime:   endTime,
 		auxFields: auxFields,
 		opt:       opt,
+		lookback:  lookback,
+		seeds:     lookbackSeries(lookback, opt.Ascending),
 	}
 }
 
@@ -10639,13 +12723,10 @@ func (itr *stringFillIterator) Close() error         { return itr.input.Close()
 func (itr *stringFillIterator) Next() (*StringPoint, error) {
 	if !itr.init {
 		p, err := itr.input.peek()
-		if p == nil || err != nil {
+		if err != nil {
 			return nil, err
-		}
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		} else if !itr.nextSeries(p) {
+			return nil, nil
 		}
 		itr.init = true
 	}
@@ -10673,19 +12754,18 @@ func (itr *stringFillIterator) Next() (*StringPoint, error) {
 			}
 		}
 
-		// We are *not* in a current interval. If there is no next point,
-		// we are at the end of all intervals.
-		if p == nil {
+		// We are *not* in a current interval. If there is no next point and
+		// no series with only a lookback point, we are at the end of all
+		// intervals.
+		if !itr.nextSeries(p) {
 			return nil, nil
 		}
 
-		// Set the new interval.
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		// Fill the series with only a lookback point before the point.
+		if p != nil && (p.Name != itr.window.name || p.Tags.ID() != itr.window.tags.ID()) {
+			itr.input.unread(p)
+			p = nil
 		}
-		itr.prev = StringPoint{Nil: true}
 		break
 	}
 
@@ -10724,7 +12804,14 @@ func (itr *stringFillIterator) Next() (*StringPoint, error) {
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows vary in length so look up the adjacent window.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -10732,7 +12819,7 @@ func (itr *stringFillIterator) Next() (*StringPoint, error) {
 
 	// Check to see if we have passed over an offset change and adjust the time
 	// to account for this new offset.
-	if itr.opt.Location != nil {
+	if itr.opt.Location != nil && itr.opt.Interval.Months == 0 {
 		if _, offset := itr.opt.Zone(itr.window.time - 1); offset != itr.window.offset {
 			diff := itr.window.offset - offset
 			if abs(diff) < int64(itr.opt.Interval.Duration) {
@@ -10744,6 +12831,50 @@ func (itr *stringFillIterator) Next() (*StringPoint, error) {
 	return p, nil
 }
 
+// nextSeries starts the windows of the next series. The series of the point
+// is next unless a series with only a lookback point comes before it. It
+// returns false if there are no more series.
+func (itr *stringFillIterator) nextSeries(p *StringPoint) bool {
+	var key fillSeriesKey
+	if p != nil {
+		key = fillSeriesKey{name: p.Name, tags: p.Tags.ID()}
+	}
+
+	if len(itr.seeds) > 0 && (p == nil || itr.seeds[0].before(key, itr.opt.Ascending)) {
+		itr.window.name, itr.window.tags = itr.seeds[0].name, itr.lookback[itr.seeds[0]].tags
+		itr.seeds = itr.seeds[1:]
+	} else if p != nil {
+		if len(itr.seeds) > 0 && itr.seeds[0] == key {
+			itr.seeds = itr.seeds[1:]
+		}
+		itr.window.name, itr.window.tags = p.Name, p.Tags
+	} else {
+		return false
+	}
+
+	itr.window.time = itr.startTime
+	if itr.opt.Location != nil {
+		_, itr.window.offset = itr.opt.Zone(itr.window.time)
+	}
+	itr.prev = itr.previous()
+	return true
+}
+
+// previous returns the point that precedes the current series from the
+// lookback points. A nil point is returned if there is none.
+func (itr *stringFillIterator) previous() StringPoint {
+	p, ok := itr.lookback[fillSeriesKey{name: itr.window.name, tags: itr.window.tags.ID()}]
+	if !ok {
+		return StringPoint{Nil: true}
+	}
+	return StringPoint{
+		Name:  itr.window.name,
+		Tags:  itr.window.tags,
+		Time:  p.time,
+		Value: castToString(p.value),
+	}
+}
+
 // stringIntervalIterator represents a string implementation of IntervalIterator.
 type stringIntervalIterator struct {
 	input StringIterator
@@ -10771,6 +12902,28 @@ func (itr *stringIntervalIterator) Next() (*StringPoint, error) {
 	return p, nil
 }
 
+// stringRenameIterator represents a string implementation of RenameIterator.
+type stringRenameIterator struct {
+	input StringIterator
+	name  string
+}
+
+func newStringRenameIterator(input StringIterator, name string) *stringRenameIterator {
+	return &stringRenameIterator{input: input, name: name}
+}
+
+func (itr *stringRenameIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *stringRenameIterator) Close() error         { return itr.input.Close() }
+
+func (itr *stringRenameIterator) Next() (*StringPoint, error) {
+	p, err := itr.input.Next()
+	if p == nil || err != nil {
+		return nil, err
+	}
+	p.Name = itr.name
+	return p, nil
+}
+
 // stringInterruptIterator represents a string implementation of InterruptIterator.
 type stringInterruptIterator struct {
 	input   StringIterator
@@ -10987,17 +13140,17 @@ func (itr *stringChanIterator) Next() (*StringPoint, error) {
 	itr.cond.L.Lock()
 	defer itr.cond.L.Unlock()
 
+	// Wait until either a value is available in the buffer, the iterator
+	// is closed or an error is set.
+	for !itr.done && !itr.buf.filled && itr.err == nil {
+		itr.cond.Wait()
+	}
+
 	// Check for an error and return one if there.
 	if itr.err != nil {
 		return nil, itr.err
 	}
 
-	// Wait until either a value is available in the buffer or
-	// the iterator is closed.
-	for !itr.done && !itr.buf.filled {
-		itr.cond.Wait()
-	}
-
 	// Return nil once the channel is done and the buffer is empty.
 	if itr.done && !itr.buf.filled {
 		return nil, nil
@@ -11016,6 +13169,7 @@ func (itr *stringChanIterator) Next() (*StringPoint, error) {
 // stringReduceFloatIterator executes a reducer for every interval and buffers the result.
 type stringReduceFloatIterator struct {
 	input    *bufStringIterator
+	windows  *stringWindows
 	create   func() (StringPointAggregator, FloatPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -11024,12 +13178,16 @@ type stringReduceFloatIterator struct {
 }
 
 func newStringReduceFloatIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, FloatPointEmitter)) *stringReduceFloatIterator {
-	return &stringReduceFloatIterator{
+	itr := &stringReduceFloatIterator{
 		input:  newBufStringIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newStringWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -11066,6 +13224,10 @@ type stringReduceFloatPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *stringReduceFloatIterator) reduce() ([]FloatPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -11133,7 +13295,42 @@ func (itr *stringReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		}
 		rp.Aggregator.AggregateString(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *stringReduceFloatIterator) reduceWindow() ([]FloatPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*stringReduceFloatPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &stringReduceFloatPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateString(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *stringReduceFloatIterator) emit(m map[string]*stringReduceFloatPoint, startTime int64) []FloatPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -11156,7 +13353,9 @@ func (itr *stringReduceFloatIterator) reduce() ([]FloatPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -11170,7 +13369,7 @@ func (itr *stringReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		sort.Stable(sort.Reverse(floatPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // stringStreamFloatIterator streams inputs into the iterator and emits points gradually.
@@ -11180,6 +13379,7 @@ type stringStreamFloatIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*stringReduceFloatPoint
+	id     string
 	points []FloatPoint
 }
 
@@ -11260,6 +13460,32 @@ func (itr *stringStreamFloatIterator) reduce() ([]FloatPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -11435,6 +13661,7 @@ type stringFloatExprFunc func(a, b string) float64
 // stringReduceIntegerIterator executes a reducer for every interval and buffers the result.
 type stringReduceIntegerIterator struct {
 	input    *bufStringIterator
+	windows  *stringWindows
 	create   func() (StringPointAggregator, IntegerPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -11443,12 +13670,16 @@ type stringReduceIntegerIterator struct {
 }
 
 func newStringReduceIntegerIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, IntegerPointEmitter)) *stringReduceIntegerIterator {
-	return &stringReduceIntegerIterator{
+	itr := &stringReduceIntegerIterator{
 		input:  newBufStringIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newStringWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -11485,6 +13716,10 @@ type stringReduceIntegerPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *stringReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -11533,12 +13768,43 @@ func (itr *stringReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 			break
 		}
 
-		// Retrieve the tags on this point for this level of the query.
-		// This may be different than the bucket dimensions.
-		tags := curr.Tags.Subset(itr.dims)
-		id := tags.ID()
+		// Retrieve the tags on this point for this level of the query.
+		// This may be different than the bucket dimensions.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &stringReduceIntegerPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateString(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *stringReduceIntegerIterator) reduceWindow() ([]IntegerPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*stringReduceIntegerPoint)
+	for i := range points {
+		curr := &points[i]
 
 		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
 		rp := m[id]
 		if rp == nil {
 			aggregator, emitter := itr.create()
@@ -11552,7 +13818,11 @@ func (itr *stringReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		}
 		rp.Aggregator.AggregateString(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *stringReduceIntegerIterator) emit(m map[string]*stringReduceIntegerPoint, startTime int64) []IntegerPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -11575,7 +13845,9 @@ func (itr *stringReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -11589,7 +13861,7 @@ func (itr *stringReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		sort.Stable(sort.Reverse(integerPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // stringStreamIntegerIterator streams inputs into the iterator and emits points gradually.
@@ -11599,6 +13871,7 @@ type stringStreamIntegerIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*stringReduceIntegerPoint
+	id     string
 	points []IntegerPoint
 }
 
@@ -11679,6 +13952,32 @@ func (itr *stringStreamIntegerIterator) reduce() ([]IntegerPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -11854,6 +14153,7 @@ type stringIntegerExprFunc func(a, b string) int64
 // stringReduceUnsignedIterator executes a reducer for every interval and buffers the result.
 type stringReduceUnsignedIterator struct {
 	input    *bufStringIterator
+	windows  *stringWindows
 	create   func() (StringPointAggregator, UnsignedPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -11862,12 +14162,16 @@ type stringReduceUnsignedIterator struct {
 }
 
 func newStringReduceUnsignedIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, UnsignedPointEmitter)) *stringReduceUnsignedIterator {
-	return &stringReduceUnsignedIterator{
+	itr := &stringReduceUnsignedIterator{
 		input:  newBufStringIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newStringWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -11904,6 +14208,10 @@ type stringReduceUnsignedPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *stringReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -11971,7 +14279,42 @@ func (itr *stringReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		}
 		rp.Aggregator.AggregateString(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *stringReduceUnsignedIterator) reduceWindow() ([]UnsignedPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*stringReduceUnsignedPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &stringReduceUnsignedPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateString(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *stringReduceUnsignedIterator) emit(m map[string]*stringReduceUnsignedPoint, startTime int64) []UnsignedPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -11994,7 +14337,9 @@ func (itr *stringReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -12008,7 +14353,7 @@ func (itr *stringReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		sort.Stable(sort.Reverse(unsignedPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // stringStreamUnsignedIterator streams inputs into the iterator and emits points gradually.
@@ -12018,6 +14363,7 @@ type stringStreamUnsignedIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*stringReduceUnsignedPoint
+	id     string
 	points []UnsignedPoint
 }
 
@@ -12098,6 +14444,32 @@ func (itr *stringStreamUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -12273,6 +14645,7 @@ type stringUnsignedExprFunc func(a, b string) uint64
 // stringReduceStringIterator executes a reducer for every interval and buffers the result.
 type stringReduceStringIterator struct {
 	input    *bufStringIterator
+	windows  *stringWindows
 	create   func() (StringPointAggregator, StringPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -12281,12 +14654,16 @@ type stringReduceStringIterator struct {
 }
 
 func newStringReduceStringIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, StringPointEmitter)) *stringReduceStringIterator {
-	return &stringReduceStringIterator{
+	itr := &stringReduceStringIterator{
 		input:  newBufStringIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newStringWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -12323,6 +14700,10 @@ type stringReduceStringPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *stringReduceStringIterator) reduce() ([]StringPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -12390,7 +14771,42 @@ func (itr *stringReduceStringIterator) reduce() ([]StringPoint, error) {
 		}
 		rp.Aggregator.AggregateString(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *stringReduceStringIterator) reduceWindow() ([]StringPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*stringReduceStringPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &stringReduceStringPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateString(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *stringReduceStringIterator) emit(m map[string]*stringReduceStringPoint, startTime int64) []StringPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -12413,7 +14829,9 @@ func (itr *stringReduceStringIterator) reduce() ([]StringPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -12427,7 +14845,7 @@ func (itr *stringReduceStringIterator) reduce() ([]StringPoint, error) {
 		sort.Stable(sort.Reverse(stringPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // stringStreamStringIterator streams inputs into the iterator and emits points gradually.
@@ -12437,6 +14855,7 @@ type stringStreamStringIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*stringReduceStringPoint
+	id     string
 	points []StringPoint
 }
 
@@ -12517,6 +14936,32 @@ func (itr *stringStreamStringIterator) reduce() ([]StringPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -12688,6 +15133,7 @@ type stringExprFunc func(a, b string) string
 // stringReduceBooleanIterator executes a reducer for every interval and buffers the result.
 type stringReduceBooleanIterator struct {
 	input    *bufStringIterator
+	windows  *stringWindows
 	create   func() (StringPointAggregator, BooleanPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -12696,12 +15142,16 @@ type stringReduceBooleanIterator struct {
 }
 
 func newStringReduceBooleanIterator(input StringIterator, opt IteratorOptions, createFn func() (StringPointAggregator, BooleanPointEmitter)) *stringReduceBooleanIterator {
-	return &stringReduceBooleanIterator{
+	itr := &stringReduceBooleanIterator{
 		input:  newBufStringIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newStringWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -12738,6 +15188,10 @@ type stringReduceBooleanPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *stringReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -12805,7 +15259,42 @@ func (itr *stringReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		}
 		rp.Aggregator.AggregateString(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *stringReduceBooleanIterator) reduceWindow() ([]BooleanPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*stringReduceBooleanPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &stringReduceBooleanPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateString(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *stringReduceBooleanIterator) emit(m map[string]*stringReduceBooleanPoint, startTime int64) []BooleanPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -12828,7 +15317,9 @@ func (itr *stringReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -12842,7 +15333,7 @@ func (itr *stringReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		sort.Stable(sort.Reverse(booleanPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // stringStreamBooleanIterator streams inputs into the iterator and emits points gradually.
@@ -12852,6 +15343,7 @@ type stringStreamBooleanIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*stringReduceBooleanPoint
+	id     string
 	points []BooleanPoint
 }
 
@@ -12932,6 +15424,32 @@ func (itr *stringStreamBooleanIterator) reduce() ([]BooleanPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -13294,6 +15812,52 @@ func (itr *stringFilterIterator) Next() (*StringPoint, error) {
 	}
 }
 
+// stringCaseIterator evaluates a CASE expression for each point of the
+// input. The auxiliary fields of the input are the values of refs.
+type stringCaseIterator struct {
+	input FloatIterator
+	expr  *CaseExpr
+	refs  []VarRef
+	m     map[string]interface{}
+}
+
+func newStringCaseIterator(input FloatIterator, expr *CaseExpr, refs []VarRef) *stringCaseIterator {
+	return &stringCaseIterator{
+		input: input,
+		expr:  expr,
+		refs:  refs,
+		m:     make(map[string]interface{}, len(refs)),
+	}
+}
+
+func (itr *stringCaseIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *stringCaseIterator) Close() error         { return itr.input.Close() }
+
+func (itr *stringCaseIterator) Next() (*StringPoint, error) {
+	p, err := itr.input.Next()
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	for i, ref := range itr.refs {
+		itr.m[ref.Val] = p.Aux[i]
+	}
+
+	out := &StringPoint{
+		Name:       p.Name,
+		Tags:       p.Tags,
+		Time:       p.Time,
+		Aggregated: p.Aggregated,
+	}
+	switch v := Eval(itr.expr, itr.m).(type) {
+	case string:
+		out.Value = v
+	default:
+		out.Nil = true
+	}
+	return out, nil
+}
+
 // newStringDedupeIterator returns a new instance of stringDedupeIterator.
 func newStringDedupeIterator(input StringIterator) *stringDedupeIterator {
 	return &stringDedupeIterator{
@@ -13462,6 +16026,160 @@ func (itr *bufBooleanIterator) NextInWindow(startTime, endTime int64) (*BooleanP
 // unread sets v to the buffer. It is read on the next call to Next().
 func (itr *bufBooleanIterator) unread(v *BooleanPoint) { itr.buf = v }
 
+// booleanWindows reads the points of overlapping windows from an input
+// iterator. The input is read one interval at a time and each interval is
+// buffered until every window that contains it has been read.
+type booleanWindows struct {
+	input *bufBooleanIterator
+	opt   IteratorOptions
+
+	// The series being read and its buffered intervals in the order they
+	// were read.
+	name, tags string
+	active     bool
+	done       bool
+	intervals  []booleanWindowInterval
+	last       int64
+
+	// The start of the next window and of the earliest window in the
+	// time range of the query.
+	next, min int64
+}
+
+// booleanWindowInterval holds the points of a series within an interval.
+type booleanWindowInterval struct {
+	start  int64
+	points []BooleanPoint
+}
+
+// newBooleanWindows returns a new instance of booleanWindows.
+func newBooleanWindows(input *bufBooleanIterator, opt IteratorOptions) *booleanWindows {
+	min, _ := opt.Window(opt.StartTime)
+	return &booleanWindows{input: input, opt: opt, min: min}
+}
+
+// Next returns the start time and the points of the next window that contains
+// any points. Returns nil points when there are no more windows.
+func (w *booleanWindows) Next() (int64, []BooleanPoint, error) {
+	step, length := int64(w.opt.Interval.Duration), int64(w.opt.Interval.Length)
+	for {
+		// Read the first interval of the next series.
+		if !w.active {
+			iv, err := w.read()
+			if err != nil || iv == nil {
+				return 0, nil, err
+			}
+			w.intervals = append(w.intervals[:0], *iv)
+			w.last, w.done = iv.start, false
+			if w.opt.Ascending {
+				w.next = w.first(iv.start)
+			} else {
+				w.next = iv.start
+			}
+			continue
+		}
+
+		// Read intervals until every interval of the next window has been read.
+		if !w.done && ((w.opt.Ascending && w.last-w.next < length) || (!w.opt.Ascending && w.last >= w.next)) {
+			iv, err := w.read()
+			if err != nil {
+				return 0, nil, err
+			} else if iv == nil {
+				w.done = true
+				continue
+			}
+
+			// Skip the windows without any points if the series has a gap.
+			if len(w.intervals) == 0 {
+				if w.opt.Ascending {
+					if first := w.first(iv.start); first > w.next {
+						w.next = first
+					}
+				} else if iv.start < w.next {
+					w.next = iv.start
+				}
+			}
+			w.intervals = append(w.intervals, *iv)
+			w.last = iv.start
+			continue
+		}
+
+		// Move on to the next series once every window has been read.
+		if len(w.intervals) == 0 {
+			w.active = false
+			continue
+		}
+
+		start := w.next
+		var points []BooleanPoint
+		for _, iv := range w.intervals {
+			if iv.start >= start && iv.start-start < length {
+				points = append(points, iv.points...)
+			}
+		}
+
+		// Drop the intervals that are not a part of any of the later windows.
+		i := 0
+		if w.opt.Ascending {
+			w.next += step
+			for i < len(w.intervals) && w.intervals[i].start < w.next {
+				i++
+			}
+		} else {
+			w.next -= step
+			for i < len(w.intervals) && w.intervals[i].start-w.next >= length {
+				i++
+			}
+		}
+		w.intervals = w.intervals[i:]
+
+		if len(points) > 0 && start >= w.min {
+			return start, points, nil
+		}
+	}
+}
+
+// first returns the start of the earliest window that contains the interval.
+func (w *booleanWindows) first(start int64) int64 {
+	if d := int64(w.opt.Interval.Length - w.opt.Interval.Duration); uint64(start-w.min) > uint64(d) {
+		return start - d
+	}
+	return w.min
+}
+
+// read reads the points of the next interval of the current series. Returns
+// nil if there are no more points in the series.
+func (w *booleanWindows) read() (*booleanWindowInterval, error) {
+	var iv *booleanWindowInterval
+	for {
+		p, err := w.input.Next()
+		if err != nil {
+			return nil, err
+		} else if p == nil {
+			return iv, nil
+		} else if p.Nil {
+			continue
+		}
+
+		tags := p.Tags.Subset(w.opt.Dimensions).ID()
+		if !w.active {
+			w.name, w.tags, w.active = p.Name, tags, true
+		} else if p.Name != w.name || tags != w.tags {
+			w.input.unread(p)
+			return iv, nil
+		}
+
+		start, _ := w.opt.Window(p.Time)
+		if iv == nil {
+			iv = &booleanWindowInterval{start: start}
+		} else if start != iv.start {
+			w.input.unread(p)
+			return iv, nil
+		}
+		iv.points = append(iv.points, *p.Clone())
+	}
+}
+
 // booleanMergeIterator represents an iterator that combines multiple boolean iterators.
 type booleanMergeIterator struct {
 	inputs []BooleanIterator
@@ -13922,6 +16640,8 @@ type booleanFillIterator struct {
 	auxFields []interface{}
 	init      bool
 	opt       IteratorOptions
+	lookback  map[fillSeriesKey]lookbackPoint
+	seeds     []fillSeriesKey
 
 	window struct {
 		name   string
@@ -13931,9 +16651,9 @@ type booleanFillIterator struct {
 	}
 }
 
-func newBooleanFillIterator(input BooleanIterator, expr Expr, opt IteratorOptions) *booleanFillIterator {
+func newBooleanFillIterator(input BooleanIterator, expr Expr, opt IteratorOptions, lookback map[fillSeriesKey]lookbackPoint) *booleanFillIterator {
 	if opt.Fill == NullFill {
-		if expr, ok := expr.(*Call); ok && expr.Name == "count" {
+		if expr, ok := expr.(*Call); ok && (expr.Name == "count" || expr.Name == "count_distinct_approx" || expr.Name == "histogram") {
 			opt.Fill = NumberFill
 			opt.FillValue = false
 		}
@@ -13960,6 +16680,8 @@ func newBooleanFillIterator(input BooleanIterator, expr Expr, opt IteratorOption
 		endTime:   endTime,
 		auxFields: auxFields,
 		opt:       opt,
+		lookback:  lookback,
+		seeds:     lookbackSeries(lookback, opt.Ascending),
 	}
 }
 
@@ -13969,13 +16691,10 @@ func (itr *booleanFillIterator) Close() error         { return itr.input.Close()
 func (itr *booleanFillIterator) Next() (*BooleanPoint, error) {
 	if !itr.init {
 		p, err := itr.input.peek()
-		if p == nil || err != nil {
+		if err != nil {
 			return nil, err
-		}
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		} else if !itr.nextSeries(p) {
+			return nil, nil
 		}
 		itr.init = true
 	}
@@ -14003,19 +16722,18 @@ func (itr *booleanFillIterator) Next() (*BooleanPoint, error) {
 			}
 		}
 
-		// We are *not* in a current interval. If there is no next point,
-		// we are at the end of all intervals.
-		if p == nil {
+		// We are *not* in a current interval. If there is no next point and
+		// no series with only a lookback point, we are at the end of all
+		// intervals.
+		if !itr.nextSeries(p) {
 			return nil, nil
 		}
 
-		// Set the new interval.
-		itr.window.name, itr.window.tags = p.Name, p.Tags
-		itr.window.time = itr.startTime
-		if itr.opt.Location != nil {
-			_, itr.window.offset = itr.opt.Zone(itr.window.time)
+		// Fill the series with only a lookback point before the point.
+		if p != nil && (p.Name != itr.window.name || p.Tags.ID() != itr.window.tags.ID()) {
+			itr.input.unread(p)
+			p = nil
 		}
-		itr.prev = BooleanPoint{Nil: true}
 		break
 	}
 
@@ -14054,7 +16772,14 @@ func (itr *booleanFillIterator) Next() (*BooleanPoint, error) {
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows vary in length so look up the adjacent window.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -14062,7 +16787,7 @@ func (itr *booleanFillIterator) Next() (*BooleanPoint, error) {
 
 	// Check to see if we have passed over an offset change and adjust the time
 	// to account for this new offset.
-	if itr.opt.Location != nil {
+	if itr.opt.Location != nil && itr.opt.Interval.Months == 0 {
 		if _, offset := itr.opt.Zone(itr.window.time - 1); offset != itr.window.offset {
 			diff := itr.window.offset - offset
 			if abs(diff) < int64(itr.opt.Interval.Duration) {
@@ -14071,7 +16796,51 @@ func (itr *booleanFillIterator) Next() (*BooleanPoint, error) {
 			itr.window.offset = offset
 		}
 	}
-	return p, nil
+	return p, nil
+}
+
+// nextSeries starts the windows of the next series. The series of the point
+// is next unless a series with only a lookback point comes before it. It
+// returns false if there are no more series.
+func (itr *booleanFillIterator) nextSeries(p *BooleanPoint) bool {
+	var key fillSeriesKey
+	if p != nil {
+		key = fillSeriesKey{name: p.Name, tags: p.Tags.ID()}
+	}
+
+	if len(itr.seeds) > 0 && (p == nil || itr.seeds[0].before(key, itr.opt.Ascending)) {
+		itr.window.name, itr.window.tags = itr.seeds[0].name, itr.lookback[itr.seeds[0]].tags
+		itr.seeds = itr.seeds[1:]
+	} else if p != nil {
+		if len(itr.seeds) > 0 && itr.seeds[0] == key {
+			itr.seeds = itr.seeds[1:]
+		}
+		itr.window.name, itr.window.tags = p.Name, p.Tags
+	} else {
+		return false
+	}
+
+	itr.window.time = itr.startTime
+	if itr.opt.Location != nil {
+		_, itr.window.offset = itr.opt.Zone(itr.window.time)
+	}
+	itr.prev = itr.previous()
+	return true
+}
+
+// previous returns the point that precedes the current series from the
+// lookback points. A nil point is returned if there is none.
+func (itr *booleanFillIterator) previous() BooleanPoint {
+	p, ok := itr.lookback[fillSeriesKey{name: itr.window.name, tags: itr.window.tags.ID()}]
+	if !ok {
+		return BooleanPoint{Nil: true}
+	}
+	return BooleanPoint{
+		Name:  itr.window.name,
+		Tags:  itr.window.tags,
+		Time:  p.time,
+		Value: castToBoolean(p.value),
+	}
 }
 
 // booleanIntervalIterator represents a boolean implementation of IntervalIterator.
@@ -14101,6 +16870,28 @@ func (itr *booleanIntervalIterator) Next() (*BooleanPoint, error) {
 	return p, nil
 }
 
+// booleanRenameIterator represents a boolean implementation of RenameIterator.
+type booleanRenameIterator struct {
+	input BooleanIterator
+	name  string
+}
+
+func newBooleanRenameIterator(input BooleanIterator, name string) *booleanRenameIterator {
+	return &booleanRenameIterator{input: input, name: name}
+}
+
+func (itr *booleanRenameIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *booleanRenameIterator) Close() error         { return itr.input.Close() }
+
+func (itr *booleanRenameIterator) Next() (*BooleanPoint, error) {
+	p, err := itr.input.Next()
+	if p == nil || err != nil {
+		return nil, err
+	}
+	p.Name = itr.name
+	return p, nil
+}
+
 // booleanInterruptIterator represents a boolean implementation of InterruptIterator.
 type booleanInterruptIterator struct {
 	input   BooleanIterator
@@ -14317,17 +17108,17 @@ func (itr *booleanChanIterator) Next() (*BooleanPoint, error) {
 	itr.cond.L.Lock()
 	defer itr.cond.L.Unlock()
 
+	// Wait until either a value is available in the buffer, the iterator
+	// is closed or an error is set.
+	for !itr.done && !itr.buf.filled && itr.err == nil {
+		itr.cond.Wait()
+	}
+
 	// Check for an error and return one if there.
 	if itr.err != nil {
 		return nil, itr.err
 	}
 
-	// Wait until either a value is available in the buffer or
-	// the iterator is closed.
-	for !itr.done && !itr.buf.filled {
-		itr.cond.Wait()
-	}
-
 	// Return nil once the channel is done and the buffer is empty.
 	if itr.done && !itr.buf.filled {
 		return nil, nil
@@ -14346,6 +17137,7 @@ func (itr *booleanChanIterator) Next() (*BooleanPoint, error) {
 // booleanReduceFloatIterator executes a reducer for every interval and buffers the result.
 type booleanReduceFloatIterator struct {
 	input    *bufBooleanIterator
+	windows  *booleanWindows
 	create   func() (BooleanPointAggregator, FloatPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -14354,12 +17146,16 @@ type booleanReduceFloatIterator struct {
 }
 
 func newBooleanReduceFloatIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, FloatPointEmitter)) *booleanReduceFloatIterator {
-	return &booleanReduceFloatIterator{
+	itr := &booleanReduceFloatIterator{
 		input:  newBufBooleanIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newBooleanWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -14396,6 +17192,10 @@ type booleanReduceFloatPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *booleanReduceFloatIterator) reduce() ([]FloatPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -14463,7 +17263,42 @@ func (itr *booleanReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		}
 		rp.Aggregator.AggregateBoolean(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *booleanReduceFloatIterator) reduceWindow() ([]FloatPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*booleanReduceFloatPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &booleanReduceFloatPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateBoolean(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *booleanReduceFloatIterator) emit(m map[string]*booleanReduceFloatPoint, startTime int64) []FloatPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -14486,7 +17321,9 @@ func (itr *booleanReduceFloatIterator) reduce() ([]FloatPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -14500,7 +17337,7 @@ func (itr *booleanReduceFloatIterator) reduce() ([]FloatPoint, error) {
 		sort.Stable(sort.Reverse(floatPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // booleanStreamFloatIterator streams inputs into the iterator and emits points gradually.
@@ -14510,6 +17347,7 @@ type booleanStreamFloatIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*booleanReduceFloatPoint
+	id     string
 	points []FloatPoint
 }
 
@@ -14590,6 +17428,32 @@ func (itr *booleanStreamFloatIterator) reduce() ([]FloatPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -14765,6 +17629,7 @@ type booleanFloatExprFunc func(a, b bool) float64
 // booleanReduceIntegerIterator executes a reducer for every interval and buffers the result.
 type booleanReduceIntegerIterator struct {
 	input    *bufBooleanIterator
+	windows  *booleanWindows
 	create   func() (BooleanPointAggregator, IntegerPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -14773,12 +17638,16 @@ type booleanReduceIntegerIterator struct {
 }
 
 func newBooleanReduceIntegerIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, IntegerPointEmitter)) *booleanReduceIntegerIterator {
-	return &booleanReduceIntegerIterator{
+	itr := &booleanReduceIntegerIterator{
 		input:  newBufBooleanIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newBooleanWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -14815,6 +17684,10 @@ type booleanReduceIntegerPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *booleanReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -14882,7 +17755,42 @@ func (itr *booleanReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		}
 		rp.Aggregator.AggregateBoolean(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *booleanReduceIntegerIterator) reduceWindow() ([]IntegerPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*booleanReduceIntegerPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &booleanReduceIntegerPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateBoolean(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *booleanReduceIntegerIterator) emit(m map[string]*booleanReduceIntegerPoint, startTime int64) []IntegerPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -14905,7 +17813,9 @@ func (itr *booleanReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -14919,7 +17829,7 @@ func (itr *booleanReduceIntegerIterator) reduce() ([]IntegerPoint, error) {
 		sort.Stable(sort.Reverse(integerPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // booleanStreamIntegerIterator streams inputs into the iterator and emits points gradually.
@@ -14929,6 +17839,7 @@ type booleanStreamIntegerIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*booleanReduceIntegerPoint
+	id     string
 	points []IntegerPoint
 }
 
@@ -15009,6 +17920,32 @@ func (itr *booleanStreamIntegerIterator) reduce() ([]IntegerPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -15184,6 +18121,7 @@ type booleanIntegerExprFunc func(a, b bool) int64
 // booleanReduceUnsignedIterator executes a reducer for every interval and buffers the result.
 type booleanReduceUnsignedIterator struct {
 	input    *bufBooleanIterator
+	windows  *booleanWindows
 	create   func() (BooleanPointAggregator, UnsignedPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -15192,12 +18130,16 @@ type booleanReduceUnsignedIterator struct {
 }
 
 func newBooleanReduceUnsignedIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, UnsignedPointEmitter)) *booleanReduceUnsignedIterator {
-	return &booleanReduceUnsignedIterator{
+	itr := &booleanReduceUnsignedIterator{
 		input:  newBufBooleanIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newBooleanWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -15234,6 +18176,10 @@ type booleanReduceUnsignedPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *booleanReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -15301,7 +18247,42 @@ func (itr *booleanReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		}
 		rp.Aggregator.AggregateBoolean(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *booleanReduceUnsignedIterator) reduceWindow() ([]UnsignedPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*booleanReduceUnsignedPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &booleanReduceUnsignedPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateBoolean(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *booleanReduceUnsignedIterator) emit(m map[string]*booleanReduceUnsignedPoint, startTime int64) []UnsignedPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -15324,7 +18305,9 @@ func (itr *booleanReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -15338,7 +18321,7 @@ func (itr *booleanReduceUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 		sort.Stable(sort.Reverse(unsignedPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // booleanStreamUnsignedIterator streams inputs into the iterator and emits points gradually.
@@ -15348,6 +18331,7 @@ type booleanStreamUnsignedIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*booleanReduceUnsignedPoint
+	id     string
 	points []UnsignedPoint
 }
 
@@ -15428,6 +18412,32 @@ func (itr *booleanStreamUnsignedIterator) reduce() ([]UnsignedPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -15603,6 +18613,7 @@ type booleanUnsignedExprFunc func(a, b bool) uint64
 // booleanReduceStringIterator executes a reducer for every interval and buffers the result.
 type booleanReduceStringIterator struct {
 	input    *bufBooleanIterator
+	windows  *booleanWindows
 	create   func() (BooleanPointAggregator, StringPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -15611,12 +18622,16 @@ type booleanReduceStringIterator struct {
 }
 
 func newBooleanReduceStringIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, StringPointEmitter)) *booleanReduceStringIterator {
-	return &booleanReduceStringIterator{
+	itr := &booleanReduceStringIterator{
 		input:  newBufBooleanIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newBooleanWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -15653,6 +18668,10 @@ type booleanReduceStringPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *booleanReduceStringIterator) reduce() ([]StringPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -15720,7 +18739,42 @@ func (itr *booleanReduceStringIterator) reduce() ([]StringPoint, error) {
 		}
 		rp.Aggregator.AggregateBoolean(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *booleanReduceStringIterator) reduceWindow() ([]StringPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*booleanReduceStringPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &booleanReduceStringPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateBoolean(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *booleanReduceStringIterator) emit(m map[string]*booleanReduceStringPoint, startTime int64) []StringPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -15743,7 +18797,9 @@ func (itr *booleanReduceStringIterator) reduce() ([]StringPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -15757,7 +18813,7 @@ func (itr *booleanReduceStringIterator) reduce() ([]StringPoint, error) {
 		sort.Stable(sort.Reverse(stringPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // booleanStreamStringIterator streams inputs into the iterator and emits points gradually.
@@ -15767,6 +18823,7 @@ type booleanStreamStringIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*booleanReduceStringPoint
+	id     string
 	points []StringPoint
 }
 
@@ -15847,6 +18904,32 @@ func (itr *booleanStreamStringIterator) reduce() ([]StringPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -16022,6 +19105,7 @@ type booleanStringExprFunc func(a, b bool) string
 // booleanReduceBooleanIterator executes a reducer for every interval and buffers the result.
 type booleanReduceBooleanIterator struct {
 	input    *bufBooleanIterator
+	windows  *booleanWindows
 	create   func() (BooleanPointAggregator, BooleanPointEmitter)
 	dims     []string
 	opt      IteratorOptions
@@ -16030,12 +19114,16 @@ type booleanReduceBooleanIterator struct {
 }
 
 func newBooleanReduceBooleanIterator(input BooleanIterator, opt IteratorOptions, createFn func() (BooleanPointAggregator, BooleanPointEmitter)) *booleanReduceBooleanIterator {
-	return &booleanReduceBooleanIterator{
+	itr := &booleanReduceBooleanIterator{
 		input:  newBufBooleanIterator(input),
 		create: createFn,
 		dims:   opt.GetDimensions(),
 		opt:    opt,
 	}
+	if opt.Interval.Length != 0 {
+		itr.windows = newBooleanWindows(itr.input, opt)
+	}
+	return itr
 }
 
 // Stats returns stats from the input iterator.
@@ -16072,6 +19160,10 @@ type booleanReduceBooleanPoint struct {
 // reduce executes fn once for every point in the next window.
 // The previous value for the dimension is passed to fn.
 func (itr *booleanReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
+	if itr.windows != nil {
+		return itr.reduceWindow()
+	}
+
 	// Calculate next window.
 	var (
 		startTime, endTime int64
@@ -16139,7 +19231,42 @@ func (itr *booleanReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		}
 		rp.Aggregator.AggregateBoolean(curr)
 	}
+	return itr.emit(m, startTime), nil
+}
+
+// reduceWindow executes fn once for every point in the next overlapping window.
+func (itr *booleanReduceBooleanIterator) reduceWindow() ([]BooleanPoint, error) {
+	startTime, points, err := itr.windows.Next()
+	if err != nil || points == nil {
+		return nil, err
+	}
+
+	// Create points by tags.
+	m := make(map[string]*booleanReduceBooleanPoint)
+	for i := range points {
+		curr := &points[i]
+
+		// Retrieve the aggregator for this name/tag combination or create one.
+		tags := curr.Tags.Subset(itr.dims)
+		id := tags.ID()
+		rp := m[id]
+		if rp == nil {
+			aggregator, emitter := itr.create()
+			rp = &booleanReduceBooleanPoint{
+				Name:       curr.Name,
+				Tags:       tags,
+				Aggregator: aggregator,
+				Emitter:    emitter,
+			}
+			m[id] = rp
+		}
+		rp.Aggregator.AggregateBoolean(curr)
+	}
+	return itr.emit(m, startTime), nil
+}
 
+// emit returns the points emitted by the reducers of a window.
+func (itr *booleanReduceBooleanIterator) emit(m map[string]*booleanReduceBooleanPoint, startTime int64) []BooleanPoint {
 	// Reverse sort points by name & tag if our output is supposed to be ordered.
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -16162,7 +19289,9 @@ func (itr *booleanReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 				points[i].Tags = rp.Tags
 			}
 			// Set the points time to the interval time if the reducer didn't provide one.
-			if points[i].Time == ZeroTime {
+			// The time of a point within overlapping windows does not identify
+			// the window so the interval time is always used.
+			if points[i].Time == ZeroTime || itr.windows != nil {
 				points[i].Time = startTime
 			} else {
 				sortedByTime = false
@@ -16176,7 +19305,7 @@ func (itr *booleanReduceBooleanIterator) reduce() ([]BooleanPoint, error) {
 		sort.Stable(sort.Reverse(booleanPointsByTime(a)))
 	}
 
-	return a, nil
+	return a
 }
 
 // booleanStreamBooleanIterator streams inputs into the iterator and emits points gradually.
@@ -16186,6 +19315,7 @@ type booleanStreamBooleanIterator struct {
 	dims   []string
 	opt    IteratorOptions
 	m      map[string]*booleanReduceBooleanPoint
+	id     string
 	points []BooleanPoint
 }
 
@@ -16266,6 +19396,32 @@ func (itr *booleanStreamBooleanIterator) reduce() ([]BooleanPoint, error) {
 			id += "\x00" + tags.ID()
 		}
 
+		// The points of each series are read in order so an aggregator that
+		// emits its last points when it is closed is closed once the next
+		// series begins. The point is read again for the next series.
+		if id != itr.id {
+			if rp := itr.m[itr.id]; rp != nil {
+				if aggregator, ok := rp.Aggregator.(io.Closer); ok {
+					if err := aggregator.Close(); err != nil {
+						return nil, err
+					}
+					delete(itr.m, itr.id)
+					itr.input.unread(curr)
+
+					points := rp.Emitter.Emit()
+					if len(points) == 0 {
+						continue
+					}
+					for i := range points {
+						points[i].Name = rp.Name
+						points[i].Tags = rp.Tags
+					}
+					return points, nil
+				}
+			}
+			itr.id = id
+		}
+
 		// Retrieve the aggregator for this name/tag combination or create one.
 		rp := itr.m[id]
 		if rp == nil {
@@ -16624,6 +19780,52 @@ func (itr *booleanFilterIterator) Next() (*BooleanPoint, error) {
 	}
 }
 
+// booleanCaseIterator evaluates a CASE expression for each point of the
+// input. The auxiliary fields of the input are the values of refs.
+type booleanCaseIterator struct {
+	input FloatIterator
+	expr  *CaseExpr
+	refs  []VarRef
+	m     map[string]interface{}
+}
+
+func newBooleanCaseIterator(input FloatIterator, expr *CaseExpr, refs []VarRef) *booleanCaseIterator {
+	return &booleanCaseIterator{
+		input: input,
+		expr:  expr,
+		refs:  refs,
+		m:     make(map[string]interface{}, len(refs)),
+	}
+}
+
+func (itr *booleanCaseIterator) Stats() IteratorStats { return itr.input.Stats() }
+func (itr *booleanCaseIterator) Close() error         { return itr.input.Close() }
+
+func (itr *booleanCaseIterator) Next() (*BooleanPoint, error) {
+	p, err := itr.input.Next()
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	for i, ref := range itr.refs {
+		itr.m[ref.Val] = p.Aux[i]
+	}
+
+	out := &BooleanPoint{
+		Name:       p.Name,
+		Tags:       p.Tags,
+		Time:       p.Time,
+		Aggregated: p.Aggregated,
+	}
+	switch v := Eval(itr.expr, itr.m).(type) {
+	case bool:
+		out.Value = v
+	default:
+		out.Nil = true
+	}
+	return out, nil
+}
+
 // newBooleanDedupeIterator returns a new instance of booleanDedupeIterator.
 func newBooleanDedupeIterator(input BooleanIterator) *booleanDedupeIterator {
 	return &booleanDedupeIterator{
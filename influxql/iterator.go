@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 	"time"
 
@@ -147,16 +148,49 @@ func (a Iterators) Merge(opt IteratorOptions) (Iterator, error) {
 		return itr, nil
 	}
 
-	// When merging the count() function, use sum() to sum the counted points.
-	if call.Name == "count" {
+	// The inputs have already been reduced into their windows so each
+	// point only belongs to one window. The inputs of a selector have only
+	// been reduced into the steps of the windows.
+	if !mergesWindowSteps(call) {
+		opt.Interval.Length = 0
+	}
+
+	switch call.Name {
+	case "count":
+		// When merging the count() function, use sum() to sum the counted points.
 		opt.Expr = &Call{
 			Name: "sum",
 			Args: call.Args,
 		}
+	case "histogram":
+		// When merging the histogram() function, add up the count of each
+		// bucket instead of only the total count.
+		opt.Expr = &Call{
+			Name: "merge_histogram",
+			Args: call.Args,
+		}
+	case "count_distinct_approx":
+		// When merging the count_distinct_approx() function, merge the
+		// sketches instead of counting the encoded sketches as values.
+		opt.Expr = &Call{
+			Name: "merge_hll",
+			Args: call.Args,
+		}
 	}
 	return NewCallIterator(itr, opt)
 }
 
+// mergesWindowSteps returns true if the call selects a point from each step
+// of overlapping windows and combines the selected points into the windows
+// when they are merged.
+func mergesWindowSteps(call *Call) bool {
+	switch call.Name {
+	case "first", "last", "min", "max":
+		return true
+	}
+	return false
+}
+
 // NewMergeIterator returns an iterator to merge itrs into one.
 // Inputs must either be merge iterators or only contain a single name/tag in
 // sorted order. The iterator will output all points by window, name/tag, then
@@ -330,15 +364,61 @@ func NewDedupeIterator(input Iterator) Iterator {
 
 // NewFillIterator returns an iterator that fills in missing points in an aggregate.
 func NewFillIterator(input Iterator, expr Expr, opt IteratorOptions) Iterator {
+	return newFillIterator(input, expr, opt, nil)
+}
+
+// fillSeriesKey identifies a series in the output of a fill iterator.
+type fillSeriesKey struct {
+	name string
+	tags string
+}
+
+// before returns true if the series is read before the other series.
+func (k fillSeriesKey) before(other fillSeriesKey, ascending bool) bool {
+	if k.name != other.name {
+		return (k.name < other.name) == ascending
+	}
+	return k.tags != other.tags && (k.tags < other.tags) == ascending
+}
+
+// lookbackPoint is the last point of a series before the time range of a
+// query. The time is the start of the window that contains the point.
+type lookbackPoint struct {
+	tags  Tags
+	time  int64
+	value interface{}
+}
+
+// lookbackSeries returns the series of the lookback points in the order the
+// series are read. A series without points within the time range is still
+// filled from its lookback point.
+func lookbackSeries(lookback map[fillSeriesKey]lookbackPoint, ascending bool) []fillSeriesKey {
+	if len(lookback) == 0 {
+		return nil
+	}
+	series := make([]fillSeriesKey, 0, len(lookback))
+	for k := range lookback {
+		series = append(series, k)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].before(series[j], ascending)
+	})
+	return series
+}
+
+// newFillIterator returns an iterator that fills in missing points in an
+// aggregate. Each series starts from its point in lookback, if there is one,
+// instead of starting without a previous value.
+func newFillIterator(input Iterator, expr Expr, opt IteratorOptions, lookback map[fillSeriesKey]lookbackPoint) Iterator {
 	switch input := input.(type) {
 	case FloatIterator:
-		return newFloatFillIterator(input, expr, opt)
+		return newFloatFillIterator(input, expr, opt, lookback)
 	case IntegerIterator:
-		return newIntegerFillIterator(input, expr, opt)
+		return newIntegerFillIterator(input, expr, opt, lookback)
 	case StringIterator:
-		return newStringFillIterator(input, expr, opt)
+		return newStringFillIterator(input, expr, opt, lookback)
 	case BooleanIterator:
-		return newBooleanFillIterator(input, expr, opt)
+		return newBooleanFillIterator(input, expr, opt, lookback)
 	default:
 		panic(fmt.Sprintf("unsupported fill iterator type: %T", input))
 	}
@@ -360,6 +440,93 @@ func NewIntervalIterator(input Iterator, opt IteratorOptions) Iterator {
 	}
 }
 
+// NewRenameIterator returns an iterator that sets the name on each point.
+func NewRenameIterator(input Iterator, name string) Iterator {
+	switch input := input.(type) {
+	case FloatIterator:
+		return newFloatRenameIterator(input, name)
+	case IntegerIterator:
+		return newIntegerRenameIterator(input, name)
+	case StringIterator:
+		return newStringRenameIterator(input, name)
+	case BooleanIterator:
+		return newBooleanRenameIterator(input, name)
+	default:
+		panic(fmt.Sprintf("unsupported rename iterator type: %T", input))
+	}
+}
+
+// joinIterator aligns the points of several iterators by time, name and tags.
+// A point is only returned when every input has a point with the same key and
+// its auxiliary fields are the auxiliary fields of each input in order.
+type joinIterator struct {
+	e      *Emitter
+	widths []int
+	width  int
+}
+
+// newJoinIterator returns a new instance of joinIterator. The points of the
+// input at index i have widths[i] auxiliary fields.
+func newJoinIterator(inputs []Iterator, widths []int, ascending bool) *joinIterator {
+	itr := &joinIterator{
+		e:      NewEmitter(inputs, ascending, 0),
+		widths: widths,
+	}
+	for _, n := range widths {
+		itr.width += n
+	}
+	return itr
+}
+
+// Stats returns stats from the inputs.
+func (itr *joinIterator) Stats() IteratorStats {
+	var stats IteratorStats
+	for _, input := range itr.e.itrs {
+		stats.Add(input.Stats())
+	}
+	return stats
+}
+
+// Close closes the inputs.
+func (itr *joinIterator) Close() error { return itr.e.Close() }
+
+// Next returns the next point with a match in every input.
+func (itr *joinIterator) Next() (*FloatPoint, error) {
+	for {
+		t, name, tags, err := itr.e.loadBuf()
+		if err != nil || t == ZeroTime {
+			return nil, err
+		}
+
+		p := &FloatPoint{Name: name, Tags: tags, Time: t, Nil: true, Aux: make([]interface{}, 0, itr.width)}
+		for i, buf := range itr.e.buf {
+			if buf == nil {
+				p = nil
+				continue
+			} else if bufTags := buf.tags(); buf.time() != t || buf.name() != name || !bufTags.Equals(&tags) {
+				p = nil
+				continue
+			}
+			itr.e.buf[i] = nil
+
+			if p != nil {
+				aux := buf.aux()
+				for j := 0; j < itr.widths[i]; j++ {
+					if j < len(aux) {
+						p.Aux = append(p.Aux, aux[j])
+					} else {
+						p.Aux = append(p.Aux, nil)
+					}
+				}
+			}
+		}
+
+		if p != nil {
+			return p, nil
+		}
+	}
+}
+
 // NewInterruptIterator returns an iterator that will stop producing output
 // when the passed-in channel is closed.
 func NewInterruptIterator(input Iterator, closing <-chan struct{}) Iterator {
@@ -636,6 +803,51 @@ type IteratorCreator interface {
 	CreateIterator(source *Measurement, opt IteratorOptions) (Iterator, error)
 }
 
+// IteratorCostEstimator is implemented by iterator creators that can estimate
+// the cost of creating an iterator without reading any data.
+type IteratorCostEstimator interface {
+	IteratorCost(source *Measurement, opt IteratorOptions) (IteratorCost, error)
+}
+
+// IteratorCost contains statistics about the potential cost of creating an
+// iterator. Series, files and blocks are counted once for every cursor that
+// would read them so the same series or file may be counted more than once.
+type IteratorCost struct {
+	// The number of shards that are accessed.
+	NumShards int64
+
+	// The number of tag sets the series are grouped into.
+	NumTagSets int64
+
+	// The number of series that are accessed.
+	NumSeries int64
+
+	// The number of values in the in-memory cache that may be read.
+	CachedValues int64
+
+	// The number of files that may be accessed.
+	NumFiles int64
+
+	// The number of blocks that may be decoded.
+	BlocksRead int64
+
+	// The total size in bytes of the blocks that may be decoded.
+	BlockSize int64
+}
+
+// Combine returns the sum of two costs.
+func (c IteratorCost) Combine(other IteratorCost) IteratorCost {
+	return IteratorCost{
+		NumShards:    c.NumShards + other.NumShards,
+		NumTagSets:   c.NumTagSets + other.NumTagSets,
+		NumSeries:    c.NumSeries + other.NumSeries,
+		CachedValues: c.CachedValues + other.CachedValues,
+		NumFiles:     c.NumFiles + other.NumFiles,
+		BlocksRead:   c.BlocksRead + other.BlocksRead,
+		BlockSize:    c.BlockSize + other.BlockSize,
+	}
+}
+
 // FieldMapper returns the data type for the field inside of the measurement.
 type FieldMapper interface {
 	FieldDimensions(m *Measurement) (fields map[string]DataType, dimensions map[string]struct{}, err error)
@@ -662,9 +874,14 @@ type IteratorOptions struct {
 	GroupBy    map[string]struct{} // Dimensions to group points by in intermediate iterators.
 	Location   *time.Location
 
+	// Dimensions whose value is computed from the value of a tag, keyed by
+	// the tag key of the dimension.
+	DerivedDimensions map[string]*Call
+
 	// Fill options.
-	Fill      FillOption
-	FillValue interface{}
+	Fill         FillOption
+	FillValue    interface{}
+	FillLookback bool
 
 	// Condition to filter by.
 	Condition Expr
@@ -691,6 +908,10 @@ type IteratorOptions struct {
 	// Limits on the creation of iterators.
 	MaxSeriesN int
 
+	// The maximum number of points that may be buffered to sort them. This
+	// is only used by the iterators that are created by Select.
+	MaxPointN int
+
 	// If this channel is set and is closed, the iterator should try to exit
 	// and close as soon as possible.
 	InterruptCh <-chan struct{}
@@ -741,9 +962,15 @@ func newIteratorOptionsStmt(stmt *SelectStatement, sopt *SelectOptions) (opt Ite
 		if err != nil {
 			return opt, err
 		}
+		opt.Interval.Months = stmt.GroupByMonths()
 	}
 	opt.Interval.Duration = interval
 
+	// Overlapping windows start every step and are as long as the interval.
+	if step := stmt.GroupByStep(); step > 0 && interval > 0 {
+		opt.Interval.Duration, opt.Interval.Length = step, interval
+	}
+
 	// Always request an ordered output for the top level iterators.
 	// The emitter will always emit points as ordered.
 	opt.Ordered = true
@@ -751,9 +978,31 @@ func newIteratorOptionsStmt(stmt *SelectStatement, sopt *SelectOptions) (opt Ite
 	// Determine dimensions.
 	opt.GroupBy = make(map[string]struct{}, len(opt.Dimensions))
 	for _, d := range stmt.Dimensions {
-		if d, ok := d.Expr.(*VarRef); ok {
-			opt.Dimensions = append(opt.Dimensions, d.Val)
-			opt.GroupBy[d.Val] = struct{}{}
+		key, ok := d.TagKey()
+		if !ok {
+			continue
+		}
+		opt.Dimensions = append(opt.Dimensions, key)
+		opt.GroupBy[key] = struct{}{}
+
+		// The value of a derived dimension is computed by the sources.
+		if call, ok := d.Expr.(*Call); ok {
+			if opt.DerivedDimensions == nil {
+				opt.DerivedDimensions = make(map[string]*Call)
+			}
+			opt.DerivedDimensions[key] = call
+		}
+	}
+
+	// The series of a join are grouped by the tag keys they are joined on.
+	for _, src := range stmt.Sources {
+		if join, ok := src.(*Join); ok {
+			for _, key := range join.On {
+				if _, ok := opt.GroupBy[key]; !ok {
+					opt.Dimensions = append(opt.Dimensions, key)
+					opt.GroupBy[key] = struct{}{}
+				}
+			}
 		}
 	}
 
@@ -761,7 +1010,7 @@ func newIteratorOptionsStmt(stmt *SelectStatement, sopt *SelectOptions) (opt Ite
 	opt.Ascending = stmt.TimeAscending()
 	opt.Dedupe = stmt.Dedupe
 
-	opt.Fill, opt.FillValue = stmt.Fill, stmt.FillValue
+	opt.Fill, opt.FillValue, opt.FillLookback = stmt.Fill, stmt.FillValue, stmt.FillLookback
 	if opt.Fill == NullFill && stmt.Target != nil {
 		// Set the fill option to none if a target has been given.
 		// Null values will get ignored when being written to the target
@@ -772,6 +1021,7 @@ func newIteratorOptionsStmt(stmt *SelectStatement, sopt *SelectOptions) (opt Ite
 	opt.SLimit, opt.SOffset = stmt.SLimit, stmt.SOffset
 	if sopt != nil {
 		opt.MaxSeriesN = sopt.MaxSeriesN
+		opt.MaxPointN = sopt.MaxPointN
 		opt.InterruptCh = sopt.InterruptCh
 		opt.Authorizer = sopt.Authorizer
 	}
@@ -796,6 +1046,12 @@ func newIteratorOptionsSubstatement(stmt *SelectStatement, opt IteratorOptions)
 	for d := range opt.GroupBy {
 		subOpt.GroupBy[d] = struct{}{}
 	}
+	for d, call := range opt.DerivedDimensions {
+		if subOpt.DerivedDimensions == nil {
+			subOpt.DerivedDimensions = make(map[string]*Call)
+		}
+		subOpt.DerivedDimensions[d] = call
+	}
 	subOpt.InterruptCh = opt.InterruptCh
 
 	// Propagate the SLIMIT and SOFFSET from the outer query.
@@ -820,7 +1076,10 @@ func newIteratorOptionsSubstatement(stmt *SelectStatement, opt IteratorOptions)
 	if err != nil {
 		return IteratorOptions{}, err
 	} else if interval == 0 {
+		// The windows of the subquery do not overlap so the outer query
+		// can combine them into its own windows.
 		subOpt.Interval = opt.Interval
+		subOpt.Interval.Length = 0
 	}
 	return subOpt, nil
 }
@@ -843,6 +1102,8 @@ func (opt IteratorOptions) SeekTime() int64 {
 func (opt IteratorOptions) Window(t int64) (start, end int64) {
 	if opt.Interval.IsZero() {
 		return opt.StartTime, opt.EndTime + 1
+	} else if opt.Interval.Months != 0 {
+		return opt.calendarWindow(t)
 	}
 
 	// Subtract the offset to the time so we calculate the correct base interval.
@@ -918,6 +1179,37 @@ func (opt IteratorOptions) Window(t int64) (start, end int64) {
 	return
 }
 
+// calendarWindow returns the calendar window [start,end) that t falls within.
+// The windows are aligned to the first month of the year in the location of
+// the query.
+func (opt IteratorOptions) calendarWindow(t int64) (start, end int64) {
+	loc := opt.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	months := opt.Interval.Months
+
+	// Find the month of the time and round it down to the start of the window.
+	ts := time.Unix(0, t-int64(opt.Interval.Offset)).In(loc)
+	n := ts.Year()*12 + int(ts.Month()) - 1
+	n -= n % months
+
+	first := time.Date(n/12, time.Month(n%12+1), 1, 0, 0, 0, 0, loc)
+	if minTime := time.Unix(0, MinTime); first.Before(minTime) {
+		start = MinTime
+	} else {
+		start = first.UnixNano() + int64(opt.Interval.Offset)
+	}
+
+	last := first.AddDate(0, months, 0)
+	if maxTime := time.Unix(0, MaxTime); !last.Before(maxTime) {
+		end = MaxTime
+	} else {
+		end = last.UnixNano() + int64(opt.Interval.Offset)
+	}
+	return start, end
+}
+
 // DerivativeInterval returns the time interval for the derivative function.
 func (opt IteratorOptions) DerivativeInterval() Interval {
 	// Use the interval on the derivative() call, if specified.
@@ -925,8 +1217,11 @@ func (opt IteratorOptions) DerivativeInterval() Interval {
 		return Interval{Duration: expr.Args[1].(*DurationLiteral).Val}
 	}
 
-	// Otherwise use the group by interval, if specified.
-	if opt.Interval.Duration > 0 {
+	// Otherwise use the group by interval, if specified. The length of a
+	// calendar month varies so the derivative is calculated per month.
+	if opt.Interval.Months != 0 {
+		return Interval{Months: opt.Interval.Months}
+	} else if opt.Interval.Duration > 0 {
 		return Interval{Duration: opt.Interval.Duration}
 	}
 
@@ -965,6 +1260,69 @@ func (opt IteratorOptions) GetDimensions() []string {
 	return opt.Dimensions
 }
 
+// DimensionValue returns the value of a dimension for a series. The value of
+// a derived dimension is computed from the tag it is derived from.
+func (opt IteratorOptions) DimensionValue(dim string, tagValue func(key string) string) string {
+	call, ok := opt.DerivedDimensions[dim]
+	if !ok {
+		return tagValue(dim)
+	}
+	value := tagValue(call.Args[0].(*VarRef).Val)
+
+	switch call.Name {
+	case "regex_extract":
+		re := call.Args[1].(*RegexLiteral).Val
+		m := re.FindStringSubmatch(value)
+		if m == nil {
+			return ""
+		}
+
+		// Use the first capture group unless a group is given or the
+		// regular expression has no capture groups.
+		group := 0
+		if len(call.Args) == 3 {
+			group = int(call.Args[2].(*IntegerLiteral).Val)
+		} else if len(m) > 1 {
+			group = 1
+		}
+		return m[group]
+	case "map_values":
+		args := call.Args[1:]
+		for ; len(args) >= 2; args = args[2:] {
+			switch match := args[0].(type) {
+			case *StringLiteral:
+				if match.Val == value {
+					return args[1].(*StringLiteral).Val
+				}
+			case *RegexLiteral:
+				if match.Val.MatchString(value) {
+					return args[1].(*StringLiteral).Val
+				}
+			}
+		}
+		if len(args) == 1 {
+			return args[0].(*StringLiteral).Val
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// DimensionTags returns the tags of a series that points are grouped by.
+func (opt IteratorOptions) DimensionTags(tags Tags) Tags {
+	dimensions := opt.GetDimensions()
+	if len(opt.DerivedDimensions) == 0 {
+		return tags.Subset(dimensions)
+	}
+
+	m := make(map[string]string, len(dimensions))
+	for _, dim := range dimensions {
+		m[dim] = opt.DimensionValue(dim, tags.Value)
+	}
+	return NewTags(m)
+}
+
 // Zone returns the zone information for the given time. The offset is in nanoseconds.
 func (opt *IteratorOptions) Zone(ns int64) (string, int64) {
 	if opt.Location == nil {
@@ -1165,6 +1523,9 @@ func newSelectInfo(stmt *SelectStatement) *selectInfo {
 func (v *selectInfo) Visit(n Node) Visitor {
 	switch n := n.(type) {
 	case *Call:
+		if isMathFunction(n) {
+			return v
+		}
 		v.calls[n] = struct{}{}
 		return nil
 	case *VarRef:
@@ -1194,6 +1555,15 @@ func (v *selectInfo) FindSelector() *Call {
 type Interval struct {
 	Duration time.Duration
 	Offset   time.Duration
+
+	// The number of calendar months in the interval. If this is set, the
+	// windows are aligned to the calendar in the location of the query and
+	// Duration is the longest possible length of a window.
+	Months int
+
+	// The length of each window if the windows overlap. A window starts
+	// every Duration and each point is a part of Length / Duration windows.
+	Length time.Duration
 }
 
 // IsZero returns true if the interval has no duration.
@@ -1203,6 +1573,8 @@ func encodeInterval(i Interval) *internal.Interval {
 	return &internal.Interval{
 		Duration: proto.Int64(i.Duration.Nanoseconds()),
 		Offset:   proto.Int64(i.Offset.Nanoseconds()),
+		Months:   proto.Int64(int64(i.Months)),
+		Length:   proto.Int64(i.Length.Nanoseconds()),
 	}
 }
 
@@ -1210,6 +1582,8 @@ func decodeInterval(pb *internal.Interval) Interval {
 	return Interval{
 		Duration: time.Duration(pb.GetDuration()),
 		Offset:   time.Duration(pb.GetOffset()),
+		Months:   int(pb.GetMonths()),
+		Length:   time.Duration(pb.GetLength()),
 	}
 }
 
@@ -1290,12 +1664,21 @@ func (itr *integerFloatCastIterator) Next() (*FloatPoint, error) {
 type IteratorStats struct {
 	SeriesN int // series represented
 	PointN  int // points returned
+
+	// Storage statistics are only collected by local shards and are not
+	// encoded when an iterator is sent to another node.
+	BlockN      int // storage blocks decoded
+	CachePointN int // points read from the in-memory cache
+	TSMPointN   int // points read from TSM files
 }
 
 // Add aggregates fields from s and other together. Overwrites s.
 func (s *IteratorStats) Add(other IteratorStats) {
 	s.SeriesN += other.SeriesN
 	s.PointN += other.PointN
+	s.BlockN += other.BlockN
+	s.CachePointN += other.CachePointN
+	s.TSMPointN += other.TSMPointN
 }
 
 func encodeIteratorStats(stats *IteratorStats) *internal.IteratorStats {
@@ -1132,6 +1132,65 @@ func TestIteratorOptions_Window_Location(t *testing.T) {
 	}
 }
 
+func TestIteratorOptions_Window_Calendar(t *testing.T) {
+	for _, tt := range []struct {
+		now        time.Time
+		start, end time.Time
+		months     int
+		offset     time.Duration
+	}{
+		{
+			now:    mustParseTime("2000-02-17T12:14:15-08:00"),
+			start:  mustParseTime("2000-02-01T00:00:00-08:00"),
+			end:    mustParseTime("2000-03-01T00:00:00-08:00"),
+			months: 1,
+		},
+		{
+			now:    mustParseTime("2000-04-17T12:14:15-07:00"),
+			start:  mustParseTime("2000-04-01T00:00:00-08:00"),
+			end:    mustParseTime("2000-05-01T00:00:00-07:00"),
+			months: 1,
+		},
+		{
+			now:    mustParseTime("2000-05-17T12:14:15-07:00"),
+			start:  mustParseTime("2000-04-01T00:00:00-08:00"),
+			end:    mustParseTime("2000-07-01T00:00:00-07:00"),
+			months: 3,
+		},
+		{
+			now:    mustParseTime("2000-12-31T23:59:59-08:00"),
+			start:  mustParseTime("2000-01-01T00:00:00-08:00"),
+			end:    mustParseTime("2001-01-01T00:00:00-08:00"),
+			months: 12,
+		},
+		{
+			now:    mustParseTime("2000-03-01T03:00:00-08:00"),
+			start:  mustParseTime("2000-02-01T06:00:00-08:00"),
+			end:    mustParseTime("2000-03-01T06:00:00-08:00"),
+			months: 1,
+			offset: 6 * time.Hour,
+		},
+	} {
+		t.Run(fmt.Sprintf("%s/%dmo", tt.now, tt.months), func(t *testing.T) {
+			opt := influxql.IteratorOptions{
+				Location: LosAngeles,
+				Interval: influxql.Interval{
+					Duration: time.Duration(tt.months) * influxql.MaxMonthDuration,
+					Offset:   tt.offset,
+					Months:   tt.months,
+				},
+			}
+			start, end := opt.Window(tt.now.UnixNano())
+			if have, want := time.Unix(0, start).In(LosAngeles), tt.start; !have.Equal(want) {
+				t.Errorf("unexpected start time: %s != %s", have, want)
+			}
+			if have, want := time.Unix(0, end).In(LosAngeles), tt.end; !have.Equal(want) {
+				t.Errorf("unexpected end time: %s != %s", have, want)
+			}
+		})
+	}
+}
+
 func TestIteratorOptions_Window_MinTime(t *testing.T) {
 	opt := influxql.IteratorOptions{
 		StartTime: influxql.MinTime,
@@ -1294,6 +1353,37 @@ func TestIteratorOptions_IntegralInterval_Default(t *testing.T) {
 }
 
 // Ensure iterator options can be marshaled to and from a binary format.
+func TestIteratorOptions_DimensionValue(t *testing.T) {
+	for _, tt := range []struct {
+		expr  string
+		value string
+		exp   string
+	}{
+		{expr: `regex_extract(host, /^(\w+)-(\w+)/)`, value: "web-eu-12", exp: "web"},
+		{expr: `regex_extract(host, /^(\w+)-(\w+)/, 2)`, value: "web-eu-12", exp: "eu"},
+		{expr: `regex_extract(host, /^(\w+)-(\w+)/, 0)`, value: "web-eu-12", exp: "web-eu"},
+		{expr: `regex_extract(host, /^\w+/)`, value: "web-eu-12", exp: "web"},
+		{expr: `regex_extract(host, /^(\w+)-(\w+)/, 2)`, value: "localhost", exp: ""},
+		{expr: `map_values(host, 'db-1', 'db', /^web-/, 'web')`, value: "web-eu-12", exp: "web"},
+		{expr: `map_values(host, 'db-1', 'db', /^web-/, 'web')`, value: "db-1", exp: "db"},
+		{expr: `map_values(host, 'db-1', 'db', /^web-/, 'web')`, value: "cache-1", exp: "cache-1"},
+		{expr: `map_values(host, 'db-1', 'db', /^web-/, 'web', 'other')`, value: "cache-1", exp: "other"},
+	} {
+		opt := influxql.IteratorOptions{
+			DerivedDimensions: map[string]*influxql.Call{
+				"group": influxql.MustParseExpr(tt.expr).(*influxql.Call),
+			},
+		}
+		tags := ParseTags("host=" + tt.value)
+		if got := opt.DimensionValue("group", tags.Value); got != tt.exp {
+			t.Errorf("%s(%q): got %q, exp %q", tt.expr, tt.value, got, tt.exp)
+		}
+		if got, exp := opt.DimensionValue("host", tags.Value), tt.value; got != exp {
+			t.Errorf("%s(%q): unexpected tag value: got %q, exp %q", tt.expr, tt.value, got, exp)
+		}
+	}
+}
+
 func TestIteratorOptions_MarshalBinary(t *testing.T) {
 	opt := &influxql.IteratorOptions{
 		Expr: MustParseExpr("count(value)"),
@@ -1301,6 +1391,7 @@ func TestIteratorOptions_MarshalBinary(t *testing.T) {
 		Interval: influxql.Interval{
 			Duration: 1 * time.Hour,
 			Offset:   20 * time.Minute,
+			Length:   4 * time.Hour,
 		},
 		Dimensions: []string{"region", "host"},
 		Fill:       influxql.NumberFill,
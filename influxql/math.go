@@ -0,0 +1,399 @@
+package influxql
+
+import (
+	"fmt"
+	"math"
+)
+
+// isMathFunction returns true if the call is a scalar math function. Math
+// functions are applied to every point individually rather than aggregating
+// the points within an interval so they can be used on raw fields and on the
+// output of an aggregate.
+func isMathFunction(call *Call) bool {
+	switch call.Name {
+	case "abs", "sin", "cos", "tan", "asin", "acos", "atan", "atan2", "exp", "ln", "log", "log2", "log10", "sqrt", "pow", "floor", "ceil", "round":
+		return true
+	}
+	return false
+}
+
+// mathFunctionArgN returns the number of arguments accepted by a math function.
+func mathFunctionArgN(name string) int {
+	switch name {
+	case "atan2", "log", "pow":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// validateMathFunction ensures the arguments of a math function are valid.
+func validateMathFunction(call *Call) error {
+	if exp, got := mathFunctionArgN(call.Name), len(call.Args); got != exp {
+		return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", call.Name, exp, got)
+	}
+
+	for _, arg := range call.Args {
+		switch arg := arg.(type) {
+		case *VarRef, *Call, *BinaryExpr, *ParenExpr, *NumberLiteral, *IntegerLiteral, *Wildcard, *RegexLiteral:
+			// do nothing
+		case *Distinct:
+			return fmt.Errorf("expected field argument in %s()", call.Name)
+		default:
+			return fmt.Errorf("invalid argument for %s(): %s", call.Name, arg)
+		}
+	}
+	return nil
+}
+
+// validateMathArgs ensures the math functions within a field expression refer
+// to a field. The function is applied to each point of its arguments so a call
+// with only literal arguments has no points to be applied to. Math functions
+// within a CASE expression are evaluated on each point so they are not checked.
+func validateMathArgs(expr Expr) error {
+	switch expr := expr.(type) {
+	case *Call:
+		if isMathFunction(expr) {
+			var hasField bool
+			WalkFunc(expr, func(n Node) {
+				switch n.(type) {
+				case *VarRef, *Wildcard, *RegexLiteral:
+					hasField = true
+				}
+			})
+			if !hasField {
+				return fmt.Errorf("expected field argument in %s()", expr.Name)
+			}
+		}
+		for _, arg := range expr.Args {
+			if err := validateMathArgs(arg); err != nil {
+				return err
+			}
+		}
+	case *BinaryExpr:
+		if err := validateMathArgs(expr.LHS); err != nil {
+			return err
+		}
+		return validateMathArgs(expr.RHS)
+	case *ParenExpr:
+		return validateMathArgs(expr.Expr)
+	}
+	return nil
+}
+
+// mathFunctionType returns the data type produced by a math function when it
+// is called with an argument of the given type.
+func mathFunctionType(name string, typ DataType) DataType {
+	if typ == Integer && integerMathFunc(name) != nil {
+		return Integer
+	}
+	return Float
+}
+
+// floatMathFunc returns the implementation of a single argument math function.
+func floatMathFunc(name string) func(float64) float64 {
+	switch name {
+	case "abs":
+		return math.Abs
+	case "sin":
+		return math.Sin
+	case "cos":
+		return math.Cos
+	case "tan":
+		return math.Tan
+	case "asin":
+		return math.Asin
+	case "acos":
+		return math.Acos
+	case "atan":
+		return math.Atan
+	case "exp":
+		return math.Exp
+	case "ln":
+		return math.Log
+	case "log2":
+		return math.Log2
+	case "log10":
+		return math.Log10
+	case "sqrt":
+		return math.Sqrt
+	case "floor":
+		return math.Floor
+	case "ceil":
+		return math.Ceil
+	case "round":
+		return round
+	}
+	return nil
+}
+
+// floatMathFunc2 returns the implementation of a math function with two arguments.
+func floatMathFunc2(name string) func(float64, float64) float64 {
+	switch name {
+	case "atan2":
+		return math.Atan2
+	case "log":
+		return func(x, base float64) float64 {
+			return math.Log(x) / math.Log(base)
+		}
+	case "pow":
+		return math.Pow
+	}
+	return nil
+}
+
+// integerMathFunc returns the implementation of a math function that keeps
+// integers as integers. It returns nil if the function must cast its argument
+// to a float. The function returns false if the result cannot be represented
+// as an integer.
+func integerMathFunc(name string) func(int64) (int64, bool) {
+	switch name {
+	case "abs":
+		return func(v int64) (int64, bool) {
+			if v == math.MinInt64 {
+				return 0, false
+			} else if v < 0 {
+				return -v, true
+			}
+			return v, true
+		}
+	case "floor", "ceil", "round":
+		return func(v int64) (int64, bool) { return v, true }
+	}
+	return nil
+}
+
+// isMathResult returns true if a math function returned a number. Arguments
+// outside of the domain of a function, such as sqrt(-1) or log(0), return NaN
+// or an infinity. These cannot be encoded in a response so they are null.
+func isMathResult(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// setMathResult sets the value of a point to the result of a math function.
+// The point is null if the result is not a number.
+func setMathResult(p *FloatPoint, v float64) {
+	if !isMathResult(v) {
+		p.Value, p.Nil = 0, true
+		return
+	}
+	p.Value = v
+}
+
+// round rounds a value to the nearest integer, rounding half away from zero.
+func round(v float64) float64 {
+	if v < 0 {
+		return -math.Floor(-v + 0.5)
+	}
+	return math.Floor(v + 0.5)
+}
+
+// evalMathCall evaluates a math function against a map.
+func evalMathCall(expr *Call, m map[string]interface{}) interface{} {
+	if len(expr.Args) != mathFunctionArgN(expr.Name) {
+		return nil
+	}
+
+	args := make([]float64, len(expr.Args))
+	for i, arg := range expr.Args {
+		switch v := Eval(arg, m).(type) {
+		case float64:
+			args[i] = v
+		case int64:
+			if len(args) == 1 {
+				if fn := integerMathFunc(expr.Name); fn != nil {
+					if v, ok := fn(v); ok {
+						return v
+					}
+					return nil
+				}
+			}
+			args[i] = float64(v)
+		case uint64:
+			args[i] = float64(v)
+		default:
+			return nil
+		}
+	}
+
+	var v float64
+	if len(args) == 1 {
+		v = floatMathFunc(expr.Name)(args[0])
+	} else {
+		v = floatMathFunc2(expr.Name)(args[0], args[1])
+	}
+	if !isMathResult(v) {
+		return nil
+	}
+	return v
+}
+
+// buildMathIterator constructs an iterator that applies a math function to
+// every point of its arguments. The build function is used to construct the
+// iterator for each argument that is not a literal.
+func buildMathIterator(expr *Call, opt IteratorOptions, build func(Expr) (Iterator, error)) (Iterator, error) {
+	if len(expr.Args) == 1 {
+		input, err := build(expr.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return buildUnaryMathIterator(expr.Name, input)
+	}
+
+	fn := floatMathFunc2(expr.Name)
+	if rhs, ok := expr.Args[1].(Literal); ok {
+		val, err := mathLiteralValue(expr.Name, rhs)
+		if err != nil {
+			return nil, err
+		}
+
+		lhs, err := build(expr.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		input, err := mathFloatIterator(expr.Name, lhs)
+		if err != nil {
+			lhs.Close()
+			return nil, err
+		}
+		return &floatTransformIterator{
+			input: input,
+			fn: func(p *FloatPoint) *FloatPoint {
+				if p == nil {
+					return nil
+				} else if p.Nil {
+					return p
+				}
+				setMathResult(p, fn(p.Value, val))
+				return p
+			},
+		}, nil
+	} else if lhs, ok := expr.Args[0].(Literal); ok {
+		val, err := mathLiteralValue(expr.Name, lhs)
+		if err != nil {
+			return nil, err
+		}
+
+		rhs, err := build(expr.Args[1])
+		if err != nil {
+			return nil, err
+		}
+		input, err := mathFloatIterator(expr.Name, rhs)
+		if err != nil {
+			rhs.Close()
+			return nil, err
+		}
+		return &floatTransformIterator{
+			input: input,
+			fn: func(p *FloatPoint) *FloatPoint {
+				if p == nil {
+					return nil
+				} else if p.Nil {
+					return p
+				}
+				setMathResult(p, fn(val, p.Value))
+				return p
+			},
+		}, nil
+	}
+
+	// We have two iterators. Combine them into a single iterator.
+	lhs, err := build(expr.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	left, err := mathFloatIterator(expr.Name, lhs)
+	if err != nil {
+		lhs.Close()
+		return nil, err
+	}
+
+	rhs, err := build(expr.Args[1])
+	if err != nil {
+		left.Close()
+		return nil, err
+	}
+	right, err := mathFloatIterator(expr.Name, rhs)
+	if err != nil {
+		left.Close()
+		rhs.Close()
+		return nil, err
+	}
+	return &floatTransformIterator{
+		input: newFloatExprIterator(left, right, opt, fn),
+		fn: func(p *FloatPoint) *FloatPoint {
+			if p != nil && !p.Nil {
+				setMathResult(p, p.Value)
+			}
+			return p
+		},
+	}, nil
+}
+
+// buildUnaryMathIterator wraps an input iterator with a single argument math function.
+func buildUnaryMathIterator(name string, input Iterator) (Iterator, error) {
+	if input, ok := input.(IntegerIterator); ok {
+		if fn := integerMathFunc(name); fn != nil {
+			return &integerTransformIterator{
+				input: input,
+				fn: func(p *IntegerPoint) *IntegerPoint {
+					if p == nil {
+						return nil
+					} else if p.Nil {
+						return p
+					}
+					if v, ok := fn(p.Value); ok {
+						p.Value = v
+					} else {
+						p.Value, p.Nil = 0, true
+					}
+					return p
+				},
+			}, nil
+		}
+	}
+
+	itr, err := mathFloatIterator(name, input)
+	if err != nil {
+		input.Close()
+		return nil, err
+	}
+
+	fn := floatMathFunc(name)
+	return &floatTransformIterator{
+		input: itr,
+		fn: func(p *FloatPoint) *FloatPoint {
+			if p == nil {
+				return nil
+			} else if p.Nil {
+				return p
+			}
+			setMathResult(p, fn(p.Value))
+			return p
+		},
+	}, nil
+}
+
+// mathFloatIterator casts the input of a math function to a FloatIterator.
+func mathFloatIterator(name string, input Iterator) (FloatIterator, error) {
+	switch input := input.(type) {
+	case FloatIterator:
+		return input, nil
+	case IntegerIterator:
+		return &integerFloatCastIterator{input: input}, nil
+	default:
+		return nil, fmt.Errorf("cannot use type %s in argument to %s()", iteratorDataType(input), name)
+	}
+}
+
+// mathLiteralValue returns the float value of a literal argument to a math function.
+func mathLiteralValue(name string, lit Literal) (float64, error) {
+	switch lit := lit.(type) {
+	case *NumberLiteral:
+		return lit.Val, nil
+	case *IntegerLiteral:
+		return float64(lit.Val), nil
+	default:
+		return 0, fmt.Errorf("cannot use %s as an argument to %s()", lit, name)
+	}
+}
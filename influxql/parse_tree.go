@@ -105,8 +105,13 @@ func init() {
 		return p.parseDeleteStatement()
 	})
 	Language.Group(SHOW).With(func(show *ParseTree) {
-		show.Group(CONTINUOUS).Handle(QUERIES, func(p *Parser) (Statement, error) {
-			return p.parseShowContinuousQueriesStatement()
+		show.Group(CONTINUOUS).With(func(continuous *ParseTree) {
+			continuous.Handle(QUERIES, func(p *Parser) (Statement, error) {
+				return p.parseShowContinuousQueriesStatement()
+			})
+			continuous.Handle(QUERY, func(p *Parser) (Statement, error) {
+				return p.parseShowContinuousQueryStatusStatement()
+			})
 		})
 		show.Handle(DATABASES, func(p *Parser) (Statement, error) {
 			return p.parseShowDatabasesStatement()
@@ -114,12 +119,34 @@ func init() {
 		show.Handle(DIAGNOSTICS, func(p *Parser) (Statement, error) {
 			return p.parseShowDiagnosticsStatement()
 		})
-		show.Group(FIELD).Handle(KEYS, func(p *Parser) (Statement, error) {
-			return p.parseShowFieldKeysStatement()
+		show.Group(EXACT).With(func(exact *ParseTree) {
+			exact.Group(FIELD, KEY).Handle(CARDINALITY, func(p *Parser) (Statement, error) {
+				return p.parseShowCardinalityStatement(&ShowFieldKeyCardinalityStatement{Exact: true})
+			})
+			exact.Group(MEASUREMENT).Handle(CARDINALITY, func(p *Parser) (Statement, error) {
+				return p.parseShowCardinalityStatement(&ShowMeasurementCardinalityStatement{Exact: true})
+			})
+			exact.Group(SERIES).Handle(CARDINALITY, func(p *Parser) (Statement, error) {
+				return p.parseShowCardinalityStatement(&ShowSeriesCardinalityStatement{Exact: true})
+			})
+			exact.Group(TAG, VALUES).Handle(CARDINALITY, func(p *Parser) (Statement, error) {
+				return p.parseShowCardinalityStatement(&ShowTagValuesCardinalityStatement{Exact: true})
+			})
+		})
+		show.Group(FIELD).With(func(field *ParseTree) {
+			field.Group(KEY).Handle(CARDINALITY, func(p *Parser) (Statement, error) {
+				return p.parseShowCardinalityStatement(&ShowFieldKeyCardinalityStatement{Exact: false})
+			})
+			field.Handle(KEYS, func(p *Parser) (Statement, error) {
+				return p.parseShowFieldKeysStatement()
+			})
 		})
 		show.Group(GRANTS).Handle(FOR, func(p *Parser) (Statement, error) {
 			return p.parseGrantsForUserStatement()
 		})
+		show.Group(MEASUREMENT).Handle(CARDINALITY, func(p *Parser) (Statement, error) {
+			return p.parseShowCardinalityStatement(&ShowMeasurementCardinalityStatement{Exact: false})
+		})
 		show.Handle(MEASUREMENTS, func(p *Parser) (Statement, error) {
 			return p.parseShowMeasurementsStatement()
 		})
@@ -130,6 +157,10 @@ func init() {
 			return p.parseShowRetentionPoliciesStatement()
 		})
 		show.Handle(SERIES, func(p *Parser) (Statement, error) {
+			if tok, _, _ := p.ScanIgnoreWhitespace(); tok == CARDINALITY {
+				return p.parseShowCardinalityStatement(&ShowSeriesCardinalityStatement{Exact: false})
+			}
+			p.Unscan()
 			return p.parseShowSeriesStatement()
 		})
 		show.Group(SHARD).Handle(GROUPS, func(p *Parser) (Statement, error) {
@@ -149,6 +180,10 @@ func init() {
 				return p.parseShowTagKeysStatement()
 			})
 			tag.Handle(VALUES, func(p *Parser) (Statement, error) {
+				if tok, _, _ := p.ScanIgnoreWhitespace(); tok == CARDINALITY {
+					return p.parseShowCardinalityStatement(&ShowTagValuesCardinalityStatement{Exact: false})
+				}
+				p.Unscan()
 				return p.parseShowTagValuesStatement()
 			})
 		})
@@ -156,6 +191,9 @@ func init() {
 			return p.parseShowUsersStatement()
 		})
 	})
+	Language.Group(BACKFILL, CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
+		return p.parseBackfillContinuousQueryStatement()
+	})
 	Language.Group(CREATE).With(func(create *ParseTree) {
 		create.Group(CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
 			return p.parseCreateContinuousQueryStatement()
@@ -180,6 +218,9 @@ func init() {
 		drop.Handle(DATABASE, func(p *Parser) (Statement, error) {
 			return p.parseDropDatabaseStatement()
 		})
+		drop.Handle(FIELD, func(p *Parser) (Statement, error) {
+			return p.parseDropFieldStatement()
+		})
 		drop.Handle(MEASUREMENT, func(p *Parser) (Statement, error) {
 			return p.parseDropMeasurementStatement()
 		})
@@ -205,8 +246,13 @@ func init() {
 	Language.Handle(REVOKE, func(p *Parser) (Statement, error) {
 		return p.parseRevokeStatement()
 	})
-	Language.Group(ALTER, RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
-		return p.parseAlterRetentionPolicyStatement()
+	Language.Group(ALTER).With(func(alter *ParseTree) {
+		alter.Handle(MEASUREMENT, func(p *Parser) (Statement, error) {
+			return p.parseAlterMeasurementStatement()
+		})
+		alter.Group(RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
+			return p.parseAlterRetentionPolicyStatement()
+		})
 	})
 	Language.Group(SET, PASSWORD).Handle(FOR, func(p *Parser) (Statement, error) {
 		return p.parseSetPasswordUserStatement()
@@ -214,4 +260,7 @@ func init() {
 	Language.Group(KILL).Handle(QUERY, func(p *Parser) (Statement, error) {
 		return p.parseKillQueryStatement()
 	})
+	Language.Handle(EXPLAIN, func(p *Parser) (Statement, error) {
+		return p.parseExplainStatement()
+	})
 }
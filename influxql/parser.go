@@ -24,6 +24,10 @@ const (
 type Parser struct {
 	s      *bufScanner
 	params map[string]interface{}
+
+	// Set while parsing a dimension, which is the only place calendar
+	// durations such as 1mo can be used.
+	calendar bool
 }
 
 // NewParser returns a new instance of Parser.
@@ -142,6 +146,31 @@ func (p *Parser) parseKillQueryStatement() (*KillQueryStatement, error) {
 	return &KillQueryStatement{QueryID: qid, Host: host}, nil
 }
 
+// parseExplainStatement parses a string and return an explain statement.
+// This function assumes the EXPLAIN token has already been consumed.
+func (p *Parser) parseExplainStatement() (*ExplainStatement, error) {
+	stmt := &ExplainStatement{}
+
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ANALYZE {
+		stmt.Analyze = true
+	} else {
+		p.Unscan()
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != SELECT {
+		return nil, newParseError(tokstr(tok, lit), []string{"SELECT"}, pos)
+	}
+
+	s, err := p.parseSelectStatement(targetNotRequired)
+	if err != nil {
+		return nil, err
+	} else if s.Target != nil {
+		return nil, errors.New("EXPLAIN cannot be used with SELECT INTO")
+	}
+	stmt.Statement = s
+	return stmt, nil
+}
+
 // parseCreateSubscriptionStatement parses a string and returns a CreateSubscriptionStatement.
 // This function assumes the "CREATE SUBSCRIPTION" tokens have already been consumed.
 func (p *Parser) parseCreateSubscriptionStatement() (*CreateSubscriptionStatement, error) {
@@ -280,6 +309,59 @@ func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicySt
 	return stmt, nil
 }
 
+// parseAlterMeasurementStatement parses a string and returns an alter measurement statement.
+// This function assumes the ALTER MEASUREMENT tokens have already been consumed.
+func (p *Parser) parseAlterMeasurementStatement() (*AlterMeasurementStatement, error) {
+	stmt := &AlterMeasurementStatement{}
+
+	// Parse the name of the measurement.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Parse the RENAME keyword. It is not reserved so it is an identifier.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "RENAME" {
+		return nil, newParseError(tokstr(tok, lit), []string{"RENAME"}, pos)
+	}
+
+	// Parse the optional tag key and tag value.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == TAG {
+		if stmt.TagKey, err = p.ParseIdent(); err != nil {
+			return nil, err
+		}
+
+		if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "VALUE" {
+			if stmt.TagValue, err = p.parseString(); err != nil {
+				return nil, err
+			} else if stmt.TagValue == "" {
+				return nil, errors.New("tag value cannot be empty")
+			}
+		} else {
+			p.Unscan()
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse the new name.
+	if err := p.parseTokens([]Token{TO}); err != nil {
+		return nil, err
+	}
+	if stmt.TagValue != "" {
+		if stmt.NewName, err = p.parseString(); err != nil {
+			return nil, err
+		} else if stmt.NewName == "" {
+			return nil, errors.New("tag value cannot be empty")
+		}
+	} else if stmt.NewName, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
 // parseAlterRetentionPolicyStatement parses a string and returns an alter retention policy statement.
 // This function assumes the ALTER RETENTION POLICY tokens have already been consumed.
 func (p *Parser) parseAlterRetentionPolicyStatement() (*AlterRetentionPolicyStatement, error) {
@@ -748,13 +830,18 @@ func (p *Parser) parseSelectStatement(tr targetRequirement) (*SelectStatement, e
 		return nil, err
 	}
 
-	// Parse fill options: "fill(<option>)"
-	if stmt.Fill, stmt.FillValue, err = p.parseFill(); err != nil {
+	// Parse fill options: "fill(<option>[, lookback])"
+	if stmt.Fill, stmt.FillValue, stmt.FillLookback, err = p.parseFill(); err != nil {
+		return nil, err
+	}
+
+	// Parse having: "HAVING EXPR".
+	if stmt.Having, err = p.parseHaving(); err != nil {
 		return nil, err
 	}
 
 	// Parse sort: "ORDER BY FIELD+".
-	if stmt.SortFields, err = p.parseOrderBy(); err != nil {
+	if stmt.SortFields, err = p.parseSelectOrderBy(); err != nil {
 		return nil, err
 	}
 
@@ -786,7 +873,7 @@ func (p *Parser) parseSelectStatement(tr targetRequirement) (*SelectStatement, e
 	// Set if the query is a raw data query or one with an aggregate
 	stmt.IsRawQuery = true
 	WalkFunc(stmt.Fields, func(n Node) {
-		if _, ok := n.(*Call); ok {
+		if call, ok := n.(*Call); ok && !isMathFunction(call) {
 			stmt.IsRawQuery = false
 		}
 	})
@@ -859,6 +946,14 @@ func (p *Parser) parseDeleteStatement() (Statement, error) {
 
 	tok, pos, lit := p.ScanIgnoreWhitespace()
 
+	// Parse optional field: "FIELD field_key".
+	if tok == FIELD {
+		if stmt.Field, err = p.ParseIdent(); err != nil {
+			return nil, err
+		}
+		tok, pos, lit = p.ScanIgnoreWhitespace()
+	}
+
 	if tok == FROM {
 		// Parse source.
 		if stmt.Sources, err = p.parseSources(false); err != nil {
@@ -1151,6 +1246,65 @@ func (p *Parser) parseShowTagValuesStatement() (*ShowTagValuesStatement, error)
 	return stmt, nil
 }
 
+// parseShowCardinalityStatement parses the clauses of a SHOW [EXACT] ... CARDINALITY
+// statement into stmt and returns it. The WITH KEY clause is only parsed for
+// SHOW TAG VALUES CARDINALITY.
+// This function assumes the "SHOW [EXACT] ... CARDINALITY" tokens have already been consumed.
+func (p *Parser) parseShowCardinalityStatement(stmt Statement) (Statement, error) {
+	var (
+		database  *string
+		sources   *Sources
+		condition *Expr
+	)
+	switch stmt := stmt.(type) {
+	case *ShowSeriesCardinalityStatement:
+		database, sources, condition = &stmt.Database, &stmt.Sources, &stmt.Condition
+	case *ShowMeasurementCardinalityStatement:
+		database, sources, condition = &stmt.Database, &stmt.Sources, &stmt.Condition
+	case *ShowTagValuesCardinalityStatement:
+		database, sources, condition = &stmt.Database, &stmt.Sources, &stmt.Condition
+	case *ShowFieldKeyCardinalityStatement:
+		database, sources, condition = &stmt.Database, &stmt.Sources, &stmt.Condition
+	default:
+		panic(fmt.Sprintf("unsupported cardinality statement: %T", stmt))
+	}
+	var err error
+
+	// Parse optional ON clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		// Parse the database.
+		*database, err = p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional FROM.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FROM {
+		if *sources, err = p.parseSources(false); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse required WITH KEY.
+	if stmt, ok := stmt.(*ShowTagValuesCardinalityStatement); ok {
+		if stmt.Op, stmt.TagKeyExpr, err = p.parseTagKeyExpr(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse condition: "WHERE EXPR".
+	if *condition, err = p.parseCondition(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
 // parseTagKeys parses a string and returns a list of tag keys.
 func (p *Parser) parseTagKeyExpr() (Token, Literal, error) {
 	var err error
@@ -1320,6 +1474,45 @@ func (p *Parser) parseDropSeriesStatement() (*DropSeriesStatement, error) {
 	return stmt, nil
 }
 
+// parseDropFieldStatement parses a string and returns a DropFieldStatement.
+// This function assumes the "DROP FIELD" tokens have already been consumed.
+func (p *Parser) parseDropFieldStatement() (*DropFieldStatement, error) {
+	stmt := &DropFieldStatement{}
+	var err error
+
+	// Parse the name of the field to be dropped.
+	if stmt.Name, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	// Parse the measurements the field is dropped from.
+	if err := p.parseTokens([]Token{FROM}); err != nil {
+		return nil, err
+	}
+	if stmt.Sources, err = p.parseSources(false); err != nil {
+		return nil, err
+	}
+
+	WalkFunc(stmt.Sources, func(n Node) {
+		if t, ok := n.(*Measurement); ok {
+			// Don't allow database or retention policy in from clause for drop
+			// field statement.  They apply to the selected database across all
+			// retention policies.
+			if t.Database != "" {
+				err = &ParseError{Message: "database not supported"}
+			}
+			if t.RetentionPolicy != "" {
+				err = &ParseError{Message: "retention policy not supported"}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
 // parseDropShardStatement parses a string and returns a
 // DropShardStatement. This function assumes the "DROP SHARD" tokens
 // have already been consumed.
@@ -1340,6 +1533,16 @@ func (p *Parser) parseShowContinuousQueriesStatement() (*ShowContinuousQueriesSt
 	return &ShowContinuousQueriesStatement{}, nil
 }
 
+// parseShowContinuousQueryStatusStatement parses a string and returns a ShowContinuousQueryStatusStatement.
+// This function assumes the "SHOW CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseShowContinuousQueryStatusStatement() (*ShowContinuousQueryStatusStatement, error) {
+	// STATUS is not a keyword so it can still be used as an identifier.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "STATUS" {
+		return nil, newParseError(tokstr(tok, lit), []string{"STATUS"}, pos)
+	}
+	return &ShowContinuousQueryStatusStatement{}, nil
+}
+
 // parseGrantsForUserStatement parses a string and returns a ShowGrantsForUserStatement.
 // This function assumes the "SHOW GRANTS" tokens have already been consumed.
 func (p *Parser) parseGrantsForUserStatement() (*ShowGrantsForUserStatement, error) {
@@ -1393,6 +1596,13 @@ func (p *Parser) parseCreateContinuousQueryStatement() (*CreateContinuousQuerySt
 		p.Unscan()
 	}
 
+	// INCREMENTAL is not a keyword so it can still be used as an identifier.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "INCREMENTAL" {
+		stmt.Incremental = true
+	} else {
+		p.Unscan()
+	}
+
 	// Expect a "BEGIN SELECT" tokens.
 	if err := p.parseTokens([]Token{BEGIN, SELECT}); err != nil {
 		return nil, err
@@ -1681,6 +1891,88 @@ func (p *Parser) parseShowDiagnosticsStatement() (*ShowDiagnosticsStatement, err
 	return stmt, err
 }
 
+// parseBackfillContinuousQueryStatement parses a string and returns a BackfillContinuousQueryStatement.
+// This function assumes the "BACKFILL CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseBackfillContinuousQueryStatement() (*BackfillContinuousQueryStatement, error) {
+	stmt := &BackfillContinuousQueryStatement{}
+
+	// Read the name of the query to backfill.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Read the name of the database the query belongs to.
+	if ident, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	// Parse the time range.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+	if stmt.StartTime, err = p.parseBackfillTime(); err != nil {
+		return nil, err
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+	if stmt.EndTime, err = p.parseBackfillTime(); err != nil {
+		return nil, err
+	}
+
+	// Validate the time range as if the statement was executed now.
+	if _, _, err := stmt.TimeRange(time.Now()); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseBackfillTime parses the start or end time of a backfill. The time is
+// either a literal time or an expression of now(), which is returned as it is
+// so it is evaluated when the statement is executed.
+func (p *Parser) parseBackfillTime() (Expr, error) {
+	tok, _, lit := p.ScanIgnoreWhitespace()
+	p.Unscan()
+	if tok == IDENT && strings.ToLower(lit) == "now" {
+		return p.ParseExpr()
+	}
+
+	t, err := p.parseTime()
+	if err != nil {
+		return nil, err
+	}
+	return &TimeLiteral{Val: t}, nil
+}
+
+// parseTime parses a time string or an integer as a timestamp in nanoseconds.
+func (p *Parser) parseTime() (time.Time, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case STRING:
+		t, err := (&StringLiteral{Val: lit}).ToTimeLiteral(time.UTC)
+		if err != nil {
+			return time.Time{}, &ParseError{Message: fmt.Sprintf("invalid time: %s", lit), Pos: pos}
+		}
+		return t.Val.UTC(), nil
+	case INTEGER:
+		n, err := strconv.ParseInt(lit, 10, 64)
+		if err != nil {
+			return time.Time{}, &ParseError{Message: "unable to parse integer", Pos: pos}
+		}
+		return time.Unix(0, n).UTC(), nil
+	default:
+		return time.Time{}, newParseError(tokstr(tok, lit), []string{"time"}, pos)
+	}
+}
+
 // parseDropContinuousQueriesStatement parses a string and returns a DropContinuousQueryStatement.
 // This function assumes the "DROP CONTINUOUS" tokens have already been consumed.
 func (p *Parser) parseDropContinuousQueryStatement() (*DropContinuousQueryStatement, error) {
@@ -1777,6 +2069,23 @@ type validateField struct {
 }
 
 func (c *validateField) Visit(n Node) Visitor {
+	// The condition of a CASE expression is allowed to use the comparison
+	// and logical operators.
+	if w, ok := n.(*WhenClause); ok {
+		Walk(c, w.Result)
+		return nil
+	}
+
+	// The same is true for the condition of a state function.
+	if call, ok := n.(*Call); ok && isStateFunction(call) {
+		for i, arg := range call.Args {
+			if i > 0 {
+				Walk(c, arg)
+			}
+		}
+		return nil
+	}
+
 	e, ok := n.(*BinaryExpr)
 	if !ok {
 		return c
@@ -1818,6 +2127,13 @@ func (p *Parser) parseSources(subqueries bool) (Sources, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		// Joins are only allowed in queries that allow subqueries.
+		if m, ok := s.(*Measurement); ok && subqueries {
+			if s, err = p.parseJoin(m); err != nil {
+				return nil, err
+			}
+		}
 		sources = append(sources, s)
 
 		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
@@ -1912,6 +2228,72 @@ func (p *Parser) parseSource(subqueries bool) (Source, error) {
 	return m, nil
 }
 
+// parseJoin parses a join of measurements after its first measurement has
+// been parsed. The measurement is returned unchanged if it is not followed
+// by an alias or by the "JOIN" token.
+func (p *Parser) parseJoin(m *Measurement) (Source, error) {
+	alias, err := p.parseAlias()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != JOIN {
+		if alias != "" {
+			return nil, newParseError(tokstr(tok, lit), []string{"JOIN"}, pos)
+		}
+		p.Unscan()
+		return m, nil
+	}
+
+	join := &Join{Sources: []*JoinSource{{Measurement: m, Alias: alias}}}
+	for {
+		src, err := p.parseSource(false)
+		if err != nil {
+			return nil, err
+		}
+		js := &JoinSource{Measurement: src.(*Measurement)}
+		if js.Alias, err = p.parseAlias(); err != nil {
+			return nil, err
+		}
+		join.Sources = append(join.Sources, js)
+
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok == ON {
+			break
+		} else if tok != JOIN {
+			return nil, newParseError(tokstr(tok, lit), []string{"JOIN", "ON"}, pos)
+		}
+	}
+
+	// Parse the tag keys to join on.
+	for {
+		key, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		join.On = append(join.On, key)
+
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
+			p.Unscan()
+			break
+		}
+	}
+
+	names := make(map[string]struct{}, len(join.Sources))
+	for _, js := range join.Sources {
+		if js.Measurement.Regex != nil {
+			return nil, errors.New("regular expressions are not supported with JOIN")
+		}
+
+		name := js.Name()
+		if _, ok := names[name]; ok {
+			return nil, fmt.Errorf("duplicate join alias: %s", name)
+		}
+		names[name] = struct{}{}
+	}
+	return join, nil
+}
+
 // parseCondition parses the "WHERE" clause of the query, if it exists.
 func (p *Parser) parseCondition() (Expr, error) {
 	// Check if the WHERE token exists.
@@ -1929,6 +2311,16 @@ func (p *Parser) parseCondition() (Expr, error) {
 	return expr, nil
 }
 
+// parseHaving parses the "HAVING" clause of the query, if it exists.
+func (p *Parser) parseHaving() (Expr, error) {
+	// Check if the HAVING token exists.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != HAVING {
+		p.Unscan()
+		return nil, nil
+	}
+	return p.ParseExpr()
+}
+
 // parseDimensions parses the "GROUP BY" clause of the query, if it exists.
 func (p *Parser) parseDimensions() (Dimensions, error) {
 	// If the next token is not GROUP then exit.
@@ -1972,7 +2364,15 @@ func (p *Parser) parseDimension() (*Dimension, error) {
 	}
 
 	// Parse the expression first.
+	p.calendar = true
 	expr, err := p.ParseExpr()
+	p.calendar = false
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the alias of a derived dimension, if there is one.
+	alias, err := p.parseAlias()
 	if err != nil {
 		return nil, err
 	}
@@ -1980,45 +2380,58 @@ func (p *Parser) parseDimension() (*Dimension, error) {
 	// Consume all trailing whitespace.
 	p.consumeWhitespace()
 
-	return &Dimension{Expr: expr}, nil
+	return &Dimension{Expr: expr, Alias: alias}, nil
 }
 
 // parseFill parses the fill call and its options.
-func (p *Parser) parseFill() (FillOption, interface{}, error) {
+func (p *Parser) parseFill() (FillOption, interface{}, bool, error) {
 	// Parse the expression first.
 	tok, _, lit := p.ScanIgnoreWhitespace()
 	p.Unscan()
 	if tok != IDENT || strings.ToLower(lit) != "fill" {
-		return NullFill, nil, nil
+		return NullFill, nil, false, nil
 	}
 
 	expr, err := p.ParseExpr()
 	if err != nil {
-		return NullFill, nil, err
+		return NullFill, nil, false, err
 	}
 	fill, ok := expr.(*Call)
 	if !ok {
-		return NullFill, nil, errors.New("fill must be a function call")
-	} else if len(fill.Args) != 1 {
-		return NullFill, nil, errors.New("fill requires an argument, e.g.: 0, null, none, previous, linear")
+		return NullFill, nil, false, errors.New("fill must be a function call")
+	} else if len(fill.Args) != 1 && len(fill.Args) != 2 {
+		return NullFill, nil, false, errors.New("fill requires an argument, e.g.: 0, null, none, previous, linear")
 	}
+
+	// The first buckets can be filled with the last point before the
+	// time range when filling with the previous or a linear value.
+	var lookback bool
+	if len(fill.Args) == 2 {
+		if ref, ok := fill.Args[1].(*VarRef); !ok || strings.ToLower(ref.Val) != "lookback" {
+			return NullFill, nil, false, fmt.Errorf("expected lookback as the second argument in fill()")
+		} else if s := fill.Args[0].String(); s != "previous" && s != "linear" {
+			return NullFill, nil, false, errors.New("lookback can only be used with fill(previous) or fill(linear)")
+		}
+		lookback = true
+	}
+
 	switch fill.Args[0].String() {
 	case "null":
-		return NullFill, nil, nil
+		return NullFill, nil, false, nil
 	case "none":
-		return NoFill, nil, nil
+		return NoFill, nil, false, nil
 	case "previous":
-		return PreviousFill, nil, nil
+		return PreviousFill, nil, lookback, nil
 	case "linear":
-		return LinearFill, nil, nil
+		return LinearFill, nil, lookback, nil
 	default:
 		switch num := fill.Args[0].(type) {
 		case *IntegerLiteral:
-			return NumberFill, num.Val, nil
+			return NumberFill, num.Val, false, nil
 		case *NumberLiteral:
-			return NumberFill, num.Val, nil
+			return NumberFill, num.Val, false, nil
 		default:
-			return NullFill, nil, fmt.Errorf("expected number argument in fill()")
+			return NullFill, nil, false, fmt.Errorf("expected number argument in fill()")
 		}
 	}
 }
@@ -2082,7 +2495,22 @@ func (p *Parser) ParseOptionalTokenAndInt(t Token) (int, error) {
 }
 
 // parseOrderBy parses the "ORDER BY" clause of a query, if it exists.
+// Only the time may be sorted.
 func (p *Parser) parseOrderBy() (SortFields, error) {
+	fields, err := p.parseSelectOrderBy()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) > 1 || (len(fields) == 1 && fields[0].Name != "" && fields[0].Name != "time") {
+		return nil, errors.New("only ORDER BY time supported at this time")
+	}
+	return fields, nil
+}
+
+// parseSelectOrderBy parses the "ORDER BY" clause of a SELECT statement, if
+// it exists. The results may be sorted by any number of fields and tags.
+func (p *Parser) parseSelectOrderBy() (SortFields, error) {
 	// Return nil result and nil error if no ORDER token at this position.
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != ORDER {
 		p.Unscan()
@@ -2121,10 +2549,6 @@ func (p *Parser) parseSortFields() (SortFields, error) {
 			return nil, err
 		}
 
-		if lit != "time" {
-			return nil, errors.New("only ORDER BY time supported at this time")
-		}
-
 		fields = append(fields, field)
 	// Parse error...
 	default:
@@ -2148,10 +2572,6 @@ func (p *Parser) parseSortFields() (SortFields, error) {
 		fields = append(fields, field)
 	}
 
-	if len(fields) > 1 {
-		return nil, errors.New("only ORDER BY time supported at this time")
-	}
-
 	return fields, nil
 }
 
@@ -2323,6 +2743,8 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 		}
 
 		return nil, newParseError(tokstr(tok0, lit), []string{"(", "identifier"}, pos)
+	case CASE:
+		return p.parseCaseExpr()
 	case STRING:
 		return &StringLiteral{Val: lit}, nil
 	case NUMBER:
@@ -2340,6 +2762,13 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 	case TRUE, FALSE:
 		return &BooleanLiteral{Val: (tok == TRUE)}, nil
 	case DURATIONVAL:
+		if p.calendar {
+			if months, err := ParseCalendarDuration(lit); err == nil {
+				return &CalendarDurationLiteral{Months: months}, nil
+			} else if err != ErrInvalidDuration {
+				return nil, err
+			}
+		}
 		v, err := ParseDuration(lit)
 		if err != nil {
 			return nil, err
@@ -2412,6 +2841,8 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 				lit.Val *= int64(mul)
 			case *DurationLiteral:
 				lit.Val *= time.Duration(mul)
+			case *CalendarDurationLiteral:
+				lit.Months *= mul
 			case *VarRef, *Call, *ParenExpr:
 				// Multiply the variable.
 				return &BinaryExpr{
@@ -2431,6 +2862,56 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 	}
 }
 
+// parseCaseExpr parses a conditional expression.
+// This function assumes the CASE token has been consumed.
+func (p *Parser) parseCaseExpr() (*CaseExpr, error) {
+	expr := &CaseExpr{}
+
+	// Parse one or more WHEN clauses.
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok != WHEN {
+			if len(expr.WhenClauses) == 0 {
+				return nil, newParseError(tokstr(tok, lit), []string{"WHEN"}, pos)
+			}
+			p.Unscan()
+			break
+		}
+
+		cond, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != THEN {
+			return nil, newParseError(tokstr(tok, lit), []string{"THEN"}, pos)
+		}
+
+		result, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.WhenClauses = append(expr.WhenClauses, &WhenClause{Condition: cond, Result: result})
+	}
+
+	// Parse the optional ELSE clause.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok == ELSE {
+		result, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.Else = result
+
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != END {
+			return nil, newParseError(tokstr(tok, lit), []string{"END"}, pos)
+		}
+	} else if tok != END {
+		return nil, newParseError(tokstr(tok, lit), []string{"WHEN", "ELSE", "END"}, pos)
+	}
+	return expr, nil
+}
+
 // parseRegex parses a regular expression.
 func (p *Parser) parseRegex() (*RegexLiteral, error) {
 	nextRune := p.peekRune()
@@ -2686,6 +3167,51 @@ func ParseDuration(s string) (time.Duration, error) {
 	return d, nil
 }
 
+// MaxMonthDuration is the length of the longest month.
+const MaxMonthDuration = 31 * 24 * time.Hour
+
+// ParseCalendarDuration parses a duration of calendar months from a string.
+// The supported units are months (mo), quarters (q) and years (y).
+func ParseCalendarDuration(s string) (int, error) {
+	i := 0
+	for ; i < len(s) && isDigit(rune(s[i])); i++ {
+		// Scan for the digits.
+	}
+	if i == 0 {
+		return 0, ErrInvalidDuration
+	}
+
+	// Limit the number of digits so the number of months cannot overflow.
+	if i > 6 {
+		return 0, fmt.Errorf("overflowed duration %s: choose a smaller duration", s)
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return 0, ErrInvalidDuration
+	}
+
+	switch s[i:] {
+	case "mo":
+		return n, nil
+	case "q":
+		return n * 3, nil
+	case "y":
+		return n * 12, nil
+	default:
+		return 0, ErrInvalidDuration
+	}
+}
+
+// FormatCalendarDuration formats a duration of calendar months to a string.
+func FormatCalendarDuration(months int) string {
+	if months != 0 && months%12 == 0 {
+		return fmt.Sprintf("%dy", months/12)
+	} else if months != 0 && months%3 == 0 {
+		return fmt.Sprintf("%dq", months/3)
+	}
+	return fmt.Sprintf("%dmo", months)
+}
+
 // FormatDuration formats a duration to a string.
 func FormatDuration(d time.Duration) string {
 	if d == 0 {
@@ -205,6 +205,34 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// math functions
+		{
+			s: `SELECT abs(field1), pow(field2, 2) FROM myseries`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: true,
+				Fields: []*influxql.Field{
+					{Expr: &influxql.Call{Name: "abs", Args: []influxql.Expr{&influxql.VarRef{Val: "field1"}}}},
+					{Expr: &influxql.Call{Name: "pow", Args: []influxql.Expr{&influxql.VarRef{Val: "field2"}, &influxql.IntegerLiteral{Val: 2}}}},
+				},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "myseries"}},
+			},
+		},
+		{
+			s: `SELECT round(mean(field1)) FROM myseries`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: false,
+				Fields: []*influxql.Field{
+					{Expr: &influxql.Call{
+						Name: "round",
+						Args: []influxql.Expr{
+							&influxql.Call{Name: "mean", Args: []influxql.Expr{&influxql.VarRef{Val: "field1"}}},
+						},
+					}},
+				},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "myseries"}},
+			},
+		},
+
 		// derivative
 		{
 			s: `SELECT derivative(field1, 1h) FROM myseries;`,
@@ -652,21 +680,37 @@ func TestParser_ParseStatement(t *testing.T) {
 
 		// SELECT statement with multiple ORDER BY fields
 		{
-			skip: true,
-			s:    `SELECT field1 FROM myseries ORDER BY ASC, field1, field2 DESC LIMIT 10`,
+			s: `SELECT field1 FROM myseries ORDER BY ASC, field1, field2 DESC LIMIT 10`,
 			stmt: &influxql.SelectStatement{
 				IsRawQuery: true,
 				Fields:     []*influxql.Field{{Expr: &influxql.VarRef{Val: "field1"}}},
 				Sources:    []influxql.Source{&influxql.Measurement{Name: "myseries"}},
 				SortFields: []*influxql.SortField{
 					{Ascending: true},
-					{Name: "field1"},
+					{Name: "field1", Ascending: true},
 					{Name: "field2"},
 				},
 				Limit: 10,
 			},
 		},
 
+		// SELECT statement ordered by an aggregate and a tag
+		{
+			s: `SELECT max(value) FROM cpu GROUP BY host ORDER BY max DESC, host LIMIT 5`,
+			stmt: &influxql.SelectStatement{
+				Fields: []*influxql.Field{
+					{Expr: &influxql.Call{Name: "max", Args: []influxql.Expr{&influxql.VarRef{Val: "value"}}}},
+				},
+				Sources:    []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Dimensions: []*influxql.Dimension{{Expr: &influxql.VarRef{Val: "host"}}},
+				SortFields: []*influxql.SortField{
+					{Name: "max"},
+					{Name: "host", Ascending: true},
+				},
+				Limit: 5,
+			},
+		},
+
 		// SELECT statement with SLIMIT and SOFFSET
 		{
 			s: `SELECT field1 FROM myseries SLIMIT 10 SOFFSET 5`,
@@ -726,6 +770,45 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// select percentile_approx statements
+		{
+			s: `select percentile_approx("field1", 99) from cpu`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: false,
+				Fields: []*influxql.Field{
+					{Expr: &influxql.Call{Name: "percentile_approx", Args: []influxql.Expr{&influxql.VarRef{Val: "field1"}, &influxql.IntegerLiteral{Val: 99}}}},
+				},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+			},
+		},
+
+		// select histogram statements
+		{
+			s: `select histogram("field1", linear(0, 10, 20)) from cpu`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: false,
+				Fields: []*influxql.Field{
+					{Expr: &influxql.Call{Name: "histogram", Args: []influxql.Expr{
+						&influxql.VarRef{Val: "field1"},
+						&influxql.Call{Name: "linear", Args: []influxql.Expr{&influxql.IntegerLiteral{Val: 0}, &influxql.IntegerLiteral{Val: 10}, &influxql.IntegerLiteral{Val: 20}}},
+					}}},
+				},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+			},
+		},
+
+		// select count_distinct_approx statements
+		{
+			s: `select count_distinct_approx("field1") from cpu`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: false,
+				Fields: []*influxql.Field{
+					{Expr: &influxql.Call{Name: "count_distinct_approx", Args: []influxql.Expr{&influxql.VarRef{Val: "field1"}}}},
+				},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+			},
+		},
+
 		// select top statements
 		{
 			s: `select top("field1", 2) from cpu`,
@@ -1053,6 +1136,62 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SELECT statement with fill(previous, lookback)
+		{
+			s: fmt.Sprintf(`SELECT mean(value) FROM cpu where time < '%s' GROUP BY time(5m) fill(previous, lookback)`, now.UTC().Format(time.RFC3339Nano)),
+			stmt: &influxql.SelectStatement{
+				Fields: []*influxql.Field{{
+					Expr: &influxql.Call{
+						Name: "mean",
+						Args: []influxql.Expr{&influxql.VarRef{Val: "value"}}}}},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.LT,
+					LHS: &influxql.VarRef{Val: "time"},
+					RHS: &influxql.StringLiteral{Val: now.UTC().Format(time.RFC3339Nano)},
+				},
+				Dimensions:   []*influxql.Dimension{{Expr: &influxql.Call{Name: "time", Args: []influxql.Expr{&influxql.DurationLiteral{Val: 5 * time.Minute}}}}},
+				Fill:         influxql.PreviousFill,
+				FillLookback: true,
+			},
+		},
+
+		// SELECT statement with a derived dimension
+		{
+			s: `SELECT mean(value) FROM cpu GROUP BY regex_extract(host, /^(\w+)-(\w+)/, 2) AS region, map_values(host, /^web-/, 'web', 'other')`,
+			stmt: &influxql.SelectStatement{
+				Fields: []*influxql.Field{{
+					Expr: &influxql.Call{
+						Name: "mean",
+						Args: []influxql.Expr{&influxql.VarRef{Val: "value"}}}}},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Dimensions: []*influxql.Dimension{
+					{
+						Expr: &influxql.Call{
+							Name: "regex_extract",
+							Args: []influxql.Expr{
+								&influxql.VarRef{Val: "host"},
+								&influxql.RegexLiteral{Val: regexp.MustCompile(`^(\w+)-(\w+)`)},
+								&influxql.IntegerLiteral{Val: 2},
+							},
+						},
+						Alias: "region",
+					},
+					{
+						Expr: &influxql.Call{
+							Name: "map_values",
+							Args: []influxql.Expr{
+								&influxql.VarRef{Val: "host"},
+								&influxql.RegexLiteral{Val: regexp.MustCompile(`^web-`)},
+								&influxql.StringLiteral{Val: "web"},
+								&influxql.StringLiteral{Val: "other"},
+							},
+						},
+					},
+				},
+			},
+		},
+
 		// SELECT statement with FILL(none) -- check case insensitivity
 		{
 			s: fmt.Sprintf(`SELECT mean(value) FROM cpu where time < '%s' GROUP BY time(5m) FILL(none)`, now.UTC().Format(time.RFC3339Nano)),
@@ -1072,6 +1211,101 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SELECT statement with HAVING
+		{
+			s: fmt.Sprintf(`SELECT mean(value) AS m FROM cpu WHERE time < '%s' GROUP BY time(5m), host fill(none) HAVING m > 90 AND host != 'server01' LIMIT 10`, now.UTC().Format(time.RFC3339Nano)),
+			stmt: &influxql.SelectStatement{
+				Fields: []*influxql.Field{{
+					Expr: &influxql.Call{
+						Name: "mean",
+						Args: []influxql.Expr{&influxql.VarRef{Val: "value"}}},
+					Alias: "m"}},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.LT,
+					LHS: &influxql.VarRef{Val: "time"},
+					RHS: &influxql.StringLiteral{Val: now.UTC().Format(time.RFC3339Nano)},
+				},
+				Dimensions: []*influxql.Dimension{
+					{Expr: &influxql.Call{Name: "time", Args: []influxql.Expr{&influxql.DurationLiteral{Val: 5 * time.Minute}}}},
+					{Expr: &influxql.VarRef{Val: "host"}},
+				},
+				Fill: influxql.NoFill,
+				Having: &influxql.BinaryExpr{
+					Op: influxql.AND,
+					LHS: &influxql.BinaryExpr{
+						Op:  influxql.GT,
+						LHS: &influxql.VarRef{Val: "m"},
+						RHS: &influxql.IntegerLiteral{Val: 90},
+					},
+					RHS: &influxql.BinaryExpr{
+						Op:  influxql.NEQ,
+						LHS: &influxql.VarRef{Val: "host"},
+						RHS: &influxql.StringLiteral{Val: "server01"},
+					},
+				},
+				Limit: 10,
+			},
+		},
+
+		// SELECT statement with CASE expression
+		{
+			s: `SELECT CASE WHEN status >= 500 THEN 1 WHEN host = 'server01' THEN 2 ELSE 0 END AS class FROM cpu`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: true,
+				Fields: []*influxql.Field{{
+					Expr: &influxql.CaseExpr{
+						WhenClauses: []*influxql.WhenClause{
+							{
+								Condition: &influxql.BinaryExpr{
+									Op:  influxql.GTE,
+									LHS: &influxql.VarRef{Val: "status"},
+									RHS: &influxql.IntegerLiteral{Val: 500},
+								},
+								Result: &influxql.IntegerLiteral{Val: 1},
+							},
+							{
+								Condition: &influxql.BinaryExpr{
+									Op:  influxql.EQ,
+									LHS: &influxql.VarRef{Val: "host"},
+									RHS: &influxql.StringLiteral{Val: "server01"},
+								},
+								Result: &influxql.IntegerLiteral{Val: 2},
+							},
+						},
+						Else: &influxql.IntegerLiteral{Val: 0},
+					},
+					Alias: "class"}},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+			},
+		},
+
+		// SELECT statement with CASE expression within an aggregate
+		{
+			s: `SELECT sum(CASE WHEN status >= 500 THEN value * 2 END) FROM cpu`,
+			stmt: &influxql.SelectStatement{
+				Fields: []*influxql.Field{{
+					Expr: &influxql.Call{
+						Name: "sum",
+						Args: []influxql.Expr{&influxql.CaseExpr{
+							WhenClauses: []*influxql.WhenClause{{
+								Condition: &influxql.BinaryExpr{
+									Op:  influxql.GTE,
+									LHS: &influxql.VarRef{Val: "status"},
+									RHS: &influxql.IntegerLiteral{Val: 500},
+								},
+								Result: &influxql.BinaryExpr{
+									Op:  influxql.MUL,
+									LHS: &influxql.VarRef{Val: "value"},
+									RHS: &influxql.IntegerLiteral{Val: 2},
+								},
+							}},
+						}},
+					}}},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+			},
+		},
+
 		// SELECT statement with previous fill
 		{
 			s: fmt.Sprintf(`SELECT mean(value) FROM cpu where time < '%s' GROUP BY time(5m) FILL(previous)`, now.UTC().Format(time.RFC3339Nano)),
@@ -1274,6 +1508,70 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SELECT statement with a join
+		{
+			s: `SELECT a.value + b.value, c.value FROM cpu AS a JOIN mem AS b JOIN db.rp.disk AS c ON host, region`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: true,
+				Fields: []*influxql.Field{
+					{Expr: &influxql.BinaryExpr{
+						Op:  influxql.ADD,
+						LHS: &influxql.VarRef{Val: "a.value"},
+						RHS: &influxql.VarRef{Val: "b.value"},
+					}},
+					{Expr: &influxql.VarRef{Val: "c.value"}},
+				},
+				Sources: []influxql.Source{
+					&influxql.Join{
+						Sources: []*influxql.JoinSource{
+							{Measurement: &influxql.Measurement{Name: "cpu"}, Alias: "a"},
+							{Measurement: &influxql.Measurement{Name: "mem"}, Alias: "b"},
+							{Measurement: &influxql.Measurement{Database: "db", RetentionPolicy: "rp", Name: "disk"}, Alias: "c"},
+						},
+						On: []string{"host", "region"},
+					},
+				},
+			},
+		},
+
+		// SELECT statement with a join grouped by time
+		{
+			s: `SELECT mean(cpu.value) / mean(mem.value) FROM cpu JOIN mem ON host WHERE time > now() - 1h GROUP BY time(1m)`,
+			stmt: &influxql.SelectStatement{
+				Fields: []*influxql.Field{{
+					Expr: &influxql.BinaryExpr{
+						Op:  influxql.DIV,
+						LHS: &influxql.Call{Name: "mean", Args: []influxql.Expr{&influxql.VarRef{Val: "cpu.value"}}},
+						RHS: &influxql.Call{Name: "mean", Args: []influxql.Expr{&influxql.VarRef{Val: "mem.value"}}},
+					},
+				}},
+				Sources: []influxql.Source{
+					&influxql.Join{
+						Sources: []*influxql.JoinSource{
+							{Measurement: &influxql.Measurement{Name: "cpu"}},
+							{Measurement: &influxql.Measurement{Name: "mem"}},
+						},
+						On: []string{"host"},
+					},
+				},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.GT,
+					LHS: &influxql.VarRef{Val: "time"},
+					RHS: &influxql.BinaryExpr{
+						Op:  influxql.SUB,
+						LHS: &influxql.Call{Name: "now"},
+						RHS: &influxql.DurationLiteral{Val: time.Hour},
+					},
+				},
+				Dimensions: []*influxql.Dimension{{
+					Expr: &influxql.Call{
+						Name: "time",
+						Args: []influxql.Expr{&influxql.DurationLiteral{Val: time.Minute}},
+					},
+				}},
+			},
+		},
+
 		{
 			s: `SELECT sum(mean) FROM (SELECT mean(value) FROM cpu WHERE time >= now() - 1d GROUP BY time(1h))`,
 			stmt: &influxql.SelectStatement{
@@ -1437,6 +1735,100 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SELECT statement with a calendar interval
+		{
+			s: `SELECT sum(value) FROM cpu WHERE time >= now() - 1d GROUP BY time(1mo), host tz('America/Los_Angeles')`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: false,
+				Fields: []*influxql.Field{{
+					Expr: &influxql.Call{
+						Name: "sum",
+						Args: []influxql.Expr{
+							&influxql.VarRef{Val: "value"}},
+					}}},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.GTE,
+					LHS: &influxql.VarRef{Val: "time"},
+					RHS: &influxql.BinaryExpr{
+						Op:  influxql.SUB,
+						LHS: &influxql.Call{Name: "now"},
+						RHS: &influxql.DurationLiteral{Val: 24 * time.Hour},
+					},
+				},
+				Dimensions: []*influxql.Dimension{
+					{Expr: &influxql.Call{
+						Name: "time",
+						Args: []influxql.Expr{
+							&influxql.CalendarDurationLiteral{Months: 1}}}},
+					{Expr: &influxql.VarRef{Val: "host"}},
+				},
+				Location: LosAngeles,
+			},
+		},
+		{
+			s: `SELECT sum(value) FROM cpu WHERE time >= now() - 1d GROUP BY time(2q, 1d)`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: false,
+				Fields: []*influxql.Field{{
+					Expr: &influxql.Call{
+						Name: "sum",
+						Args: []influxql.Expr{
+							&influxql.VarRef{Val: "value"}},
+					}}},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.GTE,
+					LHS: &influxql.VarRef{Val: "time"},
+					RHS: &influxql.BinaryExpr{
+						Op:  influxql.SUB,
+						LHS: &influxql.Call{Name: "now"},
+						RHS: &influxql.DurationLiteral{Val: 24 * time.Hour},
+					},
+				},
+				Dimensions: []*influxql.Dimension{{
+					Expr: &influxql.Call{
+						Name: "time",
+						Args: []influxql.Expr{
+							&influxql.CalendarDurationLiteral{Months: 6},
+							&influxql.DurationLiteral{Val: 24 * time.Hour}}}}},
+			},
+		},
+
+		// SELECT statement with overlapping windows
+		{
+			s: `SELECT mean(value) FROM cpu WHERE time >= now() - 1d GROUP BY time(10m, 30s, step(1m))`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: false,
+				Fields: []*influxql.Field{{
+					Expr: &influxql.Call{
+						Name: "mean",
+						Args: []influxql.Expr{
+							&influxql.VarRef{Val: "value"}},
+					}}},
+				Sources: []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.GTE,
+					LHS: &influxql.VarRef{Val: "time"},
+					RHS: &influxql.BinaryExpr{
+						Op:  influxql.SUB,
+						LHS: &influxql.Call{Name: "now"},
+						RHS: &influxql.DurationLiteral{Val: 24 * time.Hour},
+					},
+				},
+				Dimensions: []*influxql.Dimension{{
+					Expr: &influxql.Call{
+						Name: "time",
+						Args: []influxql.Expr{
+							&influxql.DurationLiteral{Val: 10 * time.Minute},
+							&influxql.DurationLiteral{Val: 30 * time.Second},
+							&influxql.Call{
+								Name: "step",
+								Args: []influxql.Expr{&influxql.DurationLiteral{Val: time.Minute}},
+							}}}}},
+			},
+		},
+
 		// See issues https://github.com/influxdata/influxdb/issues/1647
 		// and https://github.com/influxdata/influxdb/issues/4404
 		// DELETE statement
@@ -1597,6 +1989,37 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// EXPLAIN SELECT
+		{
+			s: `EXPLAIN SELECT mean(value) FROM cpu GROUP BY host`,
+			stmt: &influxql.ExplainStatement{
+				Statement: &influxql.SelectStatement{
+					IsRawQuery: false,
+					Fields: []*influxql.Field{{
+						Expr: &influxql.Call{
+							Name: "mean",
+							Args: []influxql.Expr{&influxql.VarRef{Val: "value"}},
+						},
+					}},
+					Sources:    []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+					Dimensions: []*influxql.Dimension{{Expr: &influxql.VarRef{Val: "host"}}},
+				},
+			},
+		},
+
+		// EXPLAIN ANALYZE SELECT
+		{
+			s: `EXPLAIN ANALYZE SELECT value FROM cpu`,
+			stmt: &influxql.ExplainStatement{
+				Statement: &influxql.SelectStatement{
+					IsRawQuery: true,
+					Fields:     []*influxql.Field{{Expr: &influxql.VarRef{Val: "value"}}},
+					Sources:    []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				},
+				Analyze: true,
+			},
+		},
+
 		// SHOW RETENTION POLICIES
 		{
 			s:    `SHOW RETENTION POLICIES`,
@@ -1877,11 +2300,107 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SHOW SERIES CARDINALITY
+		{
+			s:    `SHOW SERIES CARDINALITY`,
+			stmt: &influxql.ShowSeriesCardinalityStatement{},
+		},
+		{
+			s: `SHOW EXACT SERIES CARDINALITY ON db0 FROM cpu WHERE host = 'serverA'`,
+			stmt: &influxql.ShowSeriesCardinalityStatement{
+				Database: "db0",
+				Exact:    true,
+				Sources:  []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.EQ,
+					LHS: &influxql.VarRef{Val: "host"},
+					RHS: &influxql.StringLiteral{Val: "serverA"},
+				},
+			},
+		},
+
+		// SHOW MEASUREMENT CARDINALITY
+		{
+			s:    `SHOW MEASUREMENT CARDINALITY ON db0`,
+			stmt: &influxql.ShowMeasurementCardinalityStatement{Database: "db0"},
+		},
+		{
+			s: `SHOW EXACT MEASUREMENT CARDINALITY FROM /[cg]pu/`,
+			stmt: &influxql.ShowMeasurementCardinalityStatement{
+				Exact: true,
+				Sources: []influxql.Source{
+					&influxql.Measurement{
+						Regex: &influxql.RegexLiteral{Val: regexp.MustCompile(`[cg]pu`)},
+					},
+				},
+			},
+		},
+
+		// SHOW TAG VALUES CARDINALITY
+		{
+			s: `SHOW TAG VALUES CARDINALITY FROM cpu WITH KEY = host WHERE region = 'uswest'`,
+			stmt: &influxql.ShowTagValuesCardinalityStatement{
+				Sources:    []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Op:         influxql.EQ,
+				TagKeyExpr: &influxql.StringLiteral{Val: "host"},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.EQ,
+					LHS: &influxql.VarRef{Val: "region"},
+					RHS: &influxql.StringLiteral{Val: "uswest"},
+				},
+			},
+		},
+		{
+			s: `SHOW EXACT TAG VALUES CARDINALITY ON db0 WITH KEY IN (host, region)`,
+			stmt: &influxql.ShowTagValuesCardinalityStatement{
+				Database:   "db0",
+				Exact:      true,
+				Op:         influxql.IN,
+				TagKeyExpr: &influxql.ListLiteral{Vals: []string{"host", "region"}},
+			},
+		},
+
+		// SHOW FIELD KEY CARDINALITY
+		{
+			s:    `SHOW FIELD KEY CARDINALITY`,
+			stmt: &influxql.ShowFieldKeyCardinalityStatement{},
+		},
+		{
+			s: `SHOW FIELD KEY CARDINALITY WHERE host = 'serverA'`,
+			stmt: &influxql.ShowFieldKeyCardinalityStatement{
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.EQ,
+					LHS: &influxql.VarRef{Val: "host"},
+					RHS: &influxql.StringLiteral{Val: "serverA"},
+				},
+			},
+		},
+		{
+			s: `SHOW EXACT FIELD KEY CARDINALITY ON db0 FROM cpu`,
+			stmt: &influxql.ShowFieldKeyCardinalityStatement{
+				Database: "db0",
+				Exact:    true,
+				Sources:  []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+			},
+		},
+
 		// DELETE statement
 		{
 			s:    `DELETE FROM src`,
 			stmt: &influxql.DeleteSeriesStatement{Sources: []influxql.Source{&influxql.Measurement{Name: "src"}}},
 		},
+		{
+			s: `DELETE FIELD value FROM src WHERE time < now()`,
+			stmt: &influxql.DeleteSeriesStatement{
+				Field:   "value",
+				Sources: []influxql.Source{&influxql.Measurement{Name: "src"}},
+				Condition: &influxql.BinaryExpr{
+					Op:  influxql.LT,
+					LHS: &influxql.VarRef{Val: "time"},
+					RHS: &influxql.Call{Name: "now"},
+				},
+			},
+		},
 		{
 			s: `DELETE WHERE host = 'hosta.influxdb.org'`,
 			stmt: &influxql.DeleteSeriesStatement{
@@ -1904,6 +2423,18 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// DROP FIELD statement
+		{
+			s: `DROP FIELD "value" FROM src, /^cpu/`,
+			stmt: &influxql.DropFieldStatement{
+				Name: "value",
+				Sources: []influxql.Source{
+					&influxql.Measurement{Name: "src"},
+					&influxql.Measurement{Regex: &influxql.RegexLiteral{Val: regexp.MustCompile(`^cpu`)}},
+				},
+			},
+		},
+
 		// DROP SERIES statement
 		{
 			s:    `DROP SERIES FROM src`,
@@ -1937,6 +2468,16 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: &influxql.ShowContinuousQueriesStatement{},
 		},
 
+		// SHOW CONTINUOUS QUERY STATUS statement
+		{
+			s:    `SHOW CONTINUOUS QUERY STATUS`,
+			stmt: &influxql.ShowContinuousQueryStatusStatement{},
+		},
+		{
+			s:    `show continuous query status`,
+			stmt: &influxql.ShowContinuousQueryStatusStatement{},
+		},
+
 		// CREATE CONTINUOUS QUERY ... INTO <measurement>
 		{
 			s: `CREATE CONTINUOUS QUERY myquery ON testdb RESAMPLE EVERY 1m FOR 1h BEGIN SELECT count(field1) INTO measure1 FROM myseries GROUP BY time(5m) END`,
@@ -2011,6 +2552,32 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		{
+			s: `CREATE CONTINUOUS QUERY myquery ON testdb RESAMPLE FOR 1h INCREMENTAL BEGIN SELECT count(field1) INTO measure1 FROM myseries GROUP BY time(5m), host END`,
+			stmt: &influxql.CreateContinuousQueryStatement{
+				Name:     "myquery",
+				Database: "testdb",
+				Source: &influxql.SelectStatement{
+					Fields:  []*influxql.Field{{Expr: &influxql.Call{Name: "count", Args: []influxql.Expr{&influxql.VarRef{Val: "field1"}}}}},
+					Target:  &influxql.Target{Measurement: &influxql.Measurement{Name: "measure1", IsTarget: true}},
+					Sources: []influxql.Source{&influxql.Measurement{Name: "myseries"}},
+					Dimensions: []*influxql.Dimension{
+						{
+							Expr: &influxql.Call{
+								Name: "time",
+								Args: []influxql.Expr{
+									&influxql.DurationLiteral{Val: 5 * time.Minute},
+								},
+							},
+						},
+						{Expr: &influxql.VarRef{Val: "host"}},
+					},
+				},
+				ResampleFor: time.Hour,
+				Incremental: true,
+			},
+		},
+
 		{
 			s: `create continuous query "this.is-a.test" on segments begin select * into measure1 from cpu_load_short end`,
 			stmt: &influxql.CreateContinuousQueryStatement{
@@ -2202,6 +2769,39 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// BACKFILL CONTINUOUS QUERY statement
+		{
+			s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM '2017-01-01T00:00:00Z' TO '2017-02-01T00:00:00Z'`,
+			stmt: &influxql.BackfillContinuousQueryStatement{
+				Name:      "myquery",
+				Database:  "foo",
+				StartTime: &influxql.TimeLiteral{Val: mustParseTime("2017-01-01T00:00:00Z")},
+				EndTime:   &influxql.TimeLiteral{Val: mustParseTime("2017-02-01T00:00:00Z")},
+			},
+		},
+		{
+			s: `BACKFILL CONTINUOUS QUERY "my query" ON "foo" FROM '2017-01-01' TO 1485907200000000000`,
+			stmt: &influxql.BackfillContinuousQueryStatement{
+				Name:      "my query",
+				Database:  "foo",
+				StartTime: &influxql.TimeLiteral{Val: mustParseTime("2017-01-01T00:00:00Z")},
+				EndTime:   &influxql.TimeLiteral{Val: mustParseTime("2017-02-01T00:00:00Z")},
+			},
+		},
+		{
+			s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM now() - 1h TO now()`,
+			stmt: &influxql.BackfillContinuousQueryStatement{
+				Name:     "myquery",
+				Database: "foo",
+				StartTime: &influxql.BinaryExpr{
+					Op:  influxql.SUB,
+					LHS: &influxql.Call{Name: "now"},
+					RHS: &influxql.DurationLiteral{Val: time.Hour},
+				},
+				EndTime: &influxql.Call{Name: "now"},
+			},
+		},
+
 		// DROP CONTINUOUS QUERY statement
 		{
 			s:    `DROP CONTINUOUS QUERY myquery ON foo`,
@@ -2414,6 +3014,20 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// ALTER MEASUREMENT
+		{
+			s:    `ALTER MEASUREMENT cpu RENAME TO "cpu v2"`,
+			stmt: &influxql.AlterMeasurementStatement{Name: "cpu", NewName: "cpu v2"},
+		},
+		{
+			s:    `ALTER MEASUREMENT cpu RENAME TAG host TO hostname`,
+			stmt: &influxql.AlterMeasurementStatement{Name: "cpu", TagKey: "host", NewName: "hostname"},
+		},
+		{
+			s:    `ALTER MEASUREMENT cpu rename tag host value 'serverA' TO 'server-a'`,
+			stmt: &influxql.AlterMeasurementStatement{Name: "cpu", TagKey: "host", TagValue: "serverA", NewName: "server-a"},
+		},
+
 		// ALTER RETENTION POLICY
 		{
 			s:    `ALTER RETENTION POLICY policy1 ON testdb DURATION 1m REPLICATION 4 DEFAULT`,
@@ -2537,10 +3151,10 @@ func TestParser_ParseStatement(t *testing.T) {
 		},
 
 		// Errors
-		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, BACKFILL, CREATE, DROP, GRANT, REVOKE, ALTER, SET, KILL, EXPLAIN at line 1, char 1`},
 		{s: `SELECT`, err: `found EOF, expected identifier, string, number, bool at line 1, char 8`},
 		{s: `SELECT time FROM myseries`, err: `at least 1 non-time field must be queried`},
-		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, BACKFILL, CREATE, DROP, GRANT, REVOKE, ALTER, SET, KILL, EXPLAIN at line 1, char 1`},
 		{s: `SELECT field1 X`, err: `found X, expected FROM at line 1, char 15`},
 		{s: `SELECT field1 FROM "series" WHERE X +;`, err: `found ;, expected identifier, string, number, bool at line 1, char 38`},
 		{s: `SELECT field1 FROM myseries GROUP`, err: `found EOF, expected BY at line 1, char 35`},
@@ -2580,6 +3194,40 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SELECT percentile(field1) FROM myseries`, err: `invalid number of arguments for percentile, expected 2, got 1`},
 		{s: `SELECT percentile(field1, foo) FROM myseries`, err: `expected float argument in percentile()`},
 		{s: `SELECT percentile(max(field1), 75) FROM myseries`, err: `expected field argument in percentile()`},
+		{s: `SELECT percentile_approx(field1) FROM myseries`, err: `invalid number of arguments for percentile_approx, expected 2, got 1`},
+		{s: `SELECT percentile_approx(field1, foo) FROM myseries`, err: `expected float argument in percentile_approx()`},
+		{s: `SELECT percentile_approx(max(field1), 75) FROM myseries`, err: `expected field argument in percentile_approx()`},
+		{s: `SELECT percentile_approx(field1, 101) FROM myseries`, err: `percentile must be between 0 and 100 in percentile_approx(), got 101`},
+		{s: `SELECT percentile_approx(field1, -0.5) FROM myseries`, err: `percentile must be between 0 and 100 in percentile_approx(), got -0.5`},
+		{s: `SELECT histogram(field1) FROM myseries`, err: `invalid number of arguments for histogram, expected 2, got 1`},
+		{s: `SELECT histogram(field1, 10) FROM myseries`, err: `expected bucket argument in histogram()`},
+		{s: `SELECT histogram(field1, buckets(0, 10)) FROM myseries`, err: `expected linear(), exponential() or fixed() bucket argument in histogram(), got buckets()`},
+		{s: `SELECT histogram(field1, linear(0, 10)) FROM myseries`, err: `invalid number of arguments for linear, expected 3, got 2`},
+		{s: `SELECT histogram(field1, linear(0, 0, 10)) FROM myseries`, err: `second argument to linear must be greater than 0`},
+		{s: `SELECT histogram(field1, linear(0, 10, 2000)) FROM myseries`, err: `histogram cannot have more than 1000 buckets`},
+		{s: `SELECT histogram(field1, exponential(0, 2, 10)) FROM myseries`, err: `first argument to exponential must be greater than 0`},
+		{s: `SELECT histogram(field1, exponential(1, 1, 10)) FROM myseries`, err: `second argument to exponential must be greater than 1`},
+		{s: `SELECT histogram(field1, fixed(10, 5)) FROM myseries`, err: `arguments to fixed must be in increasing order`},
+		{s: `SELECT histogram(field1, fixed(host, 5)) FROM myseries`, err: `expected number argument in fixed()`},
+		{s: `SELECT histogram(field1, linear(0, 10, 20)) + 1 FROM myseries`, err: `histogram() cannot be used within an expression`},
+		{s: `SELECT histogram(max(field1), linear(0, 10, 20)) FROM myseries`, err: `expected field argument in histogram()`},
+		{s: `SELECT field1 FROM myseries HAVING field1 > 10`, err: `HAVING requires at least one aggregate function`},
+		{s: `SELECT mean(field1) FROM myseries HAVING time > now()`, err: `cannot use time in HAVING clause`},
+		{s: `SELECT mean(field1) FROM myseries HAVING host =~ /server/ AND time > now()`, err: `cannot use time in HAVING clause`},
+		{s: `SELECT mean(field1) FROM myseries HAVING`, err: `found EOF, expected identifier, string, number, bool at line 1, char 42`},
+		{s: `SELECT CASE value THEN 1 END FROM myseries`, err: `found value, expected WHEN at line 1, char 13`},
+		{s: `SELECT CASE WHEN value > 1 ELSE 1 END FROM myseries`, err: `found ELSE, expected THEN at line 1, char 28`},
+		{s: `SELECT CASE WHEN value > 1 THEN 1 FROM myseries`, err: `found FROM, expected WHEN, ELSE, END at line 1, char 35`},
+		{s: `SELECT CASE WHEN value > 1 THEN 1 ELSE 0 FROM myseries`, err: `found FROM, expected END at line 1, char 42`},
+		{s: `SELECT CASE WHEN mean(value) > 1 THEN 1 END FROM myseries`, err: `mean() cannot be used within a CASE expression`},
+		{s: `SELECT sum(CASE WHEN value > 1 THEN max(value) END) FROM myseries`, err: `max() cannot be used within a CASE expression`},
+		{s: `SELECT abs() FROM myseries`, err: `invalid number of arguments for abs, expected 1, got 0`},
+		{s: `SELECT abs(-1) FROM myseries`, err: `expected field argument in abs()`},
+		{s: `SELECT pow(2, abs(3)) FROM myseries`, err: `expected field argument in pow()`},
+		{s: `SELECT pow(field1) FROM myseries`, err: `invalid number of arguments for pow, expected 2, got 1`},
+		{s: `SELECT sqrt('value') FROM myseries`, err: `invalid argument for sqrt(): 'value'`},
+		{s: `SELECT abs(mean(value)), value FROM myseries`, err: `mixing aggregate and non-aggregate queries is not supported`},
+		{s: `SELECT mean(abs(value)) FROM myseries`, err: `expected field argument in mean()`},
 		{s: `SELECT field1 FROM myseries OFFSET`, err: `found EOF, expected integer at line 1, char 36`},
 		{s: `SELECT field1 FROM myseries OFFSET 10.5`, err: `found 10.5, expected integer at line 1, char 36`},
 		{s: `SELECT field1 FROM myseries ORDER`, err: `found EOF, expected BY at line 1, char 35`},
@@ -2587,11 +3235,35 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SELECT field1 FROM myseries ORDER BY /`, err: `found /, expected identifier, ASC, DESC at line 1, char 38`},
 		{s: `SELECT field1 FROM myseries ORDER BY 1`, err: `found 1, expected identifier, ASC, DESC at line 1, char 38`},
 		{s: `SELECT field1 FROM myseries ORDER BY time ASC,`, err: `found EOF, expected identifier at line 1, char 47`},
-		{s: `SELECT field1 FROM myseries ORDER BY time, field1`, err: `only ORDER BY time supported at this time`},
+		{s: `SHOW MEASUREMENTS ORDER BY time, field1`, err: `only ORDER BY time supported at this time`},
+		{s: `SELECT value FROM (SELECT value FROM cpu ORDER BY value DESC)`, err: `cannot ORDER BY a field or tag in a subquery`},
 		{s: `SELECT field1 AS`, err: `found EOF, expected identifier at line 1, char 18`},
 		{s: `SELECT field1 FROM foo group by time(1s)`, err: `GROUP BY requires at least one aggregate function`},
 		{s: `SELECT field1 FROM foo fill(none)`, err: `fill(none) must be used with a function`},
+		{s: `SELECT a.value FROM cpu AS a`, err: `found EOF, expected JOIN at line 1, char 30`},
+		{s: `SELECT a.value FROM cpu AS a JOIN mem AS b`, err: `found EOF, expected JOIN, ON at line 1, char 44`},
+		{s: `SELECT a.value FROM cpu AS a JOIN mem AS a ON host`, err: `duplicate join alias: a`},
+		{s: `SELECT a.value FROM cpu AS a JOIN /m/ AS b ON host`, err: `regular expressions are not supported with JOIN`},
+		{s: `SELECT value FROM cpu AS a JOIN mem AS b ON host`, err: `field value must be qualified with a join alias`},
+		{s: `SELECT * FROM cpu AS a JOIN mem AS b ON host`, err: `wildcards are not supported with JOIN`},
+		{s: `SELECT a.value / b.value FROM cpu AS a JOIN mem AS b ON host GROUP BY time(1m)`, err: `GROUP BY requires at least one aggregate function`},
+		{s: `SELECT a.value FROM disk, cpu AS a JOIN mem AS b ON host`, err: `JOIN cannot be combined with other sources`},
+		{s: `SELECT mean(a.value) FROM cpu AS a JOIN mem AS b ON host WHERE a.value > b.value`, err: `condition must not reference more than one join source: "a.value" > "b.value"`},
 		{s: `SELECT field1 FROM foo fill(linear)`, err: `fill(linear) must be used with a function`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY host AS server`, err: `only regex_extract() and map_values() dimensions can have an alias`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY host, regex_extract(host, /^(\w+)-/)`, err: `duplicate dimension: host`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY regex_extract(host, /^(\w+)-/) AS dc, map_values(host, 'a', 'b') AS dc`, err: `duplicate dimension: dc`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY regex_extract('host', /^(\w+)-/)`, err: `expected tag argument in regex_extract()`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY regex_extract(host)`, err: `invalid number of arguments for regex_extract, expected at least 2 but no more than 3, got 1`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY regex_extract(host, 'web')`, err: `second argument to regex_extract must be a regular expression, got *influxql.StringLiteral`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY regex_extract(host, /^(\w+)-/, 2)`, err: `capture group for regex_extract must be between 0 and 1, got 2`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY map_values(host, 'a')`, err: `invalid number of arguments for map_values, expected at least 3, got 2`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY map_values(host, 1, 'a')`, err: `expected string or regular expression to match in map_values(), got 1`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY map_values(host, 'a', /b/)`, err: `expected string value in map_values(), got /b/`},
+		{s: `SELECT mean(field1) FROM foo GROUP BY map_values(host, 'a', 'b', 1)`, err: `expected string default value in map_values(), got 1`},
+		{s: `SELECT mean(field1) FROM foo WHERE time > now() - 1h GROUP BY time(1m) fill(0, lookback)`, err: `lookback can only be used with fill(previous) or fill(linear)`},
+		{s: `SELECT mean(field1) FROM foo WHERE time > now() - 1h GROUP BY time(1m) fill(previous, 1h)`, err: `expected lookback as the second argument in fill()`},
+		{s: `SELECT mean(field1) FROM foo WHERE time > now() - 1h GROUP BY time(1m) fill(previous, lookback, 1)`, err: `fill requires an argument, e.g.: 0, null, none, previous, linear`},
 		{s: `SELECT count(value), value FROM foo`, err: `mixing aggregate and non-aggregate queries is not supported`},
 		{s: `SELECT count(value)/10, value FROM foo`, err: `mixing aggregate and non-aggregate queries is not supported`},
 		{s: `SELECT count(value) FROM foo group by time(1s)`, err: `aggregate functions with GROUP BY time require a WHERE time clause`},
@@ -2599,8 +3271,48 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SELECT count(value) FROM foo group by time(1s) where host = 'hosta.influxdb.org'`, err: `aggregate functions with GROUP BY time require a WHERE time clause`},
 		{s: `SELECT count(value) FROM foo group by time`, err: `time() is a function and expects at least one argument`},
 		{s: `SELECT count(value) FROM foo group by 'time'`, err: `only time and tag dimensions allowed`},
-		{s: `SELECT count(value) FROM foo where time > now() and time < now() group by time()`, err: `time dimension expected 1 or 2 arguments`},
+		{s: `SELECT count(value) FROM foo where time > now() and time < now() group by time()`, err: `time dimension expected 1 to 3 arguments`},
 		{s: `SELECT count(value) FROM foo where time > now() and time < now() group by time(b)`, err: `time dimension must have duration argument`},
+		{s: `SELECT count(value) FROM foo group by time(0mo)`, err: `time dimension must have a positive calendar duration`},
+		{s: `SELECT count(value) FROM foo where time > now() - 1h group by time(10m, 1m, 2m)`, err: `time dimension third argument must be step()`},
+		{s: `SELECT count(value) FROM foo where time > now() - 1h group by time(10m, step(b))`, err: `step() requires a duration argument`},
+		{s: `SELECT count(value) FROM foo where time > now() - 1h group by time(10m, step(0s))`, err: `step() duration must be greater than zero`},
+		{s: `SELECT count(value) FROM foo where time > now() - 1h group by time(10m, step(3m))`, err: `time dimension interval must be a multiple of the step() duration`},
+		{s: `SELECT count(value) FROM foo group by time(1mo, step(1d))`, err: `step() cannot be used with a calendar interval`},
+		{s: `SELECT time_weighted_avg(value) FROM foo`, err: `invalid number of arguments for time_weighted_avg, expected 2, got 1`},
+		{s: `SELECT time_weighted_avg(value, 'cubic') FROM foo`, err: `second argument to time_weighted_avg must be 'step' or 'linear'`},
+		{s: `SELECT time_weighted_avg(value * 2, 'step') FROM foo`, err: `expected field argument in time_weighted_avg()`},
+		{s: `SELECT time_weighted_avg(value, 'step') FROM foo where time > now() - 1h group by time(10m, step(1m))`, err: `time_weighted_avg does not support overlapping windows`},
+		{s: `SELECT corr(value) FROM foo`, err: `invalid number of arguments for corr, expected 2, got 1`},
+		{s: `SELECT covar(value, 2) FROM foo`, err: `expected field arguments in covar()`},
+		{s: `SELECT linear_regression(value, 'slope', 'r2') FROM foo`, err: `invalid number of arguments for linear_regression, expected at least 1 but no more than 2, got 3`},
+		{s: `SELECT linear_regression(value, 'median') FROM foo`, err: `second argument to linear_regression must be 'slope', 'intercept' or 'r2'`},
+		{s: `SELECT linear_regression(value), value FROM foo`, err: `mixing aggregate and non-aggregate queries is not supported`},
+		{s: `SELECT zscore(value) FROM foo`, err: `invalid number of arguments for zscore, expected 2, got 1`},
+		{s: `SELECT zscore(value, 2) FROM foo`, err: `zscore window must be greater than 2, got 2`},
+		{s: `SELECT zscore(value, 10) FROM foo where time > now() - 1h group by time(1m)`, err: `aggregate function required inside the call to zscore`},
+		{s: `SELECT mad_outliers(value) FROM foo`, err: `invalid number of arguments for mad_outliers, expected 2, got 1`},
+		{s: `SELECT mad_outliers(value, 'high') FROM foo`, err: `expected float argument in mad_outliers()`},
+		{s: `SELECT mad_outliers(value, -1) FROM foo`, err: `threshold for mad_outliers must be greater than 0, got -1`},
+		{s: `SELECT outliers(value, 3) FROM foo`, err: `expected aggregate argument in outliers()`},
+		{s: `SELECT outliers(mean(value), 3, 'stddev') FROM foo`, err: `third argument to outliers must be 'mad' or 'iqr'`},
+		{s: `SELECT lttb(value) FROM foo`, err: `invalid number of arguments for lttb, expected 2, got 1`},
+		{s: `SELECT lttb(value, 2) FROM foo`, err: `lttb must select at least 3 points, got 2`},
+		{s: `SELECT lttb(value, 1.5) FROM foo`, err: `expected integer argument in lttb()`},
+		{s: `SELECT state_count(status = 'down', 1s) FROM foo`, err: `invalid number of arguments for state_count, expected at least 1 but no more than 1, got 2`},
+		{s: `SELECT state_duration(status) FROM foo`, err: `expected condition argument in state_duration()`},
+		{s: `SELECT state_duration(value + 1) FROM foo`, err: `expected condition argument in state_duration()`},
+		{s: `SELECT state_duration(mean(value) > 1) FROM foo`, err: `mean() cannot be used within the condition of state_duration()`},
+		{s: `SELECT state_duration(status = 'down', 10) FROM foo`, err: `second argument to state_duration must be a duration, got *influxql.IntegerLiteral`},
+		{s: `SELECT rate(value, 1s, 100, 1) FROM foo`, err: `invalid number of arguments for rate, expected at least 1 but no more than 3, got 4`},
+		{s: `SELECT rate(value, 100) FROM foo`, err: `second argument to rate must be a duration, got *influxql.IntegerLiteral`},
+		{s: `SELECT rate(value, 0s) FROM foo`, err: `unit for rate must be greater than 0, got 0s`},
+		{s: `SELECT increase(value, 1s) FROM foo`, err: `expected counter maximum argument in increase()`},
+		{s: `SELECT increase(value, -1) FROM foo`, err: `counter maximum for increase must be greater than 0, got -1`},
+		{s: `SELECT increase(mean(value)) FROM foo`, err: `expected field argument in increase()`},
+		{s: `SELECT rate(value) FROM foo where time > now() - 1h group by time(10m, step(1m))`, err: `rate does not support overlapping windows`},
+		{s: `SELECT count(value) FROM foo where time > now() - 1mo group by time(1mo)`, err: `invalid duration`},
+		{s: `SELECT holt_winters(mean(value), 3, 1) FROM foo group by time(1mo)`, err: `holt_winters aggregate does not support calendar intervals`},
 		{s: `SELECT count(value) FROM foo where time > now() and time < now() group by time(1s), time(2s)`, err: `multiple time dimensions not allowed`},
 		{s: `SELECT count(value) FROM foo where time > now() and time < now() group by time(1s, b)`, err: `time dimension offset must be duration or now()`},
 		{s: `SELECT field1 FROM 12`, err: `found 12, expected identifier at line 1, char 20`},
@@ -2698,25 +3410,53 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `DROP SERIES FROM src WHERE`, err: `found EOF, expected identifier, string, number, bool at line 1, char 28`},
 		{s: `DROP SERIES FROM "foo".myseries`, err: `retention policy not supported at line 1, char 1`},
 		{s: `DROP SERIES FROM foo..myseries`, err: `database not supported at line 1, char 1`},
-		{s: `SHOW CONTINUOUS`, err: `found EOF, expected QUERIES at line 1, char 17`},
+		{s: `SHOW CONTINUOUS`, err: `found EOF, expected QUERIES, QUERY at line 1, char 17`},
+		{s: `SHOW CONTINUOUS QUERY`, err: `found EOF, expected STATUS at line 1, char 23`},
+		{s: `SHOW CONTINUOUS QUERY foo`, err: `found foo, expected STATUS at line 1, char 23`},
 		{s: `SHOW RETENTION`, err: `found EOF, expected POLICIES at line 1, char 16`},
 		{s: `SHOW RETENTION ON`, err: `found ON, expected POLICIES at line 1, char 16`},
 		{s: `SHOW RETENTION POLICIES ON`, err: `found EOF, expected identifier at line 1, char 28`},
 		{s: `SHOW SHARD`, err: `found EOF, expected GROUPS at line 1, char 12`},
-		{s: `SHOW FOO`, err: `found FOO, expected CONTINUOUS, DATABASES, DIAGNOSTICS, FIELD, GRANTS, MEASUREMENTS, QUERIES, RETENTION, SERIES, SHARD, SHARDS, STATS, SUBSCRIPTIONS, TAG, USERS at line 1, char 6`},
+		{s: `SHOW FOO`, err: `found FOO, expected CONTINUOUS, DATABASES, DIAGNOSTICS, EXACT, FIELD, GRANTS, MEASUREMENT, MEASUREMENTS, QUERIES, RETENTION, SERIES, SHARD, SHARDS, STATS, SUBSCRIPTIONS, TAG, USERS at line 1, char 6`},
 		{s: `SHOW STATS FOR`, err: `found EOF, expected string at line 1, char 16`},
+		{s: `SHOW EXACT`, err: `found EOF, expected FIELD, MEASUREMENT, SERIES, TAG at line 1, char 12`},
+		{s: `SHOW EXACT SERIES`, err: `found EOF, expected CARDINALITY at line 1, char 19`},
+		{s: `SHOW MEASUREMENT`, err: `found EOF, expected CARDINALITY at line 1, char 18`},
+		{s: `SHOW FIELD KEY`, err: `found EOF, expected CARDINALITY at line 1, char 16`},
+		{s: `SHOW TAG VALUES CARDINALITY`, err: `found EOF, expected WITH at line 1, char 29`},
 		{s: `SHOW DIAGNOSTICS FOR`, err: `found EOF, expected string at line 1, char 22`},
 		{s: `SHOW GRANTS`, err: `found EOF, expected FOR at line 1, char 13`},
 		{s: `SHOW GRANTS FOR`, err: `found EOF, expected identifier at line 1, char 17`},
 		{s: `DROP CONTINUOUS`, err: `found EOF, expected QUERY at line 1, char 17`},
+		{s: `BACKFILL CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 27`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo`, err: `found EOF, expected FROM at line 1, char 42`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM now() TO now()`, err: `backfill end time must be after the start time`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM now() - 'foo' TO now()`, err: `invalid backfill time: now() - 'foo'`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM value TO now()`, err: `found value, expected time at line 1, char 47`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM '2017-01-01T00:00:00Z'`, err: `found EOF, expected TO at line 1, char 69`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM 'yesterday' TO '2017-01-01T00:00:00Z'`, err: `invalid time: yesterday at line 1, char 46`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM '2017-01-02T00:00:00Z' TO '2017-01-01T00:00:00Z'`, err: `backfill end time must be after the start time`},
 		{s: `DROP CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 23`},
 		{s: `DROP CONTINUOUS QUERY myquery`, err: `found EOF, expected ON at line 1, char 31`},
 		{s: `DROP CONTINUOUS QUERY myquery ON`, err: `found EOF, expected identifier at line 1, char 34`},
 		{s: `CREATE CONTINUOUS`, err: `found EOF, expected QUERY at line 1, char 19`},
 		{s: `CREATE CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 25`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db INCREMENTAL BEGIN SELECT mean(value) INTO cpu_mean FROM /cpu/ GROUP BY time(10s) END`, err: `incremental continuous queries must select from a single measurement`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db INCREMENTAL BEGIN SELECT median(value) INTO cpu_median FROM cpu GROUP BY time(10s) END`, err: `incremental continuous queries only support count(), sum(), min(), max(), mean() and last()`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db INCREMENTAL BEGIN SELECT mean(value) * 2 INTO cpu_mean FROM cpu GROUP BY time(10s) END`, err: `incremental continuous queries only support count(), sum(), min(), max(), mean() and last()`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db INCREMENTAL BEGIN SELECT count(distinct(value)) INTO cpu_count FROM cpu GROUP BY time(10s) END`, err: `incremental continuous queries only support fields as the argument to count()`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db INCREMENTAL BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s), * END`, err: `incremental continuous queries only support grouping by time() and tags`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db INCREMENTAL BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(1mo) END`, err: `incremental continuous queries do not support calendar intervals`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db INCREMENTAL BEGIN SELECT mean(value) INTO cpu_mean FROM cpu WHERE host = 'server01' GROUP BY time(10s) END`, err: `incremental continuous queries only support conditions on time`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db INCREMENTAL BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s) LIMIT 1 END`, err: `incremental continuous queries do not support LIMIT, OFFSET, SLIMIT or SOFFSET`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE EVERY 10s FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(5s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
-		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, MEASUREMENT, RETENTION, SERIES, SHARD, SUBSCRIPTION, USER at line 1, char 6`},
+		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, FIELD, MEASUREMENT, RETENTION, SERIES, SHARD, SUBSCRIPTION, USER at line 1, char 6`},
+		{s: `DROP FIELD`, err: `found EOF, expected identifier at line 1, char 12`},
+		{s: `DROP FIELD value`, err: `found EOF, expected FROM at line 1, char 18`},
+		{s: `DROP FIELD value FROM "foo".cpu`, err: `retention policy not supported at line 1, char 1`},
+		{s: `DELETE FIELD`, err: `found EOF, expected identifier at line 1, char 14`},
+		{s: `DELETE FIELD value`, err: `found EOF, expected FROM, WHERE at line 1, char 20`},
 		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, USER, RETENTION, SUBSCRIPTION at line 1, char 8`},
 		{s: `CREATE DATABASE`, err: `found EOF, expected identifier at line 1, char 17`},
 		{s: `CREATE DATABASE "testdb" WITH`, err: `found EOF, expected DURATION, NAME, REPLICATION, SHARD at line 1, char 31`},
@@ -2781,6 +3521,9 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `GRANT ALL TO`, err: `found EOF, expected identifier at line 1, char 14`},
 		{s: `GRANT ALL PRIVILEGES TO`, err: `found EOF, expected identifier at line 1, char 25`},
 		{s: `KILL`, err: `found EOF, expected QUERY at line 1, char 6`},
+		{s: `EXPLAIN`, err: `found EOF, expected SELECT at line 1, char 9`},
+		{s: `EXPLAIN ANALYZE SHOW DATABASES`, err: `found SHOW, expected SELECT at line 1, char 17`},
+		{s: `EXPLAIN SELECT value INTO cpu_copy FROM cpu`, err: `EXPLAIN cannot be used with SELECT INTO`},
 		{s: `KILL QUERY 10s`, err: `found 10s, expected integer at line 1, char 12`},
 		{s: `KILL QUERY 4 ON 'host'`, err: `found host, expected identifier at line 1, char 16`},
 		{s: `REVOKE`, err: `found EOF, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 8`},
@@ -2828,7 +3571,14 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 0`, err: `invalid value 0: must be 1 <= n <= 2147483647 at line 1, char 67`},
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION bad`, err: `found bad, expected integer at line 1, char 67`},
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 SHARD DURATION INF`, err: `invalid duration INF for shard duration at line 1, char 84`},
-		{s: `ALTER`, err: `found EOF, expected RETENTION at line 1, char 7`},
+		{s: `ALTER`, err: `found EOF, expected MEASUREMENT, RETENTION at line 1, char 7`},
+		{s: `ALTER MEASUREMENT`, err: `found EOF, expected identifier at line 1, char 19`},
+		{s: `ALTER MEASUREMENT cpu`, err: `found EOF, expected RENAME at line 1, char 23`},
+		{s: `ALTER MEASUREMENT cpu RENAME`, err: `found EOF, expected TO at line 1, char 30`},
+		{s: `ALTER MEASUREMENT cpu RENAME TAG`, err: `found EOF, expected identifier at line 1, char 34`},
+		{s: `ALTER MEASUREMENT cpu RENAME TAG host VALUE`, err: `found EOF, expected string at line 1, char 45`},
+		{s: `ALTER MEASUREMENT cpu RENAME TAG host VALUE 'a' TO b`, err: `found b, expected string at line 1, char 52`},
+		{s: `ALTER MEASUREMENT cpu RENAME TAG host VALUE 'a' TO ''`, err: `tag value cannot be empty`},
 		{s: `ALTER RETENTION`, err: `found EOF, expected POLICY at line 1, char 17`},
 		{s: `ALTER RETENTION POLICY`, err: `found EOF, expected identifier at line 1, char 24`},
 		{s: `ALTER RETENTION POLICY policy1`, err: `found EOF, expected ON at line 1, char 32`}, {s: `ALTER RETENTION POLICY policy1 ON`, err: `found EOF, expected identifier at line 1, char 35`},
@@ -2843,7 +3593,7 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SET PASSWORD FOR dejan`, err: `found EOF, expected = at line 1, char 24`},
 		{s: `SET PASSWORD FOR dejan =`, err: `found EOF, expected string at line 1, char 25`},
 		{s: `SET PASSWORD FOR dejan = bla`, err: `found bla, expected string at line 1, char 26`},
-		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, BACKFILL, CREATE, DROP, GRANT, REVOKE, ALTER, SET, KILL, EXPLAIN at line 1, char 1`},
 		{s: `SELECT * FROM cpu WHERE "tagkey" = $$`, err: `empty bound parameter`},
 	}
 
@@ -2866,6 +3616,8 @@ func TestParser_ParseStatement(t *testing.T) {
 					source.Statement.GroupByInterval()
 				}
 			}
+		} else if s, ok := tt.stmt.(*influxql.ExplainStatement); ok {
+			s.Statement.GroupByInterval()
 		} else if st, ok := stmt.(*influxql.CreateContinuousQueryStatement); ok { // if it's a CQ, there is a non-exported field that gets memoized during parsing that needs to be set
 			if st != nil && st.Source != nil {
 				tt.stmt.(*influxql.CreateContinuousQueryStatement).Source.GroupByInterval()
@@ -3360,6 +4112,14 @@ func TestDeleteSeriesStatement_String(t *testing.T) {
 			s:    `DELETE FROM src`,
 			stmt: &influxql.DeleteSeriesStatement{Sources: []influxql.Source{&influxql.Measurement{Name: "src"}}},
 		},
+		{
+			s:    `DELETE FIELD "my field" FROM src`,
+			stmt: &influxql.DeleteSeriesStatement{Field: "my field", Sources: []influxql.Source{&influxql.Measurement{Name: "src"}}},
+		},
+		{
+			s:    `DROP FIELD value FROM src`,
+			stmt: &influxql.DropFieldStatement{Name: "value", Sources: []influxql.Source{&influxql.Measurement{Name: "src"}}},
+		},
 		{
 			s: `DELETE FROM src WHERE host = 'hosta.influxdb.org'`,
 			stmt: &influxql.DeleteSeriesStatement{
@@ -3402,6 +4162,34 @@ func TestDeleteSeriesStatement_String(t *testing.T) {
 	}
 }
 
+// Ensure AlterMeasurementStatement can convert to a string
+func TestAlterMeasurementStatement_String(t *testing.T) {
+	var tests = []struct {
+		s    string
+		stmt influxql.Statement
+	}{
+		{
+			s:    `ALTER MEASUREMENT cpu RENAME TO "cpu v2"`,
+			stmt: &influxql.AlterMeasurementStatement{Name: "cpu", NewName: "cpu v2"},
+		},
+		{
+			s:    `ALTER MEASUREMENT cpu RENAME TAG host TO hostname`,
+			stmt: &influxql.AlterMeasurementStatement{Name: "cpu", TagKey: "host", NewName: "hostname"},
+		},
+		{
+			s:    `ALTER MEASUREMENT cpu RENAME TAG host VALUE 'serverA' TO 'server-a'`,
+			stmt: &influxql.AlterMeasurementStatement{Name: "cpu", TagKey: "host", TagValue: "serverA", NewName: "server-a"},
+		},
+	}
+
+	for _, test := range tests {
+		s := test.stmt.String()
+		if s != test.s {
+			t.Errorf("error rendering string. expected %s, actual: %s", test.s, s)
+		}
+	}
+}
+
 // Ensure DropSeriesStatement can convert to a string
 func TestDropSeriesStatement_String(t *testing.T) {
 	var tests = []struct {
@@ -441,12 +441,31 @@ type QueryTask struct {
 	query     string
 	database  string
 	startTime time.Time
+	status    string
 	closing   chan struct{}
 	monitorCh chan error
 	err       error
 	mu        sync.Mutex
 }
 
+// Status returns the status of the query that is displayed by SHOW QUERIES.
+func (q *QueryTask) Status() string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.status == "" {
+		return "running"
+	}
+	return q.status
+}
+
+// SetStatus sets the status of the query. Long running statements use this
+// to report their progress.
+func (q *QueryTask) SetStatus(status string) {
+	q.mu.Lock()
+	q.status = status
+	q.mu.Unlock()
+}
+
 // Monitor starts a new goroutine that will monitor a query. The function
 // will be passed in a channel to signal when the query has been finished
 // normally. If the function returns with an error and the query is still
@@ -171,6 +171,48 @@ func TestQueryExecutor_ShowQueries(t *testing.T) {
 	}
 }
 
+func TestQueryExecutor_ShowQueries_Status(t *testing.T) {
+	e := NewQueryExecutor()
+	e.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+			switch stmt.(type) {
+			case *influxql.SelectStatement:
+				ctx.Query.SetStatus("1/2 intervals")
+				ctx.Results <- &influxql.Result{StatementID: ctx.StatementID}
+				return nil
+			case *influxql.ShowQueriesStatement:
+				return e.TaskManager.ExecuteStatement(stmt, ctx)
+			}
+
+			t.Errorf("unexpected statement: %s", stmt)
+			return errUnexpected
+		},
+	}
+
+	q, err := influxql.ParseQuery(`SELECT count(value) FROM cpu; SHOW QUERIES`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := e.ExecuteQuery(q, influxql.ExecutionOptions{}, nil)
+	<-results
+	result := <-results
+	if result.Err != nil {
+		t.Fatalf("unexpected error: %s", result.Err)
+	} else if len(result.Series) != 1 || len(result.Series[0].Values) != 1 {
+		t.Fatalf("unexpected series: %v", result.Series)
+	}
+
+	row := result.Series[0]
+	if got, exp := row.Columns[len(row.Columns)-1], "status"; got != exp {
+		t.Errorf("unexpected column: got %s, exp %s", got, exp)
+	}
+	if got, exp := row.Values[0][len(row.Columns)-1], "1/2 intervals"; got != exp {
+		t.Errorf("unexpected status: got %v, exp %s", got, exp)
+	}
+	discardOutput(results)
+}
+
 func TestQueryExecutor_Limit_Timeout(t *testing.T) {
 	q, err := influxql.ParseQuery(`SELECT count(value) FROM cpu`)
 	if err != nil {
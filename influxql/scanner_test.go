@@ -106,10 +106,14 @@ func TestScanner_Scan(t *testing.T) {
 		// Keywords
 		{s: `ALL`, tok: influxql.ALL},
 		{s: `ALTER`, tok: influxql.ALTER},
+		{s: `ANALYZE`, tok: influxql.ANALYZE},
 		{s: `AS`, tok: influxql.AS},
 		{s: `ASC`, tok: influxql.ASC},
+		{s: `BACKFILL`, tok: influxql.BACKFILL},
 		{s: `BEGIN`, tok: influxql.BEGIN},
 		{s: `BY`, tok: influxql.BY},
+		{s: `CARDINALITY`, tok: influxql.CARDINALITY},
+		{s: `CASE`, tok: influxql.CASE},
 		{s: `CREATE`, tok: influxql.CREATE},
 		{s: `CONTINUOUS`, tok: influxql.CONTINUOUS},
 		{s: `DATABASE`, tok: influxql.DATABASE},
@@ -119,16 +123,20 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `DESC`, tok: influxql.DESC},
 		{s: `DROP`, tok: influxql.DROP},
 		{s: `DURATION`, tok: influxql.DURATION},
+		{s: `ELSE`, tok: influxql.ELSE},
 		{s: `END`, tok: influxql.END},
 		{s: `EVERY`, tok: influxql.EVERY},
+		{s: `EXACT`, tok: influxql.EXACT},
 		{s: `EXPLAIN`, tok: influxql.EXPLAIN},
 		{s: `FIELD`, tok: influxql.FIELD},
 		{s: `FROM`, tok: influxql.FROM},
 		{s: `GRANT`, tok: influxql.GRANT},
 		{s: `GROUP`, tok: influxql.GROUP},
 		{s: `GROUPS`, tok: influxql.GROUPS},
+		{s: `HAVING`, tok: influxql.HAVING},
 		{s: `INSERT`, tok: influxql.INSERT},
 		{s: `INTO`, tok: influxql.INTO},
+		{s: `JOIN`, tok: influxql.JOIN},
 		{s: `KEY`, tok: influxql.KEY},
 		{s: `KEYS`, tok: influxql.KEYS},
 		{s: `KILL`, tok: influxql.KILL},
@@ -155,10 +163,12 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `SELECT`, tok: influxql.SELECT},
 		{s: `SERIES`, tok: influxql.SERIES},
 		{s: `TAG`, tok: influxql.TAG},
+		{s: `THEN`, tok: influxql.THEN},
 		{s: `TO`, tok: influxql.TO},
 		{s: `USER`, tok: influxql.USER},
 		{s: `USERS`, tok: influxql.USERS},
 		{s: `VALUES`, tok: influxql.VALUES},
+		{s: `WHEN`, tok: influxql.WHEN},
 		{s: `WHERE`, tok: influxql.WHERE},
 		{s: `WITH`, tok: influxql.WITH},
 		{s: `WRITE`, tok: influxql.WRITE},
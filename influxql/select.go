@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -29,6 +31,10 @@ type SelectOptions struct {
 
 	// Maximum number of concurrent series.
 	MaxSeriesN int
+
+	// Maximum number of points that may be buffered to sort the rows across
+	// every series.
+	MaxPointN int
 }
 
 // Select executes stmt against ic and returns a list of iterators to stream from.
@@ -45,6 +51,21 @@ func Select(stmt *SelectStatement, ic IteratorCreator, sopt *SelectOptions) ([]I
 }
 
 func buildIterators(stmt *SelectStatement, ic IteratorCreator, opt IteratorOptions) ([]Iterator, error) {
+	// Rows that are sorted by a field or a tag are sorted across every
+	// series so the limit and offset are applied after the rows are sorted.
+	if stmt.SortsAcrossSeries() {
+		sortOpt := opt
+		sortOpt.Limit, sortOpt.Offset = 0, 0
+		itrs, err := buildStatementIterators(stmt, ic, sortOpt)
+		if err != nil {
+			return nil, err
+		}
+		return buildSortIterators(stmt, itrs, opt)
+	}
+	return buildStatementIterators(stmt, ic, opt)
+}
+
+func buildStatementIterators(stmt *SelectStatement, ic IteratorCreator, opt IteratorOptions) ([]Iterator, error) {
 	// Retrieve refs for each call and var ref.
 	info := newSelectInfo(stmt)
 	if len(info.calls) > 1 && len(info.refs) > 0 {
@@ -60,6 +81,11 @@ func buildIterators(stmt *SelectStatement, ic IteratorCreator, opt IteratorOptio
 
 	// If there are multiple auxilary fields and no calls then construct an aux iterator.
 	if len(info.calls) == 0 && len(info.refs) > 0 {
+		// The fields of a join come from different sources so they cannot
+		// share a single auxiliary iterator.
+		if stmt.Sources.HasJoin() {
+			return buildJoinFieldIterators(stmt.Fields, ic, stmt.Sources[0].(*Join), opt)
+		}
 		return buildAuxIterators(stmt.Fields, ic, stmt.Sources, opt)
 	}
 
@@ -102,11 +128,333 @@ func buildIterators(stmt *SelectStatement, ic IteratorCreator, opt IteratorOptio
 		}
 	}
 
+	// The limit and offset count the rows that match the HAVING clause so
+	// they are applied after the rows have been filtered.
+	if stmt.Having != nil {
+		fieldOpt := opt
+		fieldOpt.Limit, fieldOpt.Offset = 0, 0
+		itrs, err := buildFieldIterators(fields, ic, stmt.Sources, fieldOpt, selector, stmt.Target != nil)
+		if err != nil {
+			return nil, err
+		}
+		return buildHavingIterators(stmt, fields, itrs, opt)
+	}
+
 	return buildFieldIterators(fields, ic, stmt.Sources, opt, selector, stmt.Target != nil)
 }
 
+// buildHavingIterators combines the field iterators into rows, filters the
+// rows with the HAVING clause of the statement and separates the remaining
+// rows into an iterator for each field again.
+func buildHavingIterators(stmt *SelectStatement, fields Fields, itrs []Iterator, opt IteratorOptions) ([]Iterator, error) {
+	// The rows are read from the field iterators by their column names.
+	names := stmt.ColumnNames()
+	if !stmt.OmitTime {
+		names = names[1:]
+	}
+
+	cond, err := havingCondition(stmt, fields, names)
+	if err != nil {
+		Iterators(itrs).Close()
+		return nil, err
+	}
+
+	return mapRows(itrs, names, opt, func(input Iterator, hopt IteratorOptions) Iterator {
+		input = NewFilterIterator(input, cond, hopt)
+		if opt.Limit > 0 || opt.Offset > 0 {
+			input = NewLimitIterator(input, opt)
+		}
+		return input
+	}), nil
+}
+
+// buildSortIterators combines the field iterators into rows, sorts the rows by
+// the ORDER BY clause of the statement and separates the sorted rows into an
+// iterator for each field again. The limit and offset are applied to the
+// sorted rows.
+func buildSortIterators(stmt *SelectStatement, itrs []Iterator, opt IteratorOptions) ([]Iterator, error) {
+	names := stmt.ColumnNames()
+	if !stmt.OmitTime {
+		names = names[1:]
+	}
+
+	less, err := sortRowsLess(stmt, names)
+	if err != nil {
+		Iterators(itrs).Close()
+		return nil, err
+	}
+
+	return mapRows(itrs, names, opt, func(input Iterator, _ IteratorOptions) Iterator {
+		return newRowSortIterator(input.(FloatIterator), less, opt)
+	}), nil
+}
+
+// mapRows combines the field iterators into rows where the value of each field
+// is an auxiliary field named after its column. The rows are passed through fn
+// and then separated into an iterator for each field again.
+func mapRows(itrs []Iterator, names []string, opt IteratorOptions, fn func(input Iterator, opt IteratorOptions) Iterator) []Iterator {
+	ropt := opt
+	ropt.Aux = make([]VarRef, len(itrs))
+	maps := make([]IteratorMap, len(itrs))
+	for i, itr := range itrs {
+		ropt.Aux[i] = VarRef{Val: names[i], Type: iteratorDataType(itr)}
+		maps[i] = FieldMap(i)
+	}
+
+	input := fn(NewIteratorMapper(itrs, nil, maps, ropt), ropt)
+
+	aitr := NewAuxIterator(input, ropt)
+	outputs := make([]Iterator, len(ropt.Aux))
+	for i, ref := range ropt.Aux {
+		outputs[i] = aitr.Iterator(ref.Val, ref.Type)
+	}
+
+	// Background the primary iterator since there is no reader for it.
+	aitr.Background()
+	return outputs
+}
+
+// sortRowsLess returns a function that compares two rows created by mapRows
+// using the ORDER BY clause of the statement. Every sort field must be the
+// time, a column or a tag that the rows are grouped by. Rows without a value
+// for a column are sorted after the rows that have one.
+func sortRowsLess(stmt *SelectStatement, names []string) (func(a, b *FloatPoint) bool, error) {
+	type sortKey struct {
+		index     int
+		tag       string
+		ascending bool
+	}
+
+	columns := make(map[string]int, len(names))
+	for i := len(names) - 1; i >= 0; i-- {
+		columns[names[i]] = i
+	}
+	tags := make(map[string]struct{}, len(stmt.Dimensions))
+	for _, d := range stmt.Dimensions {
+		if key, ok := d.TagKey(); ok {
+			tags[key] = struct{}{}
+		}
+	}
+
+	keys := make([]sortKey, len(stmt.SortFields))
+	for i, field := range stmt.SortFields {
+		keys[i] = sortKey{index: -1, ascending: field.Ascending}
+		if stmt.isTimeSortField(field) {
+			continue
+		} else if index, ok := columns[field.Name]; ok {
+			keys[i].index = index
+		} else if _, ok := tags[field.Name]; ok {
+			keys[i].tag = field.Name
+		} else {
+			return nil, fmt.Errorf("unknown column or tag in ORDER BY clause: %s", field.Name)
+		}
+	}
+
+	return func(a, b *FloatPoint) bool {
+		for _, key := range keys {
+			var cmp int
+			switch {
+			case key.index >= 0:
+				av, bv := a.Aux[key.index], b.Aux[key.index]
+				if av == nil || bv == nil {
+					if av == nil && bv == nil {
+						continue
+					}
+					return bv == nil
+				}
+				cmp = compareSortValues(av, bv)
+			case key.tag != "":
+				cmp = strings.Compare(a.Tags.Value(key.tag), b.Tags.Value(key.tag))
+			case a.Time < b.Time:
+				cmp = -1
+			case a.Time > b.Time:
+				cmp = 1
+			}
+
+			if cmp != 0 {
+				if !key.ascending {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+		}
+		return false
+	}, nil
+}
+
+// compareSortValues compares two values of the same column. It returns zero
+// if the values cannot be compared.
+func compareSortValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case float64:
+		if b, ok := b.(float64); ok && a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	case int64:
+		if b, ok := b.(int64); ok && a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	case uint64:
+		if b, ok := b.(uint64); ok && a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return strings.Compare(a, b)
+		}
+	case bool:
+		if b, ok := b.(bool); ok && a != b {
+			if !a {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// rowSortIterator reads every row from its input and emits them sorted. The
+// limit and offset are applied to the sorted rows. The number of buffered rows
+// is limited by MaxPointN.
+type rowSortIterator struct {
+	input  FloatIterator
+	less   func(a, b *FloatPoint) bool
+	opt    IteratorOptions
+	points []*FloatPoint
+	sorted bool
+}
+
+// newRowSortIterator returns a new instance of rowSortIterator.
+func newRowSortIterator(input FloatIterator, less func(a, b *FloatPoint) bool, opt IteratorOptions) *rowSortIterator {
+	return &rowSortIterator{input: input, less: less, opt: opt}
+}
+
+// Stats returns stats from the input iterator.
+func (itr *rowSortIterator) Stats() IteratorStats { return itr.input.Stats() }
+
+// Close closes the underlying iterator.
+func (itr *rowSortIterator) Close() error { return itr.input.Close() }
+
+// Next returns the next row in sorted order.
+func (itr *rowSortIterator) Next() (*FloatPoint, error) {
+	if !itr.sorted {
+		if err := itr.sort(); err != nil {
+			return nil, err
+		}
+		itr.sorted = true
+	}
+
+	if len(itr.points) == 0 {
+		return nil, nil
+	}
+	p := itr.points[0]
+	itr.points = itr.points[1:]
+	return p, nil
+}
+
+// sort reads and sorts all of the rows from the input.
+func (itr *rowSortIterator) sort() error {
+	for {
+		p, err := itr.input.Next()
+		if err != nil {
+			return err
+		} else if p == nil {
+			break
+		}
+		// The input reuses the same point for every row.
+		itr.points = append(itr.points, p.Clone())
+		if itr.opt.MaxPointN > 0 && len(itr.points) > itr.opt.MaxPointN {
+			return ErrMaxSelectPointsLimitExceeded(len(itr.points), itr.opt.MaxPointN)
+		}
+	}
+
+	sort.SliceStable(itr.points, func(i, j int) bool {
+		return itr.less(itr.points[i], itr.points[j])
+	})
+
+	if itr.opt.Offset > 0 {
+		if itr.opt.Offset >= len(itr.points) {
+			itr.points = nil
+		} else {
+			itr.points = itr.points[itr.opt.Offset:]
+		}
+	}
+	if itr.opt.Limit > 0 && itr.opt.Limit < len(itr.points) {
+		itr.points = itr.points[:itr.opt.Limit]
+	}
+	return nil
+}
+
+// havingCondition returns the HAVING clause of the statement with every
+// function call replaced by a reference to the column that selects the same
+// call. Every name used by the condition must be a column or a tag that the
+// rows are grouped by.
+func havingCondition(stmt *SelectStatement, fields Fields, names []string) (Expr, error) {
+	exprs := make(map[string]string, len(fields))
+	columns := make(map[string]struct{}, len(names))
+	for i, f := range fields {
+		exprs[untypedString(f.Expr)] = names[i]
+		columns[names[i]] = struct{}{}
+	}
+	for _, d := range stmt.Dimensions {
+		if key, ok := d.TagKey(); ok {
+			columns[key] = struct{}{}
+		}
+	}
+
+	var err error
+	cond := RewriteExpr(CloneExpr(stmt.Having), func(expr Expr) Expr {
+		call, ok := expr.(*Call)
+		if !ok || isMathFunction(call) {
+			return expr
+		} else if name, ok := exprs[untypedString(call)]; ok {
+			return &VarRef{Val: name}
+		} else if err == nil {
+			err = fmt.Errorf("%s must be selected to be used in HAVING clause", call)
+		}
+		return expr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	WalkFunc(cond, func(n Node) {
+		if ref, ok := n.(*VarRef); ok && err == nil {
+			if _, ok := columns[ref.Val]; !ok {
+				err = fmt.Errorf("unknown column or tag in HAVING clause: %s", ref.Val)
+			}
+		}
+	})
+	return cond, err
+}
+
+// untypedString returns the string representation of expr without the type
+// of its variable references.
+func untypedString(expr Expr) string {
+	return RewriteExpr(CloneExpr(expr), func(expr Expr) Expr {
+		if ref, ok := expr.(*VarRef); ok {
+			return &VarRef{Val: ref.Val}
+		}
+		return expr
+	}).String()
+}
+
 // buildAuxIterators creates a set of iterators from a single combined auxiliary iterator.
 func buildAuxIterators(fields Fields, ic IteratorCreator, sources Sources, opt IteratorOptions) ([]Iterator, error) {
+	opt, err := fieldAuxOptions(ic, sources, opt)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create the auxiliary iterators for each source.
 	inputs := make([]Iterator, 0, len(sources))
 	if err := func() error {
@@ -145,7 +493,12 @@ func buildAuxIterators(fields Fields, ic IteratorCreator, sources Sources, opt I
 	} else if input == nil {
 		input = &nilFloatIterator{}
 	}
+	return splitAuxIterators(fields, input, opt)
+}
 
+// splitAuxIterators separates the auxiliary fields of the points of input
+// into an iterator for each field.
+func splitAuxIterators(fields Fields, input Iterator, opt IteratorOptions) ([]Iterator, error) {
 	// Filter out duplicate rows, if required.
 	if opt.Dedupe {
 		// If there is no group by and it is a float iterator, see if we can use a fast dedupe.
@@ -192,6 +545,103 @@ func buildAuxIterators(fields Fields, ic IteratorCreator, sources Sources, opt I
 	return itrs, nil
 }
 
+// buildJoinFieldIterators creates an iterator for each field of a raw query
+// against a join. The points of each source are aligned by exact time and
+// only the rows that have a point from every source are returned. The
+// conditions that reference more than one source are evaluated on the
+// aligned rows.
+func buildJoinFieldIterators(fields Fields, ic IteratorCreator, join *Join, opt IteratorOptions) ([]Iterator, error) {
+	cond := join.CrossCondition(opt.Condition)
+
+	// Read the referenced fields and tags of each source as auxiliary fields.
+	// The aligned rows hold the auxiliary fields of every source in order.
+	refs := make([]VarRef, 0, len(opt.Aux))
+	names := make(map[string]struct{}, len(opt.Aux))
+	for _, a := range [][]VarRef{opt.Aux, ExprNames(cond)} {
+		for _, ref := range a {
+			if _, ok := names[ref.Val]; !ok {
+				refs = append(refs, ref)
+				names[ref.Val] = struct{}{}
+			}
+		}
+	}
+
+	ropt := opt
+	ropt.Aux = nil
+	inputs := make([]Iterator, 0, len(join.Sources))
+	widths := make([]int, 0, len(join.Sources))
+	if err := func() error {
+		for _, js := range join.Sources {
+			// The points of a source are read for its fields so at least
+			// one field of every source must be referenced.
+			var aux []VarRef
+			var hasField bool
+			for _, ref := range refs {
+				if src, name := join.Resolve(ref.Val); src == js {
+					ropt.Aux = append(ropt.Aux, ref)
+					aux = append(aux, VarRef{Val: name, Type: ref.Type})
+					hasField = hasField || ref.Type != Tag
+				}
+			}
+			if !hasField {
+				return fmt.Errorf("a field must be selected from join source: %s", js.Name())
+			}
+
+			sopt := opt
+			sopt.Expr = nil
+			sopt.Aux = aux
+			sopt.Condition = join.Condition(js, opt.Condition)
+			sopt.Limit, sopt.Offset = 0, 0
+
+			input, err := ic.CreateIterator(js.Measurement, sopt)
+			if err != nil {
+				return err
+			} else if input == nil {
+				input = &nilFloatIterator{}
+			}
+			inputs = append(inputs, NewRenameIterator(input, join.Name()))
+			widths = append(widths, len(aux))
+		}
+		return nil
+	}(); err != nil {
+		Iterators(inputs).Close()
+		return nil, err
+	}
+
+	var input Iterator = newJoinIterator(inputs, widths, opt.Ascending)
+	if cond != nil {
+		input = NewFilterIterator(input, cond, ropt)
+	}
+	return splitAuxIterators(fields, input, ropt)
+}
+
+// buildJoinIterator creates an iterator for the join source referenced by the
+// expression of opt. The points are renamed to the name of the join so they
+// can be aligned with the points of the other join sources.
+func buildJoinIterator(ic IteratorCreator, join *Join, opt IteratorOptions) (Iterator, error) {
+	var js *JoinSource
+	WalkFunc(opt.Expr, func(n Node) {
+		if ref, ok := n.(*VarRef); ok && js == nil {
+			js, _ = join.Resolve(ref.Val)
+		}
+	})
+	if js == nil {
+		return nil, fmt.Errorf("expression must reference a join source: %s", opt.Expr)
+	}
+
+	opt.Expr = join.Unqualify(opt.Expr)
+	opt.Condition = join.Condition(js, opt.Condition)
+	opt.Aux = nil
+
+	input, err := ic.CreateIterator(js.Measurement, opt)
+	if err != nil {
+		return nil, err
+	} else if input == nil {
+		return nil, nil
+	}
+	return NewRenameIterator(input, join.Name()), nil
+}
+
 // buildAuxIterator constructs an Iterator for an expression from an AuxIterator.
 func buildAuxIterator(expr Expr, aitr AuxIterator, opt IteratorOptions) (Iterator, error) {
 	switch expr := expr.(type) {
@@ -229,8 +679,36 @@ func buildAuxIterator(expr Expr, aitr AuxIterator, opt IteratorOptions) (Iterato
 			}
 			return buildTransformIterator(lhs, rhs, expr.Op, opt)
 		}
+	case *Call:
+		if !isMathFunction(expr) {
+			return nil, fmt.Errorf("invalid expression type: %T", expr)
+		}
+		return buildMathIterator(expr, opt, func(arg Expr) (Iterator, error) {
+			return buildAuxIterator(arg, aitr, opt)
+		})
 	case *ParenExpr:
 		return buildAuxIterator(expr.Expr, aitr, opt)
+	case *CaseExpr:
+		// Combine the fields used by the expression into a single row so
+		// the expression can be evaluated against it.
+		refs := ExprNames(expr)
+		itrs := make([]Iterator, len(refs))
+		maps := make([]IteratorMap, len(refs))
+		for i, ref := range refs {
+			itrs[i] = aitr.Iterator(ref.Val, ref.Type)
+			maps[i] = FieldMap(i)
+		}
+
+		// An expression without any references is evaluated once for every
+		// row of the auxiliary fields.
+		if len(refs) == 0 {
+			if len(opt.Aux) == 0 {
+				return &nilFloatIterator{}, nil
+			}
+			itrs = []Iterator{aitr.Iterator(opt.Aux[0].Val, opt.Aux[0].Type)}
+		}
+		input := NewIteratorMapper(itrs, nil, maps, opt)
+		return newCaseIterator(input.(FloatIterator), expr, refs), nil
 	case *nilLiteral:
 		return &nilFloatIterator{}, nil
 	default:
@@ -238,6 +716,21 @@ func buildAuxIterator(expr Expr, aitr AuxIterator, opt IteratorOptions) (Iterato
 	}
 }
 
+// newCaseIterator returns an iterator that evaluates a CASE expression for each
+// point of the input. The type of the iterator is the type of the results.
+func newCaseIterator(input FloatIterator, expr *CaseExpr, refs []VarRef) Iterator {
+	switch EvalType(expr, nil, nil) {
+	case Integer:
+		return newIntegerCaseIterator(input, expr, refs)
+	case String:
+		return newStringCaseIterator(input, expr, refs)
+	case Boolean:
+		return newBooleanCaseIterator(input, expr, refs)
+	default:
+		return newFloatCaseIterator(input, expr, refs)
+	}
+}
+
 // buildFieldIterators creates an iterator for each field expression.
 func buildFieldIterators(fields Fields, ic IteratorCreator, sources Sources, opt IteratorOptions, selector, writeMode bool) ([]Iterator, error) {
 	// Create iterators from fields against the iterator creator.
@@ -247,7 +740,42 @@ func buildFieldIterators(fields Fields, ic IteratorCreator, sources Sources, opt
 		hasAuxFields := false
 
 		var input Iterator
+
+		// The buckets of a histogram are counted by a single iterator.
+		for i := 0; i < len(fields); {
+			buckets := histogramBuckets(fields[i:])
+			if len(buckets) == 0 {
+				i++
+				continue
+			}
+
+			b := exprIteratorBuilder{
+				ic:        ic,
+				sources:   sources,
+				opt:       opt,
+				selector:  selector,
+				writeMode: writeMode,
+			}
+			a, err := b.buildHistogramIterators(buckets)
+			if err != nil {
+				return err
+			}
+			for _, itr := range a {
+				// If there is a limit or offset then apply it.
+				if opt.Limit > 0 || opt.Offset > 0 {
+					itr = NewLimitIterator(itr, opt)
+				}
+				itrs[i] = itr
+				input = itr
+				i++
+			}
+		}
+
 		for i, f := range fields {
+			if itrs[i] != nil {
+				continue
+			}
+
 			// Build iterators for calls first and save the iterator.
 			// We do this so we can keep the ordering provided by the user, but
 			// still build the Call's iterator first.
@@ -305,6 +833,25 @@ func buildFieldIterators(fields Fields, ic IteratorCreator, sources Sources, opt
 	return itrs, nil
 }
 
+// histogramBuckets returns the calls of the leading fields that count the
+// adjacent buckets of a histogram of the same expression.
+func histogramBuckets(fields Fields) []*Call {
+	var buckets []*Call
+	for _, f := range fields {
+		call, ok := f.Expr.(*Call)
+		if !ok || call.Name != "histogram" || len(call.Args) != 3 {
+			break
+		} else if len(buckets) > 0 {
+			prev := buckets[len(buckets)-1]
+			if call.Args[0].String() != prev.Args[0].String() || call.Args[1].String() != prev.Args[2].String() {
+				break
+			}
+		}
+		buckets = append(buckets, call)
+	}
+	return buckets
+}
+
 // buildExprIterator creates an iterator for an expression.
 func buildExprIterator(expr Expr, ic IteratorCreator, sources Sources, opt IteratorOptions, selector, writeMode bool) (Iterator, error) {
 	opt.Expr = expr
@@ -325,6 +872,8 @@ func buildExprIterator(expr Expr, ic IteratorCreator, sources Sources, opt Itera
 		return b.buildBinaryExprIterator(expr)
 	case *ParenExpr:
 		return buildExprIterator(expr.Expr, ic, sources, opt, selector, writeMode)
+	case *CaseExpr:
+		return b.buildCaseIterator(expr)
 	case *nilLiteral:
 		return &nilFloatIterator{}, nil
 	default:
@@ -351,6 +900,12 @@ func (b *exprIteratorBuilder) buildVarRefIterator(expr *VarRef) (Iterator, error
 					return err
 				}
 				inputs = append(inputs, input)
+			case *Join:
+				input, err := buildJoinIterator(b.ic, source, b.opt)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
 			case *SubQuery:
 				subquery := subqueryBuilder{
 					ic:   b.ic,
@@ -383,7 +938,136 @@ func (b *exprIteratorBuilder) buildVarRefIterator(expr *VarRef) (Iterator, error
 	return itr, nil
 }
 
+// buildCaseIterator evaluates a CASE expression against the raw points of the
+// sources.
+func (b *exprIteratorBuilder) buildCaseIterator(expr *CaseExpr) (Iterator, error) {
+	refs := ExprNames(expr)
+	opt := b.opt
+	opt.Expr = nil
+	opt.Aux = refs
+
+	opt, err := fieldAuxOptions(b.ic, b.sources, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]Iterator, 0, len(b.sources))
+	if err := func() error {
+		for _, source := range b.sources {
+			switch source := source.(type) {
+			case *Measurement:
+				input, err := b.ic.CreateIterator(source, opt)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
+			case *Join:
+				return errors.New("CASE expressions cannot be used with a JOIN")
+			case *SubQuery:
+				subquery := subqueryBuilder{
+					ic:   b.ic,
+					stmt: source.Statement,
+				}
+
+				input, err := subquery.buildAuxIterator(opt)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
+			}
+		}
+		return nil
+	}(); err != nil {
+		Iterators(inputs).Close()
+		return nil, err
+	}
+
+	var itr Iterator
+	if opt.MergeSorted() {
+		itr = NewSortedMergeIterator(inputs, opt)
+	} else {
+		itr = NewMergeIterator(inputs, opt)
+	}
+	if itr == nil {
+		return &nilFloatIterator{}, nil
+	}
+	input, ok := itr.(FloatIterator)
+	if !ok {
+		itr.Close()
+		return nil, fmt.Errorf("unexpected iterator type for CASE expression: %T", itr)
+	}
+
+	itr = newCaseIterator(input, expr, refs)
+	if b.opt.InterruptCh != nil {
+		itr = NewInterruptIterator(itr, b.opt.InterruptCh)
+	}
+	return itr, nil
+}
+
+// fieldAuxOptions returns the options used to read the auxiliary fields of
+// the sources. Points are only read where a field has a value so, when the
+// auxiliary fields only reference tags, every field of the sources is read
+// after them to produce the points.
+func fieldAuxOptions(ic IteratorCreator, sources Sources, opt IteratorOptions) (IteratorOptions, error) {
+	for _, ref := range opt.Aux {
+		if ref.Type != Tag {
+			return opt, nil
+		}
+	}
+
+	m, ok := ic.(FieldMapper)
+	if !ok {
+		return opt, errors.New("at least one field must be referenced")
+	}
+	fields, _, err := FieldDimensions(sources, m)
+	if err != nil {
+		return opt, err
+	}
+
+	aux := make([]VarRef, len(opt.Aux), len(opt.Aux)+len(fields))
+	copy(aux, opt.Aux)
+	for name, typ := range fields {
+		aux = append(aux, VarRef{Val: name, Type: typ})
+	}
+	sort.Sort(VarRefs(aux[len(opt.Aux):]))
+	opt.Aux = aux
+	return opt, nil
+}
+
+// seriesAuxOptions returns the options with the tags that are not grouped by
+// added as auxiliary fields so the series of each point can be identified
+// within its group. The options are returned unchanged if the tags cannot be
+// mapped.
+func seriesAuxOptions(ic IteratorCreator, sources Sources, opt IteratorOptions) (IteratorOptions, error) {
+	m, ok := ic.(FieldMapper)
+	if !ok {
+		return opt, nil
+	}
+	_, dimensions, err := FieldDimensions(sources, m)
+	if err != nil {
+		return opt, err
+	}
+	for _, dim := range opt.GetDimensions() {
+		delete(dimensions, dim)
+	}
+
+	aux := make([]VarRef, 0, len(dimensions))
+	for dim := range dimensions {
+		aux = append(aux, VarRef{Val: dim, Type: Tag})
+	}
+	sort.Sort(VarRefs(aux))
+	opt.Aux = aux
+	return opt, nil
+}
+
 func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
+	// Math functions are applied to each point of their arguments.
+	if isMathFunction(expr) {
+		return buildMathIterator(expr, b.opt, func(arg Expr) (Iterator, error) {
+			return buildExprIterator(arg, b.ic, b.sources, b.opt, b.selector, false)
+		})
+	}
+
 	// TODO(jsternberg): Refactor this. This section needs to die in a fire.
 	opt := b.opt
 	// Eliminate limits and offsets if they were previously set. These are handled by the caller.
@@ -391,7 +1075,7 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 	switch expr.Name {
 	case "distinct":
 		opt.Ordered = true
-		input, err := buildExprIterator(expr.Args[0].(*VarRef), b.ic, b.sources, opt, b.selector, false)
+		input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, b.selector, false)
 		if err != nil {
 			return nil, err
 		}
@@ -409,6 +1093,48 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 		size := expr.Args[1].(*IntegerLiteral)
 
 		return newSampleIterator(input, opt, int(size.Val))
+	case "lttb":
+		opt.Ordered = true
+		input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, b.selector, false)
+		if err != nil {
+			return nil, err
+		}
+		n := expr.Args[1].(*IntegerLiteral)
+
+		return newLTTBIterator(input, opt, int(n.Val))
+	case "mad_outliers":
+		opt.Ordered = true
+		input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, b.selector, false)
+		if err != nil {
+			return nil, err
+		}
+		var threshold float64
+		switch arg := expr.Args[1].(type) {
+		case *NumberLiteral:
+			threshold = arg.Val
+		case *IntegerLiteral:
+			threshold = float64(arg.Val)
+		}
+		return newMADOutliersIterator(input, opt, threshold)
+	case "outliers":
+		// The aggregate is computed for every series before the series
+		// are compared with each other.
+		input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
+		if err != nil {
+			return nil, err
+		}
+		var threshold float64
+		switch arg := expr.Args[1].(type) {
+		case *NumberLiteral:
+			threshold = arg.Val
+		case *IntegerLiteral:
+			threshold = float64(arg.Val)
+		}
+		method := "mad"
+		if len(expr.Args) == 3 {
+			method = expr.Args[2].(*StringLiteral).Val
+		}
+		return newOutliersIterator(input, method, threshold)
 	case "holt_winters", "holt_winters_with_fit":
 		opt.Ordered = true
 		input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, b.selector, false)
@@ -427,8 +1153,11 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 		opt.Interval = Interval{}
 
 		return newHoltWintersIterator(input, opt, int(h.Val), int(m.Val), includeFitData, interval)
-	case "derivative", "non_negative_derivative", "difference", "non_negative_difference", "moving_average", "elapsed":
-		if !opt.Interval.IsZero() {
+	case "derivative", "non_negative_derivative", "difference", "non_negative_difference", "moving_average", "zscore", "elapsed":
+		// The earlier windows are read so the first window within the time
+		// range has a previous window. An overlapping window that starts
+		// before the time range is only partially read so it is not used.
+		if !opt.Interval.IsZero() && opt.Interval.Length == 0 {
 			if opt.Ascending {
 				opt.StartTime -= int64(opt.Interval.Duration)
 			} else {
@@ -455,7 +1184,7 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 			return newDifferenceIterator(input, opt, isNonNegative)
 		case "moving_average":
 			n := expr.Args[1].(*IntegerLiteral)
-			if n.Val > 1 && !opt.Interval.IsZero() {
+			if n.Val > 1 && !opt.Interval.IsZero() && opt.Interval.Length == 0 {
 				if opt.Ascending {
 					opt.StartTime -= int64(opt.Interval.Duration) * (n.Val - 1)
 				} else {
@@ -463,8 +1192,42 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 				}
 			}
 			return newMovingAverageIterator(input, int(n.Val), opt)
+		case "zscore":
+			n := expr.Args[1].(*IntegerLiteral)
+			if n.Val > 1 && !opt.Interval.IsZero() && opt.Interval.Length == 0 {
+				if opt.Ascending {
+					opt.StartTime -= int64(opt.Interval.Duration) * (n.Val - 1)
+				} else {
+					opt.EndTime += int64(opt.Interval.Duration) * (n.Val - 1)
+				}
+			}
+			return newZScoreIterator(input, int(n.Val), opt)
 		}
 		panic(fmt.Sprintf("invalid series aggregate function: %s", expr.Name))
+	case "state_duration", "state_count":
+		opt.Ordered = true
+
+		// The condition is evaluated on each point by a CASE expression
+		// that results in whether the point is in the state.
+		cond := &CaseExpr{
+			WhenClauses: []*WhenClause{{Condition: expr.Args[0], Result: &BooleanLiteral{Val: true}}},
+			Else:        &BooleanLiteral{Val: false},
+		}
+		input, err := buildExprIterator(cond, b.ic, b.sources, opt, false, false)
+		if err != nil {
+			return nil, err
+		} else if _, ok := input.(*nilFloatIterator); ok {
+			return input, nil
+		}
+
+		var unit time.Duration
+		if expr.Name == "state_duration" {
+			unit = time.Second
+			if len(expr.Args) == 2 {
+				unit = expr.Args[1].(*DurationLiteral).Val
+			}
+		}
+		return newStateIterator(input, opt, unit)
 	case "cumulative_sum":
 		opt.Ordered = true
 		input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, b.selector, false)
@@ -474,12 +1237,24 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 		return newCumulativeSumIterator(input, opt)
 	case "integral":
 		opt.Ordered = true
-		input, err := buildExprIterator(expr.Args[0].(*VarRef), b.ic, b.sources, opt, false, false)
+		input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
 		if err != nil {
 			return nil, err
 		}
 		interval := opt.IntegralInterval()
-		return newIntegralIterator(input, opt, interval)
+		if opt.Interval.Length == 0 {
+			return newIntegralIterator(input, opt, interval)
+		}
+
+		// The integral of an overlapping window is the sum of the
+		// integrals of each step within the window.
+		stepOpt := opt
+		stepOpt.Interval.Length = 0
+		itr, err := newIntegralIterator(input, stepOpt, interval)
+		if err != nil {
+			return nil, err
+		}
+		return newSumIterator(itr, opt)
 	case "top":
 		if len(expr.Args) < 2 {
 			return nil, fmt.Errorf("top() requires 2 or more arguments, got %d", len(expr.Args))
@@ -516,6 +1291,9 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 				return nil, err
 			}
 			input = i
+
+			// The points have already been reduced into their windows.
+			opt.Interval.Length = 0
 		} else {
 			// There are no arguments so do not organize the points by tags.
 			builder := *b
@@ -537,6 +1315,7 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 		if len(expr.Args) < 2 {
 			return nil, fmt.Errorf("bottom() requires 2 or more arguments, got %d", len(expr.Args))
 		}
+		bottomOpt := b.opt
 
 		var input Iterator
 		if len(expr.Args) > 2 {
@@ -569,6 +1348,9 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 				return nil, err
 			}
 			input = i
+
+			// The points have already been reduced into their windows.
+			bottomOpt.Interval.Length = 0
 		} else {
 			// There are no arguments so do not organize the points by tags.
 			builder := *b
@@ -585,7 +1367,7 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 		}
 
 		n := expr.Args[len(expr.Args)-1].(*IntegerLiteral)
-		return newBottomIterator(input, b.opt, int(n.Val), b.writeMode)
+		return newBottomIterator(input, bottomOpt, int(n.Val), b.writeMode)
 	}
 
 	itr, err := func() (Iterator, error) {
@@ -598,6 +1380,8 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 					if err != nil {
 						return nil, err
 					}
+					// The distinct values have already been reduced into their windows.
+					opt.Interval.Length = 0
 					return newCountIterator(input, opt)
 				}
 			}
@@ -606,33 +1390,33 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 			return b.callIterator(expr, opt)
 		case "median":
 			opt.Ordered = true
-			input, err := buildExprIterator(expr.Args[0].(*VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
 			return newMedianIterator(input, opt)
 		case "mode":
-			input, err := buildExprIterator(expr.Args[0].(*VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
 			return NewModeIterator(input, opt)
 		case "stddev":
-			input, err := buildExprIterator(expr.Args[0].(*VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
 			return newStddevIterator(input, opt)
 		case "spread":
 			// OPTIMIZE(benbjohnson): convert to map/reduce
-			input, err := buildExprIterator(expr.Args[0].(*VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
 			return newSpreadIterator(input, opt)
 		case "percentile":
 			opt.Ordered = true
-			input, err := buildExprIterator(expr.Args[0].(*VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
@@ -644,6 +1428,146 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 				percentile = float64(arg.Val)
 			}
 			return newPercentileIterator(input, opt, percentile)
+		case "time_weighted_avg":
+			opt.Ordered = true
+			input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
+			if err != nil {
+				return nil, err
+			}
+
+			// The value at the start of the first window comes from the last
+			// point before the query so it is read separately and merged in
+			// front of the points within the query.
+			if opt.StartTime != MinTime {
+				call := &Call{Name: "last", Args: expr.Args[:1]}
+				lastOpt := opt
+				lastOpt.Expr = call
+				lastOpt.StartTime, lastOpt.EndTime = MinTime, opt.StartTime-1
+				lastOpt.Interval = Interval{}
+				lastOpt.Fill = NoFill
+
+				builder := *b
+				builder.opt = lastOpt
+				builder.selector = true
+				builder.writeMode = false
+
+				last, err := builder.callIterator(call, lastOpt)
+				if err != nil {
+					input.Close()
+					return nil, err
+				}
+				input = NewSortedMergeIterator([]Iterator{input, last}, opt)
+			}
+			method := expr.Args[1].(*StringLiteral).Val
+			return newTimeWeightedAverageIterator(input, opt, method)
+		case "rate", "increase":
+			// Counters are read in order so resets can be detected between
+			// consecutive points.
+			if ref, ok := expr.Args[0].(*VarRef); ok && (ref.Type == String || ref.Type == Boolean) {
+				return nil, fmt.Errorf("unsupported %s type: %s", expr.Name, ref.Type)
+			}
+			// The counter of each series is calculated separately so the
+			// series are identified by the tags that are not grouped by.
+			opt.Ordered = true
+			var err error
+			if opt, err = seriesAuxOptions(b.ic, b.sources, opt); err != nil {
+				return nil, err
+			}
+			input, err := buildExprIterator(expr.Args[0], b.ic, b.sources, opt, false, false)
+			if err != nil {
+				return nil, err
+			}
+
+			var unit time.Duration
+			args := expr.Args[1:]
+			if expr.Name == "rate" {
+				unit = time.Second
+				if len(args) > 0 {
+					unit = args[0].(*DurationLiteral).Val
+					args = args[1:]
+				}
+			}
+			var max float64
+			if len(args) > 0 {
+				switch arg := args[0].(type) {
+				case *NumberLiteral:
+					max = arg.Val
+				case *IntegerLiteral:
+					max = float64(arg.Val)
+				}
+			}
+			return newCounterIterator(input, opt, unit, max)
+		case "percentile_approx":
+			// Each source summarizes its points into t-digests which are
+			// merged before the percentile is estimated.
+			if ref, ok := expr.Args[0].(*VarRef); ok && (ref.Type == String || ref.Type == Boolean) {
+				return nil, fmt.Errorf("unsupported percentile_approx type: %s", ref.Type)
+			}
+			input, err := b.callIterator(expr, opt)
+			if err != nil {
+				return nil, err
+			} else if _, ok := input.(*nilFloatIterator); ok {
+				return input, nil
+			}
+			var percentile float64
+			switch arg := expr.Args[1].(type) {
+			case *NumberLiteral:
+				percentile = arg.Val
+			case *IntegerLiteral:
+				percentile = float64(arg.Val)
+			}
+			// The sketches have already been reduced into their windows.
+			opt.Interval.Length = 0
+			return newPercentileApproxIterator(input, opt, percentile)
+		case "corr", "covar", "linear_regression":
+			// Each source computes the co-moments of its pairs which are
+			// merged before the statistic is computed.
+			for _, arg := range expr.Args {
+				if ref, ok := arg.(*VarRef); ok && (ref.Type == String || ref.Type == Boolean) {
+					return nil, fmt.Errorf("unsupported %s type: %s", expr.Name, ref.Type)
+				}
+			}
+			callOpt := opt
+			stat := expr.Name
+			if expr.Name == "linear_regression" {
+				// The fields are expanded into one call per statistic by
+				// RewriteFields.
+				if got := len(expr.Args); got != 2 {
+					return nil, fmt.Errorf("invalid number of arguments for linear_regression, expected 2, got %d", got)
+				}
+				stat = expr.Args[1].(*StringLiteral).Val
+			} else {
+				callOpt.Aux = []VarRef{*expr.Args[1].(*VarRef)}
+			}
+			input, err := b.callIterator(expr, callOpt)
+			if err != nil {
+				return nil, err
+			} else if _, ok := input.(*nilFloatIterator); ok {
+				return input, nil
+			}
+			// The co-moments have already been reduced into their windows.
+			opt.Interval.Length = 0
+			return newCovarianceStatIterator(input, opt, stat)
+		case "count_distinct_approx":
+			// Each source adds its values to sketches which are merged
+			// before the number of distinct values is estimated.
+			input, err := b.callIterator(expr, opt)
+			if err != nil {
+				return nil, err
+			} else if _, ok := input.(*nilFloatIterator); ok {
+				return input, nil
+			}
+			// The sketches have already been reduced into their windows.
+			opt.Interval.Length = 0
+			return newCountDistinctApproxIterator(input, opt)
+		case "histogram":
+			// A histogram is rewritten into one call per bucket by
+			// RewriteFields. The sources count the points of the bucket
+			// and the value of the merged points is the count.
+			if got := len(expr.Args); got != 3 {
+				return nil, fmt.Errorf("invalid number of arguments for histogram, expected 3, got %d", got)
+			}
+			return b.callIterator(expr, opt)
 		default:
 			return nil, fmt.Errorf("unsupported call: %s", expr.Name)
 		}
@@ -652,11 +1576,68 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 	if err != nil {
 		return nil, err
 	}
+	return b.windowCallIterator(expr, itr, opt)
+}
+
+// buildHistogramIterators creates an iterator for each bucket of a histogram.
+// The buckets are the calls that RewriteFields created for a single
+// histogram() call and they are counted from a single call iterator.
+func (b *exprIteratorBuilder) buildHistogramIterators(buckets []*Call) ([]Iterator, error) {
+	opt := b.opt
+	opt.Limit, opt.Offset = 0, 0
+
+	// Count every bucket with a single call that has each bound.
+	call := &Call{Name: "histogram", Args: []Expr{buckets[0].Args[0]}}
+	for _, bucket := range buckets {
+		call.Args = append(call.Args, bucket.Args[1])
+	}
+	call.Args = append(call.Args, buckets[len(buckets)-1].Args[2])
+	opt.Expr = call
+
+	input, err := b.callIterator(call, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Separate the count of each bucket into its own iterator.
+	aopt := opt
+	aopt.Aux = make([]VarRef, len(buckets))
+	for i := range buckets {
+		aopt.Aux[i] = VarRef{Val: strconv.Itoa(i), Type: Integer}
+	}
+	aitr := NewAuxIterator(input, aopt)
 
+	itrs := make([]Iterator, len(buckets))
+	for i, bucket := range buckets {
+		itr, err := b.windowCallIterator(bucket, aitr.Iterator(aopt.Aux[i].Val, Integer), opt)
+		if err != nil {
+			Iterators(Iterators(itrs).filterNonNil()).Close()
+			aitr.Close()
+			return nil, err
+		}
+		itrs[i] = itr
+	}
+
+	// Background the primary iterator since there is no reader for it.
+	aitr.Background()
+	return itrs, nil
+}
+
+// windowCallIterator sets the time of the points of a call iterator to their
+// window and fills in the windows without a point.
+func (b *exprIteratorBuilder) windowCallIterator(expr *Call, itr Iterator, opt IteratorOptions) (Iterator, error) {
+	var err error
 	if !b.selector || !opt.Interval.IsZero() {
 		itr = NewIntervalIterator(itr, opt)
 		if !opt.Interval.IsZero() && opt.Fill != NoFill {
-			itr = NewFillIterator(itr, expr, opt)
+			var lookback map[fillSeriesKey]lookbackPoint
+			if opt.FillLookback {
+				if lookback, err = b.lookback(expr, opt); err != nil {
+					itr.Close()
+					return nil, err
+				}
+			}
+			itr = newFillIterator(itr, expr, opt, lookback)
 		}
 	}
 	if opt.InterruptCh != nil {
@@ -665,6 +1646,111 @@ func (b *exprIteratorBuilder) buildCallIterator(expr *Call) (Iterator, error) {
 	return itr, nil
 }
 
+// lookback returns the last point of each series before the time range of
+// the query so fill(previous) and fill(linear) can fill the first windows.
+// A descending query reads the first point after the time range instead.
+func (b *exprIteratorBuilder) lookback(expr *Call, opt IteratorOptions) (map[fillSeriesKey]lookbackPoint, error) {
+	// A single point is only a stand-in for the previous window when the
+	// aggregate of that point is its own value.
+	switch expr.Name {
+	case "first", "last", "min", "max", "mean", "median", "mode",
+		"percentile", "percentile_approx", "time_weighted_avg":
+	default:
+		return nil, nil
+	}
+	ref, ok := expr.Args[0].(*VarRef)
+	if !ok {
+		return nil, nil
+	}
+
+	// The sources only need to read a single point for each series so
+	// the first and last calls are read using a cursor that starts at the
+	// edge of the time range.
+	lookbackOpt := opt
+	lookbackOpt.Interval = Interval{}
+	lookbackOpt.Aux = nil
+	lookbackOpt.Limit, lookbackOpt.Offset = 0, 0
+	lookbackOpt.Ascending = true
+	lookbackOpt.Ordered = true
+	if opt.Ascending {
+		if opt.StartTime == MinTime {
+			return nil, nil
+		}
+		lookbackOpt.Expr = &Call{Name: "last", Args: []Expr{ref}}
+		lookbackOpt.StartTime, lookbackOpt.EndTime = MinTime, opt.StartTime-1
+	} else {
+		if opt.EndTime == MaxTime {
+			return nil, nil
+		}
+		lookbackOpt.Expr = &Call{Name: "first", Args: []Expr{ref}}
+		lookbackOpt.StartTime, lookbackOpt.EndTime = opt.EndTime+1, MaxTime
+	}
+
+	inputs := make([]Iterator, 0, len(b.sources))
+	for _, source := range b.sources {
+		// Subqueries and joins do not have points outside of the time
+		// range of the outer query.
+		m, ok := source.(*Measurement)
+		if !ok {
+			continue
+		}
+		input, err := b.ic.CreateIterator(m, lookbackOpt)
+		if err != nil {
+			Iterators(inputs).Close()
+			return nil, err
+		}
+		inputs = append(inputs, input)
+	}
+
+	itr, err := Iterators(inputs).Merge(lookbackOpt)
+	if err != nil {
+		Iterators(inputs).Close()
+		return nil, err
+	} else if itr == nil {
+		return nil, nil
+	}
+	defer itr.Close()
+
+	lookback := make(map[fillSeriesKey]lookbackPoint)
+	for {
+		var p Point
+		switch itr := itr.(type) {
+		case FloatIterator:
+			if fp, err := itr.Next(); err != nil {
+				return nil, err
+			} else if fp != nil {
+				p = fp
+			}
+		case IntegerIterator:
+			if ip, err := itr.Next(); err != nil {
+				return nil, err
+			} else if ip != nil {
+				p = ip
+			}
+		case StringIterator:
+			if sp, err := itr.Next(); err != nil {
+				return nil, err
+			} else if sp != nil {
+				p = sp
+			}
+		case BooleanIterator:
+			if bp, err := itr.Next(); err != nil {
+				return nil, err
+			} else if bp != nil {
+				p = bp
+			}
+		default:
+			return nil, fmt.Errorf("unsupported lookback iterator type: %T", itr)
+		}
+		if p == nil {
+			return lookback, nil
+		} else if v := p.value(); v != nil {
+			start, _ := opt.Window(p.time())
+			lookback[fillSeriesKey{name: p.name(), tags: p.tags().ID()}] = lookbackPoint{tags: p.tags(), time: start, value: v}
+		}
+	}
+}
+
 func (b *exprIteratorBuilder) buildBinaryExprIterator(expr *BinaryExpr) (Iterator, error) {
 	if rhs, ok := expr.RHS.(Literal); ok {
 		// The right hand side is a literal. It is more common to have the RHS be a literal,
@@ -700,12 +1786,37 @@ func (b *exprIteratorBuilder) buildBinaryExprIterator(expr *BinaryExpr) (Iterato
 }
 
 func (b *exprIteratorBuilder) callIterator(expr *Call, opt IteratorOptions) (Iterator, error) {
+	// A CASE expression is evaluated on each point so the call cannot be
+	// pushed down to the sources.
+	if arg0, ok := expr.Args[0].(*CaseExpr); ok {
+		input, err := buildExprIterator(arg0, b.ic, b.sources, opt, b.selector, false)
+		if err != nil {
+			return nil, err
+		}
+		return NewCallIterator(input, opt)
+	}
+
+	// A selector keeps the time of the selected point, which does not
+	// identify the overlapping window it was selected from. The sources
+	// select a point from each step instead and the selected points are
+	// combined into the overlapping windows when they are merged.
+	sopt := opt
+	if mergesWindowSteps(expr) {
+		sopt.Interval.Length = 0
+	}
+
 	inputs := make([]Iterator, 0, len(b.sources))
 	if err := func() error {
 		for _, source := range b.sources {
 			switch source := source.(type) {
 			case *Measurement:
-				input, err := b.ic.CreateIterator(source, opt)
+				input, err := b.ic.CreateIterator(source, sopt)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
+			case *Join:
+				input, err := buildJoinIterator(b.ic, source, sopt)
 				if err != nil {
 					return err
 				}
@@ -714,13 +1825,13 @@ func (b *exprIteratorBuilder) callIterator(expr *Call, opt IteratorOptions) (Ite
 				// Identify the name of the field we are using.
 				arg0 := expr.Args[0].(*VarRef)
 
-				input, err := buildExprIterator(arg0, b.ic, []Source{source}, opt, b.selector, false)
+				input, err := buildExprIterator(arg0, b.ic, []Source{source}, sopt, b.selector, false)
 				if err != nil {
 					return err
 				}
 
 				// Wrap the result in a call iterator.
-				i, err := NewCallIterator(input, opt)
+				i, err := NewCallIterator(input, sopt)
 				if err != nil {
 					input.Close()
 					return err
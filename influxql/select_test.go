@@ -2,13 +2,17 @@ package influxql_test
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/influxdb/influxql"
 )
 
@@ -1305,6 +1309,38 @@ func TestSelect_Bottom_GroupByTags_Integer(t *testing.T) {
 	}
 }
 
+// Ensure a SELECT query passes a derived dimension to the sources.
+func TestSelect_DerivedDimension(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		} else if !reflect.DeepEqual(opt.Dimensions, []string{"region"}) {
+			t.Fatalf("unexpected dimensions: %v", opt.Dimensions)
+		} else if call := opt.DerivedDimensions["region"]; call == nil || call.String() != `regex_extract(host, /^(\w+)-(\w+)/, 2)` {
+			t.Fatalf("unexpected derived dimension: %v", call)
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("region=eu"), Time: 0 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("region=eu"), Time: 5 * Second, Value: 3},
+			{Name: "cpu", Tags: ParseTags("region=us"), Time: 0 * Second, Value: 4},
+		}}, opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:10Z' GROUP BY regex_extract(host, /^(\w+)-(\w+)/, 2) AS region`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("region=eu"), Time: 5 * Second, Value: 3, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("region=us"), Time: 0 * Second, Value: 4, Aggregated: 1}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
 // Ensure a SELECT query with a fill(null) statement can be executed.
 func TestSelect_Fill_Null_Float(t *testing.T) {
 	var ic IteratorCreator
@@ -1395,7 +1431,331 @@ func TestSelect_Fill_Previous_Float(t *testing.T) {
 	}
 }
 
+// Ensure a SELECT query with fill(previous, lookback) fills the first windows
+// with the last point before the time range.
+func TestSelect_Fill_Previous_Lookback(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		var points []influxql.FloatPoint
+		for _, p := range []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=0"), Time: 2 * Second, Value: 9},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 42 * Second, Value: 3},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 45 * Second, Value: 5},
+			{Name: "cpu", Tags: ParseTags("host=C"), Time: 8 * Second, Value: 7},
+		} {
+			if p.Time >= opt.StartTime && p.Time <= opt.EndTime {
+				points = append(points, p)
+			}
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT mean(value) FROM cpu WHERE time >= '1970-01-01T00:00:20Z' AND time < '1970-01-01T00:01:00Z' GROUP BY host, time(10s) fill(previous, lookback)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=0"), Time: 20 * Second, Value: 9}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=0"), Time: 30 * Second, Value: 9}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=0"), Time: 40 * Second, Value: 9}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=0"), Time: 50 * Second, Value: 9}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 20 * Second, Value: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 30 * Second, Value: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 40 * Second, Value: 3, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 50 * Second, Value: 3}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 20 * Second, Nil: true}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 30 * Second, Nil: true}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 40 * Second, Value: 5, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 50 * Second, Value: 5}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=C"), Time: 20 * Second, Value: 7}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=C"), Time: 30 * Second, Value: 7}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=C"), Time: 40 * Second, Value: 7}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=C"), Time: 50 * Second, Value: 7}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
 // Ensure a SELECT query with a fill(linear) statement can be executed.
+func TestSelect_Calendar(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: mustParseTime("2000-01-01T08:00:00Z").UnixNano(), Value: 1},
+			{Name: "cpu", Time: mustParseTime("2000-01-31T12:00:00Z").UnixNano(), Value: 2},
+			{Name: "cpu", Time: mustParseTime("2000-02-01T04:00:00Z").UnixNano(), Value: 4},
+			{Name: "cpu", Time: mustParseTime("2000-05-15T00:00:00Z").UnixNano(), Value: 8},
+		}}, opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT sum(value) FROM cpu WHERE time >= '2000-01-01T08:00:00Z' AND time < '2000-07-01T07:00:00Z' GROUP BY time(1mo) fill(0) TZ('America/Los_Angeles')`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Time: mustParseTime("2000-01-01T08:00:00Z").UnixNano(), Value: 7, Aggregated: 3}},
+		{&influxql.FloatPoint{Name: "cpu", Time: mustParseTime("2000-02-01T08:00:00Z").UnixNano(), Value: 0}},
+		{&influxql.FloatPoint{Name: "cpu", Time: mustParseTime("2000-03-01T08:00:00Z").UnixNano(), Value: 0}},
+		{&influxql.FloatPoint{Name: "cpu", Time: mustParseTime("2000-04-01T08:00:00Z").UnixNano(), Value: 0}},
+		{&influxql.FloatPoint{Name: "cpu", Time: mustParseTime("2000-05-01T07:00:00Z").UnixNano(), Value: 8, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Time: mustParseTime("2000-06-01T07:00:00Z").UnixNano(), Value: 0}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+// Ensure the derivative of monthly windows is calculated per calendar month.
+func TestSelect_Calendar_Derivative(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: mustParseTime("2000-01-10T00:00:00Z").UnixNano(), Value: 10},
+			{Name: "cpu", Time: mustParseTime("2000-02-10T00:00:00Z").UnixNano(), Value: 7},
+			{Name: "cpu", Time: mustParseTime("2000-04-10T00:00:00Z").UnixNano(), Value: 13},
+		}}, opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT derivative(sum(value)) FROM cpu WHERE time >= '2000-01-01T00:00:00Z' AND time < '2000-05-01T00:00:00Z' GROUP BY time(1mo) fill(none)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Time: mustParseTime("2000-02-01T00:00:00Z").UnixNano(), Value: -3}},
+		{&influxql.FloatPoint{Name: "cpu", Time: mustParseTime("2000-04-01T00:00:00Z").UnixNano(), Value: 3}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_OverlappingWindows(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		points := []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: 2},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 25 * Second, Value: 4},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 35 * Second, Value: 8},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 55 * Second, Value: 16},
+		}
+		if !opt.Ascending {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+		if len(opt.Dimensions) > 0 {
+			sort.SliceStable(points, func(i, j int) bool {
+				return points[i].Tags.ID() < points[j].Tags.ID()
+			})
+		}
+		if _, ok := opt.Expr.(*influxql.Call); !ok {
+			return &FloatIterator{Points: points}, nil
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "Sum",
+			q:    `SELECT sum(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(20s, step(10s))`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 3, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 6, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 12, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 8, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 40 * Second, Value: 16, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 50 * Second, Value: 16, Aggregated: 1}},
+			},
+		},
+		{
+			name: "Count",
+			q:    `SELECT count(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(30s, step(10s)) fill(0)`,
+			points: [][]influxql.Point{
+				{&influxql.IntegerPoint{Name: "cpu", Time: 0 * Second, Value: 3, Aggregated: 3}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 10 * Second, Value: 3, Aggregated: 3}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 20 * Second, Value: 2, Aggregated: 2}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 30 * Second, Value: 2, Aggregated: 2}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 40 * Second, Value: 1, Aggregated: 1}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 50 * Second, Value: 1, Aggregated: 1}},
+			},
+		},
+		{
+			name: "Median",
+			q:    `SELECT median(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(30s, step(10s))`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 4}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 6}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 12}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 40 * Second, Value: 16}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 50 * Second, Value: 16}},
+			},
+		},
+		{
+			name: "Max",
+			q:    `SELECT max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(20s, step(10s)), host fill(none)`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 1, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 4, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 20 * Second, Value: 8, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 30 * Second, Value: 8, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 2, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 2, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 40 * Second, Value: 16, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 50 * Second, Value: 16, Aggregated: 1}},
+			},
+		},
+		{
+			name: "Descending",
+			q:    `SELECT sum(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(20s, step(10s)) ORDER BY time DESC`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 50 * Second, Value: 16, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 40 * Second, Value: 16, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 8, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 12, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 6, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 3, Aggregated: 2}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			itrs, err := influxql.Select(MustParseSelectStatement(tt.q), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
+// Ensure the sources of overlapping windows are merged into the windows when
+// each series is reduced separately.
+func TestSelect_OverlappingWindows_Merge(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		// Reduce each series separately and merge the results.
+		series := [][]influxql.FloatPoint{
+			{
+				{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 1},
+				{Name: "cpu", Tags: ParseTags("host=A"), Time: 25 * Second, Value: 4},
+				{Name: "cpu", Tags: ParseTags("host=A"), Time: 35 * Second, Value: 8},
+			},
+			{
+				{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: 2},
+				{Name: "cpu", Tags: ParseTags("host=B"), Time: 55 * Second, Value: 16},
+			},
+		}
+		itrs := make([]influxql.Iterator, 0, len(series))
+		for _, points := range series {
+			if _, ok := opt.Expr.(*influxql.Call); !ok {
+				itrs = append(itrs, &FloatIterator{Points: points})
+				continue
+			}
+			itr, err := influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+			if err != nil {
+				return nil, err
+			}
+			itrs = append(itrs, itr)
+		}
+		return influxql.Iterators(itrs).Merge(opt)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "First",
+			q:    `SELECT first(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(20s, step(10s))`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 1, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 2, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 4, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 8, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 40 * Second, Value: 16, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 50 * Second, Value: 16, Aggregated: 1}},
+			},
+		},
+		{
+			name: "Last",
+			q:    `SELECT last(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(20s, step(10s))`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 2, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 4, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 8, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 8, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 40 * Second, Value: 16, Aggregated: 1}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 50 * Second, Value: 16, Aggregated: 1}},
+			},
+		},
+		{
+			name: "Derivative",
+			q:    `SELECT derivative(mean(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(20s, step(10s))`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 1.5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 3}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 40 * Second, Value: 8}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 50 * Second, Value: 0}},
+			},
+		},
+		{
+			name: "Difference",
+			q:    `SELECT difference(mean(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(20s, step(10s))`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 1.5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 3}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 40 * Second, Value: 8}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 50 * Second, Value: 0}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			itrs, err := influxql.Select(MustParseSelectStatement(tt.q), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestSelect_Fill_Linear_Float_One(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
@@ -1493,6 +1853,43 @@ func TestSelect_Fill_Linear_Float_MultipleSeries(t *testing.T) {
 	}
 }
 
+// Ensure a SELECT query with fill(linear, lookback) interpolates the first
+// windows from the last point before the time range.
+func TestSelect_Fill_Linear_Lookback(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		var points []influxql.FloatPoint
+		for _, p := range []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 42 * Second, Value: 3},
+		} {
+			if p.Time >= opt.StartTime && p.Time <= opt.EndTime {
+				points = append(points, p)
+			}
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT mean(value) FROM cpu WHERE time >= '1970-01-01T00:00:20Z' AND time < '1970-01-01T00:01:00Z' GROUP BY host, time(10s) fill(linear, lookback)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 20 * Second, Value: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 30 * Second, Value: 2.5}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 40 * Second, Value: 3, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 50 * Second, Nil: true}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
 // Ensure a SELECT query with a fill(linear) statement can be executed for integers.
 func TestSelect_Fill_Linear_Integer_One(t *testing.T) {
 	var ic IteratorCreator
@@ -1872,123 +2269,677 @@ func TestSelect_Percentile_Integer(t *testing.T) {
 	}
 }
 
-// Ensure a SELECT sample() query can be executed.
-func TestSelect_Sample_Float(t *testing.T) {
+// Ensure a SELECT percentile_approx() query can be executed.
+func TestSelect_PercentileApprox_Float(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
 		if m.Name != "cpu" {
 			t.Fatalf("unexpected source: %s", m.Name)
 		}
-		return influxql.Iterators{
+
+		// Summarize each shard into t-digests before merging them.
+		var inputs influxql.Iterators
+		for _, input := range []influxql.Iterator{
 			&FloatIterator{Points: []influxql.FloatPoint{
 				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 5 * Second, Value: 10},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: 3},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 31 * Second, Value: 100},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 50 * Second, Value: 10},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 51 * Second, Value: 9},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 52 * Second, Value: 8},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 53 * Second, Value: 7},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 54 * Second, Value: 6},
 			}},
 			&FloatIterator{Points: []influxql.FloatPoint{
-				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 10 * Second, Value: 19},
-				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 15 * Second, Value: 2},
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: 19},
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 10 * Second, Value: 2},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 55 * Second, Value: 5},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 56 * Second, Value: 4},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 57 * Second, Value: 3},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 58 * Second, Value: 2},
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 59 * Second, Value: 1},
 			}},
-		}.Merge(opt)
+		} {
+			itr, err := influxql.NewCallIterator(input, opt)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, itr)
+		}
+		return inputs.Merge(opt)
 	}
 
 	// Execute selection.
-	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT sample(value, 2) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT percentile_approx(value, 90) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
 	if err != nil {
 		t.Fatal(err)
 	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	} else if diff := cmp.Diff(a, [][]influxql.Point{
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 10}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 19}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 3, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 30 * Second, Value: 100, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 10, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 50 * Second, Value: 9.5, Aggregated: 10}},
 	}); diff != "" {
 		t.Fatalf("unexpected points:\n%s", diff)
 	}
 }
 
-// Ensure a SELECT sample() query can be executed.
-func TestSelect_Sample_Integer(t *testing.T) {
+// Ensure a SELECT percentile_approx() query can be executed on integers.
+func TestSelect_PercentileApprox_Integer(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
 		if m.Name != "cpu" {
 			t.Fatalf("unexpected source: %s", m.Name)
 		}
-		return influxql.Iterators{
+		input, err := influxql.Iterators{
 			&IntegerIterator{Points: []influxql.IntegerPoint{
 				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 5 * Second, Value: 10},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: 3},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 31 * Second, Value: 100},
 			}},
 			&IntegerIterator{Points: []influxql.IntegerPoint{
-				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 10 * Second, Value: 19},
-				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 15 * Second, Value: 2},
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: 19},
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 10 * Second, Value: 2},
 			}},
 		}.Merge(opt)
+		if err != nil {
+			return nil, err
+		}
+		return influxql.NewCallIterator(input, opt)
 	}
 
 	// Execute selection.
-	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT sample(value, 2) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT percentile_approx(value, 50) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s) fill(none)`), &ic, nil)
 	if err != nil {
 		t.Fatal(err)
 	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	} else if diff := cmp.Diff(a, [][]influxql.Point{
-		{&influxql.IntegerPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20}},
-		{&influxql.IntegerPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 10}},
-		{&influxql.IntegerPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 19}},
-		{&influxql.IntegerPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 19.5, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 2.5, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 100, Aggregated: 1}},
 	}); diff != "" {
 		t.Fatalf("unexpected points:\n%s", diff)
 	}
 }
 
-// Ensure a SELECT sample() query can be executed.
-func TestSelect_Sample_Boolean(t *testing.T) {
+// Ensure a SELECT count_distinct_approx() query merges the sketches of each
+// shard before the distinct values are counted.
+func TestSelect_CountDistinctApprox_String(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
-		if m.Name != "cpu" {
+		if m.Name != "req" {
 			t.Fatalf("unexpected source: %s", m.Name)
 		}
-		return influxql.Iterators{
-			&BooleanIterator{Points: []influxql.BooleanPoint{
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: true},
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 5 * Second, Value: false},
+
+		var inputs influxql.Iterators
+		for _, input := range []influxql.Iterator{
+			&StringIterator{Points: []influxql.StringPoint{
+				{Name: "req", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: "alice"},
+				{Name: "req", Tags: ParseTags("region=west,host=A"), Time: 1 * Second, Value: "bob"},
+				{Name: "req", Tags: ParseTags("region=west,host=A"), Time: 2 * Second, Value: "alice"},
+				{Name: "req", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: "carol"},
+				{Name: "req", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: "dave"},
 			}},
-			&BooleanIterator{Points: []influxql.BooleanPoint{
-				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 10 * Second, Value: false},
-				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 15 * Second, Value: true},
+			&StringIterator{Points: []influxql.StringPoint{
+				{Name: "req", Tags: ParseTags("region=east,host=A"), Time: 3 * Second, Value: "bob"},
+				{Name: "req", Tags: ParseTags("region=east,host=A"), Time: 4 * Second, Value: "erin"},
+				{Name: "req", Tags: ParseTags("region=west,host=B"), Time: 6 * Second, Value: "dave"},
+				{Name: "req", Tags: ParseTags("region=west,host=B"), Time: 7 * Second, Value: "frank"},
 			}},
-		}.Merge(opt)
+		} {
+			itr, err := influxql.NewCallIterator(input, opt)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, itr)
+		}
+		return inputs.Merge(opt)
 	}
 
 	// Execute selection.
-	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT sample(value, 2) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT count_distinct_approx(user_id) FROM req WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:30Z' GROUP BY time(10s), host`), &ic, nil)
 	if err != nil {
 		t.Fatal(err)
 	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	} else if diff := cmp.Diff(a, [][]influxql.Point{
-		{&influxql.BooleanPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: true}},
-		{&influxql.BooleanPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: false}},
-		{&influxql.BooleanPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: false}},
-		{&influxql.BooleanPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: true}},
+		{&influxql.IntegerPoint{Name: "req", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 3}},
+		{&influxql.IntegerPoint{Name: "req", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 1}},
+		{&influxql.IntegerPoint{Name: "req", Tags: ParseTags("host=A"), Time: 20 * Second, Value: 0}},
+		{&influxql.IntegerPoint{Name: "req", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 2}},
+		{&influxql.IntegerPoint{Name: "req", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 0}},
+		{&influxql.IntegerPoint{Name: "req", Tags: ParseTags("host=B"), Time: 20 * Second, Value: 0}},
 	}); diff != "" {
 		t.Fatalf("unexpected points:\n%s", diff)
 	}
 }
 
-// Ensure a SELECT sample() query can be executed.
-func TestSelect_Sample_String(t *testing.T) {
+// Ensure a SELECT count_distinct_approx() query can be executed on floats.
+func TestSelect_CountDistinctApprox_Float(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
-		if m.Name != "cpu" {
-			t.Fatalf("unexpected source: %s", m.Name)
+		var points []influxql.FloatPoint
+		for i := 0; i < 10000; i++ {
+			points = append(points, influxql.FloatPoint{Name: "cpu", Time: int64(i), Value: float64(i % 1000)})
 		}
-		return influxql.Iterators{
-			&StringIterator{Points: []influxql.StringPoint{
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: "a"},
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 5 * Second, Value: "b"},
-			}},
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT count_distinct_approx(value) FROM cpu`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := Iterators(itrs).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(a) != 1 {
+		t.Fatalf("unexpected number of points: %d", len(a))
+	}
+
+	p, ok := a[0][0].(*influxql.IntegerPoint)
+	if !ok {
+		t.Fatalf("unexpected point: %T", a[0][0])
+	} else if p.Value < 990 || p.Value > 1010 {
+		t.Fatalf("unexpected distinct count: %d", p.Value)
+	}
+}
+
+// Ensure a SELECT histogram() query counts the values of each bucket.
+func TestSelect_Histogram(t *testing.T) {
+	var ic IteratorCreator
+	var n int
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "req" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		} else if got, exp := opt.Expr.String(), `histogram(latency::float, 0.000, 10.000, 20.000, 30.000)`; got != exp {
+			t.Fatalf("unexpected expr: exp %s, got %s", exp, got)
+		} else if n++; n > 1 {
+			t.Fatal("buckets must be counted by a single iterator")
+		}
+
+		var inputs influxql.Iterators
+		for _, input := range []influxql.Iterator{
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "req", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 1},
+				{Name: "req", Tags: ParseTags("host=A"), Time: 1 * Second, Value: 12},
+				{Name: "req", Tags: ParseTags("host=A"), Time: 2 * Second, Value: 40},
+				{Name: "req", Tags: ParseTags("host=A"), Time: 11 * Second, Value: 15},
+			}},
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "req", Tags: ParseTags("host=A"), Time: 3 * Second, Value: 5},
+				{Name: "req", Tags: ParseTags("host=A"), Time: 4 * Second, Value: 25},
+			}},
+		} {
+			itr, err := influxql.NewCallIterator(input, opt)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, itr)
+		}
+		return inputs.Merge(opt)
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"latency": influxql.Float}, map[string]struct{}{"host": struct{}{}}, nil
+	}
+
+	stmt, err := MustParseSelectStatement(`SELECT histogram(latency, linear(0, 10, 3)) FROM req WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s)`).RewriteFields(&ic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(stmt, &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{
+			&influxql.IntegerPoint{Name: "req", Time: 0 * Second, Value: 2},
+			&influxql.IntegerPoint{Name: "req", Time: 0 * Second, Value: 1},
+			&influxql.IntegerPoint{Name: "req", Time: 0 * Second, Value: 1},
+		},
+		{
+			&influxql.IntegerPoint{Name: "req", Time: 10 * Second, Value: 0},
+			&influxql.IntegerPoint{Name: "req", Time: 10 * Second, Value: 1},
+			&influxql.IntegerPoint{Name: "req", Time: 10 * Second, Value: 0},
+		},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_Covariance(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		} else if diff := cmp.Diff(opt.Aux, []influxql.VarRef{{Val: "y", Type: influxql.Integer}}); diff != "" {
+			t.Fatalf("unexpected auxiliary fields:\n%s", diff)
+		}
+
+		var inputs influxql.Iterators
+		for _, input := range []influxql.Iterator{
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Time: 0 * Second, Value: 1, Aux: []interface{}{int64(2)}},
+				{Name: "cpu", Time: 1 * Second, Value: 2, Aux: []interface{}{int64(4)}},
+				{Name: "cpu", Time: 2 * Second, Value: 3, Aux: []interface{}{int64(5)}},
+				{Name: "cpu", Time: 11 * Second, Value: 1, Aux: []interface{}{int64(1)}},
+			}},
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Time: 3 * Second, Value: 4, Aux: []interface{}{int64(9)}},
+				{Name: "cpu", Time: 4 * Second, Value: 100, Aux: []interface{}{nil}},
+			}},
+		} {
+			itr, err := influxql.NewCallIterator(input, opt)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, itr)
+		}
+		return inputs.Merge(opt)
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"x": influxql.Float, "y": influxql.Integer}, nil, nil
+	}
+
+	stmt, err := MustParseSelectStatement(`SELECT corr(x, y), covar(x, y) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s) fill(none)`).RewriteFields(&ic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(stmt, &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{
+			&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 11 / math.Sqrt(130), Aggregated: 4},
+			&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 11.0 / 3, Aggregated: 4},
+		},
+	}, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_LinearRegression(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		var inputs influxql.Iterators
+		for _, input := range []influxql.Iterator{
+			&IntegerIterator{Points: []influxql.IntegerPoint{
+				{Name: "cpu", Time: 0 * Second, Value: 1},
+				{Name: "cpu", Time: 2 * Second, Value: 5},
+				{Name: "cpu", Time: 10 * Second, Value: 10},
+				{Name: "cpu", Time: 12 * Second, Value: 6},
+			}},
+			&IntegerIterator{Points: []influxql.IntegerPoint{
+				{Name: "cpu", Time: 1 * Second, Value: 3},
+				{Name: "cpu", Time: 3 * Second, Value: 7},
+			}},
+		} {
+			itr, err := influxql.NewCallIterator(input, opt)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, itr)
+		}
+		return inputs.Merge(opt)
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"value": influxql.Integer}, nil, nil
+	}
+
+	stmt, err := MustParseSelectStatement(`SELECT linear_regression(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s)`).RewriteFields(&ic)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := stmt.ColumnNames(), []string{"time", "linear_regression_slope", "linear_regression_intercept", "linear_regression_r2"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected columns: got %v, exp %v", got, exp)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(stmt, &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{
+			&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 2, Aggregated: 4},
+			&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 1, Aggregated: 4},
+			&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 1, Aggregated: 4},
+		},
+		{
+			&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: -2, Aggregated: 2},
+			&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 10, Aggregated: 2},
+			&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 1, Aggregated: 2},
+		},
+	}, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+// Ensure a SELECT query only returns the rows that match the HAVING clause.
+func TestSelect_Having(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+			{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 1 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: 3},
+			{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 12 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 31 * Second, Value: 100},
+			{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 50 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 51 * Second, Value: 9},
+		}}, opt)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "Alias and tag",
+			q:    `SELECT mean(value) AS m, max(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(10s), host fill(none) HAVING m > 5 AND host = 'A'`,
+			points: [][]influxql.Point{
+				{
+					&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 15},
+					&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20},
+				},
+				{
+					&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 30 * Second, Value: 100},
+					&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 30 * Second, Value: 100},
+				},
+			},
+		},
+		{
+			name: "Call with limit",
+			q:    `SELECT mean(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:01:00Z' GROUP BY time(10s), host fill(none) HAVING mean(value) < 10 LIMIT 1`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 50 * Second, Value: 5}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			itrs, err := influxql.Select(MustParseSelectStatement(tt.q), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
+// Ensure a SELECT query returns an error when the HAVING clause references
+// a call that is not selected.
+func TestSelect_Having_UnknownCall(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		return &FloatIterator{}, nil
+	}
+
+	_, err := influxql.Select(MustParseSelectStatement(`SELECT mean(value) FROM cpu GROUP BY host HAVING max(value) > 10`), &ic, nil)
+	if err == nil || err.Error() != `max(value) must be selected to be used in HAVING clause` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = influxql.Select(MustParseSelectStatement(`SELECT mean(value) FROM cpu GROUP BY host HAVING region = 'west'`), &ic, nil)
+	if err == nil || err.Error() != `unknown column or tag in HAVING clause: region` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSelect_OrderBy(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		points := []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 5},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 20},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 5 * Second, Value: 30},
+			{Name: "cpu", Tags: ParseTags("host=C"), Time: 0 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=C"), Time: 20 * Second, Value: 10},
+		}
+		for i := range points {
+			points[i].Tags = points[i].Tags.Subset(opt.Dimensions)
+		}
+		sort.SliceStable(points, func(i, j int) bool {
+			if a, b := points[i].Tags.ID(), points[j].Tags.ID(); a != b {
+				return a < b
+			}
+			return points[i].Time < points[j].Time
+		})
+
+		if _, ok := opt.Expr.(*influxql.Call); !ok {
+			for i := range points {
+				points[i].Aux = []interface{}{points[i].Value}
+			}
+			return &FloatIterator{Points: points}, nil
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "Aggregate with limit",
+			q:    `SELECT max(value) FROM cpu GROUP BY host ORDER BY max DESC LIMIT 2`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 5 * Second, Value: 30}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 20}},
+			},
+		},
+		{
+			name: "Tag",
+			q:    `SELECT mean(value) FROM cpu GROUP BY host ORDER BY host DESC`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=C"), Time: 0, Value: 5.5}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0, Value: 30}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0, Value: 12.5}},
+			},
+		},
+		{
+			name: "Raw with offset",
+			q:    `SELECT value::float FROM cpu ORDER BY value DESC LIMIT 2 OFFSET 1`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 20}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 10}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			itrs, err := influxql.Select(MustParseSelectStatement(tt.q), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
+// Ensure a SELECT query returns an error when sorting its rows across series
+// buffers more points than the limit.
+func TestSelect_OrderBy_MaxPointN(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		return &FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: 0 * Second, Aux: []interface{}{float64(1)}},
+			{Name: "cpu", Time: 5 * Second, Aux: []interface{}{float64(3)}},
+			{Name: "cpu", Time: 10 * Second, Aux: []interface{}{float64(2)}},
+		}}, nil
+	}
+
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT value::float FROM cpu ORDER BY value DESC LIMIT 1`), &ic, &influxql.SelectOptions{MaxPointN: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer influxql.Iterators(itrs).Close()
+
+	if _, err := Iterators(itrs).ReadAll(); err == nil || err.Error() != `max-select-point limit exceeed: (3/2)` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a SELECT query returns an error when it is sorted by a name that is
+// neither a column nor a tag it is grouped by.
+func TestSelect_OrderBy_Unknown(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		return &FloatIterator{}, nil
+	}
+
+	_, err := influxql.Select(MustParseSelectStatement(`SELECT max(value) FROM cpu GROUP BY host ORDER BY region`), &ic, nil)
+	if err == nil || err.Error() != `unknown column or tag in ORDER BY clause: region` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensure a SELECT sample() query can be executed.
+func TestSelect_Sample_Float(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.Iterators{
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 5 * Second, Value: 10},
+			}},
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 10 * Second, Value: 19},
+				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 15 * Second, Value: 2},
+			}},
+		}.Merge(opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT sample(value, 2) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 10}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 19}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: 2}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+// Ensure a SELECT sample() query can be executed.
+func TestSelect_Sample_Integer(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.Iterators{
+			&IntegerIterator{Points: []influxql.IntegerPoint{
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 5 * Second, Value: 10},
+			}},
+			&IntegerIterator{Points: []influxql.IntegerPoint{
+				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 10 * Second, Value: 19},
+				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 15 * Second, Value: 2},
+			}},
+		}.Merge(opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT sample(value, 2) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.IntegerPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20}},
+		{&influxql.IntegerPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 10}},
+		{&influxql.IntegerPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 19}},
+		{&influxql.IntegerPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: 2}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+// Ensure a SELECT sample() query can be executed.
+func TestSelect_Sample_Boolean(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.Iterators{
+			&BooleanIterator{Points: []influxql.BooleanPoint{
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: true},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 5 * Second, Value: false},
+			}},
+			&BooleanIterator{Points: []influxql.BooleanPoint{
+				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 10 * Second, Value: false},
+				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 15 * Second, Value: true},
+			}},
+		}.Merge(opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT sample(value, 2) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.BooleanPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: true}},
+		{&influxql.BooleanPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: false}},
+		{&influxql.BooleanPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: false}},
+		{&influxql.BooleanPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: true}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+// Ensure a SELECT sample() query can be executed.
+func TestSelect_Sample_String(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.Iterators{
+			&StringIterator{Points: []influxql.StringPoint{
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: "a"},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 5 * Second, Value: "b"},
+			}},
 			&StringIterator{Points: []influxql.StringPoint{
 				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 10 * Second, Value: "c"},
 				{Name: "cpu", Tags: ParseTags("region=east,host=B"), Time: 15 * Second, Value: "d"},
@@ -2055,6 +3006,199 @@ func TestSelect_Raw(t *testing.T) {
 	}
 }
 
+// Ensure a SELECT query with CASE expressions can be executed.
+func TestSelect_Case(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		} else if opt.Expr != nil {
+			t.Fatalf("unexpected expression: %s", opt.Expr)
+		}
+
+		// Points are only read where a field has a value.
+		var hasField bool
+		for _, ref := range opt.Aux {
+			hasField = hasField || ref.Type != influxql.Tag
+		}
+		if !hasField {
+			return nil, nil
+		}
+
+		var points []influxql.FloatPoint
+		for _, row := range []struct {
+			time   int64
+			host   string
+			status int64
+			value  float64
+		}{
+			{time: 0 * Second, host: "A", status: 200, value: 1},
+			{time: 5 * Second, host: "B", status: 200, value: 2},
+			{time: 10 * Second, host: "A", status: 500, value: 3},
+			{time: 15 * Second, host: "B", status: 503, value: 4},
+		} {
+			aux := make([]interface{}, len(opt.Aux))
+			for i, ref := range opt.Aux {
+				switch ref.Val {
+				case "host":
+					aux[i] = row.host
+				case "status":
+					aux[i] = row.status
+				case "value":
+					aux[i] = row.value
+				}
+			}
+			points = append(points, influxql.FloatPoint{Name: "cpu", Time: row.time, Aux: aux})
+		}
+		return &FloatIterator{Points: points}, nil
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{
+			"status": influxql.Integer,
+			"value":  influxql.Float,
+		}, map[string]struct{}{"host": struct{}{}}, nil
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "Raw",
+			q:    `SELECT value, CASE WHEN status >= 500 THEN 'error' WHEN host = 'B' THEN 'warn' ELSE 'ok' END FROM cpu`,
+			points: [][]influxql.Point{
+				{
+					&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 1},
+					&influxql.StringPoint{Name: "cpu", Time: 0 * Second, Value: "ok"},
+				},
+				{
+					&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 2},
+					&influxql.StringPoint{Name: "cpu", Time: 5 * Second, Value: "warn"},
+				},
+				{
+					&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 3},
+					&influxql.StringPoint{Name: "cpu", Time: 10 * Second, Value: "error"},
+				},
+				{
+					&influxql.FloatPoint{Name: "cpu", Time: 15 * Second, Value: 4},
+					&influxql.StringPoint{Name: "cpu", Time: 15 * Second, Value: "error"},
+				},
+			},
+		},
+		{
+			name: "Aggregate",
+			q:    `SELECT sum(CASE WHEN status >= 500 THEN 1 ELSE 0 END), mean(CASE WHEN host = 'A' THEN value END) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s)`,
+			points: [][]influxql.Point{
+				{
+					&influxql.IntegerPoint{Name: "cpu", Time: 0 * Second, Value: 0, Aggregated: 2},
+					&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 1, Aggregated: 1},
+				},
+				{
+					&influxql.IntegerPoint{Name: "cpu", Time: 10 * Second, Value: 2, Aggregated: 2},
+					&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 3, Aggregated: 1},
+				},
+			},
+		},
+		{
+			name: "TagsOnly",
+			q:    `SELECT CASE WHEN host = 'A' THEN 1 ELSE 0 END FROM cpu`,
+			points: [][]influxql.Point{
+				{&influxql.IntegerPoint{Name: "cpu", Time: 0 * Second, Value: 1}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 5 * Second, Value: 0}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 10 * Second, Value: 1}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 15 * Second, Value: 0}},
+			},
+		},
+		{
+			name: "TagsOnly_Aggregate",
+			q:    `SELECT sum(CASE WHEN host = 'A' THEN 1 ELSE 0 END) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z'`,
+			points: [][]influxql.Point{
+				{&influxql.IntegerPoint{Name: "cpu", Time: 0 * Second, Value: 2, Aggregated: 4}},
+			},
+		},
+		{
+			name: "Literal",
+			q:    `SELECT CASE WHEN true THEN 1 END FROM cpu`,
+			points: [][]influxql.Point{
+				{&influxql.IntegerPoint{Name: "cpu", Time: 0 * Second, Value: 1}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 5 * Second, Value: 1}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 10 * Second, Value: 1}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 15 * Second, Value: 1}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := MustParseSelectStatement(tt.q).RewriteFields(&ic)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			itrs, err := influxql.Select(stmt, &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
+// Ensure a CASE expression reads its inputs in time order when it is used
+// within a function that requires ordered points.
+func TestSelect_Case_Ordered(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		// Each subquery reads the points for a single host.
+		var times []int64
+		switch opt.Condition.String() {
+		case `host::tag = 'A'`:
+			times = []int64{0 * Second, 10 * Second}
+		case `host::tag = 'B'`:
+			times = []int64{5 * Second, 15 * Second}
+		default:
+			t.Fatalf("unexpected condition: %s", opt.Condition)
+		}
+
+		var points []influxql.FloatPoint
+		for i, ts := range times {
+			aux := make([]interface{}, len(opt.Aux))
+			for j := range aux {
+				aux[j] = float64(ts/Second + int64(i))
+			}
+			points = append(points, influxql.FloatPoint{Name: "cpu", Time: ts, Aux: aux})
+		}
+		return &FloatIterator{Points: points}, nil
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"value": influxql.Float}, map[string]struct{}{"host": struct{}{}}, nil
+	}
+
+	stmt, err := MustParseSelectStatement(`SELECT difference(CASE WHEN value > 5 THEN value ELSE 0 END) FROM (SELECT value FROM cpu WHERE host = 'A'), (SELECT value FROM cpu WHERE host = 'B')`).RewriteFields(&ic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itrs, err := influxql.Select(stmt, &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 0}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 11}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 15 * Second, Value: 5}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
 // Ensure a SELECT binary expr queries can be executed as floats.
 func TestSelect_BinaryExpr_Float(t *testing.T) {
 	var ic IteratorCreator
@@ -2700,29 +3844,199 @@ func TestSelect_BinaryExpr_Boolean(t *testing.T) {
 	}
 }
 
-// Ensure a SELECT binary expr with nil values can be executed.
-// Nil values may be present when a field is missing from one iterator,
-// but not the other.
-func TestSelect_BinaryExpr_NilValues(t *testing.T) {
+// Ensure a SELECT binary expr with nil values can be executed.
+// Nil values may be present when a field is missing from one iterator,
+// but not the other.
+func TestSelect_BinaryExpr_NilValues(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return &FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: 0 * Second, Value: 20, Aux: []interface{}{float64(20), nil}},
+			{Name: "cpu", Time: 5 * Second, Value: 10, Aux: []interface{}{float64(10), float64(15)}},
+			{Name: "cpu", Time: 9 * Second, Value: 19, Aux: []interface{}{nil, float64(5)}},
+		}}, nil
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return map[string]influxql.DataType{
+			"total": influxql.Float,
+			"value": influxql.Float,
+		}, nil, nil
+	}
+
+	for _, test := range []struct {
+		Name      string
+		Statement string
+		Points    [][]influxql.Point
+	}{
+		{
+			Name:      "nil binary add",
+			Statement: `SELECT total + value FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 25}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
+			},
+		},
+		{
+			Name:      "nil binary subtract",
+			Statement: `SELECT total - value FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: -5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
+			},
+		},
+		{
+			Name:      "nil binary multiply",
+			Statement: `SELECT total * value FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 150}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
+			},
+		},
+		{
+			Name:      "nil binary division",
+			Statement: `SELECT total / value FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: float64(10) / float64(15)}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
+			},
+		},
+	} {
+		stmt, err := MustParseSelectStatement(test.Statement).RewriteFields(&ic)
+		if err != nil {
+			t.Errorf("%s: rewrite error: %s", test.Name, err)
+		}
+
+		itrs, err := influxql.Select(stmt, &ic, nil)
+		if err != nil {
+			t.Errorf("%s: parse error: %s", test.Name, err)
+		} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.Name, err)
+		} else if diff := cmp.Diff(a, test.Points); diff != "" {
+			t.Errorf("%s: unexpected points:\n%s", test.Name, diff)
+		}
+	}
+}
+
+// Ensure a SELECT (...) query can be executed.
+func TestSelect_ParenExpr(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		if !reflect.DeepEqual(opt.Expr, MustParseExpr(`min(value)`)) {
+			t.Fatalf("unexpected expr: %s", spew.Sdump(opt.Expr))
+		}
+
+		input, err := influxql.Iterators{
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: 3},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 31 * Second, Value: 100},
+			}},
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
+			}},
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: 19},
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 10 * Second, Value: 2},
+			}},
+		}.Merge(opt)
+		if err != nil {
+			return nil, err
+		}
+		return influxql.NewCallIterator(input, opt)
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT (min(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 19, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 2, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 30 * Second, Value: 100, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 10, Aggregated: 1}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.Iterators{
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 1 * Second, Value: 19},
+			}},
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
+			}},
+			&FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: 19},
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 10 * Second, Value: 2},
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 11 * Second, Value: 2},
+				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 12 * Second, Value: 2},
+			}},
+		}.Merge(opt)
+	}
+
+	// Execute selection.
+	itrs, err = influxql.Select(MustParseSelectStatement(`SELECT (distinct(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 19}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 10}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+// Ensure a SELECT with math functions can be executed on raw fields.
+func TestSelect_MathFunctions_Raw(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
 		if m.Name != "cpu" {
 			t.Fatalf("unexpected source: %s", m.Name)
 		}
+		makeAuxFields := func(f, i interface{}) []interface{} {
+			aux := make([]interface{}, len(opt.Aux))
+			for j, ref := range opt.Aux {
+				switch ref.Val {
+				case "f":
+					aux[j] = f
+				case "i":
+					aux[j] = i
+				}
+			}
+			return aux
+		}
 		return &FloatIterator{Points: []influxql.FloatPoint{
-			{Name: "cpu", Time: 0 * Second, Value: 20, Aux: []interface{}{float64(20), nil}},
-			{Name: "cpu", Time: 5 * Second, Value: 10, Aux: []interface{}{float64(10), float64(15)}},
-			{Name: "cpu", Time: 9 * Second, Value: 19, Aux: []interface{}{nil, float64(5)}},
+			{Name: "cpu", Time: 0 * Second, Aux: makeAuxFields(float64(-2.5), int64(-4))},
+			{Name: "cpu", Time: 5 * Second, Aux: makeAuxFields(float64(4), int64(9))},
+			{Name: "cpu", Time: 9 * Second, Aux: makeAuxFields(nil, int64(16))},
 		}}, nil
 	}
 	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
-		if m.Name != "cpu" {
-			t.Fatalf("unexpected source: %s", m.Name)
-		}
-		return map[string]influxql.DataType{
-			"total": influxql.Float,
-			"value": influxql.Float,
-		}, nil, nil
+		return map[string]influxql.DataType{"f": influxql.Float, "i": influxql.Integer}, nil, nil
 	}
 
 	for _, test := range []struct {
@@ -2731,38 +4045,92 @@ func TestSelect_BinaryExpr_NilValues(t *testing.T) {
 		Points    [][]influxql.Point
 	}{
 		{
-			Name:      "nil binary add",
-			Statement: `SELECT total + value FROM cpu`,
+			Name:      "abs float",
+			Statement: `SELECT abs(f) FROM cpu`,
 			Points: [][]influxql.Point{
-				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
-				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 25}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 2.5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 4}},
 				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
 			},
 		},
 		{
-			Name:      "nil binary subtract",
-			Statement: `SELECT total - value FROM cpu`,
+			Name:      "abs integer",
+			Statement: `SELECT abs(i) FROM cpu`,
 			Points: [][]influxql.Point{
-				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
-				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: -5}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 0 * Second, Value: 4}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 5 * Second, Value: 9}},
+				{&influxql.IntegerPoint{Name: "cpu", Time: 9 * Second, Value: 16}},
+			},
+		},
+		{
+			Name:      "sqrt integer",
+			Statement: `SELECT sqrt(abs(i)) FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 3}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Value: 4}},
+			},
+		},
+		{
+			Name:      "round binary expr",
+			Statement: `SELECT round(f * 2 + 0.2) FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: -5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 8}},
 				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
 			},
 		},
 		{
-			Name:      "nil binary multiply",
-			Statement: `SELECT total * value FROM cpu`,
+			Name:      "pow with literal",
+			Statement: `SELECT pow(i, 2) FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 16}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 81}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Value: 256}},
+			},
+		},
+		{
+			Name:      "log with literal base",
+			Statement: `SELECT log(16, abs(i)) FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: math.Log(16) / math.Log(9)}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Value: 1}},
+			},
+		},
+		{
+			Name:      "sqrt outside of the domain",
+			Statement: `SELECT sqrt(f) FROM cpu`,
 			Points: [][]influxql.Point{
 				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
-				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 150}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: 2}},
 				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
 			},
 		},
 		{
-			Name:      "nil binary division",
-			Statement: `SELECT total / value FROM cpu`,
+			Name:      "ln outside of the domain",
+			Statement: `SELECT ln(i) FROM cpu`,
 			Points: [][]influxql.Point{
 				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
-				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: float64(10) / float64(15)}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: math.Log(9)}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Value: math.Log(16)}},
+			},
+		},
+		{
+			Name:      "log of two fields outside of the domain",
+			Statement: `SELECT log(f, i) FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Nil: true}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: math.Log(4) / math.Log(9)}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
+			},
+		},
+		{
+			Name:      "pow with two fields",
+			Statement: `SELECT pow(f, i) FROM cpu`,
+			Points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: math.Pow(-2.5, -4)}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 5 * Second, Value: math.Pow(4, 9)}},
 				{&influxql.FloatPoint{Name: "cpu", Time: 9 * Second, Nil: true}},
 			},
 		},
@@ -2783,29 +4151,22 @@ func TestSelect_BinaryExpr_NilValues(t *testing.T) {
 	}
 }
 
-// Ensure a SELECT (...) query can be executed.
-func TestSelect_ParenExpr(t *testing.T) {
+// Ensure a SELECT with a math function can be applied to the output of an aggregate.
+func TestSelect_MathFunctions_Aggregate(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
 		if m.Name != "cpu" {
 			t.Fatalf("unexpected source: %s", m.Name)
 		}
-		if !reflect.DeepEqual(opt.Expr, MustParseExpr(`min(value)`)) {
+		if !reflect.DeepEqual(opt.Expr, MustParseExpr(`mean(value)`)) {
 			t.Fatalf("unexpected expr: %s", spew.Sdump(opt.Expr))
 		}
 
 		input, err := influxql.Iterators{
 			&FloatIterator{Points: []influxql.FloatPoint{
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: 3},
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 31 * Second, Value: 100},
-			}},
-			&FloatIterator{Points: []influxql.FloatPoint{
-				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
-			}},
-			&FloatIterator{Points: []influxql.FloatPoint{
-				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: 19},
-				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 10 * Second, Value: 2},
+				{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: -20},
+				{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: -3},
+				{Name: "cpu", Tags: ParseTags("host=A"), Time: 11 * Second, Value: 2.2},
 			}},
 		}.Merge(opt)
 		if err != nil {
@@ -2814,58 +4175,174 @@ func TestSelect_ParenExpr(t *testing.T) {
 		return influxql.NewCallIterator(input, opt)
 	}
 
-	// Execute selection.
-	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT (min(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT round(abs(mean(value))) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
 	if err != nil {
 		t.Fatal(err)
 	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	} else if diff := cmp.Diff(a, [][]influxql.Point{
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 19, Aggregated: 2}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 2, Aggregated: 2}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 30 * Second, Value: 100, Aggregated: 1}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 10, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 12, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 2, Aggregated: 1}},
 	}); diff != "" {
 		t.Fatalf("unexpected points:\n%s", diff)
 	}
+}
 
+// Ensure a SELECT can join the series of two measurements by time bucket and tags.
+func TestSelect_Join(t *testing.T) {
+	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
-		if m.Name != "cpu" {
+		if !reflect.DeepEqual(opt.Dimensions, []string{"host"}) {
+			t.Fatalf("unexpected dimensions: %v", opt.Dimensions)
+		} else if got := opt.Expr.String(); got != "mean(value::float)" {
+			t.Fatalf("unexpected expr: %s", got)
+		}
+
+		var points []influxql.FloatPoint
+		switch m.Name {
+		case "cpu":
+			if !strings.Contains(opt.Condition.String(), "region = 'west'") {
+				t.Fatalf("unexpected condition: %s", opt.Condition)
+			}
+			points = []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("host=A,region=west"), Time: 0 * Second, Value: 10},
+				{Name: "cpu", Tags: ParseTags("host=A,region=west"), Time: 5 * Second, Value: 30},
+				{Name: "cpu", Tags: ParseTags("host=A,region=west"), Time: 10 * Second, Value: 6},
+				{Name: "cpu", Tags: ParseTags("host=B,region=west"), Time: 0 * Second, Value: 40},
+			}
+		case "mem":
+			if strings.Contains(opt.Condition.String(), "region") {
+				t.Fatalf("unexpected condition: %s", opt.Condition)
+			}
+			points = []influxql.FloatPoint{
+				{Name: "mem", Tags: ParseTags("host=A"), Time: 1 * Second, Value: 4},
+				{Name: "mem", Tags: ParseTags("host=A"), Time: 12 * Second, Value: 3},
+				{Name: "mem", Tags: ParseTags("host=B"), Time: 2 * Second, Value: 10},
+			}
+		default:
 			t.Fatalf("unexpected source: %s", m.Name)
 		}
-		return influxql.Iterators{
-			&FloatIterator{Points: []influxql.FloatPoint{
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
-				{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 1 * Second, Value: 19},
-			}},
-			&FloatIterator{Points: []influxql.FloatPoint{
-				{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
-			}},
-			&FloatIterator{Points: []influxql.FloatPoint{
-				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 9 * Second, Value: 19},
-				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 10 * Second, Value: 2},
-				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 11 * Second, Value: 2},
-				{Name: "cpu", Tags: ParseTags("region=east,host=A"), Time: 12 * Second, Value: 2},
-			}},
-		}.Merge(opt)
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"value": influxql.Float}, map[string]struct{}{"host": struct{}{}}, nil
 	}
 
-	// Execute selection.
-	itrs, err = influxql.Select(MustParseSelectStatement(`SELECT (distinct(value)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`), &ic, nil)
+	stmt, err := MustParseSelectStatement(`SELECT mean(a.value) / mean(b.value) FROM cpu AS a JOIN mem AS b ON host WHERE a.region = 'west' AND time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s) fill(none)`).RewriteFields(&ic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	itrs, err := influxql.Select(stmt, &ic, nil)
 	if err != nil {
 		t.Fatal(err)
 	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	} else if diff := cmp.Diff(a, [][]influxql.Point{
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 19}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 2}},
-		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 10}},
+		{&influxql.FloatPoint{Name: "cpu_mem", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 5, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu_mem", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 2, Aggregated: 1}},
+		{&influxql.FloatPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 4, Aggregated: 1}},
 	}); diff != "" {
 		t.Fatalf("unexpected points:\n%s", diff)
 	}
 }
 
+// Ensure a raw SELECT can join the points of two measurements by exact time.
+func TestSelect_Join_Raw(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if opt.Expr != nil {
+			t.Fatalf("unexpected expr: %s", opt.Expr)
+		} else if opt.Limit != 0 || opt.Offset != 0 {
+			t.Fatalf("unexpected limit: %d, offset: %d", opt.Limit, opt.Offset)
+		}
+
+		switch m.Name {
+		case "cpu":
+			if !reflect.DeepEqual(opt.Aux, []influxql.VarRef{{Val: "host", Type: influxql.Tag}, {Val: "value", Type: influxql.Float}}) {
+				t.Fatalf("unexpected aux fields: %v", opt.Aux)
+			}
+			return &FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Aux: []interface{}{"A", float64(1)}},
+				{Name: "cpu", Tags: ParseTags("host=A"), Time: 1 * Second, Aux: []interface{}{"A", float64(2)}},
+				{Name: "cpu", Tags: ParseTags("host=B"), Time: 1 * Second, Aux: []interface{}{"B", float64(3)}},
+				{Name: "cpu", Tags: ParseTags("host=B"), Time: 3 * Second, Aux: []interface{}{"B", float64(5)}},
+			}}, nil
+		case "mem":
+			if !reflect.DeepEqual(opt.Aux, []influxql.VarRef{{Val: "value", Type: influxql.Float}}) {
+				t.Fatalf("unexpected aux fields: %v", opt.Aux)
+			}
+			return &FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "mem", Tags: ParseTags("host=A"), Time: 0 * Second, Aux: []interface{}{float64(10)}},
+				{Name: "mem", Tags: ParseTags("host=B"), Time: 1 * Second, Aux: []interface{}{float64(30)}},
+				{Name: "mem", Tags: ParseTags("host=B"), Time: 2 * Second, Aux: []interface{}{float64(40)}},
+				{Name: "mem", Tags: ParseTags("host=B"), Time: 3 * Second, Aux: []interface{}{float64(1)}},
+			}}, nil
+		default:
+			t.Fatalf("unexpected source: %s", m.Name)
+			return nil, nil
+		}
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"value": influxql.Float}, map[string]struct{}{"host": struct{}{}}, nil
+	}
+
+	for _, tt := range []struct {
+		s      string
+		points [][]influxql.Point
+	}{
+		{
+			s: `SELECT a.host, a.value + b.value FROM cpu AS a JOIN mem AS b ON host`,
+			points: [][]influxql.Point{
+				{
+					&influxql.StringPoint{Name: "cpu_mem", Tags: ParseTags("host=A"), Time: 0 * Second, Value: "A"},
+					&influxql.FloatPoint{Name: "cpu_mem", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 11},
+				},
+				{
+					&influxql.StringPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 1 * Second, Value: "B"},
+					&influxql.FloatPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 1 * Second, Value: 33},
+				},
+				{
+					&influxql.StringPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 3 * Second, Value: "B"},
+					&influxql.FloatPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 3 * Second, Value: 6},
+				},
+			},
+		},
+		{
+			s: `SELECT a.host, a.value + b.value FROM cpu AS a JOIN mem AS b ON host WHERE a.value > b.value`,
+			points: [][]influxql.Point{
+				{
+					&influxql.StringPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 3 * Second, Value: "B"},
+					&influxql.FloatPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 3 * Second, Value: 6},
+				},
+			},
+		},
+		{
+			s: `SELECT a.host, a.value + b.value FROM cpu AS a JOIN mem AS b ON host LIMIT 1 OFFSET 1`,
+			points: [][]influxql.Point{
+				{
+					&influxql.StringPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 3 * Second, Value: "B"},
+					&influxql.FloatPoint{Name: "cpu_mem", Tags: ParseTags("host=B"), Time: 3 * Second, Value: 6},
+				},
+			},
+		},
+	} {
+		stmt, err := MustParseSelectStatement(tt.s).RewriteFields(&ic)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.s, err)
+		}
+
+		itrs, err := influxql.Select(stmt, &ic, nil)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.s, err)
+		} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.s, err)
+		} else if diff := cmp.Diff(a, tt.points); diff != "" {
+			t.Fatalf("%s: unexpected points:\n%s", tt.s, diff)
+		}
+	}
+}
+
 func TestSelect_Derivative_Float(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
@@ -3345,46 +4822,190 @@ func TestSelect_Elapsed_String(t *testing.T) {
 	}
 
 	// Execute selection.
-	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT elapsed(value, 1s) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z'`), &ic, nil)
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT elapsed(value, 1s) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z'`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.IntegerPoint{Name: "cpu", Time: 4 * Second, Value: 4}},
+		{&influxql.IntegerPoint{Name: "cpu", Time: 8 * Second, Value: 4}},
+		{&influxql.IntegerPoint{Name: "cpu", Time: 11 * Second, Value: 3}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_Elapsed_Boolean(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return &BooleanIterator{Points: []influxql.BooleanPoint{
+			{Name: "cpu", Time: 0 * Second, Value: true},
+			{Name: "cpu", Time: 4 * Second, Value: false},
+			{Name: "cpu", Time: 8 * Second, Value: false},
+			{Name: "cpu", Time: 11 * Second, Value: true},
+		}}, nil
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT elapsed(value, 1s) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z'`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.IntegerPoint{Name: "cpu", Time: 4 * Second, Value: 4}},
+		{&influxql.IntegerPoint{Name: "cpu", Time: 8 * Second, Value: 4}},
+		{&influxql.IntegerPoint{Name: "cpu", Time: 11 * Second, Value: 3}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_Rate(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return &FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: 1 * Second, Value: 10},
+			{Name: "cpu", Time: 3 * Second, Value: 20},
+			{Name: "cpu", Time: 5 * Second, Value: 5},
+			{Name: "cpu", Time: 7 * Second, Value: 15},
+			{Name: "cpu", Time: 9 * Second, Value: 25},
+			{Name: "cpu", Time: 12 * Second, Value: 30},
+			{Name: "cpu", Time: 16 * Second, Value: 40},
+		}}, nil
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"value": influxql.Float}, nil, nil
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT increase(value), rate(value, 1s) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{
+			&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 43.75, Aggregated: 5},
+			&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 4.375, Aggregated: 5},
+		},
+		{
+			&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 25, Aggregated: 2},
+			&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 2.5, Aggregated: 2},
+		},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_Increase_Wraparound(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return &IntegerIterator{Points: []influxql.IntegerPoint{
+			{Name: "cpu", Time: 1 * Second, Value: 90},
+			{Name: "cpu", Time: 5 * Second, Value: 10},
+		}}, nil
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"value": influxql.Integer}, nil, nil
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT increase(value, 100) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:10Z'`), &ic, nil)
 	if err != nil {
 		t.Fatal(err)
 	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	} else if diff := cmp.Diff(a, [][]influxql.Point{
-		{&influxql.IntegerPoint{Name: "cpu", Time: 4 * Second, Value: 4}},
-		{&influxql.IntegerPoint{Name: "cpu", Time: 8 * Second, Value: 4}},
-		{&influxql.IntegerPoint{Name: "cpu", Time: 11 * Second, Value: 3}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 36.75, Aggregated: 2}},
 	}); diff != "" {
 		t.Fatalf("unexpected points:\n%s", diff)
 	}
 }
 
-func TestSelect_Elapsed_Boolean(t *testing.T) {
+func TestSelect_Increase_Series(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
 		if m.Name != "cpu" {
 			t.Fatalf("unexpected source: %s", m.Name)
 		}
-		return &BooleanIterator{Points: []influxql.BooleanPoint{
-			{Name: "cpu", Time: 0 * Second, Value: true},
-			{Name: "cpu", Time: 4 * Second, Value: false},
-			{Name: "cpu", Time: 8 * Second, Value: false},
-			{Name: "cpu", Time: 11 * Second, Value: true},
+
+		// The points of the series are merged in order of time unless the
+		// series are grouped by the host.
+		if len(opt.Dimensions) == 0 {
+			if diff := cmp.Diff(opt.Aux, []influxql.VarRef{{Val: "host", Type: influxql.Tag}}); diff != "" {
+				t.Fatalf("unexpected auxiliary fields:\n%s", diff)
+			}
+			return &FloatIterator{Points: []influxql.FloatPoint{
+				{Name: "cpu", Time: 0 * Second, Value: 10, Aux: []interface{}{"A"}},
+				{Name: "cpu", Time: 0 * Second, Value: 100, Aux: []interface{}{"B"}},
+				{Name: "cpu", Time: 5 * Second, Value: 15, Aux: []interface{}{"A"}},
+				{Name: "cpu", Time: 5 * Second, Value: 120, Aux: []interface{}{"B"}},
+				{Name: "cpu", Time: 10 * Second, Value: 20, Aux: []interface{}{"A"}},
+				{Name: "cpu", Time: 10 * Second, Value: 140, Aux: []interface{}{"B"}},
+				{Name: "cpu", Time: 15 * Second, Value: 25, Aux: []interface{}{"A"}},
+				{Name: "cpu", Time: 15 * Second, Value: 160, Aux: []interface{}{"B"}},
+			}}, nil
+		}
+		return &FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 15},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 20},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 15 * Second, Value: 25},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 100},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 5 * Second, Value: 120},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 140},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 15 * Second, Value: 160},
 		}}, nil
 	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"value": influxql.Float}, map[string]struct{}{"host": struct{}{}}, nil
+	}
 
-	// Execute selection.
-	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT elapsed(value, 1s) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z'`), &ic, nil)
-	if err != nil {
-		t.Fatal(err)
-	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
-		t.Fatalf("unexpected error: %s", err)
-	} else if diff := cmp.Diff(a, [][]influxql.Point{
-		{&influxql.IntegerPoint{Name: "cpu", Time: 4 * Second, Value: 4}},
-		{&influxql.IntegerPoint{Name: "cpu", Time: 8 * Second, Value: 4}},
-		{&influxql.IntegerPoint{Name: "cpu", Time: 11 * Second, Value: 3}},
-	}); diff != "" {
-		t.Fatalf("unexpected points:\n%s", diff)
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "All",
+			q:    `SELECT increase(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s)`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 50, Aggregated: 4}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 50, Aggregated: 4}},
+			},
+		},
+		{
+			name: "GroupByHost",
+			q:    `SELECT increase(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s), host`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 10, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 10, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 40, Aggregated: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 40, Aggregated: 2}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			itrs, err := influxql.Select(MustParseSelectStatement(tt.q), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
 	}
 }
 
@@ -3468,6 +5089,34 @@ func TestSelect_Integral_Float_InterpolateGroupByTime(t *testing.T) {
 	}
 }
 
+func TestSelect_Integral_Float_OverlappingWindows(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return &FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: 0 * Second, Value: 0},
+			{Name: "cpu", Time: 10 * Second, Value: 10},
+			{Name: "cpu", Time: 20 * Second, Value: 20},
+			{Name: "cpu", Time: 30 * Second, Value: 0},
+		}}, nil
+	}
+
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT integral(value) FROM cpu WHERE time >= 0s AND time < 40s GROUP BY time(20s, step(10s))`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Time: 0, Value: 200, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 250, Aggregated: 2}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 100, Aggregated: 1}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
 func TestSelect_Integral_Integer(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
@@ -3546,6 +5195,257 @@ func TestSelect_Integral_Duplicate_Integer(t *testing.T) {
 	}
 }
 
+func TestSelect_TimeWeightedAvg(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		var points []influxql.FloatPoint
+		for _, p := range []influxql.FloatPoint{
+			{Name: "cpu", Time: 30 * Second, Value: 10},
+			{Name: "cpu", Time: 90 * Second, Value: 20},
+			{Name: "cpu", Time: 150 * Second, Value: 40},
+			{Name: "cpu", Time: 200 * Second, Value: 0},
+		} {
+			if p.Time >= opt.StartTime && p.Time <= opt.EndTime {
+				points = append(points, p)
+			}
+		}
+		if !opt.Ascending {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+		if _, ok := opt.Expr.(*influxql.Call); !ok {
+			return &FloatIterator{Points: points}, nil
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "Step",
+			q:    `SELECT time_weighted_avg(value, 'step') FROM cpu WHERE time >= 60s AND time < 180s GROUP BY time(60s)`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 60 * Second, Value: 15}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 120 * Second, Value: 30}},
+			},
+		},
+		{
+			name: "Linear",
+			q:    `SELECT time_weighted_avg(value, 'linear') FROM cpu WHERE time >= 60s AND time < 180s GROUP BY time(60s)`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 60 * Second, Value: 21.25}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 120 * Second, Value: 37.5}},
+			},
+		},
+		{
+			name: "Descending",
+			q:    `SELECT time_weighted_avg(value, 'linear') FROM cpu WHERE time >= 60s AND time < 180s GROUP BY time(60s) ORDER BY time DESC`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 120 * Second, Value: 37.5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 60 * Second, Value: 21.25}},
+			},
+		},
+		{
+			name: "NoPointBefore",
+			q:    `SELECT time_weighted_avg(value, 'step') FROM cpu WHERE time >= 0s AND time < 120s GROUP BY time(60s)`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 10}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 60 * Second, Value: 15}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			itrs, err := influxql.Select(MustParseSelectStatement(tt.q), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
+// Ensure time_weighted_avg() emits the windows of each series before the next
+// series when grouped by tags.
+func TestSelect_TimeWeightedAvg_GroupBy(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		var points []influxql.FloatPoint
+		for _, p := range []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 60 * Second, Value: 20},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 30 * Second, Value: 2},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 90 * Second, Value: 4},
+		} {
+			if p.Time >= opt.StartTime && p.Time <= opt.EndTime {
+				points = append(points, p)
+			}
+		}
+		if _, ok := opt.Expr.(*influxql.Call); !ok {
+			return &FloatIterator{Points: points}, nil
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "All",
+			q:    `SELECT time_weighted_avg(value, 'step') FROM cpu WHERE time >= 0s AND time < 120s GROUP BY time(60s), host`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 10}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 60 * Second, Value: 20}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 2}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 60 * Second, Value: 3}},
+			},
+		},
+		{
+			name: "Limit",
+			q:    `SELECT time_weighted_avg(value, 'step') FROM cpu WHERE time >= 0s AND time < 120s GROUP BY time(60s), host LIMIT 1`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 10}},
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 2}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			itrs, err := influxql.Select(MustParseSelectStatement(tt.q), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
+// Ensure time_weighted_avg() returns the held or interpolated value for the
+// windows between points.
+func TestSelect_TimeWeightedAvg_Gap(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		var all []influxql.FloatPoint
+		switch m.Name {
+		case "cpu":
+			all = []influxql.FloatPoint{
+				{Name: "cpu", Time: 1 * Second, Value: 5},
+				{Name: "cpu", Time: 35 * Second, Value: 7},
+			}
+		case "mem":
+			all = []influxql.FloatPoint{
+				{Name: "mem", Time: 0 * Second, Value: 0},
+				{Name: "mem", Time: 40 * Second, Value: 40},
+			}
+		default:
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		var points []influxql.FloatPoint
+		for _, p := range all {
+			if p.Time >= opt.StartTime && p.Time <= opt.EndTime {
+				points = append(points, p)
+			}
+		}
+		if !opt.Ascending {
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		}
+		if _, ok := opt.Expr.(*influxql.Call); !ok {
+			return &FloatIterator{Points: points}, nil
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: points}, opt)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		points [][]influxql.Point
+	}{
+		{
+			name: "Step",
+			q:    `SELECT time_weighted_avg(value, 'step') FROM cpu WHERE time >= 0s AND time < 40s GROUP BY time(10s)`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 6}},
+			},
+		},
+		{
+			name: "Descending",
+			q:    `SELECT time_weighted_avg(value, 'step') FROM cpu WHERE time >= 0s AND time < 40s GROUP BY time(10s) ORDER BY time DESC`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 6}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 0 * Second, Value: 5}},
+			},
+		},
+		{
+			name: "PointBefore",
+			q:    `SELECT time_weighted_avg(value, 'step') FROM cpu WHERE time >= 10s AND time < 40s GROUP BY time(10s) ORDER BY time DESC`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Time: 30 * Second, Value: 6}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 20 * Second, Value: 5}},
+				{&influxql.FloatPoint{Name: "cpu", Time: 10 * Second, Value: 5}},
+			},
+		},
+		{
+			name: "Linear",
+			q:    `SELECT time_weighted_avg(value, 'linear') FROM mem WHERE time >= 0s AND time < 60s GROUP BY time(10s)`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "mem", Time: 0 * Second, Value: 5}},
+				{&influxql.FloatPoint{Name: "mem", Time: 10 * Second, Value: 15}},
+				{&influxql.FloatPoint{Name: "mem", Time: 20 * Second, Value: 25}},
+				{&influxql.FloatPoint{Name: "mem", Time: 30 * Second, Value: 35}},
+				{&influxql.FloatPoint{Name: "mem", Time: 40 * Second, Value: 40}},
+				{&influxql.FloatPoint{Name: "mem", Time: 50 * Second, Value: 40}},
+			},
+		},
+		{
+			name: "LinearPointBefore",
+			q:    `SELECT time_weighted_avg(value, 'linear') FROM mem WHERE time >= 10s AND time < 50s GROUP BY time(10s)`,
+			points: [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "mem", Time: 10 * Second, Value: 15}},
+				{&influxql.FloatPoint{Name: "mem", Time: 20 * Second, Value: 25}},
+				{&influxql.FloatPoint{Name: "mem", Time: 30 * Second, Value: 35}},
+				{&influxql.FloatPoint{Name: "mem", Time: 40 * Second, Value: 40}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			itrs, err := influxql.Select(MustParseSelectStatement(tt.q), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, tt.points); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestSelect_MovingAverage_Float(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
@@ -3604,6 +5504,227 @@ func TestSelect_MovingAverage_Integer(t *testing.T) {
 	}
 }
 
+func TestSelect_LTTB(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return &FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 0},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 1 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 2 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 3 * Second, Value: 1},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 4 * Second, Value: 0},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 5},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 1 * Second, Value: 3},
+		}}, nil
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT lttb(value, 3) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:10Z' GROUP BY host`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 0}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 2 * Second, Value: 10}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=A"), Time: 4 * Second, Value: 0}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 5}},
+		{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=B"), Time: 1 * Second, Value: 3}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_ZScore(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return &FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Time: 0 * Second, Value: 1},
+			{Name: "cpu", Time: 4 * Second, Value: 2},
+			{Name: "cpu", Time: 8 * Second, Value: 3},
+			{Name: "cpu", Time: 12 * Second, Value: 10},
+		}}, nil
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT zscore(value, 3) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:16Z'`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.FloatPoint{Name: "cpu", Time: 8 * Second, Value: 1, Aggregated: 3}},
+		{&influxql.FloatPoint{Name: "cpu", Time: 12 * Second, Value: 5 / math.Sqrt(19), Aggregated: 3}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_MADOutliers(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return &IntegerIterator{Points: []influxql.IntegerPoint{
+			{Name: "cpu", Time: 0 * Second, Value: 10},
+			{Name: "cpu", Time: 1 * Second, Value: 11},
+			{Name: "cpu", Time: 2 * Second, Value: 9},
+			{Name: "cpu", Time: 3 * Second, Value: 10},
+			{Name: "cpu", Time: 4 * Second, Value: 50},
+			{Name: "cpu", Time: 10 * Second, Value: 10},
+			{Name: "cpu", Time: 11 * Second, Value: 10},
+			{Name: "cpu", Time: 12 * Second, Value: 10},
+		}}, nil
+	}
+
+	// Execute selection.
+	itrs, err := influxql.Select(MustParseSelectStatement(`SELECT mad_outliers(value, 3.5) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s)`), &ic, nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff(a, [][]influxql.Point{
+		{&influxql.IntegerPoint{Name: "cpu", Time: 4 * Second, Value: 50}},
+	}); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_Outliers(t *testing.T) {
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "cpu" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+		return influxql.NewCallIterator(&FloatIterator{Points: []influxql.FloatPoint{
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("host=A"), Time: 10 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 11},
+			{Name: "cpu", Tags: ParseTags("host=B"), Time: 10 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("host=C"), Time: 0 * Second, Value: 10},
+			{Name: "cpu", Tags: ParseTags("host=C"), Time: 10 * Second, Value: 12},
+			{Name: "cpu", Tags: ParseTags("host=D"), Time: 0 * Second, Value: 30},
+			{Name: "cpu", Tags: ParseTags("host=D"), Time: 5 * Second, Value: 50},
+			{Name: "cpu", Tags: ParseTags("host=D"), Time: 10 * Second, Value: 11},
+		}}, opt)
+	}
+
+	for _, tt := range []struct {
+		method    string
+		threshold string
+	}{
+		{method: "mad", threshold: "3"},
+		{method: "iqr", threshold: "1.5"},
+	} {
+		t.Run(tt.method, func(t *testing.T) {
+			// Execute selection.
+			itrs, err := influxql.Select(MustParseSelectStatement(fmt.Sprintf(`SELECT outliers(mean(value), %s, '%s') FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:20Z' GROUP BY time(10s), host`, tt.threshold, tt.method)), &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, [][]influxql.Point{
+				{&influxql.FloatPoint{Name: "cpu", Tags: ParseTags("host=D"), Time: 0 * Second, Value: 40, Aggregated: 2}},
+			}); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSelect_State(t *testing.T) {
+	times := []int64{0 * Second, 10 * Second, 20 * Second, 50 * Second, 60 * Second, 70 * Second}
+	statuses := []string{"up", "down", "down", "down", "up", "down"}
+	zones := []string{"a", "a", "b", "b", "a", "a"}
+
+	var ic IteratorCreator
+	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
+		if m.Name != "machine" {
+			t.Fatalf("unexpected source: %s", m.Name)
+		}
+
+		// The status field is read even when only the zone tag is referenced.
+		var values [][]string
+		switch {
+		case cmp.Equal(opt.Aux, []influxql.VarRef{{Val: "status", Type: influxql.String}}):
+			values = [][]string{statuses}
+		case cmp.Equal(opt.Aux, []influxql.VarRef{{Val: "zone", Type: influxql.Tag}, {Val: "status", Type: influxql.String}}):
+			values = [][]string{zones, statuses}
+		default:
+			t.Fatalf("unexpected auxiliary fields: %v", opt.Aux)
+		}
+
+		points := make([]influxql.FloatPoint, len(times))
+		for i := range times {
+			aux := make([]interface{}, len(values))
+			for j := range values {
+				aux[j] = values[j][i]
+			}
+			points[i] = influxql.FloatPoint{Name: "machine", Time: times[i], Aux: aux}
+		}
+		return &FloatIterator{Points: points}, nil
+	}
+	ic.FieldDimensionsFn = func(m *influxql.Measurement) (map[string]influxql.DataType, map[string]struct{}, error) {
+		return map[string]influxql.DataType{"status": influxql.String}, map[string]struct{}{"zone": struct{}{}}, nil
+	}
+
+	for _, tt := range []struct {
+		name   string
+		q      string
+		values []int64
+	}{
+		{
+			name:   "Duration",
+			q:      `SELECT state_duration(status = 'down') FROM machine`,
+			values: []int64{-1, 0, 10, 40, -1, 0},
+		},
+		{
+			name:   "DurationUnit",
+			q:      `SELECT state_duration(status = 'down', 10s) FROM machine`,
+			values: []int64{-1, 0, 1, 4, -1, 0},
+		},
+		{
+			name:   "Count",
+			q:      `SELECT state_count(status = 'down') FROM machine`,
+			values: []int64{-1, 1, 2, 3, -1, 1},
+		},
+		{
+			name:   "Tag",
+			q:      `SELECT state_count(zone = 'a') FROM machine`,
+			values: []int64{1, 2, -1, -1, 1, 2},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := MustParseSelectStatement(tt.q).RewriteFields(&ic)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var exp [][]influxql.Point
+			for i, v := range tt.values {
+				exp = append(exp, []influxql.Point{&influxql.IntegerPoint{Name: "machine", Time: times[i], Value: v}})
+			}
+
+			// Execute selection.
+			itrs, err := influxql.Select(stmt, &ic, nil)
+			if err != nil {
+				t.Fatal(err)
+			} else if a, err := Iterators(itrs).ReadAll(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(a, exp); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestSelect_CumulativeSum_Float(t *testing.T) {
 	var ic IteratorCreator
 	ic.CreateIteratorFn = func(m *influxql.Measurement, opt influxql.IteratorOptions) (influxql.Iterator, error) {
@@ -11,10 +11,18 @@ func RewriteStatement(stmt Statement) (Statement, error) {
 		return rewriteShowMeasurementsStatement(stmt)
 	case *ShowSeriesStatement:
 		return rewriteShowSeriesStatement(stmt)
+	case *ShowSeriesCardinalityStatement:
+		return rewriteShowSeriesCardinalityStatement(stmt)
+	case *ShowMeasurementCardinalityStatement:
+		return rewriteShowMeasurementCardinalityStatement(stmt)
 	case *ShowTagKeysStatement:
 		return rewriteShowTagKeysStatement(stmt)
 	case *ShowTagValuesStatement:
 		return rewriteShowTagValuesStatement(stmt)
+	case *ShowTagValuesCardinalityStatement:
+		return rewriteShowTagValuesCardinalityStatement(stmt)
+	case *ShowFieldKeyCardinalityStatement:
+		return rewriteShowFieldKeyCardinalityStatement(stmt)
 	default:
 		return stmt, nil
 	}
@@ -77,50 +85,39 @@ func rewriteShowSeriesStatement(stmt *ShowSeriesStatement) (Statement, error) {
 	}, nil
 }
 
-func rewriteShowTagValuesStatement(stmt *ShowTagValuesStatement) (Statement, error) {
+func rewriteShowSeriesCardinalityStatement(stmt *ShowSeriesCardinalityStatement) (Statement, error) {
 	// Check for time in WHERE clause (not supported).
 	if HasTimeExpr(stmt.Condition) {
-		return nil, errors.New("SHOW TAG VALUES doesn't support time in WHERE clause")
+		return nil, errors.New("SHOW SERIES CARDINALITY doesn't support time in WHERE clause")
 	}
 
-	condition := stmt.Condition
-	var expr Expr
-	if list, ok := stmt.TagKeyExpr.(*ListLiteral); ok {
-		for _, tagKey := range list.Vals {
-			tagExpr := &BinaryExpr{
-				Op:  EQ,
-				LHS: &VarRef{Val: "_tagKey"},
-				RHS: &StringLiteral{Val: tagKey},
-			}
+	return &ShowSeriesCardinalityStatement{
+		Database:  stmt.Database,
+		Exact:     stmt.Exact,
+		Condition: rewriteSourcesCondition(stmt.Sources, stmt.Condition),
+	}, nil
+}
 
-			if expr != nil {
-				expr = &BinaryExpr{
-					Op:  OR,
-					LHS: expr,
-					RHS: tagExpr,
-				}
-			} else {
-				expr = tagExpr
-			}
-		}
-	} else {
-		expr = &BinaryExpr{
-			Op:  stmt.Op,
-			LHS: &VarRef{Val: "_tagKey"},
-			RHS: stmt.TagKeyExpr,
-		}
+func rewriteShowMeasurementCardinalityStatement(stmt *ShowMeasurementCardinalityStatement) (Statement, error) {
+	// Check for time in WHERE clause (not supported).
+	if HasTimeExpr(stmt.Condition) {
+		return nil, errors.New("SHOW MEASUREMENT CARDINALITY doesn't support time in WHERE clause")
 	}
 
-	// Set condition or "AND" together.
-	if condition == nil {
-		condition = expr
-	} else {
-		condition = &BinaryExpr{
-			Op:  AND,
-			LHS: &ParenExpr{Expr: condition},
-			RHS: &ParenExpr{Expr: expr},
-		}
+	return &ShowMeasurementCardinalityStatement{
+		Database:  stmt.Database,
+		Exact:     stmt.Exact,
+		Condition: rewriteSourcesCondition(stmt.Sources, stmt.Condition),
+	}, nil
+}
+
+func rewriteShowTagValuesStatement(stmt *ShowTagValuesStatement) (Statement, error) {
+	// Check for time in WHERE clause (not supported).
+	if HasTimeExpr(stmt.Condition) {
+		return nil, errors.New("SHOW TAG VALUES doesn't support time in WHERE clause")
 	}
+
+	condition := rewriteTagKeyCondition(stmt.Op, stmt.TagKeyExpr, stmt.Condition)
 	condition = rewriteSourcesCondition(stmt.Sources, condition)
 
 	return &ShowTagValuesStatement{
@@ -134,6 +131,37 @@ func rewriteShowTagValuesStatement(stmt *ShowTagValuesStatement) (Statement, err
 	}, nil
 }
 
+func rewriteShowTagValuesCardinalityStatement(stmt *ShowTagValuesCardinalityStatement) (Statement, error) {
+	// Check for time in WHERE clause (not supported).
+	if HasTimeExpr(stmt.Condition) {
+		return nil, errors.New("SHOW TAG VALUES CARDINALITY doesn't support time in WHERE clause")
+	}
+
+	condition := rewriteTagKeyCondition(stmt.Op, stmt.TagKeyExpr, stmt.Condition)
+	condition = rewriteSourcesCondition(stmt.Sources, condition)
+
+	return &ShowTagValuesCardinalityStatement{
+		Database:   stmt.Database,
+		Exact:      stmt.Exact,
+		Op:         stmt.Op,
+		TagKeyExpr: stmt.TagKeyExpr,
+		Condition:  condition,
+	}, nil
+}
+
+func rewriteShowFieldKeyCardinalityStatement(stmt *ShowFieldKeyCardinalityStatement) (Statement, error) {
+	// Check for time in WHERE clause (not supported).
+	if HasTimeExpr(stmt.Condition) {
+		return nil, errors.New("SHOW FIELD KEY CARDINALITY doesn't support time in WHERE clause")
+	}
+
+	return &ShowFieldKeyCardinalityStatement{
+		Database:  stmt.Database,
+		Exact:     stmt.Exact,
+		Condition: rewriteSourcesCondition(stmt.Sources, stmt.Condition),
+	}, nil
+}
+
 func rewriteShowTagKeysStatement(stmt *ShowTagKeysStatement) (Statement, error) {
 	// Check for time in WHERE clause (not supported).
 	if HasTimeExpr(stmt.Condition) {
@@ -155,6 +183,47 @@ func rewriteShowTagKeysStatement(stmt *ShowTagKeysStatement) (Statement, error)
 	}, nil
 }
 
+// rewriteTagKeyCondition rewrites the tag key selection of a WITH KEY clause
+// into `_tagKey` expressions. Merges with cond and returns a new condition.
+func rewriteTagKeyCondition(op Token, tagKeyExpr Literal, cond Expr) Expr {
+	var expr Expr
+	if list, ok := tagKeyExpr.(*ListLiteral); ok {
+		for _, tagKey := range list.Vals {
+			tagExpr := &BinaryExpr{
+				Op:  EQ,
+				LHS: &VarRef{Val: "_tagKey"},
+				RHS: &StringLiteral{Val: tagKey},
+			}
+
+			if expr != nil {
+				expr = &BinaryExpr{
+					Op:  OR,
+					LHS: expr,
+					RHS: tagExpr,
+				}
+			} else {
+				expr = tagExpr
+			}
+		}
+	} else {
+		expr = &BinaryExpr{
+			Op:  op,
+			LHS: &VarRef{Val: "_tagKey"},
+			RHS: tagKeyExpr,
+		}
+	}
+
+	// Set condition or "AND" together.
+	if cond == nil {
+		return expr
+	}
+	return &BinaryExpr{
+		Op:  AND,
+		LHS: &ParenExpr{Expr: cond},
+		RHS: &ParenExpr{Expr: expr},
+	}
+}
+
 // rewriteSources rewrites sources with previous database and retention policy
 func rewriteSources(sources Sources, measurementName, defaultDatabase string) Sources {
 	newSources := Sources{}
@@ -139,6 +139,30 @@ func TestRewriteStatement(t *testing.T) {
 			stmt: `SHOW TAG KEYS ON db0 FROM mydb.myrp1.cpu WHERE region = 'uswest'`,
 			s:    `SELECT tagKey FROM mydb.myrp1._tagKeys WHERE (_name = 'cpu') AND (region = 'uswest')`,
 		},
+		{
+			stmt: `SHOW SERIES CARDINALITY`,
+			s:    `SHOW SERIES CARDINALITY`,
+		},
+		{
+			stmt: `SHOW EXACT SERIES CARDINALITY ON db0 FROM cpu WHERE region = 'uswest'`,
+			s:    `SHOW EXACT SERIES CARDINALITY ON db0 WHERE (_name = 'cpu') AND (region = 'uswest')`,
+		},
+		{
+			stmt: `SHOW MEASUREMENT CARDINALITY FROM /c.*/`,
+			s:    `SHOW MEASUREMENT CARDINALITY WHERE _name =~ /c.*/`,
+		},
+		{
+			stmt: `SHOW EXACT TAG VALUES CARDINALITY FROM cpu WITH KEY = host`,
+			s:    `SHOW EXACT TAG VALUES CARDINALITY WITH KEY = host WHERE (_name = 'cpu') AND (_tagKey = 'host')`,
+		},
+		{
+			stmt: `SHOW TAG VALUES CARDINALITY WITH KEY IN (host, region) WHERE region = 'uswest'`,
+			s:    `SHOW TAG VALUES CARDINALITY WITH KEY IN (host, region) WHERE (region = 'uswest') AND (_tagKey = 'host' OR _tagKey = 'region')`,
+		},
+		{
+			stmt: `SHOW FIELD KEY CARDINALITY ON db0 FROM cpu`,
+			s:    `SHOW FIELD KEY CARDINALITY ON db0 WHERE _name = 'cpu'`,
+		},
 		{
 			stmt: `SELECT value FROM cpu`,
 			s:    `SELECT value FROM cpu`,
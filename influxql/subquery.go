@@ -74,8 +74,8 @@ func (b *subqueryBuilder) mapAuxField(name *VarRef) IteratorMap {
 	// Unable to find this in the list of fields.
 	// Look within the dimensions and create a field if we find it.
 	for _, d := range b.stmt.Dimensions {
-		if d, ok := d.Expr.(*VarRef); ok && name.Val == d.Val {
-			return TagMap(d.Val)
+		if key, ok := d.TagKey(); ok && name.Val == key {
+			return TagMap(key)
 		}
 	}
 
@@ -67,11 +67,15 @@ const (
 	// ALL and the following are InfluxQL Keywords
 	ALL
 	ALTER
+	ANALYZE
 	ANY
 	AS
 	ASC
+	BACKFILL
 	BEGIN
 	BY
+	CARDINALITY
+	CASE
 	CREATE
 	CONTINUOUS
 	DATABASE
@@ -84,8 +88,10 @@ const (
 	DISTINCT
 	DROP
 	DURATION
+	ELSE
 	END
 	EVERY
+	EXACT
 	EXPLAIN
 	FIELD
 	FOR
@@ -94,10 +100,12 @@ const (
 	GRANTS
 	GROUP
 	GROUPS
+	HAVING
 	IN
 	INF
 	INSERT
 	INTO
+	JOIN
 	KEY
 	KEYS
 	KILL
@@ -131,10 +139,12 @@ const (
 	SUBSCRIPTION
 	SUBSCRIPTIONS
 	TAG
+	THEN
 	TO
 	USER
 	USERS
 	VALUES
+	WHEN
 	WHERE
 	WITH
 	WRITE
@@ -187,11 +197,15 @@ var tokens = [...]string{
 
 	ALL:           "ALL",
 	ALTER:         "ALTER",
+	ANALYZE:       "ANALYZE",
 	ANY:           "ANY",
 	AS:            "AS",
 	ASC:           "ASC",
+	BACKFILL:      "BACKFILL",
 	BEGIN:         "BEGIN",
 	BY:            "BY",
+	CARDINALITY:   "CARDINALITY",
+	CASE:          "CASE",
 	CREATE:        "CREATE",
 	CONTINUOUS:    "CONTINUOUS",
 	DATABASE:      "DATABASE",
@@ -204,8 +218,10 @@ var tokens = [...]string{
 	DISTINCT:      "DISTINCT",
 	DROP:          "DROP",
 	DURATION:      "DURATION",
+	ELSE:          "ELSE",
 	END:           "END",
 	EVERY:         "EVERY",
+	EXACT:         "EXACT",
 	EXPLAIN:       "EXPLAIN",
 	FIELD:         "FIELD",
 	FOR:           "FOR",
@@ -214,10 +230,12 @@ var tokens = [...]string{
 	GRANTS:        "GRANTS",
 	GROUP:         "GROUP",
 	GROUPS:        "GROUPS",
+	HAVING:        "HAVING",
 	IN:            "IN",
 	INF:           "INF",
 	INSERT:        "INSERT",
 	INTO:          "INTO",
+	JOIN:          "JOIN",
 	KEY:           "KEY",
 	KEYS:          "KEYS",
 	KILL:          "KILL",
@@ -251,10 +269,12 @@ var tokens = [...]string{
 	SUBSCRIPTION:  "SUBSCRIPTION",
 	SUBSCRIPTIONS: "SUBSCRIPTIONS",
 	TAG:           "TAG",
+	THEN:          "THEN",
 	TO:            "TO",
 	USER:          "USER",
 	USERS:         "USERS",
 	VALUES:        "VALUES",
+	WHEN:          "WHEN",
 	WHERE:         "WHERE",
 	WITH:          "WITH",
 	WRITE:         "WRITE",
@@ -0,0 +1,246 @@
+// Package tdigest implements the merging t-digest described by Ted Dunning
+// and Otmar Ertl in "Computing Extremely Accurate Quantiles Using t-Digests".
+//
+// A t-digest summarizes a distribution of values with a bounded number of
+// centroids. Quantiles near the tails of the distribution are estimated more
+// accurately than quantiles near the median. Digests can be merged, which
+// allows a digest to be computed for each partition of a data set and then
+// combined into a digest of the entire data set.
+package tdigest
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Current version of the t-digest encoding.
+const version uint8 = 1
+
+// DefaultCompression is the default compression. Higher values use more
+// centroids and result in more accurate quantiles.
+const DefaultCompression = 100
+
+// Centroid is the mean of a group of values and the number of values in it.
+type Centroid struct {
+	Mean   float64
+	Weight float64
+}
+
+// TDigest is a sketch for estimating quantiles of a distribution of values.
+type TDigest struct {
+	compression float64
+
+	// The merged centroids sorted by mean and the values that have not
+	// been merged into them yet.
+	centroids []Centroid
+	unmerged  []Centroid
+
+	count    float64
+	min, max float64
+}
+
+// New returns a new t-digest with the default compression.
+func New() *TDigest {
+	return NewWithCompression(DefaultCompression)
+}
+
+// NewWithCompression returns a new t-digest with the given compression.
+func NewWithCompression(compression float64) *TDigest {
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Compression returns the compression of the t-digest.
+func (t *TDigest) Compression() float64 { return t.compression }
+
+// Count returns the number of values added to the t-digest.
+func (t *TDigest) Count() float64 { return t.count }
+
+// Add adds a single value to the t-digest.
+func (t *TDigest) Add(v float64) {
+	t.AddWeighted(v, 1)
+}
+
+// AddWeighted adds a value with the given weight to the t-digest.
+func (t *TDigest) AddWeighted(v, weight float64) {
+	if math.IsNaN(v) || weight <= 0 {
+		return
+	}
+
+	t.unmerged = append(t.unmerged, Centroid{Mean: v, Weight: weight})
+	t.count += weight
+	if v < t.min {
+		t.min = v
+	}
+	if v > t.max {
+		t.max = v
+	}
+
+	if len(t.unmerged) >= t.bufferSize() {
+		t.compress()
+	}
+}
+
+// Merge merges another t-digest into this one.
+func (t *TDigest) Merge(other *TDigest) {
+	if other == nil || other.count == 0 {
+		return
+	}
+
+	t.unmerged = append(t.unmerged, other.centroids...)
+	t.unmerged = append(t.unmerged, other.unmerged...)
+	t.count += other.count
+	if other.min < t.min {
+		t.min = other.min
+	}
+	if other.max > t.max {
+		t.max = other.max
+	}
+	t.compress()
+}
+
+// Quantile returns an estimate of the value at quantile q, where q is between
+// 0 and 1. NaN is returned if no values have been added to the t-digest.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.compress()
+
+	n := len(t.centroids)
+	if n == 0 || math.IsNaN(q) {
+		return math.NaN()
+	} else if q <= 0 {
+		return t.min
+	} else if q >= 1 {
+		return t.max
+	} else if n == 1 {
+		return t.centroids[0].Mean
+	}
+
+	// Each centroid is centered at the middle of its weight. Interpolate
+	// between the centers of the two centroids surrounding the index and
+	// between the extremes and the centers of the outer centroids.
+	index := q * t.count
+	first := t.centroids[0]
+	if index < first.Weight/2 {
+		return t.min + (first.Mean-t.min)*index/(first.Weight/2)
+	}
+
+	weightSoFar := first.Weight / 2
+	for i := 0; i < n-1; i++ {
+		left, right := t.centroids[i], t.centroids[i+1]
+		dw := (left.Weight + right.Weight) / 2
+		if weightSoFar+dw > index {
+			return left.Mean + (right.Mean-left.Mean)*(index-weightSoFar)/dw
+		}
+		weightSoFar += dw
+	}
+
+	last := t.centroids[n-1]
+	return last.Mean + (t.max-last.Mean)*(index-weightSoFar)/(last.Weight/2)
+}
+
+// Centroids returns the centroids of the t-digest sorted by mean.
+func (t *TDigest) Centroids() []Centroid {
+	t.compress()
+	return t.centroids
+}
+
+// bufferSize returns the number of values that are buffered before they are
+// merged into the centroids.
+func (t *TDigest) bufferSize() int {
+	return int(5 * math.Ceil(t.compression))
+}
+
+// compress merges the unmerged values into the centroids.
+func (t *TDigest) compress() {
+	if len(t.unmerged) == 0 {
+		return
+	}
+
+	all := append(t.unmerged, t.centroids...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Mean < all[j].Mean })
+
+	// Merge neighbouring centroids as long as the merged centroid does not
+	// exceed the size limit for its position in the distribution. The limit
+	// is derived from the arcsine scale function and is smaller near the
+	// tails so the extreme quantiles remain accurate.
+	merged := make([]Centroid, 0, len(all))
+	cur := all[0]
+	weightSoFar := 0.0
+	for _, c := range all[1:] {
+		proposed := cur.Weight + c.Weight
+		q0 := weightSoFar / t.count
+		q2 := (weightSoFar + proposed) / t.count
+		limit := t.count * 2 * math.Pi * math.Sqrt(math.Min(q0*(1-q0), q2*(1-q2))) / t.compression
+
+		if proposed <= limit {
+			cur.Mean += (c.Mean - cur.Mean) * c.Weight / proposed
+			cur.Weight = proposed
+			continue
+		}
+
+		weightSoFar += cur.Weight
+		merged = append(merged, cur)
+		cur = c
+	}
+	merged = append(merged, cur)
+
+	t.centroids = merged
+	t.unmerged = nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t *TDigest) MarshalBinary() ([]byte, error) {
+	t.compress()
+
+	buf := make([]byte, 1+8*3+4+16*len(t.centroids))
+	buf[0] = version
+	binary.BigEndian.PutUint64(buf[1:], math.Float64bits(t.compression))
+	binary.BigEndian.PutUint64(buf[9:], math.Float64bits(t.min))
+	binary.BigEndian.PutUint64(buf[17:], math.Float64bits(t.max))
+	binary.BigEndian.PutUint32(buf[25:], uint32(len(t.centroids)))
+
+	b := buf[29:]
+	for _, c := range t.centroids {
+		binary.BigEndian.PutUint64(b, math.Float64bits(c.Mean))
+		binary.BigEndian.PutUint64(b[8:], math.Float64bits(c.Weight))
+		b = b[16:]
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (t *TDigest) UnmarshalBinary(data []byte) error {
+	if len(data) < 29 {
+		return errors.New("tdigest: data too short")
+	} else if v := data[0]; v != version {
+		return fmt.Errorf("tdigest: unexpected version %d, expected %d", v, version)
+	}
+
+	n := binary.BigEndian.Uint32(data[25:])
+	b := data[29:]
+	if len(b) != 16*int(n) {
+		return fmt.Errorf("tdigest: unexpected data length %d for %d centroids", len(data), n)
+	}
+
+	t.compression = math.Float64frombits(binary.BigEndian.Uint64(data[1:]))
+	t.min = math.Float64frombits(binary.BigEndian.Uint64(data[9:]))
+	t.max = math.Float64frombits(binary.BigEndian.Uint64(data[17:]))
+	t.centroids = make([]Centroid, n)
+	t.unmerged = nil
+	t.count = 0
+	for i := range t.centroids {
+		t.centroids[i] = Centroid{
+			Mean:   math.Float64frombits(binary.BigEndian.Uint64(b)),
+			Weight: math.Float64frombits(binary.BigEndian.Uint64(b[8:])),
+		}
+		t.count += t.centroids[i].Weight
+		b = b[16:]
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package tdigest_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/influxdata/influxdb/pkg/estimator/tdigest"
+)
+
+func TestTDigest_Quantile_Empty(t *testing.T) {
+	td := tdigest.New()
+	if v := td.Quantile(0.5); !math.IsNaN(v) {
+		t.Fatalf("unexpected quantile: %v", v)
+	}
+}
+
+func TestTDigest_Quantile_Exact(t *testing.T) {
+	td := tdigest.New()
+	for _, v := range []float64{5, 1, 4, 2, 3} {
+		td.Add(v)
+	}
+
+	for _, tt := range []struct {
+		q   float64
+		exp float64
+	}{
+		{q: 0, exp: 1},
+		{q: 0.5, exp: 3},
+		{q: 1, exp: 5},
+	} {
+		if got := td.Quantile(tt.q); got != tt.exp {
+			t.Errorf("quantile(%v): got %v, exp %v", tt.q, got, tt.exp)
+		}
+	}
+}
+
+func TestTDigest_Quantile_Accuracy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	values := make([]float64, 100000)
+	td := tdigest.New()
+	for i := range values {
+		values[i] = rnd.NormFloat64()
+		td.Add(values[i])
+	}
+	sort.Float64s(values)
+
+	for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+		exp := values[int(q*float64(len(values)))]
+		if got := td.Quantile(q); math.Abs(got-exp) > 0.02 {
+			t.Errorf("quantile(%v): got %v, exp %v", q, got, exp)
+		}
+	}
+
+	if n := len(td.Centroids()); n > 2*tdigest.DefaultCompression {
+		t.Errorf("unexpected number of centroids: %d", n)
+	}
+}
+
+func TestTDigest_Merge(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	values := make([]float64, 0, 100000)
+	digests := make([]*tdigest.TDigest, 10)
+	for i := range digests {
+		digests[i] = tdigest.New()
+		for j := 0; j < 10000; j++ {
+			v := rnd.ExpFloat64()
+			values = append(values, v)
+			digests[i].Add(v)
+		}
+	}
+	sort.Float64s(values)
+
+	td := tdigest.New()
+	for _, other := range digests {
+		td.Merge(other)
+	}
+
+	if got, exp := td.Count(), float64(len(values)); got != exp {
+		t.Fatalf("unexpected count: got %v, exp %v", got, exp)
+	}
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		exp := values[int(q*float64(len(values)))]
+		if got := td.Quantile(q); math.Abs(got-exp)/exp > 0.01 {
+			t.Errorf("quantile(%v): got %v, exp %v", q, got, exp)
+		}
+	}
+}
+
+func TestTDigest_MarshalBinary(t *testing.T) {
+	td := tdigest.New()
+	for i := 0; i < 1000; i++ {
+		td.Add(float64(i))
+	}
+
+	buf, err := td.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other := tdigest.New()
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, exp := other.Count(), td.Count(); got != exp {
+		t.Fatalf("unexpected count: got %v, exp %v", got, exp)
+	}
+	for _, q := range []float64{0, 0.25, 0.5, 0.75, 0.99, 1} {
+		if got, exp := other.Quantile(q), td.Quantile(q); got != exp {
+			t.Errorf("quantile(%v): got %v, exp %v", q, got, exp)
+		}
+	}
+}
+
+func TestTDigest_UnmarshalBinary_Invalid(t *testing.T) {
+	td := tdigest.New()
+	if err := td.UnmarshalBinary([]byte("not a digest")); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -12,6 +12,18 @@ import (
 const (
 	// The default value of how often to check whether any CQs need to be run.
 	DefaultRunInterval = time.Second
+
+	// The default minimum length of time backfilled by a single query.
+	DefaultBackfillChunkSize = time.Hour
+
+	// The default time to wait between the queries of a backfill.
+	DefaultBackfillThrottle = 100 * time.Millisecond
+
+	// The default maximum number of queries run by a single backfill.
+	DefaultMaxBackfillChunks = 10000
+
+	// The default interval at which the results of incremental CQs are written.
+	DefaultIncrementalFlushInterval = time.Second
 )
 
 // Config represents a configuration for the continuous query service.
@@ -31,6 +43,23 @@ type Config struct {
 	// every minute, this should be set to 1 minute. The default is set to '1s' so the interval
 	// is compatible with most aggregations.
 	RunInterval toml.Duration `toml:"run-interval"`
+
+	// BackfillChunkSize is the minimum length of time computed by each query run by
+	// BACKFILL CONTINUOUS QUERY. Chunks are rounded down to a multiple of the group by
+	// interval of the continuous query and always contain at least one interval.
+	BackfillChunkSize toml.Duration `toml:"backfill-chunk-size"`
+
+	// BackfillThrottle is how long a backfill waits between each chunk so it does not
+	// starve other queries.
+	BackfillThrottle toml.Duration `toml:"backfill-throttle"`
+
+	// MaxBackfillChunks is the maximum number of chunks a single BACKFILL CONTINUOUS
+	// QUERY may run. A backfill with more chunks is refused. Zero means unlimited.
+	MaxBackfillChunks int `toml:"max-backfill-chunks"`
+
+	// IncrementalFlushInterval is how often the results of incremental CQs are written
+	// to their target measurements.
+	IncrementalFlushInterval toml.Duration `toml:"incremental-flush-interval"`
 }
 
 // NewConfig returns a new instance of Config with defaults.
@@ -40,6 +69,11 @@ func NewConfig() Config {
 		Enabled:           true,
 		QueryStatsEnabled: false,
 		RunInterval:       toml.Duration(DefaultRunInterval),
+		BackfillChunkSize: toml.Duration(DefaultBackfillChunkSize),
+		BackfillThrottle:  toml.Duration(DefaultBackfillThrottle),
+		MaxBackfillChunks: DefaultMaxBackfillChunks,
+
+		IncrementalFlushInterval: toml.Duration(DefaultIncrementalFlushInterval),
 	}
 }
 
@@ -54,6 +88,18 @@ func (c Config) Validate() error {
 	if c.RunInterval <= 0 {
 		return errors.New("run-interval must be positive")
 	}
+	if c.BackfillChunkSize < 0 {
+		return errors.New("backfill-chunk-size must be non-negative")
+	}
+	if c.BackfillThrottle < 0 {
+		return errors.New("backfill-throttle must be non-negative")
+	}
+	if c.MaxBackfillChunks < 0 {
+		return errors.New("max-backfill-chunks must be non-negative")
+	}
+	if c.IncrementalFlushInterval <= 0 {
+		return errors.New("incremental-flush-interval must be positive")
+	}
 
 	return nil
 }
@@ -70,5 +116,10 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"enabled":             true,
 		"query-stats-enabled": c.QueryStatsEnabled,
 		"run-interval":        c.RunInterval,
+		"backfill-chunk-size": c.BackfillChunkSize,
+		"backfill-throttle":   c.BackfillThrottle,
+		"max-backfill-chunks": c.MaxBackfillChunks,
+
+		"incremental-flush-interval": c.IncrementalFlushInterval,
 	}), nil
 }
@@ -0,0 +1,10 @@
+// +build !windows
+
+package continuous_querier
+
+import "os"
+
+// renameFile will rename the source to target using os function.
+func renameFile(oldpath, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
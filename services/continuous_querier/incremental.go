@@ -0,0 +1,617 @@
+package continuous_querier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/influxdata/influxdb/influxql"
+	"github.com/influxdata/influxdb/models"
+	"github.com/influxdata/influxdb/services/meta"
+)
+
+// pointsWriter is an internal interface to make testing easier.
+type pointsWriter interface {
+	WritePointsPrivileged(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error
+}
+
+// incrementalView maintains the results of an incremental continuous query.
+// The aggregates of each interval are updated from the points as they are
+// written instead of reading the raw data back from the shards.
+//
+// The aggregates of an interval are only kept while every point written to it
+// has been seen. Otherwise the interval is recomputed from the stored points
+// by running the query over it. This happens for the intervals that were open
+// when the view was created, for intervals where a shard dropped some of the
+// points of a write and for intervals that received a point that may
+// overwrite a point that was already aggregated.
+type incrementalView struct {
+	database string
+	name     string
+	query    string
+
+	// The measurement that is aggregated.
+	sourceRP   string
+	sourceName string
+
+	// The tags the results are grouped by, the aggregates and the names of
+	// the fields they are written to.
+	dimensions []string
+	calls      []*influxql.Call
+	columns    []string
+
+	interval    time.Duration
+	offset      time.Duration
+	resampleFor time.Duration
+	location    *time.Location
+
+	// Where the results are written to.
+	targetDB   string
+	targetRP   string
+	targetName string
+
+	mu        sync.Mutex
+	intervals map[int64]*incrementalInterval
+
+	// The intervals that were final at the last flush. They are removed
+	// once their results have been written.
+	final []int64
+}
+
+// incrementalInterval holds the results of an interval.
+type incrementalInterval struct {
+	start time.Time
+
+	// Set when the results of the interval are recomputed from the stored
+	// points instead of the aggregates. Dirty is set when the interval must
+	// be recomputed at the next flush.
+	recompute bool
+	dirty     bool
+
+	// The running aggregates of each set of tags.
+	windows map[string]*incrementalWindow
+}
+
+// incrementalWindow holds the running aggregates of an interval for a set of
+// tags.
+type incrementalWindow struct {
+	tags map[string]string
+	aggs []incrementalAggregate
+
+	// The time of the latest point of each series.
+	last map[string]int64
+
+	// Set when the aggregates have changed since they were last flushed.
+	dirty bool
+}
+
+// newIncrementalView returns a view that maintains the results of an
+// incremental continuous query. The intervals that are open at now are
+// recomputed at the first flush because their points were written before the
+// view existed.
+func newIncrementalView(dbi *meta.DatabaseInfo, cqi *meta.ContinuousQueryInfo, now time.Time) (*incrementalView, error) {
+	cq, err := NewContinuousQuery(dbi.Name, cqi)
+	if err != nil {
+		return nil, err
+	} else if !cq.Incremental {
+		return nil, fmt.Errorf("continuous query %s is not incremental", cqi.Name)
+	}
+
+	v := &incrementalView{
+		database:  dbi.Name,
+		name:      cqi.Name,
+		query:     cqi.Query,
+		location:  cq.q.Location,
+		intervals: make(map[int64]*incrementalInterval),
+	}
+	if v.location == nil {
+		v.location = time.UTC
+	}
+
+	// The query has been validated when it was parsed so the source is a
+	// single measurement and the fields are calls on a field.
+	source := cq.q.Sources[0].(*influxql.Measurement)
+	if source.Database != "" && source.Database != dbi.Name {
+		return nil, fmt.Errorf("incremental continuous query %s must select from database %s", cqi.Name, dbi.Name)
+	}
+	v.sourceRP = source.RetentionPolicy
+	if v.sourceRP == "" {
+		v.sourceRP = dbi.DefaultRetentionPolicy
+	}
+	v.sourceName = source.Name
+
+	for _, d := range cq.q.Dimensions {
+		if ref, ok := d.Expr.(*influxql.VarRef); ok {
+			v.dimensions = append(v.dimensions, ref.Val)
+		}
+	}
+	for _, f := range cq.q.Fields {
+		v.calls = append(v.calls, f.Expr.(*influxql.Call))
+	}
+	v.columns = cq.q.ColumnNames()[1:]
+
+	if v.interval, err = cq.q.GroupByInterval(); err != nil {
+		return nil, err
+	}
+	if v.offset, err = cq.q.GroupByOffset(); err != nil {
+		return nil, err
+	}
+	v.resampleFor = v.interval
+	if cq.Resample.For != 0 {
+		v.resampleFor = cq.Resample.For
+	}
+
+	target := cq.q.Target.Measurement
+	v.targetDB, v.targetRP, v.targetName = target.Database, target.RetentionPolicy, target.Name
+	if v.targetDB == "" {
+		v.targetDB = dbi.Name
+	}
+	if v.targetName == "" {
+		v.targetName = v.sourceName
+	}
+
+	for start := v.windowStart(now.Add(-v.resampleFor)); !start.After(now); start = start.Add(v.interval) {
+		v.getInterval(start).setRecompute()
+	}
+	return v, nil
+}
+
+// windowStart returns the start of the interval that contains t.
+func (v *incrementalView) windowStart(t time.Time) time.Time {
+	return truncate(t.In(v.location).Add(-v.offset), v.interval).Add(v.offset)
+}
+
+// getInterval returns the interval that starts at start, creating it if it
+// does not exist.
+func (v *incrementalView) getInterval(start time.Time) *incrementalInterval {
+	iv := v.intervals[start.UnixNano()]
+	if iv == nil {
+		iv = &incrementalInterval{
+			start:   start,
+			windows: make(map[string]*incrementalWindow),
+		}
+		v.intervals[start.UnixNano()] = iv
+	}
+	return iv
+}
+
+// changed returns true if the interval has results that have not been
+// flushed.
+func (iv *incrementalInterval) changed() bool {
+	if iv.dirty {
+		return true
+	}
+	for _, w := range iv.windows {
+		if w.dirty {
+			return true
+		}
+	}
+	return false
+}
+
+// setRecompute marks the interval to be recomputed at the next flush and
+// drops its aggregates.
+func (iv *incrementalInterval) setRecompute() {
+	iv.recompute, iv.dirty = true, true
+	iv.windows = nil
+}
+
+// writePoints updates the aggregates from points written to the retention
+// policy. Partial is set if some of the points were not stored. Points in
+// intervals that ended more than the resample duration ago are final and are
+// ignored. It returns the number of points that were ignored because they
+// were too late.
+func (v *incrementalView) writePoints(retentionPolicy string, points []models.Point, partial bool, now time.Time) (late int) {
+	if retentionPolicy != v.sourceRP {
+		return 0
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	cutoff := now.Add(-v.resampleFor)
+	for _, p := range points {
+		if string(p.Name()) != v.sourceName {
+			continue
+		}
+
+		start := v.windowStart(p.Time())
+		if !start.Add(v.interval).After(cutoff) {
+			late++
+			continue
+		}
+
+		// The stored points are not known if the write was partial.
+		iv := v.getInterval(start)
+		if iv.recompute || partial {
+			iv.setRecompute()
+			continue
+		}
+
+		fields, err := p.Fields()
+		if err != nil {
+			continue
+		}
+
+		// Find the window for the tags of the point.
+		tags := p.Tags()
+		values := make([]string, len(v.dimensions))
+		for i, dim := range v.dimensions {
+			values[i] = tags.GetString(dim)
+		}
+		key := strings.Join(values, "\x00")
+
+		w := iv.windows[key]
+		if w == nil {
+			w = &incrementalWindow{
+				tags: make(map[string]string, len(v.dimensions)),
+				aggs: make([]incrementalAggregate, len(v.calls)),
+				last: make(map[string]int64),
+			}
+			for i, dim := range v.dimensions {
+				if values[i] != "" {
+					w.tags[dim] = values[i]
+				}
+			}
+			iv.windows[key] = w
+		}
+
+		// A point that is not later than the latest point of its series may
+		// overwrite a point that has already been aggregated.
+		series := string(p.Key())
+		if last, ok := w.last[series]; ok && p.UnixNano() <= last {
+			iv.setRecompute()
+			continue
+		}
+		w.last[series] = p.UnixNano()
+
+		for i, call := range v.calls {
+			ref := call.Args[0].(*influxql.VarRef)
+			if value, ok := fields[ref.Val]; ok {
+				w.aggs[i].add(value, p.UnixNano())
+				w.dirty = true
+			}
+		}
+	}
+	return late
+}
+
+// recomputeIntervals marks the intervals that start at the given times to be
+// recomputed at the next flush. Intervals that have been removed from the
+// view are ignored.
+func (v *incrementalView) recomputeIntervals(starts []time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, start := range starts {
+		if iv := v.intervals[start.UnixNano()]; iv != nil {
+			iv.setRecompute()
+		}
+	}
+}
+
+// flush returns the results of the intervals that have changed since the
+// last flush and the start of the intervals that must be recomputed, in
+// time order. Intervals that are final are removed from the view by
+// removeFinal once the results have been written.
+func (v *incrementalView) flush(now time.Time) (models.Points, []time.Time, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	var points models.Points
+	var recompute []time.Time
+	cutoff := now.Add(-v.resampleFor)
+	v.final = v.final[:0]
+	for key, iv := range v.intervals {
+		if iv.recompute && iv.dirty {
+			recompute = append(recompute, iv.start)
+			iv.dirty = false
+		}
+
+		for _, w := range iv.windows {
+			if !w.dirty {
+				continue
+			}
+
+			fields := make(models.Fields, len(v.calls))
+			for i, call := range v.calls {
+				if value := w.aggs[i].value(call.Name); value != nil {
+					fields[v.columns[i]] = value
+				}
+			}
+
+			if len(fields) > 0 {
+				p, err := models.NewPoint(v.targetName, models.NewTags(w.tags), fields, iv.start)
+				if err != nil {
+					return nil, nil, err
+				}
+				points = append(points, p)
+			}
+			w.dirty = false
+		}
+
+		if !iv.start.Add(v.interval).After(cutoff) {
+			v.final = append(v.final, key)
+		}
+	}
+	sort.Slice(recompute, func(i, j int) bool { return recompute[i].Before(recompute[j]) })
+	return points, recompute, nil
+}
+
+// removeFinal removes the intervals that were final at the last flush from
+// the view. It is called after their results have been written so a failed
+// write can still recompute them. An interval that changed since the flush is
+// kept until its results are flushed again.
+func (v *incrementalView) removeFinal() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, key := range v.final {
+		if iv := v.intervals[key]; iv != nil && !iv.changed() {
+			delete(v.intervals, key)
+		}
+	}
+	v.final = v.final[:0]
+}
+
+// incrementalAggregate is the running state of count(), sum(), min(), max(),
+// mean() and last() over the values of a field.
+type incrementalAggregate struct {
+	// The number of values and the number of numeric values.
+	n    int64
+	numN int64
+
+	// The sum of the numeric values. Integers are summed separately so the
+	// sum of an integer field is an integer.
+	sum   float64
+	isum  int64
+	float bool
+
+	min, max interface{}
+
+	last     interface{}
+	lastTime int64
+}
+
+// add adds a value of the field at the given time.
+func (a *incrementalAggregate) add(value interface{}, t int64) {
+	a.n++
+	if a.last == nil || t >= a.lastTime {
+		a.last, a.lastTime = value, t
+	}
+
+	var f float64
+	switch value := value.(type) {
+	case float64:
+		a.sum += value
+		a.float = true
+		f = value
+	case int64:
+		a.isum += value
+		f = float64(value)
+	default:
+		return
+	}
+	a.numN++
+
+	if a.min == nil || f < toFloat(a.min) {
+		a.min = value
+	}
+	if a.max == nil || f > toFloat(a.max) {
+		a.max = value
+	}
+}
+
+// value returns the result of the named aggregate or nil if it has no value.
+func (a *incrementalAggregate) value(name string) interface{} {
+	switch name {
+	case "count":
+		return a.n
+	case "last":
+		return a.last
+	case "min":
+		return a.min
+	case "max":
+		return a.max
+	}
+
+	if a.numN == 0 {
+		return nil
+	}
+	switch name {
+	case "sum":
+		if a.float {
+			return a.sum + float64(a.isum)
+		}
+		return a.isum
+	case "mean":
+		return (a.sum + float64(a.isum)) / float64(a.numN)
+	}
+	return nil
+}
+
+// toFloat returns a numeric value as a float.
+func toFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+// PointsWritten updates the incremental continuous queries from the points
+// written to a shard. It is called by the points writer after each
+// successful write.
+func (s *Service) PointsWritten(database, retentionPolicy string, points []models.Point, partial bool) {
+	s.writeIncrementalPoints(database, retentionPolicy, points, partial, time.Now())
+}
+
+// writeIncrementalPoints updates the incremental continuous queries of the
+// database from the points written to a retention policy.
+func (s *Service) writeIncrementalPoints(database, retentionPolicy string, points []models.Point, partial bool, now time.Time) {
+	s.viewsMu.RLock()
+	defer s.viewsMu.RUnlock()
+	for _, v := range s.views {
+		if v == nil || v.database != database {
+			continue
+		}
+		if n := v.writePoints(retentionPolicy, points, partial, now); n > 0 {
+			atomic.AddInt64(&s.stats.IncrementalLate, int64(n))
+		}
+	}
+}
+
+// flushLoop periodically writes the results of the incremental continuous
+// queries to their targets.
+func (s *Service) flushLoop() {
+	defer s.wg.Done()
+	t := time.NewTicker(time.Duration(s.Config.IncrementalFlushInterval))
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			s.flushIncremental(time.Now())
+			return
+		case <-t.C:
+			s.updateViews()
+			s.flushIncremental(time.Now())
+		}
+	}
+}
+
+// updateViews creates a view for each incremental continuous query. The
+// views of queries that have not changed are kept so their aggregates are
+// not lost. Queries that are not incremental are kept with a nil view so
+// they are only parsed when they change.
+func (s *Service) updateViews() {
+	s.viewsMu.RLock()
+	prev := s.views
+	s.viewsMu.RUnlock()
+
+	views := make(map[string]*incrementalView)
+	queries := make(map[string]string)
+	for _, db := range s.MetaClient.Databases() {
+		for _, cqi := range db.ContinuousQueries {
+			id := fmt.Sprintf("%s%s%s", db.Name, idDelimiter, cqi.Name)
+			queries[id] = cqi.Query
+			if s.viewQueries[id] == cqi.Query {
+				views[id] = prev[id]
+				continue
+			}
+
+			if cq, err := NewContinuousQuery(db.Name, &cqi); err != nil || !cq.Incremental {
+				views[id] = nil
+				continue
+			}
+
+			v, err := newIncrementalView(&db, &cqi, time.Now())
+			if err != nil {
+				s.Logger.Info(fmt.Sprintf("error creating incremental continuous query %s: %s", cqi.Name, err))
+			}
+			views[id] = v
+		}
+	}
+
+	s.viewsMu.Lock()
+	s.views, s.viewQueries = views, queries
+	s.viewsMu.Unlock()
+}
+
+// flushIncremental writes the results of the incremental continuous queries
+// that have changed since the last flush.
+func (s *Service) flushIncremental(now time.Time) {
+	// The views are not locked while the results are written since the
+	// writes update the views.
+	s.viewsMu.RLock()
+	views := make([]*incrementalView, 0, len(s.views))
+	for _, v := range s.views {
+		if v != nil {
+			views = append(views, v)
+		}
+	}
+	s.viewsMu.RUnlock()
+
+	for _, v := range views {
+		points, recompute, err := v.flush(now)
+		written := int64(len(points))
+		if err == nil && len(points) > 0 {
+			err = s.PointsWriter.WritePointsPrivileged(v.targetDB, v.targetRP, models.ConsistencyLevelOne, points)
+		}
+		if err == nil && len(recompute) > 0 {
+			var n int64
+			n, err = s.recomputeIncremental(v, recompute)
+			written += n
+		}
+
+		if err != nil {
+			// The results of the intervals may not have been written so
+			// they are recomputed at the next flush if they are still open.
+			for _, p := range points {
+				recompute = append(recompute, p.Time())
+			}
+			v.recomputeIntervals(recompute)
+
+			s.Logger.Info(fmt.Sprintf("error flushing incremental continuous query %s: %s", v.name, err))
+			atomic.AddInt64(&s.stats.QueryFail, 1)
+			s.setIncrementalError(v, err, now)
+			continue
+		} else if len(points) > 0 || len(recompute) > 0 {
+			atomic.AddInt64(&s.stats.QueryOK, 1)
+		}
+		v.removeFinal()
+
+		if s.loggingEnabled && written > 0 {
+			s.Logger.Info(fmt.Sprintf("flushed incremental continuous query %s, %d point(s) written", v.name, written))
+		}
+	}
+}
+
+// recomputeIncremental recomputes the results of the intervals of an
+// incremental continuous query from the stored points. Each run of
+// consecutive intervals is computed by a single query. It returns the number
+// of points written.
+func (s *Service) recomputeIncremental(v *incrementalView, starts []time.Time) (int64, error) {
+	dbi := s.MetaClient.Database(v.database)
+	if dbi == nil {
+		return 0, influxql.ErrDatabaseNotFound(v.database)
+	}
+
+	var written int64
+	for i := 0; i < len(starts); {
+		j := i + 1
+		for j < len(starts) && starts[j].Equal(starts[j-1].Add(v.interval)) {
+			j++
+		}
+
+		cq, err := NewContinuousQuery(v.database, &meta.ContinuousQueryInfo{Name: v.name, Query: v.query})
+		if err != nil {
+			return written, err
+		}
+		if cq.intoRP() == "" {
+			cq.setIntoRP(dbi.DefaultRetentionPolicy)
+		}
+		if err := cq.q.SetTimeRange(starts[i], starts[j-1].Add(v.interval)); err != nil {
+			return written, err
+		}
+
+		res := s.runContinuousQueryAndWriteResult(cq)
+		if res.Err != nil {
+			return written, res.Err
+		}
+		written += pointsWritten(res)
+		i = j
+	}
+	return written, nil
+}
+
+// setIncrementalError records the error of a failed flush in the status of
+// the continuous query.
+func (s *Service) setIncrementalError(v *incrementalView, err error, now time.Time) {
+	s.updateStatus(v.database, v.name, func(st *ContinuousQueryStatus) {
+		st.LastError = err.Error()
+		st.LastErrorTime = now.UTC()
+	})
+}
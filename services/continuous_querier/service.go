@@ -2,8 +2,11 @@
 package continuous_querier // import "github.com/influxdata/influxdb/services/continuous_querier"
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -28,8 +31,17 @@ const (
 
 // Statistics for the CQ service.
 const (
-	statQueryOK   = "queryOk"
-	statQueryFail = "queryFail"
+	statQueryOK         = "queryOk"
+	statQueryFail       = "queryFail"
+	statIncrementalLate = "incrementalLate"
+
+	// Statistics for the status of each continuous query.
+	statLastRun       = "lastRun"
+	statDurationNs    = "durationNs"
+	statLastSuccess   = "lastSuccess"
+	statPointsWritten = "pointsWritten"
+	statLastError     = "lastError"
+	statLastErrorTime = "lastErrorTime"
 )
 
 // ContinuousQuerier represents a service that executes continuous queries.
@@ -95,6 +107,24 @@ type Service struct {
 	lastRuns map[string]time.Time
 	stop     chan struct{}
 	wg       *sync.WaitGroup
+
+	// status maps a CQ id to the outcome of its most recent runs. It is
+	// saved to StatusPath when it changes so it survives restarts. The
+	// status is only kept in memory if StatusPath is empty.
+	statusMu      sync.RWMutex
+	status        map[string]*ContinuousQueryStatus
+	statusChanged bool
+	StatusPath    string
+
+	// PointsWriter writes the results of incremental CQs. Incremental CQs
+	// are run like other CQs if it is nil.
+	PointsWriter pointsWriter
+
+	// The views of the incremental CQs. They are updated from the points
+	// written to the database.
+	viewsMu     sync.RWMutex
+	views       map[string]*incrementalView
+	viewQueries map[string]string
 }
 
 // NewService returns a new instance of Service.
@@ -109,6 +139,7 @@ func NewService(c Config) *Service {
 		Logger:            zap.New(zap.NullEncoder()),
 		stats:             &Statistics{},
 		lastRuns:          map[string]time.Time{},
+		status:            map[string]*ContinuousQueryStatus{},
 	}
 
 	return s
@@ -125,10 +156,20 @@ func (s *Service) Open() error {
 	assert(s.MetaClient != nil, "MetaClient is nil")
 	assert(s.QueryExecutor != nil, "QueryExecutor is nil")
 
+	if err := s.loadStatus(); err != nil {
+		s.Logger.Info(fmt.Sprintf("unable to load continuous query status: %s", err))
+	}
+
 	s.stop = make(chan struct{})
 	s.wg = &sync.WaitGroup{}
 	s.wg.Add(1)
 	go s.backgroundLoop()
+
+	if s.PointsWriter != nil {
+		s.updateViews()
+		s.wg.Add(1)
+		go s.flushLoop()
+	}
 	return nil
 }
 
@@ -141,7 +182,7 @@ func (s *Service) Close() error {
 	s.wg.Wait()
 	s.wg = nil
 	s.stop = nil
-	return nil
+	return s.saveStatus()
 }
 
 // WithLogger sets the logger on the service.
@@ -151,8 +192,106 @@ func (s *Service) WithLogger(log zap.Logger) {
 
 // Statistics maintains the statistics for the continuous query service.
 type Statistics struct {
-	QueryOK   int64
-	QueryFail int64
+	QueryOK         int64
+	QueryFail       int64
+	IncrementalLate int64
+}
+
+// ContinuousQueryStatus records the outcome of the most recent runs of a
+// continuous query.
+type ContinuousQueryStatus struct {
+	// The time the query was last run and how long the run took.
+	LastRun  time.Time
+	Duration time.Duration
+
+	// The time of the last successful run, the time range it computed and
+	// the number of points it wrote.
+	LastSuccess   time.Time
+	StartTime     time.Time
+	EndTime       time.Time
+	PointsWritten int64
+
+	// The error returned by the last failed run and the time of that run.
+	LastError     string
+	LastErrorTime time.Time
+}
+
+// Status returns the status of the named continuous query. The zero value is
+// returned if the query has not run.
+func (s *Service) Status(database, name string) ContinuousQueryStatus {
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+	if st := s.status[database+idDelimiter+name]; st != nil {
+		return *st
+	}
+	return ContinuousQueryStatus{}
+}
+
+// updateStatus calls fn with the status of the named continuous query.
+func (s *Service) updateStatus(database, name string, fn func(st *ContinuousQueryStatus)) {
+	id := database + idDelimiter + name
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	st := s.status[id]
+	if st == nil {
+		st = &ContinuousQueryStatus{}
+		s.status[id] = st
+	}
+	fn(st)
+	s.statusChanged = true
+}
+
+// loadStatus reads the status of the continuous queries saved by a previous
+// run of the service.
+func (s *Service) loadStatus() error {
+	if s.StatusPath == "" {
+		return nil
+	}
+	buf, err := ioutil.ReadFile(s.StatusPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	status := make(map[string]*ContinuousQueryStatus)
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return err
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.status = status
+	return nil
+}
+
+// saveStatus writes the status of the continuous queries to StatusPath if it
+// has changed since it was last saved.
+func (s *Service) saveStatus() error {
+	if s.StatusPath == "" {
+		return nil
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	if !s.statusChanged {
+		return nil
+	}
+	buf, err := json.Marshal(s.status)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a partially written file never
+	// replaces the saved status.
+	tmpPath := s.StatusPath + "tmp"
+	if err := ioutil.WriteFile(tmpPath, buf, 0666); err != nil {
+		return err
+	} else if err := renameFile(tmpPath, s.StatusPath); err != nil {
+		return err
+	}
+	s.statusChanged = false
+	return nil
 }
 
 type statistic struct {
@@ -162,14 +301,38 @@ type statistic struct {
 
 // Statistics returns statistics for periodic monitoring.
 func (s *Service) Statistics(tags map[string]string) []models.Statistic {
-	return []models.Statistic{{
+	statistics := []models.Statistic{{
 		Name: "cq",
 		Tags: tags,
 		Values: map[string]interface{}{
-			statQueryOK:   atomic.LoadInt64(&s.stats.QueryOK),
-			statQueryFail: atomic.LoadInt64(&s.stats.QueryFail),
+			statQueryOK:         atomic.LoadInt64(&s.stats.QueryOK),
+			statQueryFail:       atomic.LoadInt64(&s.stats.QueryFail),
+			statIncrementalLate: atomic.LoadInt64(&s.stats.IncrementalLate),
 		},
 	}}
+
+	// Report the status of each query that has run.
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+	for id, st := range s.status {
+		parts := strings.SplitN(id, idDelimiter, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		statistics = append(statistics, models.Statistic{
+			Name: "cq_status",
+			Tags: models.StatisticTags{"database": parts[0], "cq": parts[1]}.Merge(tags),
+			Values: map[string]interface{}{
+				statLastRun:       st.LastRun.UnixNano(),
+				statDurationNs:    int64(st.Duration),
+				statLastSuccess:   st.LastSuccess.UnixNano(),
+				statPointsWritten: st.PointsWritten,
+				statLastError:     st.LastError,
+				statLastErrorTime: st.LastErrorTime.UnixNano(),
+			},
+		})
+	}
+	return statistics
 }
 
 // Run runs the specified continuous query, or all CQs if none is specified.
@@ -274,19 +437,61 @@ func (s *Service) runContinuousQueries(req *RunRequest) {
 			}
 		}
 	}
+	s.pruneStatus(dbs)
+	if err := s.saveStatus(); err != nil {
+		s.Logger.Info(fmt.Sprintf("unable to save continuous query status: %s", err))
+	}
+}
+
+// pruneStatus removes the status of continuous queries that no longer exist.
+func (s *Service) pruneStatus(dbs []meta.DatabaseInfo) {
+	ids := make(map[string]struct{})
+	for _, db := range dbs {
+		for _, cq := range db.ContinuousQueries {
+			ids[db.Name+idDelimiter+cq.Name] = struct{}{}
+		}
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	for id := range s.status {
+		if _, ok := ids[id]; !ok {
+			delete(s.status, id)
+			s.statusChanged = true
+		}
+	}
 }
 
 // ExecuteContinuousQuery may execute a single CQ. This will return false if there were no errors and the CQ was not run.
-func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.ContinuousQueryInfo, now time.Time) (bool, error) {
+func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.ContinuousQueryInfo, now time.Time) (ok bool, err error) {
 	// TODO: re-enable stats
 	//s.stats.Inc("continuousQueryExecuted")
 
+	// Record a failed run so it can be seen with SHOW CONTINUOUS QUERY STATUS.
+	start := time.Now()
+	defer func() {
+		if err == nil {
+			return
+		}
+		s.updateStatus(dbi.Name, cqi.Name, func(st *ContinuousQueryStatus) {
+			st.LastRun = start.UTC()
+			st.Duration = time.Since(start)
+			st.LastError = err.Error()
+			st.LastErrorTime = st.LastRun
+		})
+	}()
+
 	// Local wrapper / helper.
 	cq, err := NewContinuousQuery(dbi.Name, cqi)
 	if err != nil {
 		return false, err
 	}
 
+	// Incremental CQs are updated as points are written.
+	if cq.Incremental && s.PointsWriter != nil {
+		return false, nil
+	}
+
 	// Set the time zone on the now time if the CQ has one. Otherwise, force UTC.
 	now = now.UTC()
 	if cq.q.Location != nil {
@@ -333,7 +538,12 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 
 	// We're about to run the query so store the current time closest to the nearest interval.
 	// If all is going well, this time should be the same as nextRun.
-	cq.LastRun = truncate(now.Add(-offset), resampleEvery).Add(offset)
+	months := cq.q.GroupByMonths()
+	if months > 0 && cq.Resample.Every == 0 {
+		cq.LastRun = truncateMonths(now.Add(-offset), months).Add(offset)
+	} else {
+		cq.LastRun = truncate(now.Add(-offset), resampleEvery).Add(offset)
+	}
 	s.lastRuns[id] = cq.LastRun
 
 	// Retrieve the oldest interval we should calculate based on the next time
@@ -354,8 +564,13 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	}
 
 	// Calculate and set the time range for the query.
-	startTime := truncate(nextRun.Add(interval-resampleFor-offset-1), interval).Add(offset)
-	endTime := truncate(now.Add(interval-resampleEvery-offset), interval).Add(offset)
+	var startTime, endTime time.Time
+	if months > 0 {
+		startTime, endTime = cq.calendarTimeRange(now, nextRun, months, interval, offset)
+	} else {
+		startTime = truncate(nextRun.Add(interval-resampleFor-offset-1), interval).Add(offset)
+		endTime = truncate(now.Add(interval-resampleEvery-offset), interval).Add(offset)
+	}
 	if !endTime.After(startTime) {
 		// Exit early since there is no time interval.
 		return false, nil
@@ -366,15 +581,13 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 		return false, err
 	}
 
-	var start time.Time
-	if s.loggingEnabled || s.queryStatsEnabled {
-		start = time.Now()
-	}
-
 	if s.loggingEnabled {
 		s.Logger.Info(fmt.Sprintf("executing continuous query %s (%v to %v)", cq.Info.Name, startTime, endTime))
 	}
 
+	// Only the time spent executing the query is recorded.
+	start = time.Now()
+
 	// Do the actual processing of the query & writing of results.
 	res := s.runContinuousQueryAndWriteResult(cq)
 	if res.Err != nil {
@@ -382,17 +595,17 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 		return false, res.Err
 	}
 
-	var execDuration time.Duration
-	if s.loggingEnabled || s.queryStatsEnabled {
-		execDuration = time.Since(start)
-	}
+	execDuration := time.Since(start)
+	written := pointsWritten(res)
 
-	// extract number of points written from SELECT ... INTO result
-	var written int64 = -1
-	if len(res.Series) == 1 && len(res.Series[0].Values) == 1 {
-		s := res.Series[0]
-		written = s.Values[0][1].(int64)
-	}
+	s.updateStatus(dbi.Name, cqi.Name, func(st *ContinuousQueryStatus) {
+		st.LastRun = start.UTC()
+		st.Duration = execDuration
+		st.LastSuccess = st.LastRun
+		st.StartTime = startTime.UTC()
+		st.EndTime = endTime.UTC()
+		st.PointsWritten = written
+	})
 
 	if s.loggingEnabled {
 		s.Logger.Info(fmt.Sprintf("finished continuous query %s, %d points(s) written (%v to %v) in %s", cq.Info.Name, written, startTime, endTime, execDuration))
@@ -431,6 +644,234 @@ func (s *Service) runContinuousQueryAndWriteResult(cq *ContinuousQuery) *influxq
 	return res
 }
 
+// pointsWritten extracts the number of points written from the result of a
+// SELECT ... INTO statement. It returns -1 if the result has no count.
+func pointsWritten(res *influxql.Result) int64 {
+	if len(res.Series) == 1 && len(res.Series[0].Values) == 1 {
+		if n, ok := res.Series[0].Values[0][1].(int64); ok {
+			return n
+		}
+	}
+	return -1
+}
+
+// ExecuteStatement executes a BACKFILL CONTINUOUS QUERY statement.
+func (s *Service) ExecuteStatement(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+	switch stmt := stmt.(type) {
+	case *influxql.BackfillContinuousQueryStatement:
+		var messages []*influxql.Message
+		if ctx.ReadOnly {
+			messages = append(messages, influxql.ReadOnlyWarning(stmt.String()))
+		}
+
+		written, err := s.executeBackfillContinuousQueryStatement(stmt, ctx)
+		if err != nil {
+			return err
+		}
+		return ctx.Send(&influxql.Result{
+			StatementID: ctx.StatementID,
+			Series: models.Rows{{
+				Name:    "result",
+				Columns: []string{"time", "written"},
+				Values:  [][]interface{}{{time.Unix(0, 0).UTC(), written}},
+			}},
+			Messages: messages,
+		})
+	case *influxql.ShowContinuousQueryStatusStatement:
+		return ctx.Send(&influxql.Result{
+			StatementID: ctx.StatementID,
+			Series:      s.showContinuousQueryStatus(),
+		})
+	default:
+		return influxql.ErrInvalidQuery
+	}
+}
+
+// showContinuousQueryStatus returns a row for each database with the status
+// of its continuous queries.
+func (s *Service) showContinuousQueryStatus() models.Rows {
+	// Times that have not been recorded yet are shown as null.
+	formatTime := func(t time.Time) interface{} {
+		if t.IsZero() {
+			return nil
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	rows := []*models.Row{}
+	for _, di := range s.MetaClient.Databases() {
+		row := &models.Row{Columns: []string{"name", "last_run", "duration", "last_success", "start_time", "end_time", "points_written", "last_error", "last_error_time"}, Name: di.Name}
+		for _, cqi := range di.ContinuousQueries {
+			status := s.Status(di.Name, cqi.Name)
+
+			var duration, written, lastError interface{}
+			if !status.LastRun.IsZero() {
+				duration = status.Duration.String()
+			}
+			if !status.LastSuccess.IsZero() {
+				written = status.PointsWritten
+			}
+			if status.LastError != "" {
+				lastError = status.LastError
+			}
+
+			row.Values = append(row.Values, []interface{}{
+				cqi.Name,
+				formatTime(status.LastRun),
+				duration,
+				formatTime(status.LastSuccess),
+				formatTime(status.StartTime),
+				formatTime(status.EndTime),
+				written,
+				lastError,
+				formatTime(status.LastErrorTime),
+			})
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// executeBackfillContinuousQueryStatement runs a continuous query over a past
+// time range and returns the number of points written. The range is widened
+// to the GROUP BY intervals it overlaps and executed in chunks of whole
+// intervals so the progress of the backfill can be followed with SHOW QUERIES.
+func (s *Service) executeBackfillContinuousQueryStatement(stmt *influxql.BackfillContinuousQueryStatement, ctx influxql.ExecutionContext) (int64, error) {
+	dbi := s.MetaClient.Database(stmt.Database)
+	if dbi == nil {
+		return 0, influxql.ErrDatabaseNotFound(stmt.Database)
+	}
+
+	var cqi *meta.ContinuousQueryInfo
+	for i := range dbi.ContinuousQueries {
+		if dbi.ContinuousQueries[i].Name == stmt.Name {
+			cqi = &dbi.ContinuousQueries[i]
+			break
+		}
+	}
+	if cqi == nil {
+		return 0, meta.ErrContinuousQueryNotFound
+	}
+
+	cq, err := NewContinuousQuery(dbi.Name, cqi)
+	if err != nil {
+		return 0, err
+	}
+
+	// Set the retention policy to default if it wasn't specified in the query.
+	if cq.intoRP() == "" {
+		cq.setIntoRP(dbi.DefaultRetentionPolicy)
+	}
+
+	interval, err := cq.q.GroupByInterval()
+	if err != nil {
+		return 0, err
+	} else if interval == 0 {
+		return 0, errors.New("continuous query does not have a GROUP BY time interval")
+	}
+	offset, err := cq.q.GroupByOffset()
+	if err != nil {
+		return 0, err
+	}
+
+	loc := cq.q.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// Convert "now()" to the current time.
+	start, end, err := stmt.TimeRange(time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	// Align the time range and the chunks with the GROUP BY intervals.
+	months := cq.q.GroupByMonths()
+	var startTime, endTime time.Time
+	var next func(t time.Time) time.Time
+	if months > 0 {
+		startTime = truncateMonths(start.In(loc).Add(-offset), months).Add(offset)
+		endTime = truncateMonths(end.In(loc).Add(-offset-1), months).AddDate(0, months, 0).Add(offset)
+		next = func(t time.Time) time.Time { return t.AddDate(0, months, 0) }
+	} else {
+		startTime = truncate(start.In(loc).Add(-offset), interval).Add(offset)
+		endTime = truncate(end.In(loc).Add(-offset-1), interval).Add(interval + offset)
+
+		chunk := interval
+		if size := time.Duration(s.Config.BackfillChunkSize); size > interval {
+			chunk = size - size%interval
+		}
+		next = func(t time.Time) time.Time { return t.Add(chunk) }
+	}
+
+	// Refuse a backfill with too many chunks before any of them is run. The
+	// chunks are only counted up to the limit.
+	var chunkN int
+	for t := startTime; t.Before(endTime); t = next(t) {
+		if chunkN++; s.Config.MaxBackfillChunks > 0 && chunkN > s.Config.MaxBackfillChunks {
+			return 0, fmt.Errorf("backfill exceeds the maximum of %d chunks, increase backfill-chunk-size or shorten the time range", s.Config.MaxBackfillChunks)
+		}
+	}
+
+	if s.loggingEnabled {
+		s.Logger.Info(fmt.Sprintf("backfilling continuous query %s (%v to %v) in %d chunk(s)", cq.Info.Name, startTime, endTime, chunkN))
+	}
+
+	var written int64
+	var i int
+	for t := startTime; t.Before(endTime); t = next(t) {
+		// Stop before the next chunk if the query has been killed.
+		select {
+		case <-ctx.InterruptCh:
+			return written, influxql.ErrQueryInterrupted
+		case <-ctx.AbortCh:
+			return written, influxql.ErrQueryAborted
+		default:
+		}
+
+		end := next(t)
+		if end.After(endTime) {
+			end = endTime
+		}
+
+		if err := cq.q.SetTimeRange(t, end); err != nil {
+			return written, err
+		}
+
+		res := s.runContinuousQueryAndWriteResult(cq)
+		if res.Err != nil {
+			s.Logger.Info(fmt.Sprintf("error: %s. running: %s\n", res.Err, cq.q.String()))
+			return written, res.Err
+		}
+		if n := pointsWritten(res); n > 0 {
+			written += n
+		}
+
+		i++
+		ctx.Query.SetStatus(fmt.Sprintf("backfilled %d/%d chunks up to %s", i, chunkN, end.UTC().Format(time.RFC3339Nano)))
+
+		// Wait between chunks so the backfill does not starve other queries.
+		throttle := time.Duration(s.Config.BackfillThrottle)
+		if i < chunkN && throttle > 0 {
+			timer := time.NewTimer(throttle)
+			select {
+			case <-ctx.InterruptCh:
+				timer.Stop()
+				return written, influxql.ErrQueryInterrupted
+			case <-ctx.AbortCh:
+				timer.Stop()
+				return written, influxql.ErrQueryAborted
+			case <-timer.C:
+			}
+		}
+	}
+
+	if s.loggingEnabled {
+		s.Logger.Info(fmt.Sprintf("finished backfilling continuous query %s, %d point(s) written (%v to %v)", cq.Info.Name, written, startTime, endTime))
+	}
+	return written, nil
+}
+
 // ContinuousQuery is a local wrapper / helper around continuous queries.
 type ContinuousQuery struct {
 	Database string
@@ -439,6 +880,10 @@ type ContinuousQuery struct {
 	LastRun  time.Time
 	Resample ResampleOptions
 	q        *influxql.SelectStatement
+
+	// Incremental is set if the results are updated from the points as they
+	// are written.
+	Incremental bool
 }
 
 func (cq *ContinuousQuery) intoRP() string      { return cq.q.Target.Measurement.RetentionPolicy }
@@ -478,7 +923,8 @@ func NewContinuousQuery(database string, cqi *meta.ContinuousQueryInfo) (*Contin
 			Every: q.ResampleEvery,
 			For:   q.ResampleFor,
 		},
-		q: q.Source,
+		q:           q.Source,
+		Incremental: q.Incremental,
 	}
 
 	return cquery, nil
@@ -502,6 +948,16 @@ func (cq *ContinuousQuery) shouldRunContinuousQuery(now time.Time, interval time
 	// Determine if we should run the continuous query based on the last time it ran.
 	// If the query never ran, execute it using the current time.
 	if cq.HasRun {
+		// Calendar intervals vary in length so the next run is at the start
+		// of the next calendar interval.
+		if months := cq.q.GroupByMonths(); months > 0 && cq.Resample.Every == 0 {
+			nextRun := cq.LastRun.AddDate(0, months, 0)
+			if nextRun.UnixNano() <= now.UnixNano() {
+				return true, nextRun, nil
+			}
+			return false, cq.LastRun, nil
+		}
+
 		// Retrieve the zone offset for the previous window.
 		_, startOffset := cq.LastRun.Add(-1).Zone()
 		nextRun := cq.LastRun.Add(resampleEvery)
@@ -527,6 +983,25 @@ func (cq *ContinuousQuery) shouldRunContinuousQuery(now time.Time, interval time
 	return false, cq.LastRun, nil
 }
 
+// calendarTimeRange returns the time range of a continuous query grouped by a
+// calendar interval. The range covers the calendar intervals that overlap the
+// resample duration before the next run and ends at the start of the current
+// interval. The current interval is also included when the query is
+// resampled more often than the interval.
+func (cq *ContinuousQuery) calendarTimeRange(now, nextRun time.Time, months int, interval, offset time.Duration) (startTime, endTime time.Time) {
+	resampleFor := cq.Resample.For
+	if resampleFor == 0 && cq.Resample.Every > interval {
+		resampleFor = cq.Resample.Every
+	}
+	startTime = truncateMonths(nextRun.Add(-resampleFor-offset-1), months)
+
+	endTime = truncateMonths(now.Add(-offset), months)
+	if cq.Resample.Every != 0 && cq.Resample.Every < interval {
+		endTime = endTime.AddDate(0, months, 0)
+	}
+	return startTime.Add(offset), endTime.Add(offset)
+}
+
 // assert will panic with a given formatted message if the given condition is false.
 func assert(condition bool, msg string, v ...interface{}) {
 	if !condition {
@@ -556,6 +1031,14 @@ func truncate(ts time.Time, d time.Duration) time.Time {
 	return ts
 }
 
+// truncateMonths truncates the time to the start of the calendar interval of
+// the given number of months in the location of the time.
+func truncateMonths(ts time.Time, months int) time.Time {
+	n := ts.Year()*12 + int(ts.Month()) - 1
+	n -= n % months
+	return time.Date(n/12, time.Month(n%12+1), 1, 0, 0, 0, 0, ts.Location())
+}
+
 func zone(ts time.Time) int64 {
 	_, offset := ts.Zone()
 	return int64(offset) * int64(time.Second)
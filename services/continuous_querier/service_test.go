@@ -3,7 +3,10 @@ package continuous_querier
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -11,6 +14,7 @@ import (
 	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/models"
 	"github.com/influxdata/influxdb/services/meta"
+	"github.com/influxdata/influxdb/toml"
 	"github.com/uber-go/zap"
 )
 
@@ -528,6 +532,37 @@ func TestExecuteContinuousQuery_TimeZone(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "Calendar/1mo",
+			d:       "1mo",
+			initial: mustParseTime(t, "2000-03-01T00:00:00-05:00"),
+			tests: []test{
+				{
+					start: mustParseTime(t, "2000-03-01T00:00:00-05:00"),
+					end:   mustParseTime(t, "2000-04-01T00:00:00-05:00"),
+				},
+				{
+					start: mustParseTime(t, "2000-04-01T00:00:00-05:00"),
+					end:   mustParseTime(t, "2000-05-01T00:00:00-04:00"),
+				},
+			},
+		},
+		{
+			name:    "Calendar/1q",
+			d:       "1q",
+			initial: mustParseTime(t, "2000-01-01T00:00:00-05:00"),
+			tests: []test{
+				{
+					start: mustParseTime(t, "2000-01-01T00:00:00-05:00"),
+					end:   mustParseTime(t, "2000-04-01T00:00:00-05:00"),
+				},
+				{
+					now:   mustParseTime(t, "2000-07-15T00:00:00-04:00"),
+					start: mustParseTime(t, "2000-04-01T00:00:00-05:00"),
+					end:   mustParseTime(t, "2000-07-01T00:00:00-04:00"),
+				},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			s := NewTestService(t)
@@ -649,6 +684,140 @@ func TestService_ExecuteContinuousQuery_LogsToMonitor(t *testing.T) {
 	}
 }
 
+func TestService_ExecuteContinuousQuery_Status(t *testing.T) {
+	s := NewTestService(t)
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+			ctx.Results <- &influxql.Result{
+				Series: []*models.Row{{
+					Name:    "result",
+					Columns: []string{"time", "written"},
+					Values:  [][]interface{}{{time.Time{}, int64(50)}},
+				}},
+			}
+			return nil
+		},
+	}
+
+	dbi := *s.MetaClient.Database("db2")
+	cqi := dbi.ContinuousQueries[0]
+	now := mustParseTime(t, "2000-01-01T00:10:00Z")
+	if ok, err := s.ExecuteContinuousQuery(&dbi, &cqi, now); !ok || err != nil {
+		t.Fatalf("ExecuteContinuousQuery failed, ok=%t, err=%v", ok, err)
+	}
+
+	status := s.Status("db2", cqi.Name)
+	if status.LastRun.IsZero() || !status.LastSuccess.Equal(status.LastRun) {
+		t.Errorf("unexpected last run: %s, last success: %s", status.LastRun, status.LastSuccess)
+	} else if exp := mustParseTime(t, "2000-01-01T00:09:00Z"); !status.StartTime.Equal(exp) {
+		t.Errorf("unexpected start time: got=%s exp=%s", status.StartTime, exp)
+	} else if exp := mustParseTime(t, "2000-01-01T00:10:00Z"); !status.EndTime.Equal(exp) {
+		t.Errorf("unexpected end time: got=%s exp=%s", status.EndTime, exp)
+	} else if status.PointsWritten != 50 {
+		t.Errorf("unexpected points written: %d", status.PointsWritten)
+	} else if status.LastError != "" {
+		t.Errorf("unexpected error: %s", status.LastError)
+	}
+
+	// A failed run records the error and keeps the last successful run.
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+			return errExpected
+		},
+	}
+	if _, err := s.ExecuteContinuousQuery(&dbi, &cqi, now.Add(time.Minute)); err != errExpected {
+		t.Fatalf("exp = %s, got = %v", errExpected, err)
+	}
+
+	failed := s.Status("db2", cqi.Name)
+	if failed.LastError != errExpected.Error() || !failed.LastErrorTime.Equal(failed.LastRun) {
+		t.Errorf("unexpected error: %s at %s", failed.LastError, failed.LastErrorTime)
+	} else if !failed.LastSuccess.Equal(status.LastSuccess) || failed.PointsWritten != status.PointsWritten {
+		t.Errorf("unexpected last success: %s, points written: %d", failed.LastSuccess, failed.PointsWritten)
+	}
+}
+
+func TestService_ExecuteStatement_ShowContinuousQueryStatus(t *testing.T) {
+	s := NewTestService(t)
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db0", "rp")
+	mc.CreateContinuousQuery("db0", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(value) INTO cpu_count FROM cpu GROUP BY time(10m) END`)
+	mc.CreateContinuousQuery("db0", "cq1", `CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`)
+	s.MetaClient = mc
+
+	s.updateStatus("db0", "cq1", func(st *ContinuousQueryStatus) {
+		*st = ContinuousQueryStatus{
+			LastRun:       time.Unix(0, 0).Add(20 * time.Minute),
+			Duration:      2 * time.Second,
+			LastSuccess:   time.Unix(0, 0).Add(10 * time.Minute),
+			StartTime:     time.Unix(0, 0),
+			EndTime:       time.Unix(0, 0).Add(10 * time.Minute),
+			PointsWritten: 5,
+			LastError:     "timeout",
+			LastErrorTime: time.Unix(0, 0).Add(20 * time.Minute),
+		}
+	})
+
+	results := make(chan *influxql.Result, 1)
+	if err := s.ExecuteStatement(&influxql.ShowContinuousQueryStatusStatement{}, influxql.ExecutionContext{Results: results}); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := models.Rows{{
+		Name:    "db0",
+		Columns: []string{"name", "last_run", "duration", "last_success", "start_time", "end_time", "points_written", "last_error", "last_error_time"},
+		Values: [][]interface{}{
+			{"cq0", nil, nil, nil, nil, nil, nil, nil, nil},
+			{"cq1", "1970-01-01T00:20:00Z", "2s", "1970-01-01T00:10:00Z", "1970-01-01T00:00:00Z", "1970-01-01T00:10:00Z", int64(5), "timeout", "1970-01-01T00:20:00Z"},
+		},
+	}}
+	if res := <-results; !reflect.DeepEqual(res.Series, exp) {
+		t.Fatalf("unexpected rows: exp %v, got %v", exp, res.Series)
+	}
+
+	// Dropped queries are no longer reported.
+	s.pruneStatus([]meta.DatabaseInfo{{Name: "db0", ContinuousQueries: []meta.ContinuousQueryInfo{{Name: "cq0"}}}})
+	if st := s.Status("db0", "cq1"); !st.LastRun.IsZero() {
+		t.Fatalf("unexpected status for dropped query: %+v", st)
+	}
+}
+
+func TestService_Status_Persisted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cq_status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewTestService(t)
+	s.StatusPath = filepath.Join(dir, "cq_status.json")
+	exp := ContinuousQueryStatus{
+		LastRun:       time.Unix(0, 0).Add(20 * time.Minute).UTC(),
+		Duration:      2 * time.Second,
+		LastSuccess:   time.Unix(0, 0).Add(10 * time.Minute).UTC(),
+		StartTime:     time.Unix(0, 0).UTC(),
+		EndTime:       time.Unix(0, 0).Add(10 * time.Minute).UTC(),
+		PointsWritten: 5,
+	}
+	s.updateStatus("db", "cq", func(st *ContinuousQueryStatus) { *st = exp })
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	} else if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The status is read again when the service is restarted.
+	other := NewTestService(t)
+	other.StatusPath = s.StatusPath
+	if err := other.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if st := other.Status("db", "cq"); !reflect.DeepEqual(st, exp) {
+		t.Fatalf("unexpected status: exp %+v, got %+v", exp, st)
+	}
+}
+
 func TestService_ExecuteContinuousQuery_LogToMonitor_DisabledByDefault(t *testing.T) {
 	s := NewTestService(t)
 	s.QueryExecutor.StatementExecutor = &StatementExecutor{
@@ -675,6 +844,436 @@ func TestService_ExecuteContinuousQuery_LogToMonitor_DisabledByDefault(t *testin
 	}
 }
 
+func TestService_ExecuteStatement_Backfill(t *testing.T) {
+	s := NewTestService(t)
+	s.Config.BackfillChunkSize = toml.Duration(30 * time.Minute)
+	s.Config.BackfillThrottle = 0
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db", "rp")
+	mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`)
+	s.MetaClient = mc
+
+	var ranges [][2]time.Time
+	var status string
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+			switch stmt := stmt.(type) {
+			case *influxql.BackfillContinuousQueryStatement:
+				err := s.ExecuteStatement(stmt, ctx)
+				status = ctx.Query.Status()
+				return err
+			case *influxql.SelectStatement:
+				if got, exp := stmt.Target.Measurement.RetentionPolicy, "rp"; got != exp {
+					t.Errorf("unexpected retention policy: got=%s exp=%s", got, exp)
+				}
+				min, max, err := influxql.TimeRange(stmt.Condition, stmt.Location)
+				if err != nil {
+					return err
+				}
+				ranges = append(ranges, [2]time.Time{min, max})
+				ctx.Results <- &influxql.Result{
+					Series: []*models.Row{{
+						Name:    "result",
+						Columns: []string{"time", "written"},
+						Values:  [][]interface{}{{time.Time{}, int64(5)}},
+					}},
+				}
+				return nil
+			}
+			t.Errorf("unexpected statement: %s", stmt)
+			return errUnexpected
+		},
+	}
+
+	q, err := influxql.ParseQuery(`BACKFILL CONTINUOUS QUERY cq ON db FROM '2000-01-01T00:05:00Z' TO '2000-01-01T01:35:00Z'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := s.QueryExecutor.ExecuteQuery(q, influxql.ExecutionOptions{}, nil)
+	res := <-results
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	} else if len(res.Series) != 1 || res.Series[0].Values[0][1] != int64(20) {
+		t.Fatalf("unexpected result: %v", res.Series)
+	}
+
+	// Wait for the query to finish so the status has been read.
+	for range results {
+	}
+
+	exp := [][2]time.Time{
+		{mustParseTime(t, "2000-01-01T00:00:00Z"), mustParseTime(t, "2000-01-01T00:30:00Z").Add(-1)},
+		{mustParseTime(t, "2000-01-01T00:30:00Z"), mustParseTime(t, "2000-01-01T01:00:00Z").Add(-1)},
+		{mustParseTime(t, "2000-01-01T01:00:00Z"), mustParseTime(t, "2000-01-01T01:30:00Z").Add(-1)},
+		{mustParseTime(t, "2000-01-01T01:30:00Z"), mustParseTime(t, "2000-01-01T01:40:00Z").Add(-1)},
+	}
+	if len(ranges) != len(exp) {
+		t.Fatalf("unexpected number of queries: got=%d exp=%d", len(ranges), len(exp))
+	}
+	for i := range exp {
+		if !ranges[i][0].Equal(exp[i][0]) || !ranges[i][1].Equal(exp[i][1]) {
+			t.Errorf("%d. mismatched time range: got=(%s, %s) exp=(%s, %s)", i, ranges[i][0], ranges[i][1], exp[i][0], exp[i][1])
+		}
+	}
+
+	if exp := "backfilled 4/4 chunks up to 2000-01-01T01:40:00Z"; status != exp {
+		t.Errorf("unexpected status: got=%q exp=%q", status, exp)
+	}
+}
+
+func TestService_ExecuteStatement_Backfill_Aborted(t *testing.T) {
+	s := NewTestService(t)
+	s.Config.BackfillChunkSize = toml.Duration(30 * time.Minute)
+	s.Config.BackfillThrottle = 0
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db", "rp")
+	mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`)
+	s.MetaClient = mc
+
+	abort := make(chan struct{})
+	var n int
+	var backfillErr error
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+			switch stmt := stmt.(type) {
+			case *influxql.BackfillContinuousQueryStatement:
+				backfillErr = s.ExecuteStatement(stmt, ctx)
+				return backfillErr
+			case *influxql.SelectStatement:
+				// Abort the backfill while the first chunk is running.
+				if n++; n == 1 {
+					close(abort)
+				}
+				ctx.Results <- &influxql.Result{}
+				return nil
+			}
+			t.Errorf("unexpected statement: %s", stmt)
+			return errUnexpected
+		},
+	}
+
+	q, err := influxql.ParseQuery(`BACKFILL CONTINUOUS QUERY cq ON db FROM '2000-01-01T00:00:00Z' TO '2000-01-01T02:00:00Z'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range s.QueryExecutor.ExecuteQuery(q, influxql.ExecutionOptions{AbortCh: abort}, nil) {
+	}
+
+	if backfillErr != influxql.ErrQueryAborted {
+		t.Errorf("unexpected error: %v", backfillErr)
+	} else if n != 1 {
+		t.Errorf("unexpected number of queries: %d", n)
+	}
+}
+
+func TestService_ExecuteStatement_Backfill_Now(t *testing.T) {
+	s := NewTestService(t)
+	s.Config.BackfillChunkSize = toml.Duration(30 * time.Minute)
+	s.Config.BackfillThrottle = 0
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db", "rp")
+	mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`)
+	s.MetaClient = mc
+
+	var ranges [][2]time.Time
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+			switch stmt := stmt.(type) {
+			case *influxql.BackfillContinuousQueryStatement:
+				return s.ExecuteStatement(stmt, ctx)
+			case *influxql.SelectStatement:
+				min, max, err := influxql.TimeRange(stmt.Condition, stmt.Location)
+				if err != nil {
+					return err
+				}
+				ranges = append(ranges, [2]time.Time{min, max})
+				ctx.Results <- &influxql.Result{}
+				return nil
+			}
+			t.Errorf("unexpected statement: %s", stmt)
+			return errUnexpected
+		},
+	}
+
+	q, err := influxql.ParseQuery(`BACKFILL CONTINUOUS QUERY cq ON db FROM now() - 1h TO now()`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for res := range s.QueryExecutor.ExecuteQuery(q, influxql.ExecutionOptions{}, nil) {
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	}
+
+	// The hour before now is widened to the intervals it overlaps.
+	if len(ranges) == 0 {
+		t.Fatal("expected the backfill to run queries")
+	} else if start := ranges[0][0]; start.After(now.Add(-time.Hour)) || start.Before(now.Add(-time.Hour-10*time.Minute)) {
+		t.Errorf("unexpected start time: %s", start)
+	} else if end := ranges[len(ranges)-1][1]; end.Before(now) || end.After(now.Add(10*time.Minute)) {
+		t.Errorf("unexpected end time: %s", end)
+	}
+}
+
+func TestService_ExecuteStatement_Backfill_MaxChunks(t *testing.T) {
+	s := NewTestService(t)
+	s.Config.BackfillChunkSize = toml.Duration(30 * time.Minute)
+	s.Config.MaxBackfillChunks = 3
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db", "rp")
+	mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`)
+	s.MetaClient = mc
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+			t.Errorf("unexpected statement: %s", stmt)
+			return errUnexpected
+		},
+	}
+
+	stmt := &influxql.BackfillContinuousQueryStatement{
+		Name:      "cq",
+		Database:  "db",
+		StartTime: &influxql.TimeLiteral{Val: mustParseTime(t, "2000-01-01T00:00:00Z")},
+		EndTime:   &influxql.TimeLiteral{Val: mustParseTime(t, "2000-01-01T02:00:00Z")},
+	}
+	if err := s.ExecuteStatement(stmt, influxql.ExecutionContext{}); err == nil || err.Error() != "backfill exceeds the maximum of 3 chunks, increase backfill-chunk-size or shorten the time range" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestService_ExecuteStatement_Backfill_NotFound(t *testing.T) {
+	s := NewTestService(t)
+	stmt := &influxql.BackfillContinuousQueryStatement{
+		Name:      "missing",
+		Database:  "db",
+		StartTime: &influxql.TimeLiteral{Val: mustParseTime(t, "2000-01-01T00:00:00Z")},
+		EndTime:   &influxql.TimeLiteral{Val: mustParseTime(t, "2000-01-02T00:00:00Z")},
+	}
+	if err := s.ExecuteStatement(stmt, influxql.ExecutionContext{}); err != meta.ErrContinuousQueryNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestService_IncrementalView(t *testing.T) {
+	s := NewTestService(t)
+	s.MetaClient.(*MetaClient).CreateContinuousQuery("db", "inc", `CREATE CONTINUOUS QUERY inc ON db RESAMPLE FOR 2m INCREMENTAL BEGIN SELECT count(value), sum(value), mean(value), min(value), max(value), last(value) INTO cpu_1m FROM cpu GROUP BY time(1m), host END`)
+	dbi := *s.MetaClient.Database("db")
+	cqi := dbi.ContinuousQueries[1]
+
+	// The intervals that are open when the view is created are recomputed.
+	v, err := newIncrementalView(&dbi, &cqi, mustParseTime(t, "1999-12-31T23:59:30Z"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := models.ParsePointsString(`cpu,host=a value=1i 946684800000000000
+cpu,host=a value=3i 946684805000000000
+cpu,host=a value=5i 946684810000000000
+cpu,host=b value=2i 946684860000000000
+mem,host=a value=100i 946684800000000000`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := mustParseTime(t, "2000-01-01T00:02:00Z")
+	if late := v.writePoints("other", points, false, now); late != 0 {
+		t.Fatalf("unexpected late points: %d", late)
+	} else if late := v.writePoints("rp", points, false, now); late != 0 {
+		t.Fatalf("unexpected late points: %d", late)
+	}
+
+	flushed, recompute, err := v.flush(now)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := []time.Time{mustParseTime(t, "1999-12-31T23:57:00Z"), mustParseTime(t, "1999-12-31T23:58:00Z"), mustParseTime(t, "1999-12-31T23:59:00Z")}; !reflect.DeepEqual(recompute, exp) {
+		t.Fatalf("unexpected recomputed intervals: got=%v exp=%v", recompute, exp)
+	}
+	got := make(map[string]string)
+	for _, p := range flushed {
+		got[p.Time().UTC().Format(time.RFC3339)] = p.String()
+	}
+	if exp := map[string]string{
+		"2000-01-01T00:00:00Z": "cpu_1m,host=a count=3i,last=5i,max=5i,mean=3,min=1i,sum=9i 946684800000000000",
+		"2000-01-01T00:01:00Z": "cpu_1m,host=b count=1i,last=2i,max=2i,mean=2,min=2i,sum=2i 946684860000000000",
+	}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected points:\n\ngot=%v\n\nexp=%v", got, exp)
+	}
+
+	// Only the intervals that changed are flushed again.
+	if flushed, recompute, err := v.flush(now); err != nil {
+		t.Fatal(err)
+	} else if len(flushed) != 0 || len(recompute) != 0 {
+		t.Fatalf("unexpected flush: %v %v", flushed, recompute)
+	}
+
+	// The first interval is final after the resample duration and later
+	// points for it are ignored.
+	points, _ = models.ParsePointsString(`cpu,host=a value=1.5 946684800000000000
+cpu,host=b value=4.5 946684870000000000`)
+	now = mustParseTime(t, "2000-01-01T00:03:00Z")
+	if late := v.writePoints("rp", points, false, now); late != 1 {
+		t.Fatalf("unexpected late points: %d", late)
+	}
+	if flushed, _, err := v.flush(now); err != nil {
+		t.Fatal(err)
+	} else if len(flushed) != 1 {
+		t.Fatalf("unexpected points: %v", flushed)
+	} else if got, exp := flushed[0].String(), "cpu_1m,host=b count=2i,last=4.5,max=4.5,mean=3.25,min=2i,sum=6.5 946684860000000000"; got != exp {
+		t.Fatalf("unexpected point:\n\ngot=%s\n\nexp=%s", got, exp)
+	}
+
+	// A point that may overwrite an aggregated point causes the interval to
+	// be recomputed, as does a partial write.
+	points, _ = models.ParsePointsString(`cpu,host=b value=9 946684870000000000`)
+	if late := v.writePoints("rp", points, false, now); late != 0 {
+		t.Fatalf("unexpected late points: %d", late)
+	}
+	points, _ = models.ParsePointsString(`cpu,host=a value=1 946684920000000000`)
+	if late := v.writePoints("rp", points, true, now); late != 0 {
+		t.Fatalf("unexpected late points: %d", late)
+	}
+	if flushed, recompute, err := v.flush(now); err != nil {
+		t.Fatal(err)
+	} else if len(flushed) != 0 {
+		t.Fatalf("unexpected points: %v", flushed)
+	} else if exp := []time.Time{mustParseTime(t, "2000-01-01T00:01:00Z"), mustParseTime(t, "2000-01-01T00:02:00Z")}; !reflect.DeepEqual(recompute, exp) {
+		t.Fatalf("unexpected recomputed intervals: got=%v exp=%v", recompute, exp)
+	}
+
+	// Final intervals are removed from the view once their results have
+	// been written.
+	if _, _, err := v.flush(mustParseTime(t, "2000-01-01T00:05:00Z")); err != nil {
+		t.Fatal(err)
+	} else if len(v.intervals) == 0 {
+		t.Fatal("expected final intervals to be kept until they are written")
+	}
+	v.removeFinal()
+	if len(v.intervals) != 0 {
+		t.Fatalf("unexpected intervals: %d", len(v.intervals))
+	}
+}
+
+func TestService_Incremental(t *testing.T) {
+	s := NewTestService(t)
+	s.MetaClient.(*MetaClient).CreateContinuousQuery("db", "inc", `CREATE CONTINUOUS QUERY inc ON db INCREMENTAL BEGIN SELECT count(value) INTO cpu_count FROM cpu GROUP BY time(1m) END`)
+
+	var written []models.Point
+	s.PointsWriter = &PointsWriter{
+		WritePointsPrivilegedFn: func(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
+			if database != "db" || retentionPolicy != "" {
+				t.Errorf("unexpected target: %s.%s", database, retentionPolicy)
+			}
+			written = append(written, points...)
+			return nil
+		},
+	}
+
+	var ranges [][2]time.Time
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx influxql.ExecutionContext) error {
+			s, ok := stmt.(*influxql.SelectStatement)
+			if !ok {
+				t.Errorf("unexpected statement: %s", stmt)
+				return errUnexpected
+			} else if got, exp := s.Target.Measurement.RetentionPolicy, "rp"; got != exp {
+				t.Errorf("unexpected retention policy: got=%s exp=%s", got, exp)
+			}
+			min, max, err := influxql.TimeRange(s.Condition, s.Location)
+			if err != nil {
+				return err
+			}
+			ranges = append(ranges, [2]time.Time{min, max})
+			ctx.Results <- &influxql.Result{}
+			return nil
+		},
+	}
+
+	// Incremental CQs are not run by the scheduler.
+	s.updateViews()
+	dbi := *s.MetaClient.Database("db")
+	cqi := dbi.ContinuousQueries[1]
+	if ok, err := s.ExecuteContinuousQuery(&dbi, &cqi, time.Now()); ok || err != nil {
+		t.Fatalf("unexpected run: ok=%t, err=%v", ok, err)
+	}
+
+	// Replace the view with one created at a known time.
+	id := "db" + idDelimiter + cqi.Name
+	if s.views[id] == nil {
+		t.Fatal("expected view")
+	}
+	now := mustParseTime(t, "2000-01-01T00:00:30Z")
+	v, err := newIncrementalView(&dbi, &cqi, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.views[id] = v
+
+	// The open intervals are recomputed with a query.
+	s.flushIncremental(now)
+	if exp := [][2]time.Time{{mustParseTime(t, "1999-12-31T23:59:00Z"), mustParseTime(t, "2000-01-01T00:00:59.999999999Z")}}; !reflect.DeepEqual(ranges, exp) {
+		t.Fatalf("unexpected ranges: got=%v exp=%v", ranges, exp)
+	}
+
+	// Later intervals are aggregated from the written points.
+	now = mustParseTime(t, "2000-01-01T00:01:30Z")
+	s.writeIncrementalPoints("db", "rp", []models.Point{
+		models.MustNewPoint("cpu", nil, models.Fields{"value": 1.0}, now.Add(-20*time.Second)),
+		models.MustNewPoint("cpu", nil, models.Fields{"value": 2.0}, now.Add(-10*time.Second)),
+	}, false, now)
+	s.flushIncremental(now)
+
+	if len(written) != 1 {
+		t.Fatalf("unexpected points: %v", written)
+	} else if fields, _ := written[0].Fields(); fields["count"] != int64(2) {
+		t.Fatalf("unexpected fields: %v", fields)
+	} else if string(written[0].Name()) != "cpu_count" {
+		t.Fatalf("unexpected measurement: %s", written[0].Name())
+	}
+
+	// A failed write is recorded in the status of the CQ and the interval
+	// is recomputed at the next flush.
+	s.PointsWriter = &PointsWriter{
+		WritePointsPrivilegedFn: func(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
+			return errExpected
+		},
+	}
+	s.writeIncrementalPoints("db", "rp", []models.Point{models.MustNewPoint("cpu", nil, models.Fields{"value": 3.0}, now)}, false, now)
+	s.flushIncremental(now)
+
+	if status := s.Status("db", cqi.Name); status.LastError != errExpected.Error() {
+		t.Fatalf("unexpected error: %s", status.LastError)
+	}
+
+	ranges = nil
+	s.flushIncremental(now)
+	if exp := [][2]time.Time{{mustParseTime(t, "2000-01-01T00:01:00Z"), mustParseTime(t, "2000-01-01T00:01:59.999999999Z")}}; !reflect.DeepEqual(ranges, exp) {
+		t.Fatalf("unexpected ranges: got=%v exp=%v", ranges, exp)
+	}
+
+	// A final interval is recomputed if its results could not be written
+	// and is only removed from the view once they have been written.
+	writer := s.PointsWriter
+	s.PointsWriter = &PointsWriter{
+		WritePointsPrivilegedFn: func(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
+			return errExpected
+		},
+	}
+	now = mustParseTime(t, "2000-01-01T00:02:30Z")
+	s.writeIncrementalPoints("db", "rp", []models.Point{models.MustNewPoint("cpu", nil, models.Fields{"value": 4.0}, now.Add(-20*time.Second))}, false, now)
+	now = mustParseTime(t, "2000-01-01T00:04:00Z")
+	s.flushIncremental(now)
+
+	s.PointsWriter = writer
+	ranges = nil
+	s.flushIncremental(now)
+	if exp := [][2]time.Time{{mustParseTime(t, "2000-01-01T00:02:00Z"), mustParseTime(t, "2000-01-01T00:02:59.999999999Z")}}; !reflect.DeepEqual(ranges, exp) {
+		t.Fatalf("unexpected ranges: got=%v exp=%v", ranges, exp)
+	} else if len(v.intervals) != 0 {
+		t.Fatalf("unexpected intervals: %d", len(v.intervals))
+	}
+}
+
 // NewTestService returns a new *Service with default mock object members.
 func NewTestService(t *testing.T) *Service {
 	s := NewService(NewConfig())
@@ -816,6 +1415,15 @@ func (ms *MetaClient) CreateContinuousQuery(database, name, query string) error
 	return nil
 }
 
+// PointsWriter is a mock points writer.
+type PointsWriter struct {
+	WritePointsPrivilegedFn func(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error
+}
+
+func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
+	return w.WritePointsPrivilegedFn(database, retentionPolicy, consistencyLevel, points)
+}
+
 // StatementExecutor is a mock statement executor.
 type StatementExecutor struct {
 	ExecuteStatementFn func(stmt influxql.Statement, ctx influxql.ExecutionContext) error
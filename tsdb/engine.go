@@ -43,11 +43,14 @@ type Engine interface {
 	Import(r io.Reader, basePath string) error
 
 	CreateIterator(measurement string, opt influxql.IteratorOptions) (influxql.Iterator, error)
+	IteratorCost(measurement string, opt influxql.IteratorOptions) (influxql.IteratorCost, error)
 	WritePoints(points []models.Point) error
 
 	CreateSeriesIfNotExists(key, name []byte, tags models.Tags) error
 	CreateSeriesListIfNotExists(keys, names [][]byte, tags []models.Tags) error
 	DeleteSeriesRange(keys [][]byte, min, max int64) error
+	DeleteFieldRange(name []byte, field string, keys [][]byte, min, max int64) error
+	RenameSeries(name []byte, condition influxql.Expr, fn func(name []byte, tags models.Tags) ([]byte, models.Tags)) error
 
 	SeriesSketches() (estimator.Sketch, estimator.Sketch, error)
 	MeasurementsSketches() (estimator.Sketch, estimator.Sketch, error)
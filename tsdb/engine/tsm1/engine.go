@@ -912,6 +912,229 @@ func (e *Engine) containsSeries(keys [][]byte) (map[string]bool, error) {
 	return keyMap, nil
 }
 
+// RenameSeries rewrites the series of a measurement matching condition under
+// the name and tags returned by fn. The values of the series are copied one
+// block at a time to new TSM files under the new series keys, like a
+// snapshot, and the original series are deleted. The fields of the
+// measurement are removed if none of its series are left. Values written to
+// the original series while they are renamed may be lost, so the caller must
+// block writes.
+func (e *Engine) RenameSeries(name []byte, condition influxql.Expr, fn func(name []byte, tags models.Tags) ([]byte, models.Tags)) error {
+	mf := e.fieldset.Fields(string(name))
+	if mf == nil {
+		return nil
+	}
+	fields := mf.FieldSet()
+
+	keys, err := e.index.MeasurementSeriesKeysByExpr(name, condition)
+	if err != nil {
+		return err
+	}
+
+	// The index may hold series of other shards.
+	existing, err := e.containsSeries(keys)
+	if err != nil {
+		return err
+	}
+
+	// Determine the new key of each series.
+	var oldKeys, newKeys, newNames [][]byte
+	var newTags []models.Tags
+	for _, key := range keys {
+		if !existing[string(key)] {
+			continue
+		}
+
+		_, tags := models.ParseKey(key)
+		newName, tags := fn(name, tags.Clone())
+		newKey := models.MakeKey(newName, tags)
+		if bytes.Equal(newKey, key) {
+			continue
+		}
+		oldKeys = append(oldKeys, key)
+		newKeys = append(newKeys, newKey)
+		newNames = append(newNames, newName)
+		newTags = append(newTags, tags)
+	}
+	if len(oldKeys) == 0 {
+		return nil
+	}
+
+	// Create the fields of the new measurements before anything is written
+	// so conflicting field types are found first.
+	for _, newName := range newNames {
+		nmf := e.fieldset.CreateFieldsIfNotExists(newName)
+		for field, typ := range fields {
+			if err := nmf.CreateFieldIfNotExists([]byte(field), typ, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	// A renamed series may be merged into an existing series. The values of
+	// the existing series are moved out of the cache first so the copied
+	// values, which are written to newer TSM files, always replace the
+	// values with the same timestamps.
+	merged, err := e.containsSeries(newKeys)
+	if err != nil {
+		return err
+	}
+	for _, exists := range merged {
+		if exists {
+			if err := e.WriteSnapshot(); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	if err := e.CreateSeriesListIfNotExists(newKeys, newNames, newTags); err != nil {
+		return err
+	}
+
+	// Disable level compactions so the TSM files being read are not replaced.
+	e.disableLevelCompactions(true)
+	defer e.enableLevelCompactions(true)
+
+	// Copy the values of each series to the new key. The values are written
+	// to new TSM files whenever the cache holding them gets too big, so a
+	// series does not have to fit in memory.
+	cache := NewCache(0, "")
+	for i, key := range oldKeys {
+		for field := range fields {
+			newKey := SeriesFieldKeyBytes(string(newKeys[i]), field)
+			if err := e.walkValues(SeriesFieldKeyBytes(string(key), field), func(values Values) error {
+				if err := cache.Write(newKey, values); err != nil {
+					return err
+				} else if cache.Size() <= e.CacheFlushMemorySizeThreshold {
+					return nil
+				}
+
+				if err := e.writeRenamed(cache); err != nil {
+					return err
+				}
+				cache = NewCache(0, "")
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := e.writeRenamed(cache); err != nil {
+		return err
+	}
+
+	// Remove the original series.
+	if err := e.DeleteSeriesRange(oldKeys, math.MinInt64, math.MaxInt64); err != nil {
+		return err
+	}
+
+	// Remove the fields of the measurement if it has no series left.
+	if keys, err = e.index.MeasurementSeriesKeysByExpr(name, nil); err != nil {
+		return err
+	} else if existing, err = e.containsSeries(keys); err != nil {
+		return err
+	}
+	for _, exists := range existing {
+		if exists {
+			return nil
+		}
+	}
+	e.fieldset.Delete(string(name))
+	return nil
+}
+
+// walkValues calls fn with the values of a key one block at a time, from the
+// oldest TSM file to the cache. Deleted values are skipped.
+func (e *Engine) walkValues(key []byte, fn func(values Values) error) error {
+	// The cache is read first since snapshots move its values to new TSM
+	// files. Its values are newer than the values of the files.
+	cached := e.Cache.Values(key)
+
+	for _, f := range e.FileStore.Files() {
+		if !f.Contains(key) {
+			continue
+		}
+
+		if err := func() error {
+			f.Ref()
+			defer f.Unref()
+
+			tombstones := f.TombstoneRange(key)
+			for _, entry := range f.Entries(key) {
+				v, err := f.ReadAt(&entry, nil)
+				if err != nil {
+					return err
+				}
+
+				// Filter out any values that were deleted.
+				values := Values(v)
+				for _, t := range tombstones {
+					values = values.Exclude(t.Min, t.Max)
+				}
+				if len(values) == 0 {
+					continue
+				}
+
+				if err := fn(values); err != nil {
+					return err
+				}
+			}
+			return nil
+		}(); err != nil {
+			return err
+		}
+	}
+
+	if len(cached) == 0 {
+		return nil
+	}
+	return fn(cached)
+}
+
+// writeRenamed writes the values of renamed series to new TSM files.
+func (e *Engine) writeRenamed(cache *Cache) error {
+	if cache.Size() == 0 {
+		return nil
+	}
+
+	cache.Deduplicate()
+	files, err := e.Compactor.WriteSnapshot(cache)
+	if err != nil {
+		return err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.FileStore.Replace(nil, files)
+}
+
+// containsField returns true if any series of the measurement has values for
+// the field.
+func (e *Engine) containsField(name, field []byte) (bool, error) {
+	matches := func(k []byte) bool {
+		seriesKey, f := SeriesAndFieldFromCompositeKey(k)
+		return bytes.Equal(f, field) && bytes.Equal(tsdb.MeasurementFromSeriesKey(seriesKey), name)
+	}
+
+	for _, k := range e.Cache.unsortedKeys() {
+		if matches(k) {
+			return true, nil
+		}
+	}
+
+	var exists bool
+	if err := e.FileStore.WalkKeys(func(k []byte, _ byte) error {
+		if !exists && matches(k) {
+			exists = true
+		}
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
 // deleteSeries removes all series keys from the engine.
 func (e *Engine) deleteSeries(seriesKeys [][]byte) error {
 	return e.DeleteSeriesRange(seriesKeys, math.MinInt64, math.MaxInt64)
@@ -919,6 +1142,34 @@ func (e *Engine) deleteSeries(seriesKeys [][]byte) error {
 
 // DeleteSeriesRange removes the values between min and max (inclusive) from all series.
 func (e *Engine) DeleteSeriesRange(seriesKeys [][]byte, min, max int64) error {
+	return e.deleteSeriesRange(seriesKeys, nil, min, max)
+}
+
+// DeleteFieldRange removes the values of a field between min and max (inclusive)
+// from the series of a measurement. The field is removed from the measurement
+// once none of its series have values for it.
+func (e *Engine) DeleteFieldRange(name []byte, field string, seriesKeys [][]byte, min, max int64) error {
+	if err := e.deleteSeriesRange(seriesKeys, []byte(field), min, max); err != nil {
+		return err
+	}
+
+	mf := e.fieldset.Fields(string(name))
+	if mf == nil {
+		return nil
+	}
+
+	exists, err := e.containsField(name, []byte(field))
+	if err != nil {
+		return err
+	} else if !exists {
+		mf.DeleteField(field)
+	}
+	return nil
+}
+
+// deleteSeriesRange removes the values between min and max (inclusive) from
+// all series. Only the values of field are removed if it is not nil.
+func (e *Engine) deleteSeriesRange(seriesKeys [][]byte, field []byte, min, max int64) error {
 	if len(seriesKeys) == 0 {
 		return nil
 	}
@@ -941,7 +1192,7 @@ func (e *Engine) DeleteSeriesRange(seriesKeys [][]byte, min, max int64) error {
 	deleteKeys := make([][]byte, 0, len(seriesKeys))
 	// go through the keys in the file store
 	if err := e.FileStore.WalkKeys(func(k []byte, _ byte) error {
-		seriesKey, _ := SeriesAndFieldFromCompositeKey(k)
+		seriesKey, f := SeriesAndFieldFromCompositeKey(k)
 
 		// Both tempKeys and keys walked are sorted, skip any passed in keys
 		// that don't exist in our key set.
@@ -950,7 +1201,7 @@ func (e *Engine) DeleteSeriesRange(seriesKeys [][]byte, min, max int64) error {
 		}
 
 		// Keys match, add the full series key to delete.
-		if len(tempKeys) > 0 && bytes.Equal(tempKeys[0], seriesKey) {
+		if len(tempKeys) > 0 && bytes.Equal(tempKeys[0], seriesKey) && (field == nil || bytes.Equal(f, field)) {
 			deleteKeys = append(deleteKeys, k)
 		}
 
@@ -968,7 +1219,10 @@ func (e *Engine) DeleteSeriesRange(seriesKeys [][]byte, min, max int64) error {
 
 	// ApplySerialEntryFn cannot return an error in this invocation.
 	_ = e.Cache.ApplyEntryFn(func(k []byte, _ *entry) error {
-		seriesKey, _ := SeriesAndFieldFromCompositeKey([]byte(k))
+		seriesKey, f := SeriesAndFieldFromCompositeKey([]byte(k))
+		if field != nil && !bytes.Equal(f, field) {
+			return nil
+		}
 
 		// Cache does not walk keys in sorted order, so search the sorted
 		// series we need to delete to see if any of the cache keys match.
@@ -1571,6 +1825,68 @@ func (e *Engine) CreateIterator(measurement string, opt influxql.IteratorOptions
 	return influxql.Iterators(itrs).Merge(opt)
 }
 
+// IteratorCost returns the estimated cost of creating an iterator for the measurement based on opt.
+func (e *Engine) IteratorCost(measurement string, opt influxql.IteratorOptions) (influxql.IteratorCost, error) {
+	if exists, err := e.index.MeasurementExists([]byte(measurement)); err != nil {
+		return influxql.IteratorCost{}, err
+	} else if !exists {
+		return influxql.IteratorCost{}, nil
+	}
+
+	// Determine tagsets for this measurement based on dimensions and filters.
+	tagSets, err := e.index.TagSets([]byte(measurement), opt)
+	if err != nil {
+		return influxql.IteratorCost{}, err
+	}
+	tagSets = influxql.LimitTagSets(tagSets, opt.SLimit, opt.SOffset)
+
+	// Determine the field read by the main cursor, if there is one.
+	var ref *influxql.VarRef
+	switch expr := opt.Expr.(type) {
+	case *influxql.VarRef:
+		ref = expr
+	case *influxql.Call:
+		if len(expr.Args) > 0 {
+			ref, _ = expr.Args[0].(*influxql.VarRef)
+		}
+	}
+
+	cost := influxql.IteratorCost{NumTagSets: int64(len(tagSets))}
+	for _, t := range tagSets {
+		cost.NumSeries += int64(len(t.SeriesKeys))
+		for i, seriesKey := range t.SeriesKeys {
+			// A cursor is created for the main field, every auxiliary field
+			// and every field used in the condition of the series.
+			if ref != nil {
+				cost = cost.Combine(e.seriesFieldCost(seriesKey, ref.Val, opt))
+			}
+			for _, aux := range opt.Aux {
+				if aux.Type != influxql.Tag {
+					cost = cost.Combine(e.seriesFieldCost(seriesKey, aux.Val, opt))
+				}
+			}
+			if t.Filters[i] != nil {
+				for _, cond := range influxql.ExprNames(t.Filters[i]) {
+					cost = cost.Combine(e.seriesFieldCost(seriesKey, cond.Val, opt))
+				}
+			}
+		}
+	}
+	return cost, nil
+}
+
+// seriesFieldCost returns the cost of reading a field of a series within the time range of opt.
+func (e *Engine) seriesFieldCost(seriesKey, field string, opt influxql.IteratorOptions) influxql.IteratorCost {
+	key := SeriesFieldKeyBytes(seriesKey, field)
+	cost := e.FileStore.Cost(key, opt.StartTime, opt.EndTime)
+	for _, v := range e.Cache.Values(key) {
+		if t := v.UnixNano(); t >= opt.StartTime && t <= opt.EndTime {
+			cost.CachedValues++
+		}
+	}
+	return cost
+}
+
 func (e *Engine) createCallIterator(measurement string, call *influxql.Call, opt influxql.IteratorOptions) ([]influxql.Iterator, error) {
 	ref, _ := call.Args[0].(*influxql.VarRef)
 
@@ -1925,8 +2241,7 @@ func (e *Engine) createVarRefSeriesIterator(ref *influxql.VarRef, name string, s
 	condNames := influxql.VarRefs(conditionFields).Strings()
 
 	// Limit tags to only the dimensions selected.
-	dimensions := opt.GetDimensions()
-	tags = tags.Subset(dimensions)
+	tags = opt.DimensionTags(tags)
 
 	// If it's only auxiliary fields then it doesn't matter what type of iterator we use.
 	if ref == nil {
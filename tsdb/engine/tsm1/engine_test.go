@@ -575,6 +575,58 @@ func TestEngine_CreateIterator_Condition(t *testing.T) {
 	}
 }
 
+// Ensure engine can group series by a dimension derived from a tag.
+func TestEngine_CreateIterator_DerivedDimension(t *testing.T) {
+	t.Parallel()
+
+	e := MustOpenEngine()
+	defer e.Close()
+
+	e.MeasurementFields([]byte("cpu")).CreateFieldIfNotExists([]byte("value"), influxql.Float, false)
+	for _, host := range []string{"web-eu-1", "web-eu-2", "web-us-1"} {
+		e.CreateSeriesIfNotExists([]byte("cpu,host="+host), []byte("cpu"), models.NewTags(map[string]string{"host": host}))
+	}
+
+	if err := e.WritePointsString(
+		`cpu,host=web-eu-1 value=1 1000000000`,
+		`cpu,host=web-eu-2 value=2 1000000000`,
+		`cpu,host=web-us-1 value=4 1000000000`,
+	); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	itr, err := e.CreateIterator("cpu", influxql.IteratorOptions{
+		Expr:       influxql.MustParseExpr(`sum(value)`),
+		Dimensions: []string{"region"},
+		DerivedDimensions: map[string]*influxql.Call{
+			"region": influxql.MustParseExpr(`regex_extract(host, /^(\w+)-(\w+)/, 2)`).(*influxql.Call),
+		},
+		StartTime: influxql.MinTime,
+		EndTime:   influxql.MaxTime,
+		Ascending: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fitr := itr.(influxql.FloatIterator)
+
+	if p, err := fitr.Next(); err != nil {
+		t.Fatalf("unexpected error(0): %v", err)
+	} else if !reflect.DeepEqual(p, &influxql.FloatPoint{Name: "cpu", Tags: ParseTags("region=eu"), Time: influxql.MinTime, Value: 3, Aggregated: 2}) {
+		t.Fatalf("unexpected point(0): %v", p)
+	}
+	if p, err := fitr.Next(); err != nil {
+		t.Fatalf("unexpected error(1): %v", err)
+	} else if !reflect.DeepEqual(p, &influxql.FloatPoint{Name: "cpu", Tags: ParseTags("region=us"), Time: influxql.MinTime, Value: 4, Aggregated: 1}) {
+		t.Fatalf("unexpected point(1): %v", p)
+	}
+	if p, err := fitr.Next(); err != nil {
+		t.Fatalf("expected eof, got error: %v", err)
+	} else if p != nil {
+		t.Fatalf("expected eof: %v", p)
+	}
+}
+
 // Ensures that deleting series from TSM files with multiple fields removes all the
 /// series
 func TestEngine_DeleteSeries(t *testing.T) {
@@ -635,6 +687,305 @@ func TestEngine_DeleteSeries(t *testing.T) {
 
 }
 
+// Ensure engine iterators report where their points were read from.
+func TestEngine_CreateIterator_Stats(t *testing.T) {
+	t.Parallel()
+
+	e := MustOpenEngine()
+	defer e.Close()
+
+	e.MeasurementFields([]byte("cpu")).CreateFieldIfNotExists([]byte("value"), influxql.Float, false)
+	e.CreateSeriesIfNotExists([]byte("cpu,host=A"), []byte("cpu"), models.NewTags(map[string]string{"host": "A"}))
+
+	if err := e.WritePointsString(
+		`cpu,host=A value=1.1 1000000000`,
+		`cpu,host=A value=1.2 2000000000`,
+	); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	e.MustWriteSnapshot()
+
+	if err := e.WritePointsString(`cpu,host=A value=1.3 3000000000`); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	itr, err := e.CreateIterator("cpu", influxql.IteratorOptions{
+		Expr:       influxql.MustParseExpr(`value`),
+		Dimensions: []string{"host"},
+		StartTime:  influxql.MinTime,
+		EndTime:    influxql.MaxTime,
+		Ascending:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer itr.Close()
+	fitr := itr.(influxql.FloatIterator)
+
+	for {
+		if p, err := fitr.Next(); err != nil {
+			t.Fatal(err)
+		} else if p == nil {
+			break
+		}
+	}
+
+	if stats, exp := itr.Stats(), (influxql.IteratorStats{
+		SeriesN:     1,
+		PointN:      3,
+		BlockN:      1,
+		CachePointN: 1,
+		TSMPointN:   2,
+	}); !reflect.DeepEqual(stats, exp) {
+		t.Fatalf("unexpected stats:\n\nexp=%#v\n\ngot=%#v\n\n", exp, stats)
+	}
+}
+
+// Ensure engine can estimate the cost of an iterator.
+func TestEngine_IteratorCost(t *testing.T) {
+	t.Parallel()
+
+	e := MustOpenEngine()
+	defer e.Close()
+
+	e.MeasurementFields([]byte("cpu")).CreateFieldIfNotExists([]byte("value"), influxql.Float, false)
+	e.CreateSeriesIfNotExists([]byte("cpu,host=A"), []byte("cpu"), models.NewTags(map[string]string{"host": "A"}))
+	e.CreateSeriesIfNotExists([]byte("cpu,host=B"), []byte("cpu"), models.NewTags(map[string]string{"host": "B"}))
+
+	if err := e.WritePointsString(
+		`cpu,host=A value=1.1 1000000000`,
+		`cpu,host=B value=1.2 2000000000`,
+	); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	e.MustWriteSnapshot()
+
+	if err := e.WritePointsString(
+		`cpu,host=A value=1.3 3000000000`,
+		`cpu,host=A value=1.4 4000000000`,
+	); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	cost, err := e.IteratorCost("cpu", influxql.IteratorOptions{
+		Expr:       influxql.MustParseExpr(`value`),
+		Dimensions: []string{"host"},
+		StartTime:  influxql.MinTime,
+		EndTime:    3000000000,
+		Ascending:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if cost.BlockSize <= 0 {
+		t.Fatalf("unexpected block size: %d", cost.BlockSize)
+	}
+	cost.BlockSize = 0
+
+	if exp := (influxql.IteratorCost{
+		NumTagSets:   2,
+		NumSeries:    2,
+		CachedValues: 1,
+		NumFiles:     2,
+		BlocksRead:   2,
+	}); !reflect.DeepEqual(cost, exp) {
+		t.Fatalf("unexpected cost:\n\nexp=%#v\n\ngot=%#v\n\n", exp, cost)
+	}
+
+	// A measurement that does not exist has no cost.
+	if cost, err := e.IteratorCost("mem", influxql.IteratorOptions{
+		Expr:      influxql.MustParseExpr(`value`),
+		StartTime: influxql.MinTime,
+		EndTime:   influxql.MaxTime,
+	}); err != nil {
+		t.Fatal(err)
+	} else if cost != (influxql.IteratorCost{}) {
+		t.Fatalf("unexpected cost: %#v", cost)
+	}
+}
+
+func TestEngine_DeleteFieldRange(t *testing.T) {
+	// Generate temporary file.
+	dir, _ := ioutil.TempDir("", "tsm")
+	walPath := filepath.Join(dir, "wal")
+	os.MkdirAll(walPath, 0777)
+	defer os.RemoveAll(dir)
+
+	// Create a few points.
+	p1 := MustParsePointString("cpu,host=A value=1.1,sum=1.3 1000000000")
+	p2 := MustParsePointString("cpu,host=B value=1.2 2000000000")
+	p3 := MustParsePointString("cpu,host=A sum=1.4 3000000000")
+
+	// Write those points to the engine.
+	db := path.Base(dir)
+	opt := tsdb.NewEngineOptions()
+	opt.InmemIndex = inmem.NewIndex(db)
+	idx := tsdb.MustOpenIndex(1, db, filepath.Join(dir, "index"), opt)
+	defer idx.Close()
+
+	e := tsm1.NewEngine(1, idx, db, dir, walPath, opt).(*tsm1.Engine)
+
+	// mock the planner so compactions don't run during the test
+	e.CompactionPlan = &mockPlanner{}
+
+	if err := e.Open(); err != nil {
+		t.Fatalf("failed to open tsm1 engine: %s", err.Error())
+	}
+	defer e.Close()
+
+	// Fields are created by the shard before points are written.
+	mf := e.MeasurementFields([]byte("cpu"))
+	for _, name := range []string{"value", "sum"} {
+		if err := mf.CreateFieldIfNotExists([]byte(name), influxql.Float, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := e.WritePoints([]models.Point{p1, p2}); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	if err := e.WriteSnapshot(); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+
+	// Leave the last point in the cache.
+	if err := e.WritePoints([]models.Point{p3}); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	// Deleting the values of part of the series keeps the field.
+	if err := e.DeleteFieldRange([]byte("cpu"), "sum", [][]byte{[]byte("cpu,host=A")}, math.MinInt64, 2000000000); err != nil {
+		t.Fatalf("failed to delete field: %v", err)
+	}
+	if !e.MeasurementFields([]byte("cpu")).HasField("sum") {
+		t.Fatal("expected sum field to exist")
+	}
+
+	if err := e.DeleteFieldRange([]byte("cpu"), "sum", [][]byte{[]byte("cpu,host=A")}, math.MinInt64, math.MaxInt64); err != nil {
+		t.Fatalf("failed to delete field: %v", err)
+	}
+
+	keys := e.FileStore.Keys()
+	if exp, got := 2, len(keys); exp != got {
+		t.Fatalf("series count mismatch: exp %v, got %v", exp, got)
+	}
+	for _, exp := range []string{"cpu,host=A#!~#value", "cpu,host=B#!~#value"} {
+		if _, ok := keys[exp]; !ok {
+			t.Fatalf("wrong field deleted: exp %v, got %v", exp, keys)
+		}
+	}
+	if n := len(e.Cache.Keys()); n != 0 {
+		t.Fatalf("unexpected cache keys: %d", n)
+	}
+
+	fields := e.MeasurementFields([]byte("cpu"))
+	if fields.HasField("sum") {
+		t.Fatal("expected sum field to be removed")
+	} else if !fields.HasField("value") {
+		t.Fatal("expected value field to exist")
+	}
+}
+
+func TestEngine_RenameSeries(t *testing.T) {
+	// Generate temporary file.
+	dir, _ := ioutil.TempDir("", "tsm")
+	walPath := filepath.Join(dir, "wal")
+	os.MkdirAll(walPath, 0777)
+	defer os.RemoveAll(dir)
+
+	// Create a few points.
+	p1 := MustParsePointString("cpu,host=A value=1.1 1000000000")
+	p2 := MustParsePointString("cpu,host=B value=1.2 2000000000")
+	p3 := MustParsePointString("cpu,host=A value=1.3 3000000000")
+
+	// Write those points to the engine.
+	db := path.Base(dir)
+	opt := tsdb.NewEngineOptions()
+	opt.InmemIndex = inmem.NewIndex(db)
+	idx := tsdb.MustOpenIndex(1, db, filepath.Join(dir, "index"), opt)
+	defer idx.Close()
+
+	e := tsm1.NewEngine(1, idx, db, dir, walPath, opt).(*tsm1.Engine)
+
+	// mock the planner so compactions don't run during the test
+	e.CompactionPlan = &mockPlanner{}
+
+	if err := e.Open(); err != nil {
+		t.Fatalf("failed to open tsm1 engine: %s", err.Error())
+	}
+	defer e.Close()
+
+	// Series and fields are created by the shard before points are written.
+	for _, p := range []models.Point{p1, p2} {
+		if err := e.CreateSeriesIfNotExists(p.Key(), p.Name(), p.Tags()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.MeasurementFields([]byte("cpu")).CreateFieldIfNotExists([]byte("value"), influxql.Float, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := e.WritePoints([]models.Point{p1, p2}); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	if err := e.WriteSnapshot(); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+
+	// Leave the last point in the cache.
+	if err := e.WritePoints([]models.Point{p3}); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	cond := influxql.MustParseExpr(`host = 'A'`)
+	if err := e.RenameSeries([]byte("cpu"), cond, func(name []byte, tags models.Tags) ([]byte, models.Tags) {
+		tags.SetString("host", "C")
+		return []byte("cpu_v2"), tags
+	}); err != nil {
+		t.Fatalf("failed to rename series: %v", err)
+	}
+
+	keys := e.FileStore.Keys()
+	if _, ok := keys["cpu,host=A#!~#value"]; ok {
+		t.Fatalf("expected series to be renamed: %v", keys)
+	} else if _, ok := keys["cpu,host=B#!~#value"]; !ok {
+		t.Fatalf("expected series to be kept: %v", keys)
+	} else if _, ok := keys["cpu_v2,host=C#!~#value"]; !ok {
+		t.Fatalf("expected renamed series: %v", keys)
+	}
+
+	// The values from the TSM files and the cache are both renamed.
+	for _, ts := range []int64{1000000000, 3000000000} {
+		if values, err := e.FileStore.Read([]byte("cpu_v2,host=C#!~#value"), ts); err != nil {
+			t.Fatal(err)
+		} else if len(values) != 2 {
+			t.Fatalf("unexpected values: %v", values)
+		}
+	}
+
+	if !e.MeasurementFields([]byte("cpu_v2")).HasField("value") {
+		t.Fatal("expected fields of the renamed measurement")
+	} else if exists, err := e.MeasurementExists([]byte("cpu_v2")); err != nil || !exists {
+		t.Fatalf("expected renamed measurement to exist: %v", err)
+	}
+
+	// Values are copied in blocks when they do not fit in the cache, and the
+	// fields of a measurement are removed once it has no series left.
+	e.CacheFlushMemorySizeThreshold = 1
+	if err := e.RenameSeries([]byte("cpu"), nil, func(name []byte, tags models.Tags) ([]byte, models.Tags) {
+		return []byte("cpu_v3"), tags
+	}); err != nil {
+		t.Fatalf("failed to rename series: %v", err)
+	}
+
+	if values, err := e.FileStore.Read([]byte("cpu_v3,host=B#!~#value"), 2000000000); err != nil {
+		t.Fatal(err)
+	} else if len(values) != 1 || values[0].Value() != 1.2 {
+		t.Fatalf("unexpected values: %v", values)
+	} else if e.MeasurementFields([]byte("cpu")).HasField("value") {
+		t.Fatal("expected fields of the renamed measurement to be removed")
+	}
+}
+
 func TestEngine_LastModified(t *testing.T) {
 	// Generate temporary file.
 	dir, _ := ioutil.TempDir("", "tsm")
@@ -20,6 +20,7 @@ func (c *KeyCursor) ReadFloatBlock(buf *[]FloatValue) ([]FloatValue, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.blockN++
 
 	// Remove values we already read
 	values = FloatValues(values).Exclude(first.readMin, first.readMax)
@@ -88,6 +89,7 @@ func (c *KeyCursor) ReadFloatBlock(buf *[]FloatValue) ([]FloatValue, error) {
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterFloatValues(tombstones, v)
 
@@ -147,6 +149,7 @@ func (c *KeyCursor) ReadFloatBlock(buf *[]FloatValue) ([]FloatValue, error) {
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterFloatValues(tombstones, v)
 
@@ -183,6 +186,7 @@ func (c *KeyCursor) ReadIntegerBlock(buf *[]IntegerValue) ([]IntegerValue, error
 	if err != nil {
 		return nil, err
 	}
+	c.blockN++
 
 	// Remove values we already read
 	values = IntegerValues(values).Exclude(first.readMin, first.readMax)
@@ -251,6 +255,7 @@ func (c *KeyCursor) ReadIntegerBlock(buf *[]IntegerValue) ([]IntegerValue, error
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterIntegerValues(tombstones, v)
 
@@ -310,6 +315,7 @@ func (c *KeyCursor) ReadIntegerBlock(buf *[]IntegerValue) ([]IntegerValue, error
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterIntegerValues(tombstones, v)
 
@@ -346,6 +352,7 @@ func (c *KeyCursor) ReadUnsignedBlock(buf *[]UnsignedValue) ([]UnsignedValue, er
 	if err != nil {
 		return nil, err
 	}
+	c.blockN++
 
 	// Remove values we already read
 	values = UnsignedValues(values).Exclude(first.readMin, first.readMax)
@@ -414,6 +421,7 @@ func (c *KeyCursor) ReadUnsignedBlock(buf *[]UnsignedValue) ([]UnsignedValue, er
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterUnsignedValues(tombstones, v)
 
@@ -473,6 +481,7 @@ func (c *KeyCursor) ReadUnsignedBlock(buf *[]UnsignedValue) ([]UnsignedValue, er
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterUnsignedValues(tombstones, v)
 
@@ -509,6 +518,7 @@ func (c *KeyCursor) ReadStringBlock(buf *[]StringValue) ([]StringValue, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.blockN++
 
 	// Remove values we already read
 	values = StringValues(values).Exclude(first.readMin, first.readMax)
@@ -577,6 +587,7 @@ func (c *KeyCursor) ReadStringBlock(buf *[]StringValue) ([]StringValue, error) {
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterStringValues(tombstones, v)
 
@@ -636,6 +647,7 @@ func (c *KeyCursor) ReadStringBlock(buf *[]StringValue) ([]StringValue, error) {
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterStringValues(tombstones, v)
 
@@ -672,6 +684,7 @@ func (c *KeyCursor) ReadBooleanBlock(buf *[]BooleanValue) ([]BooleanValue, error
 	if err != nil {
 		return nil, err
 	}
+	c.blockN++
 
 	// Remove values we already read
 	values = BooleanValues(values).Exclude(first.readMin, first.readMax)
@@ -740,6 +753,7 @@ func (c *KeyCursor) ReadBooleanBlock(buf *[]BooleanValue) ([]BooleanValue, error
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterBooleanValues(tombstones, v)
 
@@ -799,6 +813,7 @@ func (c *KeyCursor) ReadBooleanBlock(buf *[]BooleanValue) ([]BooleanValue, error
 			if err != nil {
 				return nil, err
 			}
+			c.blockN++
 			// Remove any tombstoned values
 			v = c.filterBooleanValues(tombstones, v)
 
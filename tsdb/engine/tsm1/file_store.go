@@ -14,6 +14,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/influxdata/influxdb/influxql"
 	"github.com/influxdata/influxdb/models"
 	"github.com/uber-go/zap"
 )
@@ -720,6 +721,48 @@ func (f *FileStore) walkFiles(fn func(f TSMFile) error) error {
 	return nil
 }
 
+// Cost returns the cost of reading the blocks for a key within a time range.
+func (f *FileStore) Cost(key []byte, min, max int64) influxql.IteratorCost {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	var cost influxql.IteratorCost
+	var entries []IndexEntry
+	for _, fd := range f.files {
+		minTime, maxTime := fd.TimeRange()
+		if maxTime < min || minTime > max {
+			continue
+		}
+
+		tombstones := fd.TombstoneRange(key)
+		fd.ReadEntries(key, &entries)
+
+		var blockN int64
+	ENTRIES:
+		for _, ie := range entries {
+			if !ie.OverlapsTimeRange(min, max) {
+				continue
+			}
+
+			// Skip any blocks only contain values that are tombstoned.
+			for _, t := range tombstones {
+				if t.Min <= ie.MinTime && t.Max >= ie.MaxTime {
+					continue ENTRIES
+				}
+			}
+
+			blockN++
+			cost.BlockSize += int64(ie.Size)
+		}
+
+		if blockN > 0 {
+			cost.NumFiles++
+			cost.BlocksRead += blockN
+		}
+	}
+	return cost
+}
+
 // locations returns the files and index blocks for a key and time.  ascending indicates
 // whether the key will be scan in ascending time order or descenging time order.
 // This function assumes the read-lock has been taken.
@@ -879,6 +922,9 @@ type KeyCursor struct {
 	// If this is true, we need to scan the duplicate blocks and dedup the points
 	// as query time until they are compacted.
 	duplicates bool
+
+	// blockN is the number of blocks that have been decoded.
+	blockN int
 }
 
 type location struct {
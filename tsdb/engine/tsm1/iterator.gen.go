@@ -18,6 +18,7 @@ import (
 type cursor interface {
 	close() error
 	next() (t int64, v interface{})
+	stats() cursorStats
 }
 
 // cursorAt provides a bufferred cursor interface.
@@ -26,6 +27,7 @@ type cursorAt interface {
 	close() error
 	peek() (k int64, v interface{})
 	nextAt(seek int64) interface{}
+	stats() cursorStats
 }
 
 type nilCursor struct{}
@@ -54,6 +56,14 @@ func (c *bufCursor) close() error {
 	return err
 }
 
+// stats returns statistics about the values read by the underlying cursor.
+func (c *bufCursor) stats() cursorStats {
+	if c.cur == nil {
+		return cursorStats{}
+	}
+	return c.cur.stats()
+}
+
 // next returns the buffer, if filled. Otherwise returns the next key/value from the cursor.
 func (c *bufCursor) next() (int64, interface{}) {
 	if c.buf.filled {
@@ -224,6 +234,21 @@ func (itr *floatIterator) Next() (*influxql.FloatPoint, error) {
 
 // copyStats copies from the itr stats buffer to the stats under lock.
 func (itr *floatIterator) copyStats() {
+	// Gather storage statistics from the cursors.
+	var cs cursorStats
+	if itr.cur != nil {
+		cs.add(itr.cur.stats())
+	}
+	for _, c := range itr.aux {
+		cs.add(c.stats())
+	}
+	for _, c := range itr.conds.curs {
+		cs.add(c.stats())
+	}
+	itr.statsBuf.BlockN = cs.BlockN
+	itr.statsBuf.CachePointN = cs.CacheN
+	itr.statsBuf.TSMPointN = cs.TSMN
+
 	itr.statsLock.Lock()
 	itr.stats = itr.statsBuf
 	itr.statsLock.Unlock()
@@ -308,11 +333,13 @@ type floatAscendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []FloatValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -354,6 +381,15 @@ func (c *floatAscendingCursor) peekTSM() (t int64, v float64) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *floatAscendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *floatAscendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -380,17 +416,20 @@ func (c *floatAscendingCursor) nextFloat() (int64, float64) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey < tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -419,11 +458,13 @@ type floatDescendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []FloatValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -471,6 +512,15 @@ func (c *floatDescendingCursor) peekTSM() (t int64, v float64) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *floatDescendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *floatDescendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -497,17 +547,20 @@ func (c *floatDescendingCursor) nextFloat() (int64, float64) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey > tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -639,6 +692,21 @@ func (itr *integerIterator) Next() (*influxql.IntegerPoint, error) {
 
 // copyStats copies from the itr stats buffer to the stats under lock.
 func (itr *integerIterator) copyStats() {
+	// Gather storage statistics from the cursors.
+	var cs cursorStats
+	if itr.cur != nil {
+		cs.add(itr.cur.stats())
+	}
+	for _, c := range itr.aux {
+		cs.add(c.stats())
+	}
+	for _, c := range itr.conds.curs {
+		cs.add(c.stats())
+	}
+	itr.statsBuf.BlockN = cs.BlockN
+	itr.statsBuf.CachePointN = cs.CacheN
+	itr.statsBuf.TSMPointN = cs.TSMN
+
 	itr.statsLock.Lock()
 	itr.stats = itr.statsBuf
 	itr.statsLock.Unlock()
@@ -723,11 +791,13 @@ type integerAscendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []IntegerValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -769,6 +839,15 @@ func (c *integerAscendingCursor) peekTSM() (t int64, v int64) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *integerAscendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *integerAscendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -795,17 +874,20 @@ func (c *integerAscendingCursor) nextInteger() (int64, int64) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey < tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -834,11 +916,13 @@ type integerDescendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []IntegerValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -886,6 +970,15 @@ func (c *integerDescendingCursor) peekTSM() (t int64, v int64) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *integerDescendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *integerDescendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -912,17 +1005,20 @@ func (c *integerDescendingCursor) nextInteger() (int64, int64) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey > tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -1054,6 +1150,21 @@ func (itr *unsignedIterator) Next() (*influxql.UnsignedPoint, error) {
 
 // copyStats copies from the itr stats buffer to the stats under lock.
 func (itr *unsignedIterator) copyStats() {
+	// Gather storage statistics from the cursors.
+	var cs cursorStats
+	if itr.cur != nil {
+		cs.add(itr.cur.stats())
+	}
+	for _, c := range itr.aux {
+		cs.add(c.stats())
+	}
+	for _, c := range itr.conds.curs {
+		cs.add(c.stats())
+	}
+	itr.statsBuf.BlockN = cs.BlockN
+	itr.statsBuf.CachePointN = cs.CacheN
+	itr.statsBuf.TSMPointN = cs.TSMN
+
 	itr.statsLock.Lock()
 	itr.stats = itr.statsBuf
 	itr.statsLock.Unlock()
@@ -1138,11 +1249,13 @@ type unsignedAscendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []UnsignedValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -1184,6 +1297,15 @@ func (c *unsignedAscendingCursor) peekTSM() (t int64, v uint64) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *unsignedAscendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *unsignedAscendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -1210,17 +1332,20 @@ func (c *unsignedAscendingCursor) nextUnsigned() (int64, uint64) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey < tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -1249,11 +1374,13 @@ type unsignedDescendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []UnsignedValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -1301,6 +1428,15 @@ func (c *unsignedDescendingCursor) peekTSM() (t int64, v uint64) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *unsignedDescendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *unsignedDescendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -1327,17 +1463,20 @@ func (c *unsignedDescendingCursor) nextUnsigned() (int64, uint64) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey > tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -1469,6 +1608,21 @@ func (itr *stringIterator) Next() (*influxql.StringPoint, error) {
 
 // copyStats copies from the itr stats buffer to the stats under lock.
 func (itr *stringIterator) copyStats() {
+	// Gather storage statistics from the cursors.
+	var cs cursorStats
+	if itr.cur != nil {
+		cs.add(itr.cur.stats())
+	}
+	for _, c := range itr.aux {
+		cs.add(c.stats())
+	}
+	for _, c := range itr.conds.curs {
+		cs.add(c.stats())
+	}
+	itr.statsBuf.BlockN = cs.BlockN
+	itr.statsBuf.CachePointN = cs.CacheN
+	itr.statsBuf.TSMPointN = cs.TSMN
+
 	itr.statsLock.Lock()
 	itr.stats = itr.statsBuf
 	itr.statsLock.Unlock()
@@ -1553,11 +1707,13 @@ type stringAscendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []StringValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -1599,6 +1755,15 @@ func (c *stringAscendingCursor) peekTSM() (t int64, v string) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *stringAscendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *stringAscendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -1625,17 +1790,20 @@ func (c *stringAscendingCursor) nextString() (int64, string) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey < tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -1664,11 +1832,13 @@ type stringDescendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []StringValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -1716,6 +1886,15 @@ func (c *stringDescendingCursor) peekTSM() (t int64, v string) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *stringDescendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *stringDescendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -1742,17 +1921,20 @@ func (c *stringDescendingCursor) nextString() (int64, string) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey > tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -1884,6 +2066,21 @@ func (itr *booleanIterator) Next() (*influxql.BooleanPoint, error) {
 
 // copyStats copies from the itr stats buffer to the stats under lock.
 func (itr *booleanIterator) copyStats() {
+	// Gather storage statistics from the cursors.
+	var cs cursorStats
+	if itr.cur != nil {
+		cs.add(itr.cur.stats())
+	}
+	for _, c := range itr.aux {
+		cs.add(c.stats())
+	}
+	for _, c := range itr.conds.curs {
+		cs.add(c.stats())
+	}
+	itr.statsBuf.BlockN = cs.BlockN
+	itr.statsBuf.CachePointN = cs.CacheN
+	itr.statsBuf.TSMPointN = cs.TSMN
+
 	itr.statsLock.Lock()
 	itr.stats = itr.statsBuf
 	itr.statsLock.Unlock()
@@ -1968,11 +2165,13 @@ type booleanAscendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []BooleanValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -2014,6 +2213,15 @@ func (c *booleanAscendingCursor) peekTSM() (t int64, v bool) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *booleanAscendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *booleanAscendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -2040,17 +2248,20 @@ func (c *booleanAscendingCursor) nextBoolean() (int64, bool) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey < tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -2079,11 +2290,13 @@ type booleanDescendingCursor struct {
 	cache struct {
 		values Values
 		pos    int
+		n      int
 	}
 
 	tsm struct {
 		values    []BooleanValue
 		pos       int
+		n         int
 		keyCursor *KeyCursor
 	}
 }
@@ -2131,6 +2344,15 @@ func (c *booleanDescendingCursor) peekTSM() (t int64, v bool) {
 	return item.UnixNano(), item.value
 }
 
+// stats returns statistics about the values read by the cursor.
+func (c *booleanDescendingCursor) stats() cursorStats {
+	s := cursorStats{CacheN: c.cache.n, TSMN: c.tsm.n}
+	if c.tsm.keyCursor != nil {
+		s.BlockN = c.tsm.keyCursor.blockN
+	}
+	return s
+}
+
 // close closes the cursor and any dependent cursors.
 func (c *booleanDescendingCursor) close() error {
 	c.tsm.keyCursor.Close()
@@ -2157,17 +2379,20 @@ func (c *booleanDescendingCursor) nextBoolean() (int64, bool) {
 	if ckey == tkey {
 		c.nextCache()
 		c.nextTSM()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered cache key precedes that in TSM file.
 	if ckey != tsdb.EOF && (ckey > tkey || tkey == tsdb.EOF) {
 		c.nextCache()
+		c.cache.n++
 		return ckey, cvalue
 	}
 
 	// Buffered TSM key precedes that in cache.
 	c.nextTSM()
+	c.tsm.n++
 	return tkey, tvalue
 }
 
@@ -32,6 +32,10 @@ func (*infiniteIntegerCursor) close() error {
 	return nil
 }
 
+func (*infiniteIntegerCursor) stats() cursorStats {
+	return cursorStats{}
+}
+
 func (*infiniteIntegerCursor) next() (t int64, v interface{}) {
 	return 0, 0
 }
@@ -24,12 +24,28 @@ func newLimitIterator(input influxql.Iterator, opt influxql.IteratorOptions) inf
 	}
 }
 
+// cursorStats contains statistics about the values read by a cursor.
+type cursorStats struct {
+	CacheN int // values read from the cache
+	TSMN   int // values read from TSM files
+	BlockN int // TSM blocks decoded
+}
+
+// add adds the statistics of other to s.
+func (s *cursorStats) add(other cursorStats) {
+	s.CacheN += other.CacheN
+	s.TSMN += other.TSMN
+	s.BlockN += other.BlockN
+}
+
 type floatCastIntegerCursor struct {
 	cursor integerCursor
 }
 
 func (c *floatCastIntegerCursor) close() error { return c.cursor.close() }
 
+func (c *floatCastIntegerCursor) stats() cursorStats { return c.cursor.stats() }
+
 func (c *floatCastIntegerCursor) next() (t int64, v interface{}) { return c.nextFloat() }
 
 func (c *floatCastIntegerCursor) nextFloat() (int64, float64) {
@@ -43,6 +59,8 @@ type floatCastUnsignedCursor struct {
 
 func (c *floatCastUnsignedCursor) close() error { return c.cursor.close() }
 
+func (c *floatCastUnsignedCursor) stats() cursorStats { return c.cursor.stats() }
+
 func (c *floatCastUnsignedCursor) next() (t int64, v interface{}) { return c.nextFloat() }
 
 func (c *floatCastUnsignedCursor) nextFloat() (int64, float64) {
@@ -56,6 +74,8 @@ type integerCastFloatCursor struct {
 
 func (c *integerCastFloatCursor) close() error { return c.cursor.close() }
 
+func (c *integerCastFloatCursor) stats() cursorStats { return c.cursor.stats() }
+
 func (c *integerCastFloatCursor) next() (t int64, v interface{}) { return c.nextInteger() }
 
 func (c *integerCastFloatCursor) nextInteger() (int64, int64) {
@@ -69,6 +89,8 @@ type integerCastUnsignedCursor struct {
 
 func (c *integerCastUnsignedCursor) close() error { return c.cursor.close() }
 
+func (c *integerCastUnsignedCursor) stats() cursorStats { return c.cursor.stats() }
+
 func (c *integerCastUnsignedCursor) next() (t int64, v interface{}) { return c.nextInteger() }
 
 func (c *integerCastUnsignedCursor) nextInteger() (int64, int64) {
@@ -82,6 +104,8 @@ type unsignedCastFloatCursor struct {
 
 func (c *unsignedCastFloatCursor) close() error { return c.cursor.close() }
 
+func (c *unsignedCastFloatCursor) stats() cursorStats { return c.cursor.stats() }
+
 func (c *unsignedCastFloatCursor) next() (t int64, v interface{}) { return c.nextUnsigned() }
 
 func (c *unsignedCastFloatCursor) nextUnsigned() (int64, uint64) {
@@ -95,6 +119,8 @@ type unsignedCastIntegerCursor struct {
 
 func (c *unsignedCastIntegerCursor) close() error { return c.cursor.close() }
 
+func (c *unsignedCastIntegerCursor) stats() cursorStats { return c.cursor.stats() }
+
 func (c *unsignedCastIntegerCursor) next() (t int64, v interface{}) { return c.nextUnsigned() }
 
 func (c *unsignedCastIntegerCursor) nextUnsigned() (int64, uint64) {
@@ -109,6 +135,7 @@ type literalValueCursor struct {
 }
 
 func (c *literalValueCursor) close() error                   { return nil }
+func (c *literalValueCursor) stats() cursorStats             { return cursorStats{} }
 func (c *literalValueCursor) peek() (t int64, v interface{}) { return tsdb.EOF, c.value }
 func (c *literalValueCursor) next() (t int64, v interface{}) { return tsdb.EOF, c.value }
 func (c *literalValueCursor) nextAt(seek int64) interface{}  { return c.value }
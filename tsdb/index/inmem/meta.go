@@ -352,7 +352,17 @@ func (m *Measurement) TagSets(shardID uint64, opt influxql.IteratorOptions) ([]*
 		}
 
 		var tagsAsKey []byte
-		if len(dims) > 0 {
+		if len(dims) > 0 && len(opt.DerivedDimensions) > 0 {
+			// Derived dimensions are computed from the tags of the series
+			// before the series is grouped.
+			tags := make(map[string]string, len(dims))
+			for _, dim := range dims {
+				if v := opt.DimensionValue(dim, s.Tags().GetString); v != "" {
+					tags[dim] = v
+				}
+			}
+			tagsAsKey = tsdb.MarshalTags(tags)
+		} else if len(dims) > 0 {
 			tagsAsKey = tsdb.MakeTagsKey(dims, s.Tags())
 		}
 
@@ -692,7 +692,7 @@ func (i *Index) TagSets(name []byte, opt influxql.IteratorOptions) ([]*influxql.
 
 			// Build the TagSet for this series.
 			for _, dim := range opt.Dimensions {
-				tags[dim] = e.Tags().GetString(dim)
+				tags[dim] = opt.DimensionValue(dim, e.Tags().GetString)
 			}
 
 			// Convert the TagSet to a string, so it can be added to a map
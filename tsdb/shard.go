@@ -120,6 +120,9 @@ type Shard struct {
 	engine Engine
 	index  Index
 
+	// writeMu blocks writes while series are renamed.
+	writeMu sync.RWMutex
+
 	closing chan struct{}
 	enabled bool
 
@@ -439,6 +442,9 @@ func (s *Shard) WritePoints(points []models.Point) error {
 
 	var writeError error
 
+	s.writeMu.RLock()
+	defer s.writeMu.RUnlock()
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -490,6 +496,37 @@ func (s *Shard) DeleteSeriesRange(seriesKeys [][]byte, min, max int64) error {
 	return nil
 }
 
+// DeleteFieldRange deletes the values of a field of a measurement from
+// seriesKeys between min and max (inclusive).
+func (s *Shard) DeleteFieldRange(name []byte, field string, seriesKeys [][]byte, min, max int64) error {
+	if err := s.ready(); err != nil {
+		return err
+	}
+	return s.engine.DeleteFieldRange(name, field, seriesKeys, min, max)
+}
+
+// RenameSeries rewrites the series of a measurement matching condition under
+// the name and tags returned by fn. Writes to the shard are blocked until all
+// of the values of the series have been copied, so it is meant to be run
+// while the shard is not being written to.
+func (s *Shard) RenameSeries(name []byte, condition influxql.Expr, fn func(name []byte, tags models.Tags) ([]byte, models.Tags)) error {
+	if err := s.ready(); err != nil {
+		return err
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.engine.RenameSeries(name, condition, fn)
+}
+
+// HasTagKey returns true if a series of the measurement has the tag key.
+func (s *Shard) HasTagKey(name, key []byte) (bool, error) {
+	if err := s.ready(); err != nil {
+		return false, err
+	}
+	return s.engine.HasTagKey(name, key)
+}
+
 // DeleteMeasurement deletes a measurement and all underlying series.
 func (s *Shard) DeleteMeasurement(name []byte) error {
 	if err := s.ready(); err != nil {
@@ -730,6 +767,26 @@ func (s *Shard) CreateIterator(measurement string, opt influxql.IteratorOptions)
 	return s.engine.CreateIterator(measurement, opt)
 }
 
+// IteratorCost returns the estimated cost of creating an iterator for the data in the shard.
+func (s *Shard) IteratorCost(measurement string, opt influxql.IteratorOptions) (influxql.IteratorCost, error) {
+	if err := s.ready(); err != nil {
+		return influxql.IteratorCost{}, err
+	}
+
+	// System sources are read from the index so they do not read any blocks.
+	switch measurement {
+	case "_fieldKeys", "_series", "_tagKeys":
+		return influxql.IteratorCost{NumShards: 1}, nil
+	}
+
+	cost, err := s.engine.IteratorCost(measurement, opt)
+	if err != nil {
+		return influxql.IteratorCost{}, err
+	}
+	cost.NumShards = 1
+	return cost, nil
+}
+
 // createSystemIterator returns an iterator for a system source.
 func (s *Shard) createSystemIterator(measurement string, opt influxql.IteratorOptions) (influxql.Iterator, bool, error) {
 	switch measurement {
@@ -995,6 +1052,7 @@ type ShardGroup interface {
 	FieldDimensions(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error)
 	MapType(measurement, field string) influxql.DataType
 	CreateIterator(measurement string, opt influxql.IteratorOptions) (influxql.Iterator, error)
+	IteratorCost(measurement string, opt influxql.IteratorOptions) (influxql.IteratorCost, error)
 	ExpandSources(sources influxql.Sources) (influxql.Sources, error)
 }
 
@@ -1093,6 +1151,18 @@ func (a Shards) CreateIterator(measurement string, opt influxql.IteratorOptions)
 	return influxql.Iterators(itrs).Merge(opt)
 }
 
+func (a Shards) IteratorCost(measurement string, opt influxql.IteratorOptions) (influxql.IteratorCost, error) {
+	var costs influxql.IteratorCost
+	for _, sh := range a {
+		cost, err := sh.IteratorCost(measurement, opt)
+		if err != nil {
+			return influxql.IteratorCost{}, err
+		}
+		costs = costs.Combine(cost)
+	}
+	return costs, nil
+}
+
 func (a Shards) ExpandSources(sources influxql.Sources) (influxql.Sources, error) {
 	// Use a map as a set to prevent duplicates.
 	set := map[string]influxql.Source{}
@@ -1211,6 +1281,13 @@ func (m *MeasurementFields) CreateFieldIfNotExists(name []byte, typ influxql.Dat
 	return nil
 }
 
+// DeleteField removes a field from the measurement.
+func (m *MeasurementFields) DeleteField(name string) {
+	m.mu.Lock()
+	delete(m.fields, name)
+	m.mu.Unlock()
+}
+
 func (m *MeasurementFields) FieldN() int {
 	m.mu.RLock()
 	n := len(m.fields)
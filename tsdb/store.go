@@ -27,6 +27,12 @@ var (
 	ErrShardNotFound = fmt.Errorf("shard not found")
 	// ErrStoreClosed is returned when trying to use a closed Store.
 	ErrStoreClosed = fmt.Errorf("store is closed")
+	// ErrMeasurementNotFound is returned when renaming a measurement that does not exist.
+	ErrMeasurementNotFound = fmt.Errorf("measurement not found")
+	// ErrMeasurementExists is returned when renaming a measurement to an existing measurement.
+	ErrMeasurementExists = fmt.Errorf("measurement already exists")
+	// ErrTagKeyExists is returned when renaming a tag key to an existing tag key.
+	ErrTagKeyExists = fmt.Errorf("tag key already exists")
 )
 
 // Statistics gathered by the store.
@@ -584,6 +590,118 @@ func (s *Store) DeleteMeasurement(database, name string) error {
 	})
 }
 
+// RenameMeasurement renames a measurement in all shards of a database. The
+// series of the measurement are rewritten under the new name and removed from
+// the measurement, which is dropped once it has no series left.
+func (s *Store) RenameMeasurement(database, name, newName string) error {
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	if exists, err := measurementExists(shards, name); err != nil {
+		return err
+	} else if !exists {
+		return ErrMeasurementNotFound
+	}
+
+	// Series are not merged into an existing measurement.
+	if exists, err := measurementExists(shards, newName); err != nil {
+		return err
+	} else if exists {
+		return ErrMeasurementExists
+	}
+
+	return s.renameSeries(shards, name, nil, func(_ []byte, tags models.Tags) ([]byte, models.Tags) {
+		return []byte(newName), tags
+	})
+}
+
+// RenameTagKey renames a tag key of a measurement in all shards of a database.
+func (s *Store) RenameTagKey(database, name, key, newKey string) error {
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	if exists, err := measurementExists(shards, name); err != nil {
+		return err
+	} else if !exists {
+		return ErrMeasurementNotFound
+	}
+
+	// A series cannot have the same tag key twice.
+	for _, sh := range shards {
+		if exists, err := sh.HasTagKey([]byte(name), []byte(newKey)); err != nil {
+			return err
+		} else if exists {
+			return ErrTagKeyExists
+		}
+	}
+
+	// Select the series that have the tag key.
+	cond := &influxql.BinaryExpr{
+		Op:  influxql.NEQ,
+		LHS: &influxql.VarRef{Val: key},
+		RHS: &influxql.StringLiteral{Val: ""},
+	}
+	return s.renameSeries(shards, name, cond, func(name []byte, tags models.Tags) ([]byte, models.Tags) {
+		value := tags.Get([]byte(key))
+		tags.Delete([]byte(key))
+		tags.Set([]byte(newKey), value)
+		return name, tags
+	})
+}
+
+// RenameTagValue renames a value of a tag key of a measurement in all shards of
+// a database. Series that already have the new value are merged with the
+// renamed series. A point of the renamed series replaces the point of the
+// existing series with the same timestamp, as if it was written again.
+func (s *Store) RenameTagValue(database, name, key, value, newValue string) error {
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	if exists, err := measurementExists(shards, name); err != nil {
+		return err
+	} else if !exists {
+		return ErrMeasurementNotFound
+	}
+
+	// Select the series that have the tag value.
+	cond := &influxql.BinaryExpr{
+		Op:  influxql.EQ,
+		LHS: &influxql.VarRef{Val: key},
+		RHS: &influxql.StringLiteral{Val: value},
+	}
+	return s.renameSeries(shards, name, cond, func(name []byte, tags models.Tags) ([]byte, models.Tags) {
+		tags.Set([]byte(key), []byte(newValue))
+		return name, tags
+	})
+}
+
+// measurementExists returns true if any of the shards has the measurement.
+func measurementExists(shards []*Shard, name string) (bool, error) {
+	for _, sh := range shards {
+		if exists, err := sh.MeasurementExists([]byte(name)); err != nil || exists {
+			return exists, err
+		}
+	}
+	return false, nil
+}
+
+// renameSeries rewrites the series of a measurement matching condition in
+// each shard under the name and tags returned by fn.
+func (s *Store) renameSeries(shards []*Shard, name string, condition influxql.Expr, fn func(name []byte, tags models.Tags) ([]byte, models.Tags)) error {
+	// Limit to 1 rename for each shard since the values of the series are
+	// copied through a cache as large as the cache of a shard.
+	limit := limiter.NewFixed(1)
+	return s.walkShards(shards, func(sh *Shard) error {
+		limit.Take()
+		defer limit.Release()
+
+		return sh.RenameSeries([]byte(name), condition, fn)
+	})
+}
+
 // filterShards returns a slice of shards where fn returns true
 // for the shard. If the provided predicate is nil then all shards are returned.
 func (s *Store) filterShards(fn func(sh *Shard) bool) []*Shard {
@@ -887,6 +1005,79 @@ func (s *Store) DeleteSeries(database string, sources []influxql.Source, conditi
 	})
 }
 
+// DeleteField loops through the local shards and deletes the values of a field
+// from the series matching the condition. The field is removed from a
+// measurement once no values remain for it.
+func (s *Store) DeleteField(database string, sources []influxql.Source, field string, condition influxql.Expr) error {
+	// Expand regex expressions in the FROM clause.
+	a, err := s.ExpandSources(sources)
+	if err != nil {
+		return err
+	} else if sources != nil && len(sources) != 0 && len(a) == 0 {
+		return nil
+	}
+	sources = a
+
+	// Determine deletion time range.
+	min, max, err := influxql.TimeRangeAsEpochNano(condition)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Limit to 1 delete for each shard since expanding the measurement into the list
+	// of series keys can be very memory intensive if run concurrently.
+	limit := limiter.NewFixed(1)
+
+	return s.walkShards(shards, func(sh *Shard) error {
+		// Determine list of measurements from sources.
+		// Use all measurements if no FROM clause was provided.
+		var names []string
+		if len(sources) > 0 {
+			for _, source := range sources {
+				names = append(names, source.(*influxql.Measurement).Name)
+			}
+		} else {
+			if err := sh.engine.ForEachMeasurementName(func(name []byte) error {
+				names = append(names, string(name))
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		sort.Strings(names)
+
+		limit.Take()
+		defer limit.Release()
+
+		// Delete the field from each measurement that has it.
+		for _, name := range names {
+			if mf := sh.engine.MeasurementFields([]byte(name)); mf == nil || !mf.HasField(field) {
+				continue
+			}
+
+			keys, err := sh.engine.MeasurementSeriesKeysByExpr([]byte(name), condition)
+			if err != nil {
+				return err
+			}
+			if !bytesutil.IsSorted(keys) {
+				bytesutil.Sort(keys)
+			}
+
+			if err := sh.DeleteFieldRange([]byte(name), field, keys, min, max); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // ExpandSources expands sources against all local shards.
 func (s *Store) ExpandSources(sources influxql.Sources) (influxql.Sources, error) {
 	shards := func() Shards {
@@ -956,6 +1147,92 @@ func (s *Store) MeasurementSeriesCounts(database string) (measuments int, series
 	return 0, 0
 }
 
+// MeasurementCardinality is the number of distinct items within a measurement.
+type MeasurementCardinality struct {
+	Measurement string
+	Cardinality int64
+}
+
+// SeriesCardinalityByMeasurement returns the exact number of series in each
+// measurement of the database that match the condition. Unlike SeriesCardinality,
+// the index of every shard is walked and the series keys are deduplicated, so
+// this is considerably more expensive.
+func (s *Store) SeriesCardinalityByMeasurement(database string, cond influxql.Expr) ([]MeasurementCardinality, error) {
+	measurementExpr, filterExpr := splitMeasurementCondition(cond)
+
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	m := make(map[string]map[string]struct{})
+	for _, sh := range shards {
+		names, err := sh.MeasurementNamesByExpr(measurementExpr)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			keys := m[string(name)]
+			if err := sh.engine.ForEachMeasurementSeriesByExpr(name, filterExpr, func(tags models.Tags) error {
+				if keys == nil {
+					keys = make(map[string]struct{})
+					m[string(name)] = keys
+				}
+				keys[string(models.MakeKey(name, tags))] = struct{}{}
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	a := make([]MeasurementCardinality, 0, len(m))
+	for name, keys := range m {
+		a = append(a, MeasurementCardinality{Measurement: name, Cardinality: int64(len(keys))})
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].Measurement < a[j].Measurement })
+	return a, nil
+}
+
+// FieldKeyCardinalityByMeasurement returns the number of distinct field keys in
+// each measurement of the database that matches the condition.
+func (s *Store) FieldKeyCardinalityByMeasurement(database string, cond influxql.Expr) ([]MeasurementCardinality, error) {
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	m := make(map[string]map[string]struct{})
+	for _, sh := range shards {
+		names, err := sh.MeasurementNamesByExpr(cond)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			mf := sh.MeasurementFields(name)
+			if mf == nil || mf.FieldN() == 0 {
+				continue
+			}
+
+			keys := m[string(name)]
+			if keys == nil {
+				keys = make(map[string]struct{})
+				m[string(name)] = keys
+			}
+			for key := range mf.FieldSet() {
+				keys[key] = struct{}{}
+			}
+		}
+	}
+
+	a := make([]MeasurementCardinality, 0, len(m))
+	for name, keys := range m {
+		a = append(a, MeasurementCardinality{Measurement: name, Cardinality: int64(len(keys))})
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].Measurement < a[j].Measurement })
+	return a, nil
+}
+
 type TagValues struct {
 	Measurement string
 	Values      []KeyValue
@@ -973,35 +1250,7 @@ func (s *Store) TagValues(database string, cond influxql.Expr) ([]TagValues, err
 		return nil, errors.New("a condition is required")
 	}
 
-	measurementExpr := influxql.CloneExpr(cond)
-	measurementExpr = influxql.Reduce(influxql.RewriteExpr(measurementExpr, func(e influxql.Expr) influxql.Expr {
-		switch e := e.(type) {
-		case *influxql.BinaryExpr:
-			switch e.Op {
-			case influxql.EQ, influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
-				tag, ok := e.LHS.(*influxql.VarRef)
-				if !ok || tag.Val != "_name" {
-					return nil
-				}
-			}
-		}
-		return e
-	}), nil)
-
-	filterExpr := influxql.CloneExpr(cond)
-	filterExpr = influxql.Reduce(influxql.RewriteExpr(filterExpr, func(e influxql.Expr) influxql.Expr {
-		switch e := e.(type) {
-		case *influxql.BinaryExpr:
-			switch e.Op {
-			case influxql.EQ, influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
-				tag, ok := e.LHS.(*influxql.VarRef)
-				if !ok || strings.HasPrefix(tag.Val, "_") {
-					return nil
-				}
-			}
-		}
-		return e
-	}), nil)
+	measurementExpr, filterExpr := splitMeasurementCondition(cond)
 
 	// Get all measurements for the shards we're interested in.
 	s.mu.RLock()
@@ -1057,6 +1306,41 @@ func (s *Store) TagValues(database string, cond influxql.Expr) ([]TagValues, err
 	return tagValues, nil
 }
 
+// splitMeasurementCondition splits cond into an expression that only filters on
+// the measurement name and an expression that only filters on tags.
+func splitMeasurementCondition(cond influxql.Expr) (measurementExpr, filterExpr influxql.Expr) {
+	measurementExpr = influxql.CloneExpr(cond)
+	measurementExpr = influxql.Reduce(influxql.RewriteExpr(measurementExpr, func(e influxql.Expr) influxql.Expr {
+		switch e := e.(type) {
+		case *influxql.BinaryExpr:
+			switch e.Op {
+			case influxql.EQ, influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
+				tag, ok := e.LHS.(*influxql.VarRef)
+				if !ok || tag.Val != "_name" {
+					return nil
+				}
+			}
+		}
+		return e
+	}), nil)
+
+	filterExpr = influxql.CloneExpr(cond)
+	filterExpr = influxql.Reduce(influxql.RewriteExpr(filterExpr, func(e influxql.Expr) influxql.Expr {
+		switch e := e.(type) {
+		case *influxql.BinaryExpr:
+			switch e.Op {
+			case influxql.EQ, influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX:
+				tag, ok := e.LHS.(*influxql.VarRef)
+				if !ok || strings.HasPrefix(tag.Val, "_") {
+					return nil
+				}
+			}
+		}
+		return e
+	}), nil)
+	return measurementExpr, filterExpr
+}
+
 func (s *Store) monitorShards() {
 	defer s.wg.Done()
 	t := time.NewTicker(10 * time.Second)
@@ -453,6 +453,251 @@ func TestStore_MeasurementNames_Deduplicate(t *testing.T) {
 	}
 }
 
+func TestStore_SeriesCardinalityByMeasurement(t *testing.T) {
+	t.Parallel()
+
+	s := MustOpenStore()
+	defer s.Close()
+
+	// Create two shards with overlapping series.
+	s.MustCreateShardWithData("db0", "rp0", 1,
+		`cpu,host=serverA value=1 0`,
+		`cpu,host=serverB value=1 0`,
+		`mem,host=serverA value=1 0`,
+	)
+	s.MustCreateShardWithData("db0", "rp0", 2,
+		`cpu,host=serverA value=1 10`,
+		`cpu,host=serverC value=1 10`,
+	)
+
+	a, err := s.SeriesCardinalityByMeasurement("db0", nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := []tsdb.MeasurementCardinality{
+		{Measurement: "cpu", Cardinality: 3},
+		{Measurement: "mem", Cardinality: 1},
+	}; !reflect.DeepEqual(a, exp) {
+		t.Fatalf("unexpected cardinality: exp %v, got %v", exp, a)
+	}
+
+	a, err = s.SeriesCardinalityByMeasurement("db0", influxql.MustParseExpr(`_name = 'cpu' AND host != 'serverB'`))
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := []tsdb.MeasurementCardinality{
+		{Measurement: "cpu", Cardinality: 2},
+	}; !reflect.DeepEqual(a, exp) {
+		t.Fatalf("unexpected cardinality: exp %v, got %v", exp, a)
+	}
+}
+
+func TestStore_FieldKeyCardinalityByMeasurement(t *testing.T) {
+	t.Parallel()
+
+	s := MustOpenStore()
+	defer s.Close()
+
+	// Create two shards with overlapping fields.
+	s.MustCreateShardWithData("db0", "rp0", 1,
+		`cpu,host=serverA user=1,system=2 0`,
+		`mem,host=serverA free=1 0`,
+	)
+	s.MustCreateShardWithData("db0", "rp0", 2,
+		`cpu,host=serverB user=1,idle=2 10`,
+	)
+
+	a, err := s.FieldKeyCardinalityByMeasurement("db0", nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := []tsdb.MeasurementCardinality{
+		{Measurement: "cpu", Cardinality: 3},
+		{Measurement: "mem", Cardinality: 1},
+	}; !reflect.DeepEqual(a, exp) {
+		t.Fatalf("unexpected cardinality: exp %v, got %v", exp, a)
+	}
+}
+
+func TestStore_DeleteField(t *testing.T) {
+	t.Parallel()
+
+	s := MustOpenStore()
+	defer s.Close()
+
+	s.MustCreateShardWithData("db0", "rp0", 1,
+		`cpu,host=serverA user=1,system=2 0`,
+		`cpu,host=serverB system=3 10`,
+		`mem,host=serverA free=1,system=2 0`,
+	)
+	s.MustCreateShardWithData("db0", "rp0", 2,
+		`cpu,host=serverA system=4 20`,
+	)
+
+	fieldKeys := func() []tsdb.MeasurementCardinality {
+		a, err := s.FieldKeyCardinalityByMeasurement("db0", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+
+	// Deleting the field from some of the series keeps it.
+	if err := s.DeleteField("db0", []influxql.Source{&influxql.Measurement{Name: "cpu"}}, "system", influxql.MustParseExpr(`host = 'serverA'`)); err != nil {
+		t.Fatal(err)
+	} else if a, exp := fieldKeys(), []tsdb.MeasurementCardinality{
+		{Measurement: "cpu", Cardinality: 2},
+		{Measurement: "mem", Cardinality: 2},
+	}; !reflect.DeepEqual(a, exp) {
+		t.Fatalf("unexpected cardinality: exp %v, got %v", exp, a)
+	}
+
+	// The field is removed once no series have values for it.
+	if err := s.DeleteField("db0", []influxql.Source{&influxql.Measurement{Name: "cpu"}}, "system", nil); err != nil {
+		t.Fatal(err)
+	} else if a, exp := fieldKeys(), []tsdb.MeasurementCardinality{
+		{Measurement: "cpu", Cardinality: 1},
+		{Measurement: "mem", Cardinality: 2},
+	}; !reflect.DeepEqual(a, exp) {
+		t.Fatalf("unexpected cardinality: exp %v, got %v", exp, a)
+	}
+}
+
+func testStoreRename(t *testing.T, s *Store) {
+	s.MustCreateShardWithData("db0", "rp0", 0,
+		`cpu,host=serverA value=1  0`,
+		`cpu,host=serverA value=2 10`,
+		`cpu,host=serverA value=9 20`,
+		`cpu,host=serverB value=3 20`,
+	)
+	s.MustCreateShardWithData("db0", "rp0", 1,
+		`cpu,host=serverA value=4 30`,
+		`mem,host=serverA value=5 40`,
+	)
+
+	// readPoints returns the points of a measurement grouped by a tag.
+	readPoints := func(name, dimension string) []*influxql.FloatPoint {
+		itr, err := s.ShardGroup([]uint64{0, 1}).CreateIterator(name, influxql.IteratorOptions{
+			Expr:       influxql.MustParseExpr(`value`),
+			Dimensions: []string{dimension},
+			Ascending:  true,
+			StartTime:  influxql.MinTime,
+			EndTime:    influxql.MaxTime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if itr == nil {
+			return nil
+		}
+		defer itr.Close()
+
+		var points []*influxql.FloatPoint
+		for {
+			p, err := itr.(influxql.FloatIterator).Next()
+			if err != nil {
+				t.Fatal(err)
+			} else if p == nil {
+				return points
+			}
+			points = append(points, p.Clone())
+		}
+	}
+
+	// Renaming a tag value merges the series with the existing value. The
+	// points of the renamed series replace points with the same timestamp.
+	if err := s.RenameTagValue("db0", "cpu", "host", "serverA", "serverB"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := readPoints("cpu", "host"), []*influxql.FloatPoint{
+		{Name: "cpu", Tags: ParseTags("host=serverB"), Time: time.Unix(0, 0).UnixNano(), Value: 1},
+		{Name: "cpu", Tags: ParseTags("host=serverB"), Time: time.Unix(10, 0).UnixNano(), Value: 2},
+		{Name: "cpu", Tags: ParseTags("host=serverB"), Time: time.Unix(20, 0).UnixNano(), Value: 9},
+		{Name: "cpu", Tags: ParseTags("host=serverB"), Time: time.Unix(30, 0).UnixNano(), Value: 4},
+	}; !deep.Equal(got, exp) {
+		t.Fatalf("unexpected points: %s", spew.Sdump(got))
+	}
+
+	if err := s.RenameTagKey("db0", "cpu", "host", "hostname"); err != nil {
+		t.Fatal(err)
+	} else if got := readPoints("cpu", "hostname"); len(got) != 4 || got[3].Tags.ID() != ParseTags("hostname=serverB").ID() {
+		t.Fatalf("unexpected points: %s", spew.Sdump(got))
+	}
+
+	if err := s.RenameMeasurement("db0", "cpu", "cpu_v2"); err != nil {
+		t.Fatal(err)
+	} else if got := readPoints("cpu_v2", "hostname"); len(got) != 4 || got[0].Name != "cpu_v2" || got[0].Value != 1 {
+		t.Fatalf("unexpected points: %s", spew.Sdump(got))
+	} else if got := readPoints("cpu", "hostname"); len(got) != 0 {
+		t.Fatalf("unexpected points: %s", spew.Sdump(got))
+	}
+
+	names, err := s.MeasurementNames("db0", nil)
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := names, [][]byte{[]byte("cpu_v2"), []byte("mem")}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected measurements: exp %s, got %s", exp, got)
+	}
+
+	// Measurements and tag keys are not merged.
+	if err := s.RenameMeasurement("db0", "cpu_v2", "mem"); err != tsdb.ErrMeasurementExists {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.RenameTagKey("db0", "mem", "host", "host"); err != tsdb.ErrTagKeyExists {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Measurements that do not exist cannot be renamed.
+	if err := s.RenameMeasurement("db0", "nosuch", "x"); err != tsdb.ErrMeasurementNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.RenameTagKey("db0", "nosuch", "host", "hostname"); err != tsdb.ErrMeasurementNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := s.RenameTagValue("db0", "nosuch", "host", "serverA", "serverB"); err != tsdb.ErrMeasurementNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Points written while a measurement is renamed are not lost.
+	var written int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			p := models.MustNewPoint("mem", models.NewTags(map[string]string{"host": "serverA"}), models.Fields{"value": float64(i)}, time.Unix(int64(100+i), 0))
+			if err := s.WriteToShard(1, []models.Point{p}); err != nil {
+				t.Error(err)
+				return
+			}
+			written++
+		}
+	}()
+	if err := s.RenameMeasurement("db0", "mem", "mem_v2"); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	if got, exp := len(readPoints("mem", "host"))+len(readPoints("mem_v2", "host")), written+1; got != exp {
+		t.Fatalf("unexpected number of points: got %d, exp %d", got, exp)
+	}
+}
+
+func TestStore_Rename_Inmem(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore()
+	store.EngineOptions.Config.Index = "inmem"
+	if err := store.Open(); err != nil {
+		panic(err)
+	}
+	defer store.Close()
+	testStoreRename(t, store)
+}
+
+func TestStore_Rename_TSI(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore()
+	store.EngineOptions.Config.Index = "tsi1"
+	if err := store.Open(); err != nil {
+		panic(err)
+	}
+	defer store.Close()
+	testStoreRename(t, store)
+}
+
 func testStoreCardinalityTombstoning(t *testing.T, store *Store) {
 	if testing.Short() || os.Getenv("GORACE") != "" || os.Getenv("APPVEYOR") != "" {
 		t.Skip("Skipping test in short, race and appveyor mode.")